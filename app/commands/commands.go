@@ -9,6 +9,9 @@ var (
 	API                command = "api"
 	LiveAuctions       command = "live-auctions"
 	PricelistHistories command = "pricelist-histories"
+	HTTPAPI            command = "http-api"
+	Export             command = "export"
+	Replay             command = "replay"
 
 	ProdApi                 command = "prod-api"
 	ProdMetrics             command = "prod-metrics"
@@ -3,8 +3,12 @@ module github.com/sotah-inc/steamwheedle-cartel-server/app
 go 1.12
 
 require (
+	cloud.google.com/go v0.36.0
+	github.com/boltdb/bolt v1.3.1
+	github.com/nats-io/go-nats v1.7.0
 	github.com/sirupsen/logrus v1.4.2
 	github.com/sotah-inc/steamwheedle-cartel v0.0.0-20191001024847-98c520fd22e7
 	github.com/twinj/uuid v1.0.0
+	google.golang.org/api v0.1.0
 	gopkg.in/alecthomas/kingpin.v2 v2.2.6
 )
@@ -5,14 +5,21 @@ import (
 	"fmt"
 	"io/ioutil"
 	"os"
+	"time"
 
 	"github.com/sirupsen/logrus"
 	"github.com/sotah-inc/steamwheedle-cartel-server/app/commands"
-	devCommand "github.com/sotah-inc/steamwheedle-cartel/pkg/command/dev"
+	serverCommand "github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/command/server"
+	"github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/config"
+	"github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/export"
+	"github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/pricelists"
+	serverState "github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/state/server"
+	"github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/storage"
+	"github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/transport"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/blizzard"
 	prodCommand "github.com/sotah-inc/steamwheedle-cartel/pkg/command/prod"
 	"github.com/sotah-inc/steamwheedle-cartel/pkg/logging"
 	"github.com/sotah-inc/steamwheedle-cartel/pkg/logging/stackdriver"
-	"github.com/sotah-inc/steamwheedle-cartel/pkg/sotah"
 	devState "github.com/sotah-inc/steamwheedle-cartel/pkg/state/dev"
 	prodState "github.com/sotah-inc/steamwheedle-cartel/pkg/state/prod"
 	"github.com/sotah-inc/steamwheedle-cartel/pkg/store"
@@ -42,10 +49,33 @@ func main() {
 		projectID      = app.Flag("project-id", "GCloud Storage Project ID").Default("").Envar("PROJECT_ID").String()
 		isLocal        = app.Flag("is-local", "Flag to use local config filepath or not").Bool()
 		configFilepath = app.Flag("config-filepath", "Optional config filepath").Short('c').String()
+		httpHost       = app.Flag("http-host", "HTTP listen hostname").Default("").Envar("HTTP_HOST").String()
+		httpPort       = app.Flag("http-port", "HTTP listen port").Default("8080").Envar("HTTP_PORT").Int()
+		busTransport   = app.Flag("bus-transport", "Bus transport (pubsub, nats, inprocess)").Default(string(transport.Pubsub)).Envar("BUS_TRANSPORT").Enum(string(transport.Pubsub), string(transport.Nats), string(transport.InProcess))
+		storageKind    = app.Flag("storage-backend", "Storage backend (disk, memory, gcloud)").Default("").Envar("STORAGE_BACKEND").Enum("", string(storage.Disk), string(storage.Memory), string(storage.GCloud))
 
 		apiCommand                = app.Command(string(commands.API), "For running sotah-server.")
 		liveAuctionsCommand       = app.Command(string(commands.LiveAuctions), "For in-memory storage of current auctions.")
 		pricelistHistoriesCommand = app.Command(string(commands.PricelistHistories), "For on-disk storage of pricelist histories.")
+		httpAPICommand            = app.Command(string(commands.HTTPAPI), "For exposing nats subjects over http.")
+		exportCommand             = app.Command(string(commands.Export), "For exporting auctions, prices and histories to a file.")
+		exportDataset             = exportCommand.Flag("dataset", "Dataset to export").Default(string(export.Auctions)).Enum(string(export.Auctions), string(export.Prices), string(export.PriceHistories), string(export.VendorFlips), string(export.YieldValues))
+		exportFormat              = exportCommand.Flag("format", "Output format (csv, ndjson, parquet)").Default(string(export.CSV)).Enum(string(export.CSV), string(export.NDJSON), string(export.Parquet))
+		exportRegion              = exportCommand.Flag("region", "Region name").Required().String()
+		exportRealms              = exportCommand.Flag("realm", "Realm slug, repeatable").Required().Strings()
+		exportItems               = exportCommand.Flag("item", "Item id, repeatable, where none exports every item").Ints()
+		exportFrom                = exportCommand.Flag("from", "Lower bound of price-histories as a unix timestamp").Default("0").Int64()
+		exportTo                  = exportCommand.Flag("to", "Upper bound of price-histories as a unix timestamp, defaulting to now").Default("0").Int64()
+		exportPriceBasis          = exportCommand.Flag("price-basis", "Price basis of yield-values (market, min-buyout)").Default(string(pricelists.MarketPrice)).Enum(string(pricelists.MarketPrice), string(pricelists.MinBuyoutPer))
+		exportOutput              = exportCommand.Flag("output", "Output filepath, defaulting to stdout").Short('o').String()
+		replayCommand             = app.Command(string(commands.Replay), "For rebuilding databases from archived auction dumps.")
+		replayDumpsDir            = replayCommand.Flag("dumps-dir", "Directory of <region>/<realm>/<timestamp>.json.gz auction dumps").Required().String()
+		replayRegion              = replayCommand.Flag("region", "Region name, where none replays every region").String()
+		replayRealms              = replayCommand.Flag("realm", "Realm slug, repeatable, where none replays every realm").Strings()
+		replayFrom                = replayCommand.Flag("from", "Lower bound as a unix timestamp").Default("0").Int64()
+		replayTo                  = replayCommand.Flag("to", "Upper bound as a unix timestamp, where none replays every later dump").Default("0").Int64()
+		replayLiveAuctions        = replayCommand.Flag("live-auctions", "Replay into the live-auctions databases").Default("true").Bool()
+		replayPricelistHistories  = replayCommand.Flag("pricelist-histories", "Replay into the pricelist-histories databases").Default("true").Bool()
 
 		prodApiCommand                = app.Command(string(commands.ProdApi), "For running sotah-server in prod-mode.")
 		prodMetricsCommand            = app.Command(string(commands.ProdMetrics), "For forwarding metrics to a nats channel.")
@@ -53,6 +83,7 @@ func main() {
 		prodPricelistHistoriesCommand = app.Command(string(commands.ProdPricelistHistories), "For managing pricelist-histories in gcp ce vm.")
 		prodItemsCommand              = app.Command(string(commands.ProdItems), "For managing items in gcp ce vm.")
 		prodGateway                   = app.Command(string(commands.ProdGateway), "For invoking the act gateway.")
+		prodGatewayActEndpoint        = prodGateway.Flag("act-endpoint", "Gateway act endpoint, fetched from hell when blank").Envar("ACT_ENDPOINT").String()
 		prodPubsubTopicsMonitor       = app.Command(string(commands.ProdPubsubTopicsMonitor), "For invoking the pubsub-topics-monitor gateway.")
 	)
 	cmd := kingpin.MustParse(app.Parse(os.Args[1:]))
@@ -67,36 +98,36 @@ func main() {
 	logging.SetLevel(logVerbosity)
 
 	// gathering the config file from a store
-	c, err := func() (sotah.Config, error) {
+	c, err := func() (config.Config, error) {
 		if *isLocal {
-			return sotah.NewConfigFromFilepath(*configFilepath)
+			return config.NewConfigFromFilepath(*configFilepath)
 		}
 
 		storeClient, err := store.NewClient(*projectID)
 		if err != nil {
-			return sotah.Config{}, err
+			return config.Config{}, err
 		}
 
 		bootBase := store.NewBootBase(storeClient, regions.USCentral1)
 		bootBucket, err := bootBase.GetFirmBucket()
 		configObj, err := bootBase.GetFirmObject("config.json", bootBucket)
 		if err != nil {
-			return sotah.Config{}, err
+			return config.Config{}, err
 		}
 
 		reader, err := configObj.NewReader(storeClient.Context)
 		if err != nil {
-			return sotah.Config{}, err
+			return config.Config{}, err
 		}
 
 		data, err := ioutil.ReadAll(reader)
 		if err != nil {
-			return sotah.Config{}, err
+			return config.Config{}, err
 		}
 
-		var out sotah.Config
+		var out config.Config
 		if err := json.Unmarshal(data, &out); err != nil {
-			return sotah.Config{}, err
+			return config.Config{}, err
 		}
 
 		return out, nil
@@ -126,78 +157,172 @@ func main() {
 
 	logging.WithField("command", cmd).Info("Running command")
 
+	// resolving the storage backend
+	storageBackend := storage.BackendConfig{
+		Kind:            storage.ResolveKind(storage.Kind(*storageKind), c.UseGCloud),
+		CacheDir:        *cacheDir,
+		GCloudProjectID: *projectID,
+	}
+
+	// resolving the state configs shared by the services and the replay command
+	liveAuctionsStateConfig := serverState.LiveAuctionsStateConfig{
+		LiveAuctionsStateConfig: devState.LiveAuctionsStateConfig{
+			MessengerHost:           *natsHost,
+			MessengerPort:           *natsPort,
+			DiskStoreCacheDir:       *cacheDir,
+			LiveAuctionsDatabaseDir: fmt.Sprintf("%s/databases", *cacheDir),
+		},
+		StorageBackend: storageBackend,
+		Retention:      c.Retention,
+	}
+	pricelistHistoriesStateConfig := serverState.PricelistHistoriesStateConfig{
+		PricelistHistoriesStateConfig: devState.PricelistHistoriesStateConfig{
+			DiskStoreCacheDir:             *cacheDir,
+			MessengerPort:                 *natsPort,
+			MessengerHost:                 *natsHost,
+			PricelistHistoriesDatabaseDir: fmt.Sprintf("%s/databases", *cacheDir),
+		},
+		StorageBackend: storageBackend,
+		Retention:      c.Retention,
+	}
+
 	// declaring a command map
 	cMap := commandMap{
 		apiCommand.FullCommand(): func() error {
-			return devCommand.Api(devState.APIStateConfig{
-				SotahConfig:          c,
-				DiskStoreCacheDir:    *cacheDir,
-				ItemsDatabaseDir:     fmt.Sprintf("%s/databases", *cacheDir),
-				BlizzardClientSecret: *clientSecret,
-				BlizzardClientId:     *clientID,
-				MessengerPort:        *natsPort,
-				MessengerHost:        *natsHost,
-				GCloudProjectID:      *projectID,
+			return serverCommand.API(serverState.APIStateConfig{
+				APIStateConfig: devState.APIStateConfig{
+					SotahConfig:          c.Config,
+					DiskStoreCacheDir:    *cacheDir,
+					ItemsDatabaseDir:     fmt.Sprintf("%s/databases", *cacheDir),
+					BlizzardClientSecret: *clientSecret,
+					BlizzardClientId:     *clientID,
+					MessengerPort:        *natsPort,
+					MessengerHost:        *natsHost,
+					GCloudProjectID:      *projectID,
+				},
+				StorageBackend: storageBackend,
+				Recipes:        c.Recipes,
+				Yields:         c.Yields,
+				Blizzard:       c.Blizzard,
 			})
 		},
 		liveAuctionsCommand.FullCommand(): func() error {
-			return devCommand.LiveAuctions(devState.LiveAuctionsStateConfig{
-				MessengerHost:           *natsHost,
-				MessengerPort:           *natsPort,
-				DiskStoreCacheDir:       *cacheDir,
-				LiveAuctionsDatabaseDir: fmt.Sprintf("%s/databases", *cacheDir),
-			})
+			return serverCommand.LiveAuctions(liveAuctionsStateConfig)
 		},
 		pricelistHistoriesCommand.FullCommand(): func() error {
-			return devCommand.PricelistHistories(devState.PricelistHistoriesStateConfig{
-				DiskStoreCacheDir:             *cacheDir,
-				MessengerPort:                 *natsPort,
-				MessengerHost:                 *natsHost,
-				PricelistHistoriesDatabaseDir: fmt.Sprintf("%s/databases", *cacheDir),
+			return serverCommand.PricelistHistories(pricelistHistoriesStateConfig)
+		},
+		httpAPICommand.FullCommand(): func() error {
+			return serverCommand.HTTPAPI(serverState.HTTPAPIStateConfig{
+				MessengerHost: *natsHost,
+				MessengerPort: *natsPort,
+				ListenHost:    *httpHost,
+				ListenPort:    *httpPort,
+			})
+		},
+		exportCommand.FullCommand(): func() error {
+			realmSlugs := []blizzard.RealmSlug{}
+			for _, realmSlug := range *exportRealms {
+				realmSlugs = append(realmSlugs, blizzard.RealmSlug(realmSlug))
+			}
+			itemIds := []blizzard.ItemID{}
+			for _, itemId := range *exportItems {
+				itemIds = append(itemIds, blizzard.ItemID(itemId))
+			}
+
+			return serverCommand.Export(serverCommand.ExportConfig{
+				MessengerHost: *natsHost,
+				MessengerPort: *natsPort,
+				Request: export.Request{
+					Dataset:     export.Dataset(*exportDataset),
+					Format:      export.Format(*exportFormat),
+					RegionName:  blizzard.RegionName(*exportRegion),
+					RealmSlugs:  realmSlugs,
+					ItemIds:     itemIds,
+					LowerBounds: *exportFrom,
+					UpperBounds: *exportTo,
+					PriceBasis:  pricelists.PriceBasis(*exportPriceBasis),
+				},
+				OutputFilepath: *exportOutput,
+			})
+		},
+		replayCommand.FullCommand(): func() error {
+			realmSlugs := []blizzard.RealmSlug{}
+			for _, realmSlug := range *replayRealms {
+				realmSlugs = append(realmSlugs, blizzard.RealmSlug(realmSlug))
+			}
+			upperBounds := time.Time{}
+			if *replayTo > 0 {
+				upperBounds = time.Unix(*replayTo, 0)
+			}
+
+			return serverCommand.Replay(serverCommand.ReplayConfig{
+				LiveAuctionsStateConfig:       liveAuctionsStateConfig,
+				PricelistHistoriesStateConfig: pricelistHistoriesStateConfig,
+				ReplayLiveAuctions:            *replayLiveAuctions,
+				ReplayPricelistHistories:      *replayPricelistHistories,
+				DumpsDir:                      *replayDumpsDir,
+				RegionName:                    blizzard.RegionName(*replayRegion),
+				RealmSlugs:                    realmSlugs,
+				LowerBounds:                   time.Unix(*replayFrom, 0),
+				UpperBounds:                   upperBounds,
 			})
 		},
 		prodApiCommand.FullCommand(): func() error {
 			return prodCommand.ProdApi(prodState.ProdApiStateConfig{
-				SotahConfig:     c,
+				SotahConfig:     c.Config,
 				MessengerPort:   *natsPort,
 				MessengerHost:   *natsHost,
 				GCloudProjectID: *projectID,
 			})
 		},
 		prodMetricsCommand.FullCommand(): func() error {
-			return prodCommand.ProdMetrics(prodState.ProdMetricsStateConfig{
+			return serverCommand.Metrics(serverState.MetricsStateConfig{
 				MessengerPort:   *natsPort,
 				MessengerHost:   *natsHost,
 				GCloudProjectID: *projectID,
+				BusTransport:    transport.Kind(*busTransport),
 			})
 		},
 		prodLiveAuctionsCommand.FullCommand(): func() error {
-			return prodCommand.ProdLiveAuctions(prodState.ProdLiveAuctionsStateConfig{
+			return serverCommand.ProdLiveAuctions(serverState.ProdLiveAuctionsStateConfig{
 				MessengerPort:           *natsPort,
 				MessengerHost:           *natsHost,
 				GCloudProjectID:         *projectID,
 				LiveAuctionsDatabaseDir: fmt.Sprintf("%s/databases", *cacheDir),
+				Regions:                 c.FilterInRegions(c.Regions),
+				BusTransport:            transport.Kind(*busTransport),
+				StorageBackend:          storageBackend,
 			})
 		},
 		prodPricelistHistoriesCommand.FullCommand(): func() error {
-			return prodCommand.ProdPricelistHistories(prodState.ProdPricelistHistoriesStateConfig{
+			return serverCommand.ProdPricelistHistories(serverState.ProdPricelistHistoriesStateConfig{
 				MessengerPort:                 *natsPort,
 				MessengerHost:                 *natsHost,
 				GCloudProjectID:               *projectID,
 				PricelistHistoriesDatabaseDir: fmt.Sprintf("%s/databases", *cacheDir),
+				Regions:                       c.FilterInRegions(c.Regions),
+				BusTransport:                  transport.Kind(*busTransport),
+				StorageBackend:                storageBackend,
 			})
 		},
 		prodItemsCommand.FullCommand(): func() error {
-			return prodCommand.Items(prodState.ItemsStateConfig{
+			return serverCommand.Items(serverState.ItemsStateConfig{
 				MessengerPort:    *natsPort,
 				MessengerHost:    *natsHost,
 				GCloudProjectID:  *projectID,
 				ItemsDatabaseDir: fmt.Sprintf("%s/databases", *cacheDir),
+				BusTransport:     transport.Kind(*busTransport),
+				StorageBackend:   storageBackend,
 			})
 		},
 		prodGateway.FullCommand(): func() error {
-			return prodCommand.Gateway(prodState.GatewayStateConfig{
-				ProjectId: *projectID,
+			return serverCommand.Gateway(serverState.GatewayStateConfig{
+				GCloudProjectID: *projectID,
+				MessengerPort:   *natsPort,
+				MessengerHost:   *natsHost,
+				ActEndpoint:     *prodGatewayActEndpoint,
+				BusTransport:    transport.Kind(*busTransport),
 			})
 		},
 		prodPubsubTopicsMonitor.FullCommand(): func() error {
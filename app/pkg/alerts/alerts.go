@@ -0,0 +1,162 @@
+package alerts
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/blizzard"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/sotah"
+)
+
+// bucketing
+func alertsBucketName() []byte {
+	return []byte("alerts")
+}
+
+// keying
+func alertKeyName(ID string) []byte {
+	return []byte(ID)
+}
+
+// db
+func databasePath(dirPath string) string {
+	return fmt.Sprintf("%s/alerts/alerts.db", dirPath)
+}
+
+func databaseDirPath(dirPath string) string {
+	return fmt.Sprintf("%s/alerts", dirPath)
+}
+
+// Kind - typehint for these enums
+type Kind string
+
+/*
+Kinds - conditions an alert may watch for
+*/
+const (
+	MinBuyoutBelow Kind = "min-buyout-below"
+	OwnerListsItem Kind = "owner-lists-item"
+)
+
+func NewAlert(data []byte) (Alert, error) {
+	a := &Alert{}
+	if err := json.Unmarshal(data, &a); err != nil {
+		return Alert{}, err
+	}
+
+	return *a, nil
+}
+
+// Alert is a registered price condition on a single realm
+type Alert struct {
+	ID         string              `json:"id"`
+	Kind       Kind                `json:"kind"`
+	RegionName blizzard.RegionName `json:"region_name"`
+	RealmSlug  blizzard.RealmSlug  `json:"realm_slug"`
+	ItemID     blizzard.ItemID     `json:"item_id"`
+
+	// min-buyout-below alerts
+	Threshold float64 `json:"threshold"`
+
+	// owner-lists-item alerts
+	Owner sotah.OwnerName `json:"owner"`
+
+	// optional url that matches are posted to
+	WebhookURL string `json:"webhook_url"`
+}
+
+func (a Alert) Validate() error {
+	if len(a.RegionName) == 0 || len(a.RealmSlug) == 0 {
+		return errors.New("region and realm cannot be blank")
+	}
+
+	if a.ItemID == 0 {
+		return errors.New("item id cannot be blank")
+	}
+
+	switch a.Kind {
+	case MinBuyoutBelow:
+		if a.Threshold <= 0 {
+			return errors.New("threshold must be positive")
+		}
+	case OwnerListsItem:
+		if len(a.Owner) == 0 {
+			return errors.New("owner cannot be blank")
+		}
+	default:
+		return fmt.Errorf("invalid alert kind: %s", a.Kind)
+	}
+
+	if len(a.WebhookURL) > 0 {
+		if err := ValidateWebhookURL(a.WebhookURL); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (a Alert) EncodeForStorage() ([]byte, error) {
+	return json.Marshal(a)
+}
+
+// Evaluate checks the alert against a realm's current mini-auction-list
+func (a Alert) Evaluate(maList sotah.MiniAuctionList, targetTime time.Time) (Match, bool) {
+	match := Match{Alert: a, TargetTime: targetTime.Unix()}
+
+	for _, mAuction := range maList {
+		if mAuction.ItemID != a.ItemID {
+			continue
+		}
+
+		switch a.Kind {
+		case MinBuyoutBelow:
+			if mAuction.Buyout == 0 || mAuction.Quantity == 0 {
+				continue
+			}
+
+			buyoutPer := float64(mAuction.Buyout) / float64(mAuction.Quantity)
+			if buyoutPer >= a.Threshold {
+				continue
+			}
+
+			if match.MinBuyoutPer == 0 || buyoutPer < match.MinBuyoutPer {
+				match.MinBuyoutPer = buyoutPer
+			}
+			match.TotalAuctions += len(mAuction.AucList)
+		case OwnerListsItem:
+			if mAuction.Owner != a.Owner {
+				continue
+			}
+
+			match.TotalAuctions += len(mAuction.AucList)
+		}
+	}
+
+	return match, match.TotalAuctions > 0
+}
+
+type Alerts []Alert
+
+func (as Alerts) EncodeForDelivery() (string, error) {
+	jsonEncoded, err := json.Marshal(as)
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonEncoded), nil
+}
+
+// Match is published whenever an alert's condition holds for a new snapshot
+type Match struct {
+	Alert         Alert   `json:"alert"`
+	TargetTime    int64   `json:"target_time"`
+	MinBuyoutPer  float64 `json:"min_buyout_per"`
+	TotalAuctions int     `json:"total_auctions"`
+}
+
+func (m Match) EncodeForDelivery() ([]byte, error) {
+	return json.Marshal(m)
+}
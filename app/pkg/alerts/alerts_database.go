@@ -0,0 +1,120 @@
+package alerts
+
+import (
+	"github.com/boltdb/bolt"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/blizzard"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/util"
+	"github.com/twinj/uuid"
+)
+
+func NewDatabase(dirPath string) (Database, error) {
+	if err := util.EnsureDirsExist([]string{databaseDirPath(dirPath)}); err != nil {
+		return Database{}, err
+	}
+
+	db, err := bolt.Open(databasePath(dirPath), 0600, nil)
+	if err != nil {
+		return Database{}, err
+	}
+
+	return Database{db}, nil
+}
+
+type Database struct {
+	db *bolt.DB
+}
+
+// Persist stores the alert, assigning it an id when it has none
+func (aBase Database) Persist(a Alert) (Alert, error) {
+	if len(a.ID) == 0 {
+		a.ID = uuid.NewV4().String()
+	}
+
+	encoded, err := a.EncodeForStorage()
+	if err != nil {
+		return Alert{}, err
+	}
+
+	err = aBase.db.Update(func(tx *bolt.Tx) error {
+		bkt, err := tx.CreateBucketIfNotExists(alertsBucketName())
+		if err != nil {
+			return err
+		}
+
+		return bkt.Put(alertKeyName(a.ID), encoded)
+	})
+	if err != nil {
+		return Alert{}, err
+	}
+
+	return a, nil
+}
+
+// Delete removes the alert and reports whether it existed
+func (aBase Database) Delete(ID string) (bool, error) {
+	found := false
+
+	err := aBase.db.Update(func(tx *bolt.Tx) error {
+		bkt := tx.Bucket(alertsBucketName())
+		if bkt == nil {
+			return nil
+		}
+
+		if bkt.Get(alertKeyName(ID)) == nil {
+			return nil
+		}
+		found = true
+
+		return bkt.Delete(alertKeyName(ID))
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return found, nil
+}
+
+func (aBase Database) GetAlerts() (Alerts, error) {
+	out := Alerts{}
+
+	err := aBase.db.View(func(tx *bolt.Tx) error {
+		bkt := tx.Bucket(alertsBucketName())
+		if bkt == nil {
+			return nil
+		}
+
+		return bkt.ForEach(func(k, v []byte) error {
+			a, err := NewAlert(v)
+			if err != nil {
+				return err
+			}
+
+			out = append(out, a)
+
+			return nil
+		})
+	})
+	if err != nil {
+		return Alerts{}, err
+	}
+
+	return out, nil
+}
+
+func (aBase Database) GetRealmAlerts(regionName blizzard.RegionName, realmSlug blizzard.RealmSlug) (Alerts, error) {
+	as, err := aBase.GetAlerts()
+	if err != nil {
+		return Alerts{}, err
+	}
+
+	out := Alerts{}
+	for _, a := range as {
+		if a.RegionName != regionName || a.RealmSlug != realmSlug {
+			continue
+		}
+
+		out = append(out, a)
+	}
+
+	return out, nil
+}
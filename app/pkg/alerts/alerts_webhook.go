@@ -0,0 +1,146 @@
+package alerts
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// webhookClient refuses to connect to internal addresses, checked on every dial so that neither a hostname
+// resolving to one nor a redirect can reach them
+var webhookClient = http.Client{
+	Timeout: 10 * time.Second,
+	Transport: &http.Transport{
+		Proxy: nil,
+		DialContext: (&net.Dialer{
+			Timeout: 5 * time.Second,
+			Control: webhookDialControl,
+		}).DialContext,
+	},
+	CheckRedirect: func(req *http.Request, via []*http.Request) error {
+		if len(via) >= 3 {
+			return errors.New("webhook redirected too many times")
+		}
+
+		return ValidateWebhookURL(req.URL.String())
+	},
+}
+
+func webhookDialControl(network string, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil || !isPublicIP(ip) {
+		return fmt.Errorf("webhook address is not public: %s", address)
+	}
+
+	return nil
+}
+
+func isPublicIP(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsUnspecified() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() {
+		return false
+	}
+
+	for _, block := range privateBlocks {
+		if block.Contains(ip) {
+			return false
+		}
+	}
+
+	return true
+}
+
+var privateBlocks = func() []*net.IPNet {
+	out := []*net.IPNet{}
+	for _, cidr := range []string{
+		"0.0.0.0/8",
+		"10.0.0.0/8",
+		"100.64.0.0/10",
+		"172.16.0.0/12",
+		"192.168.0.0/16",
+		"198.18.0.0/15",
+		"fc00::/7",
+	} {
+		_, block, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+
+		out = append(out, block)
+	}
+
+	return out
+}()
+
+// ValidateWebhookURL accepts https urls on a public host and the default port
+func ValidateWebhookURL(webhookURL string) error {
+	u, err := url.Parse(webhookURL)
+	if err != nil {
+		return err
+	}
+
+	if u.Scheme != "https" {
+		return errors.New("webhook url must use https")
+	}
+
+	if u.User != nil {
+		return errors.New("webhook url cannot hold credentials")
+	}
+
+	if len(u.Port()) > 0 && u.Port() != "443" {
+		return errors.New("webhook url cannot use a custom port")
+	}
+
+	host := strings.ToLower(u.Hostname())
+	if len(host) == 0 {
+		return errors.New("webhook url host cannot be blank")
+	}
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") || strings.HasSuffix(host, ".internal") {
+		return fmt.Errorf("webhook url host is not public: %s", host)
+	}
+	if ip := net.ParseIP(host); ip != nil && !isPublicIP(ip) {
+		return fmt.Errorf("webhook url host is not public: %s", host)
+	}
+
+	return nil
+}
+
+// PostWebhook delivers the match to the alert's webhook url, when it has one
+func PostWebhook(m Match) error {
+	if len(m.Alert.WebhookURL) == 0 {
+		return nil
+	}
+
+	// alerts persisted before urls were validated are checked again
+	if err := ValidateWebhookURL(m.Alert.WebhookURL); err != nil {
+		return err
+	}
+
+	body, err := m.EncodeForDelivery()
+	if err != nil {
+		return err
+	}
+
+	resp, err := webhookClient.Post(m.Alert.WebhookURL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+
+	return nil
+}
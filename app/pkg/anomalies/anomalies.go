@@ -0,0 +1,349 @@
+package anomalies
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/marketshare"
+	"github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/pricelists"
+	"github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/sales"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/blizzard"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/sotah"
+)
+
+// keying
+func baselineKeyName(ID blizzard.ItemID) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, uint64(ID))
+
+	return key
+}
+
+func eventsKeyName(targetTime time.Time) []byte {
+	// big-endian so that keys iterate in snapshot order
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, uint64(targetTime.Unix()))
+
+	return key
+}
+
+func eventsKeyTime(key []byte) time.Time {
+	return time.Unix(int64(binary.BigEndian.Uint64(key)), 0)
+}
+
+func lastSnapshotKeyName() []byte {
+	return []byte("last-snapshot")
+}
+
+// bucketing
+func baselinesBucketName() []byte {
+	return []byte("item-baselines")
+}
+
+func eventsBucketName() []byte {
+	return []byte("events")
+}
+
+func metaBucketName() []byte {
+	return []byte("meta")
+}
+
+// db
+func databaseDirPath(dirPath string) string {
+	return fmt.Sprintf("%s/anomalies", dirPath)
+}
+
+/*
+thresholds for raising events
+*/
+const (
+	// snapshots an item needs before its prices are compared against its baseline
+	minimumBaselineSamples = 6
+
+	// weight of the newest snapshot in the rolling baseline
+	baselineWeight = 0.1
+
+	spikeRatio    = 2.0
+	spikeZScore   = 3.0
+	floorRatio    = 0.5
+	buyoutShare   = 0.9
+	dominantShare = 0.5
+
+	// share a seller may already have held for it to count as new
+	incumbentShare = 0.1
+
+	// units an item needs listed for buyouts and sellers to be considered a market
+	minimumMarketVolume = 10
+)
+
+// Kind - typehint for these enums
+type Kind string
+
+/*
+Kinds - the anomalies detected between snapshots
+*/
+const (
+	PriceSpike     Kind = "price-spike"
+	FloorReset     Kind = "floor-reset"
+	MarketBuyout   Kind = "market-buyout"
+	DominantSeller Kind = "dominant-seller"
+)
+
+func (k Kind) Validate() error {
+	switch k {
+	case PriceSpike, FloorReset, MarketBuyout, DominantSeller:
+		return nil
+	default:
+		return fmt.Errorf("invalid kind: %s", k)
+	}
+}
+
+// Event is an anomaly of an item, where severity ranges from zero to one
+type Event struct {
+	ItemID     blizzard.ItemID `json:"item_id"`
+	Kind       Kind            `json:"kind"`
+	TargetTime int64           `json:"target_time"`
+	Severity   float64         `json:"severity"`
+
+	// what the baseline or previous snapshot led to expect against what was seen
+	Expected float64 `json:"expected"`
+	Observed float64 `json:"observed"`
+
+	// the owner behind the event and their realm, where one is known
+	Owner      sotah.OwnerName `json:"owner,omitempty"`
+	OwnerRealm string          `json:"owner_realm,omitempty"`
+}
+
+type Events []Event
+
+func newEventsFromBytes(data []byte) (Events, error) {
+	out := Events{}
+	if err := json.Unmarshal(data, &out); err != nil {
+		return Events{}, err
+	}
+
+	return out, nil
+}
+
+func (events Events) EncodeForStorage() ([]byte, error) {
+	return json.Marshal(events)
+}
+
+// Sort orders events by severity and then by item
+func (events Events) Sort() {
+	sort.Slice(events, func(i, j int) bool {
+		if events[i].Severity != events[j].Severity {
+			return events[i].Severity > events[j].Severity
+		}
+
+		if events[i].ItemID != events[j].ItemID {
+			return events[i].ItemID < events[j].ItemID
+		}
+
+		return events[i].Kind < events[j].Kind
+	})
+}
+
+func newBaselineFromBytes(data []byte) (Baseline, error) {
+	b := &Baseline{}
+	if err := json.Unmarshal(data, &b); err != nil {
+		return Baseline{}, err
+	}
+
+	return *b, nil
+}
+
+// Baseline is an item's recent prices as exponentially weighted averages over its snapshots
+type Baseline struct {
+	// snapshots with buyouts folded into the prices
+	Samples                 int     `json:"samples"`
+	MedianBuyoutPer         float64 `json:"median_buyout_per"`
+	MedianBuyoutPerVariance float64 `json:"median_buyout_per_variance"`
+	MinBuyoutPer            float64 `json:"min_buyout_per"`
+	Volume                  float64 `json:"volume"`
+
+	// when the item was last listed, so that baselines of items no longer listed can be dropped
+	LastSeen int64 `json:"last_seen"`
+}
+
+func (b Baseline) EncodeForStorage() ([]byte, error) {
+	return json.Marshal(b)
+}
+
+func (b Baseline) isEstablished() bool {
+	return b.Samples >= minimumBaselineSamples
+}
+
+// Update folds a snapshot's prices into the baseline
+func (b Baseline) Update(targetTime time.Time, p pricelists.Prices) Baseline {
+	out := b
+	out.LastSeen = targetTime.Unix()
+
+	if b.LastSeen == 0 {
+		out.Volume = float64(p.Volume)
+	} else {
+		out.Volume = b.Volume + baselineWeight*(float64(p.Volume)-b.Volume)
+	}
+
+	// bid-only snapshots have no buyout prices, and folding in zeroes would drag the baseline towards nothing
+	if p.MedianBuyoutPer == 0 || p.MinBuyoutPer == 0 {
+		return out
+	}
+
+	out.Samples++
+	if b.Samples == 0 {
+		out.MedianBuyoutPer = p.MedianBuyoutPer
+		out.MedianBuyoutPerVariance = 0
+		out.MinBuyoutPer = p.MinBuyoutPer
+
+		return out
+	}
+
+	delta := p.MedianBuyoutPer - b.MedianBuyoutPer
+	out.MedianBuyoutPer = b.MedianBuyoutPer + baselineWeight*delta
+	out.MedianBuyoutPerVariance = (1 - baselineWeight) * (b.MedianBuyoutPerVariance + baselineWeight*delta*delta)
+	out.MinBuyoutPer = b.MinBuyoutPer + baselineWeight*(p.MinBuyoutPer-b.MinBuyoutPer)
+
+	return out
+}
+
+type Baselines map[blizzard.ItemID]Baseline
+
+// Snapshot carries everything compared when a realm's auctions are replaced
+type Snapshot struct {
+	TargetTime time.Time
+	Previous   sotah.MiniAuctionList
+	Current    sotah.MiniAuctionList
+
+	// auctions removed since the previous snapshot, as classified by the sales tallies
+	Removed sales.ItemTallies
+}
+
+func clampSeverity(severity float64) float64 {
+	return math.Max(0, math.Min(1, severity))
+}
+
+// Detect compares the snapshot against the item baselines and the previous snapshot
+func (s Snapshot) Detect(baselines Baselines) Events {
+	targetTimestamp := s.TargetTime.Unix()
+	out := Events{}
+
+	// price anomalies against the rolling baselines
+	currentPrices := pricelists.NewItemPrices(s.Current)
+	currentReports := marketshare.NewItemReports(s.TargetTime, s.Current)
+	for itemId, p := range currentPrices {
+		b := baselines[itemId]
+		if !b.isEstablished() {
+			continue
+		}
+
+		if p.MedianBuyoutPer > 0 && b.MedianBuyoutPer > 0 && p.MedianBuyoutPer >= b.MedianBuyoutPer*spikeRatio {
+			stdDev := math.Sqrt(b.MedianBuyoutPerVariance)
+			if stdDev == 0 || (p.MedianBuyoutPer-b.MedianBuyoutPer)/stdDev >= spikeZScore {
+				out = append(out, Event{
+					ItemID:     itemId,
+					Kind:       PriceSpike,
+					TargetTime: targetTimestamp,
+					Severity:   clampSeverity(math.Log2(p.MedianBuyoutPer/b.MedianBuyoutPer) / 4),
+					Expected:   b.MedianBuyoutPer,
+					Observed:   p.MedianBuyoutPer,
+				})
+			}
+		}
+
+		if p.MinBuyoutPer > 0 && b.MinBuyoutPer > 0 && p.MinBuyoutPer <= b.MinBuyoutPer*floorRatio {
+			out = append(out, Event{
+				ItemID:     itemId,
+				Kind:       FloorReset,
+				TargetTime: targetTimestamp,
+				Severity:   clampSeverity(1 - p.MinBuyoutPer/b.MinBuyoutPer),
+				Expected:   b.MinBuyoutPer,
+				Observed:   p.MinBuyoutPer,
+				Owner:      currentReports[itemId].LowestOwner,
+				OwnerRealm: currentReports[itemId].LowestOwnerRealm,
+			})
+		}
+	}
+
+	// market anomalies against the previous snapshot, where shares are taken over every previous seller
+	previousReports := marketshare.NewItemReports(s.TargetTime, s.Previous)
+	previousShares := marketshare.NewItemSellerShares(s.Previous)
+	for itemId, previous := range previousReports {
+		if previous.TotalVolume < minimumMarketVolume {
+			continue
+		}
+
+		soldVolume := s.Removed[itemId].SoldVolume
+		if float64(soldVolume) >= float64(previous.TotalVolume)*buyoutShare {
+			out = append(out, Event{
+				ItemID:     itemId,
+				Kind:       MarketBuyout,
+				TargetTime: targetTimestamp,
+				Severity: clampSeverity(
+					float64(soldVolume) / float64(previous.TotalVolume) * math.Log10(float64(previous.TotalVolume)) / 3,
+				),
+				Expected: float64(previous.TotalVolume),
+				Observed: float64(soldVolume),
+			})
+		}
+	}
+
+	// seller anomalies, where a snapshot without owners has no sellers to compare
+	hasOwners := marketshare.HasOwners(s.Current)
+	for itemId, current := range currentReports {
+		if !hasOwners || current.TotalVolume < minimumMarketVolume || len(current.TopSellers) == 0 {
+			continue
+		}
+
+		top := current.TopSellers[0]
+		if top.VolumeShare < dominantShare {
+			continue
+		}
+
+		previousShare := previousShares[itemId][top.Key()].VolumeShare
+		if previousShare >= incumbentShare {
+			continue
+		}
+
+		out = append(out, Event{
+			ItemID:     itemId,
+			Kind:       DominantSeller,
+			TargetTime: targetTimestamp,
+			Severity:   clampSeverity(top.VolumeShare - previousShare),
+			Expected:   previousShare,
+			Observed:   top.VolumeShare,
+			Owner:      top.Owner,
+			OwnerRealm: top.OwnerRealm,
+		})
+	}
+
+	out.Sort()
+
+	return out
+}
+
+// UpdateBaselines folds the snapshot's prices into the baselines of the items it lists
+func (s Snapshot) UpdateBaselines(baselines Baselines) Baselines {
+	out := Baselines{}
+	for itemId, p := range pricelists.NewItemPrices(s.Current) {
+		out[itemId] = baselines[itemId].Update(s.TargetTime, p)
+	}
+
+	return out
+}
+
+// Report is the delivery of a realm's events for a single snapshot
+type Report struct {
+	RegionName blizzard.RegionName `json:"region_name"`
+	RealmSlug  blizzard.RealmSlug  `json:"realm_slug"`
+	TargetTime int64               `json:"target_time"`
+	Events     Events              `json:"events"`
+}
+
+func (r Report) EncodeForDelivery() ([]byte, error) {
+	return json.Marshal(r)
+}
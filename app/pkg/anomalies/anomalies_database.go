@@ -0,0 +1,220 @@
+package anomalies
+
+import (
+	"time"
+
+	"github.com/boltdb/bolt"
+	"github.com/sirupsen/logrus"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/blizzard"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/logging"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/sotah"
+)
+
+type Database struct {
+	db    *bolt.DB
+	realm sotah.Realm
+}
+
+// Persist detects the snapshot's events against the stored baselines, folds the snapshot into them, and drops events
+// and baselines beyond the retention limit
+func (aBase Database) Persist(s Snapshot, retentionLimit time.Time) (Events, error) {
+	logging.WithFields(logrus.Fields{
+		"db":       aBase.db.Path(),
+		"auctions": len(s.Current),
+	}).Debug("Detecting anomalies")
+
+	out := Events{}
+	err := aBase.db.Update(func(tx *bolt.Tx) error {
+		metaBucket, err := tx.CreateBucketIfNotExists(metaBucketName())
+		if err != nil {
+			return err
+		}
+
+		// an out-of-order snapshot would be compared against newer baselines
+		if value := metaBucket.Get(lastSnapshotKeyName()); value != nil && !s.TargetTime.After(eventsKeyTime(value)) {
+			return nil
+		}
+
+		baselinesBucket, err := tx.CreateBucketIfNotExists(baselinesBucketName())
+		if err != nil {
+			return err
+		}
+
+		// gathering baselines of every item in either snapshot
+		baselines := Baselines{}
+		for _, itemIds := range [][]blizzard.ItemID{s.Previous.ItemIds(), s.Current.ItemIds()} {
+			for _, itemId := range itemIds {
+				value := baselinesBucket.Get(baselineKeyName(itemId))
+				if value == nil {
+					continue
+				}
+
+				b, err := newBaselineFromBytes(value)
+				if err != nil {
+					return err
+				}
+
+				baselines[itemId] = b
+			}
+		}
+
+		out = s.Detect(baselines)
+		if len(out) > 0 {
+			eventsBucket, err := tx.CreateBucketIfNotExists(eventsBucketName())
+			if err != nil {
+				return err
+			}
+
+			encoded, err := out.EncodeForStorage()
+			if err != nil {
+				return err
+			}
+
+			if err := eventsBucket.Put(eventsKeyName(s.TargetTime), encoded); err != nil {
+				return err
+			}
+		}
+
+		for itemId, b := range s.UpdateBaselines(baselines) {
+			encoded, err := b.EncodeForStorage()
+			if err != nil {
+				return err
+			}
+
+			if err := baselinesBucket.Put(baselineKeyName(itemId), encoded); err != nil {
+				return err
+			}
+		}
+
+		if err := metaBucket.Put(lastSnapshotKeyName(), eventsKeyName(s.TargetTime)); err != nil {
+			return err
+		}
+
+		return aBase.prune(tx, retentionLimit)
+	})
+	if err != nil {
+		return Events{}, err
+	}
+
+	return out, nil
+}
+
+func (aBase Database) prune(tx *bolt.Tx, retentionLimit time.Time) error {
+	if eventsBucket := tx.Bucket(eventsBucketName()); eventsBucket != nil {
+		c := eventsBucket.Cursor()
+		for k, _ := c.First(); k != nil && eventsKeyTime(k).Before(retentionLimit); k, _ = c.Next() {
+			if err := c.Delete(); err != nil {
+				return err
+			}
+		}
+	}
+
+	baselinesBucket := tx.Bucket(baselinesBucketName())
+	if baselinesBucket == nil {
+		return nil
+	}
+
+	expiredKeys := [][]byte{}
+	err := baselinesBucket.ForEach(func(k, v []byte) error {
+		b, err := newBaselineFromBytes(v)
+		if err != nil {
+			return err
+		}
+
+		if time.Unix(b.LastSeen, 0).Before(retentionLimit) {
+			expiredKeys = append(expiredKeys, append([]byte{}, k...))
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, k := range expiredKeys {
+		if err := baselinesBucket.Delete(k); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Query narrows the events read back, where blank fields match everything
+type Query struct {
+	LowerBound  time.Time
+	ItemIds     []blizzard.ItemID
+	Kinds       []Kind
+	MinSeverity float64
+}
+
+func (q Query) matches(e Event) bool {
+	if e.Severity < q.MinSeverity {
+		return false
+	}
+
+	if len(q.Kinds) > 0 {
+		found := false
+		for _, k := range q.Kinds {
+			if e.Kind == k {
+				found = true
+
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if len(q.ItemIds) > 0 {
+		found := false
+		for _, itemId := range q.ItemIds {
+			if e.ItemID == itemId {
+				found = true
+
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
+
+// GetEvents gathers the events since the lower bound that match the query, newest first
+func (aBase Database) GetEvents(q Query) (Events, error) {
+	out := Events{}
+
+	err := aBase.db.View(func(tx *bolt.Tx) error {
+		eventsBucket := tx.Bucket(eventsBucketName())
+		if eventsBucket == nil {
+			return nil
+		}
+
+		c := eventsBucket.Cursor()
+		for k, v := c.Last(); k != nil && !eventsKeyTime(k).Before(q.LowerBound); k, v = c.Prev() {
+			snapshotEvents, err := newEventsFromBytes(v)
+			if err != nil {
+				return err
+			}
+
+			for _, e := range snapshotEvents {
+				if !q.matches(e) {
+					continue
+				}
+
+				out = append(out, e)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return Events{}, err
+	}
+
+	return out, nil
+}
@@ -0,0 +1,39 @@
+package anomalies
+
+import (
+	"time"
+
+	"github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/connectedrealms"
+	"github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/realmdb"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/blizzard"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/sotah"
+)
+
+func NewDatabases(dirPath string, stas sotah.Statuses, groups connectedrealms.Groups) (Databases, error) {
+	registry, err := realmdb.Open(databaseDirPath(dirPath), stas, groups)
+	if err != nil {
+		return Databases{}, err
+	}
+
+	aBases := Databases{}
+	for regionName, handles := range registry {
+		aBases[regionName] = map[blizzard.RealmSlug]Database{}
+		for realmSlug, handle := range handles {
+			aBases[regionName][realmSlug] = Database{handle.DB, handle.Realm}
+		}
+	}
+
+	return aBases, nil
+}
+
+type Databases map[blizzard.RegionName]map[blizzard.RealmSlug]Database
+
+// Record detects and persists the anomalies of a realm's snapshot
+func (aBases Databases) Record(rea sotah.Realm, s Snapshot, retentionLimit time.Time) (Events, error) {
+	aBase, ok := aBases[rea.Region.Name][rea.Slug]
+	if !ok {
+		return Events{}, nil
+	}
+
+	return aBase.Persist(s, retentionLimit)
+}
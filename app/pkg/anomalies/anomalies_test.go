@@ -0,0 +1,202 @@
+package anomalies
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/pricelists"
+	"github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/realmdb"
+	"github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/sales"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/blizzard"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/sotah"
+)
+
+func newTestDatabase(t *testing.T) (Database, func()) {
+	handle, cleanup := realmdb.OpenTemp(t, "anomalies")
+
+	return Database{handle.DB, handle.Realm}, cleanup
+}
+
+func newTestList(itemId blizzard.ItemID, owner sotah.OwnerName, buyoutPer int64, quantity int64) sotah.MiniAuctionList {
+	return sotah.MiniAuctionList{{
+		ItemID:    itemId,
+		Owner:     owner,
+		Buyout:    buyoutPer * quantity,
+		BuyoutPer: float32(buyoutPer),
+		Quantity:  quantity,
+		AucList:   []int64{1},
+	}}
+}
+
+func newEstablishedBaseline(medianBuyoutPer float64, minBuyoutPer float64) Baseline {
+	return Baseline{
+		Samples:                 minimumBaselineSamples,
+		MedianBuyoutPer:         medianBuyoutPer,
+		MedianBuyoutPerVariance: 1,
+		MinBuyoutPer:            minBuyoutPer,
+		Volume:                  10,
+		LastSeen:                1,
+	}
+}
+
+func findEvent(events Events, kind Kind) (Event, bool) {
+	for _, e := range events {
+		if e.Kind == kind {
+			return e, true
+		}
+	}
+
+	return Event{}, false
+}
+
+func TestBaselineUpdateSeedsAndWeighs(t *testing.T) {
+	b := Baseline{}.Update(time.Unix(100, 0), pricelists.Prices{MedianBuyoutPer: 100, MinBuyoutPer: 50, Volume: 10})
+	if b.Samples != 1 || b.MedianBuyoutPer != 100 || b.MinBuyoutPer != 50 || b.Volume != 10 || b.LastSeen != 100 {
+		t.Fatalf("expected the first snapshot to seed the baseline, got %+v", b)
+	}
+
+	b = b.Update(time.Unix(200, 0), pricelists.Prices{MedianBuyoutPer: 200, MinBuyoutPer: 150, Volume: 20})
+	if b.Samples != 2 || b.MedianBuyoutPer != 110 || b.MinBuyoutPer != 60 || b.Volume != 11 {
+		t.Fatalf("expected the second snapshot to be weighed in, got %+v", b)
+	}
+	if b.MedianBuyoutPerVariance != 900 {
+		t.Fatalf("unexpected variance: %f", b.MedianBuyoutPerVariance)
+	}
+}
+
+func TestBaselineUpdateSkipsZeroPrices(t *testing.T) {
+	b := Baseline{}.Update(time.Unix(100, 0), pricelists.Prices{Volume: 4})
+	if b.Samples != 0 || b.MedianBuyoutPer != 0 || b.Volume != 4 || b.LastSeen != 100 {
+		t.Fatalf("expected a bid-only snapshot to seed only the volume, got %+v", b)
+	}
+
+	b = b.Update(time.Unix(200, 0), pricelists.Prices{MedianBuyoutPer: 100, MinBuyoutPer: 50, Volume: 14})
+	if b.Samples != 1 || b.MedianBuyoutPer != 100 || b.MinBuyoutPer != 50 || b.Volume != 5 {
+		t.Fatalf("expected the first priced snapshot to seed the prices, got %+v", b)
+	}
+
+	b = b.Update(time.Unix(300, 0), pricelists.Prices{Volume: 5})
+	if b.Samples != 1 || b.MedianBuyoutPer != 100 || b.MinBuyoutPer != 50 || b.LastSeen != 300 {
+		t.Fatalf("expected a bid-only snapshot to leave the prices alone, got %+v", b)
+	}
+}
+
+func TestDetectPriceAnomalies(t *testing.T) {
+	s := Snapshot{
+		TargetTime: time.Unix(100, 0),
+		Current:    append(newTestList(1, "a", 300, 1), newTestList(2, "b", 20, 1)...),
+	}
+	baselines := Baselines{
+		1: newEstablishedBaseline(100, 100),
+		2: newEstablishedBaseline(50, 50),
+	}
+
+	events := s.Detect(baselines)
+	if e, ok := findEvent(events, PriceSpike); !ok || e.ItemID != 1 || e.Observed != 300 {
+		t.Fatalf("expected a price spike on item 1, got %+v", events)
+	}
+	if e, ok := findEvent(events, FloorReset); !ok || e.ItemID != 2 || e.Owner != "b" {
+		t.Fatalf("expected a floor reset on item 2 by b, got %+v", events)
+	}
+
+	// baselines without enough samples are not compared
+	baselines[1] = Baseline{Samples: 1, MedianBuyoutPer: 100, MinBuyoutPer: 100}
+	baselines[2] = Baseline{Samples: 1, MedianBuyoutPer: 50, MinBuyoutPer: 50}
+	if events := s.Detect(baselines); len(events) != 0 {
+		t.Fatalf("expected no events against unestablished baselines, got %+v", events)
+	}
+}
+
+func TestDetectMarketBuyout(t *testing.T) {
+	s := Snapshot{
+		TargetTime: time.Unix(100, 0),
+		Previous:   newTestList(1, "a", 10, 20),
+		Current:    sotah.MiniAuctionList{},
+		Removed:    sales.ItemTallies{1: {SoldAuctions: 1, SoldVolume: 19}},
+	}
+
+	if e, ok := findEvent(s.Detect(Baselines{}), MarketBuyout); !ok || e.Observed != 19 || e.Expected != 20 {
+		t.Fatalf("expected a market buyout, got %+v", e)
+	}
+}
+
+func TestDetectDominantSellerAgainstEverySeller(t *testing.T) {
+	// g held 15 percent of the item, while ranking below five larger sellers
+	previous := sotah.MiniAuctionList{}
+	for _, owner := range []sotah.OwnerName{"a", "b", "c", "d", "e"} {
+		previous = append(previous, newTestList(1, owner, 10, 17)...)
+	}
+	previous = append(previous, newTestList(1, "g", 10, 15)...)
+
+	current := append(newTestList(1, "g", 10, 60), newTestList(1, "a", 10, 40)...)
+	s := Snapshot{TargetTime: time.Unix(100, 0), Previous: previous, Current: current}
+	if e, ok := findEvent(s.Detect(Baselines{}), DominantSeller); ok {
+		t.Fatalf("expected an incumbent seller to not be flagged, got %+v", e)
+	}
+
+	// a seller absent from the previous snapshot is flagged
+	current = append(newTestList(1, "z", 10, 60), newTestList(1, "a", 10, 40)...)
+	s = Snapshot{TargetTime: time.Unix(100, 0), Previous: previous, Current: current}
+	e, ok := findEvent(s.Detect(Baselines{}), DominantSeller)
+	if !ok || e.Owner != "z" || e.Expected != 0 || e.Observed != 0.6 {
+		t.Fatalf("expected z to be flagged as a new dominant seller, got %+v", e)
+	}
+}
+
+func TestDatabasePersistBuildsBaselines(t *testing.T) {
+	aBase, cleanup := newTestDatabase(t)
+	defer cleanup()
+
+	targetTime := time.Unix(1000, 0)
+	for i := 0; i < minimumBaselineSamples; i++ {
+		s := Snapshot{TargetTime: targetTime, Current: newTestList(1, "a", 100, 1)}
+		if events, err := aBase.Persist(s, time.Unix(0, 0)); err != nil || len(events) != 0 {
+			t.Fatalf("expected no events while the baseline is built, got %+v %v", events, err)
+		}
+
+		targetTime = targetTime.Add(time.Hour)
+	}
+
+	// out-of-order snapshots are skipped
+	stale := Snapshot{TargetTime: time.Unix(1000, 0), Current: newTestList(1, "a", 1000, 1)}
+	if events, err := aBase.Persist(stale, time.Unix(0, 0)); err != nil || len(events) != 0 {
+		t.Fatalf("expected an out-of-order snapshot to be skipped, got %+v %v", events, err)
+	}
+
+	s := Snapshot{TargetTime: targetTime, Current: newTestList(1, "a", 1000, 1)}
+	events, err := aBase.Persist(s, time.Unix(0, 0))
+	if err != nil {
+		t.Fatalf("could not persist: %s", err)
+	}
+	if _, ok := findEvent(events, PriceSpike); !ok {
+		t.Fatalf("expected a price spike once the baseline is established, got %+v", events)
+	}
+
+	stored, err := aBase.GetEvents(Query{Kinds: []Kind{PriceSpike}, ItemIds: []blizzard.ItemID{1}})
+	if err != nil {
+		t.Fatalf("could not get events: %s", err)
+	}
+	if len(stored) != 1 || stored[0].TargetTime != targetTime.Unix() {
+		t.Fatalf("expected the spike to be stored, got %+v", stored)
+	}
+
+	if stored, err := aBase.GetEvents(Query{MinSeverity: 1.1}); err != nil || len(stored) != 0 {
+		t.Fatalf("expected no events above the maximum severity, got %+v %v", stored, err)
+	}
+}
+
+func TestDetectWithoutOwners(t *testing.T) {
+	previous := append(newTestList(1, "", 10, 50), newTestList(1, "", 10, 50)...)
+	current := newTestList(1, "", 10, 100)
+	current = append(current, newTestList(2, "", 1000, 1)...)
+	baselines := Baselines{2: newEstablishedBaseline(100, 100)}
+
+	s := Snapshot{TargetTime: time.Unix(100, 0), Previous: previous, Current: current}
+	events := s.Detect(baselines)
+	if e, ok := findEvent(events, DominantSeller); ok {
+		t.Fatalf("expected the blank owner to not be flagged as a dominant seller, got %+v", e)
+	}
+	if _, ok := findEvent(events, PriceSpike); !ok {
+		t.Fatalf("expected price anomalies to be detected without owners, got %+v", events)
+	}
+}
@@ -0,0 +1,347 @@
+package auctions
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/pricelists"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/blizzard"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/sotah"
+)
+
+// time-left buckets as reported by the auction house
+var timeLefts = map[string]struct{}{
+	"SHORT":     {},
+	"MEDIUM":    {},
+	"LONG":      {},
+	"VERY_LONG": {},
+}
+
+// BuyoutKind - typehint for these enums
+type BuyoutKind string
+
+/*
+BuyoutKinds - whether an auction may be bought out
+*/
+const (
+	BidOnly    BuyoutKind = "bid-only"
+	WithBuyout BuyoutKind = "buyout"
+)
+
+// Range is an inclusive range where either bound may be left open
+type Range struct {
+	Min *float64 `json:"min"`
+	Max *float64 `json:"max"`
+}
+
+func (r Range) validate(name string) error {
+	if r.Min != nil && r.Max != nil && *r.Min > *r.Max {
+		return fmt.Errorf("%s min cannot exceed max", name)
+	}
+
+	return nil
+}
+
+func (r Range) contains(value float64) bool {
+	if r.Min != nil && value < *r.Min {
+		return false
+	}
+	if r.Max != nil && value > *r.Max {
+		return false
+	}
+
+	return true
+}
+
+// Filter is an expression over auctions, where every condition set on a filter must hold
+type Filter struct {
+	// composition
+	All []Filter `json:"all"`
+	Any []Filter `json:"any"`
+	Not *Filter  `json:"not"`
+
+	// conditions
+	BuyoutPer  *Range     `json:"buyout_per"`
+	Quantity   *Range     `json:"quantity"`
+	TimeLeft   []string   `json:"time_left"`
+	BuyoutKind BuyoutKind `json:"buyout_kind"`
+
+	// joined from the items database
+	ItemClasses []blizzard.ItemClassClass `json:"item_classes"`
+	Qualities   []int                     `json:"qualities"`
+
+	// percentage below the item's current median buyout-per, where negative values are above it
+	UndercutPercent *Range `json:"undercut_percent"`
+}
+
+func (f Filter) Validate() error {
+	for _, child := range f.All {
+		if err := child.Validate(); err != nil {
+			return err
+		}
+	}
+	for _, child := range f.Any {
+		if err := child.Validate(); err != nil {
+			return err
+		}
+	}
+	if f.Not != nil {
+		if err := f.Not.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if f.BuyoutPer != nil {
+		if err := f.BuyoutPer.validate("buyout-per"); err != nil {
+			return err
+		}
+	}
+	if f.Quantity != nil {
+		if err := f.Quantity.validate("quantity"); err != nil {
+			return err
+		}
+	}
+	if f.UndercutPercent != nil {
+		if err := f.UndercutPercent.validate("undercut-percent"); err != nil {
+			return err
+		}
+	}
+
+	for _, timeLeft := range f.TimeLeft {
+		if _, ok := timeLefts[timeLeft]; !ok {
+			return fmt.Errorf("invalid time-left: %s", timeLeft)
+		}
+	}
+
+	switch f.BuyoutKind {
+	case "", BidOnly, WithBuyout:
+	default:
+		return errors.New("invalid buyout kind")
+	}
+
+	return nil
+}
+
+// NeedsItems reports whether any part of the expression filters on item details
+func (f Filter) NeedsItems() bool {
+	if len(f.ItemClasses) > 0 || len(f.Qualities) > 0 {
+		return true
+	}
+
+	for _, child := range f.All {
+		if child.NeedsItems() {
+			return true
+		}
+	}
+	for _, child := range f.Any {
+		if child.NeedsItems() {
+			return true
+		}
+	}
+
+	return f.Not != nil && f.Not.NeedsItems()
+}
+
+// Context holds what an expression joins against
+type Context struct {
+	Items  sotah.ItemsMap
+	Prices pricelists.ItemPrices
+}
+
+func NewContext(maList sotah.MiniAuctionList, iMap sotah.ItemsMap) Context {
+	return Context{Items: iMap, Prices: pricelists.NewItemPrices(maList)}
+}
+
+// listing carries the fields of a mini-auction that an expression may inspect
+type listing struct {
+	itemId    blizzard.ItemID
+	buyout    int64
+	buyoutPer float64
+	quantity  int64
+	timeLeft  string
+}
+
+func newListing(maList sotah.MiniAuctionList, i int) listing {
+	mAuction := maList[i]
+
+	return listing{
+		itemId:    mAuction.ItemID,
+		buyout:    mAuction.Buyout,
+		buyoutPer: float64(mAuction.BuyoutPer),
+		quantity:  mAuction.Quantity,
+		timeLeft:  mAuction.TimeLeft,
+	}
+}
+
+// Apply keeps the auctions matching the expression
+func (f Filter) Apply(maList sotah.MiniAuctionList, ctx Context) sotah.MiniAuctionList {
+	out := sotah.MiniAuctionList{}
+	for i, mAuction := range maList {
+		if !f.matches(newListing(maList, i), ctx) {
+			continue
+		}
+
+		out = append(out, mAuction)
+	}
+
+	return out
+}
+
+// Candidates keeps the auctions that may match once item details are joined, so only their items need fetching
+func (f Filter) Candidates(maList sotah.MiniAuctionList, ctx Context) sotah.MiniAuctionList {
+	out := sotah.MiniAuctionList{}
+	for i, mAuction := range maList {
+		if f.evaluate(newListing(maList, i), ctx, false) == isFalse {
+			continue
+		}
+
+		out = append(out, mAuction)
+	}
+
+	return out
+}
+
+// truth is the outcome of an expression where item conditions may be left undecided
+type truth int
+
+const (
+	isFalse truth = iota
+	isUnknown
+	isTrue
+)
+
+func (t truth) and(other truth) truth {
+	if t < other {
+		return t
+	}
+
+	return other
+}
+
+func (t truth) or(other truth) truth {
+	if t > other {
+		return t
+	}
+
+	return other
+}
+
+func (t truth) not() truth {
+	return isTrue - t
+}
+
+func fromBool(value bool) truth {
+	if value {
+		return isTrue
+	}
+
+	return isFalse
+}
+
+func (f Filter) matches(l listing, ctx Context) bool {
+	return f.evaluate(l, ctx, true) == isTrue
+}
+
+// evaluate checks a listing against the expression, where item conditions are unknown unless items are joined
+func (f Filter) evaluate(l listing, ctx Context, joinItems bool) truth {
+	out := isTrue
+	for _, child := range f.All {
+		out = out.and(child.evaluate(l, ctx, joinItems))
+	}
+	if len(f.Any) > 0 {
+		anyMatched := isFalse
+		for _, child := range f.Any {
+			anyMatched = anyMatched.or(child.evaluate(l, ctx, joinItems))
+		}
+		out = out.and(anyMatched)
+	}
+	if f.Not != nil {
+		out = out.and(f.Not.evaluate(l, ctx, joinItems).not())
+	}
+	if out == isFalse {
+		return isFalse
+	}
+
+	if f.BuyoutPer != nil && (l.buyout == 0 || !f.BuyoutPer.contains(l.buyoutPer)) {
+		return isFalse
+	}
+	if f.Quantity != nil && !f.Quantity.contains(float64(l.quantity)) {
+		return isFalse
+	}
+	if len(f.TimeLeft) > 0 && !containsString(f.TimeLeft, l.timeLeft) {
+		return isFalse
+	}
+
+	switch f.BuyoutKind {
+	case BidOnly:
+		if l.buyout > 0 {
+			return isFalse
+		}
+	case WithBuyout:
+		if l.buyout == 0 {
+			return isFalse
+		}
+	}
+
+	if f.UndercutPercent != nil {
+		// bid-only auctions and items without a median cannot undercut
+		median := ctx.Prices[l.itemId].MedianBuyoutPer
+		if l.buyout == 0 || median == 0 {
+			return isFalse
+		}
+
+		if !f.UndercutPercent.contains((median - l.buyoutPer) / median * 100) {
+			return isFalse
+		}
+	}
+
+	if len(f.ItemClasses) > 0 || len(f.Qualities) > 0 {
+		if !joinItems {
+			return out.and(isUnknown)
+		}
+
+		item, ok := ctx.Items[l.itemId]
+		if !ok {
+			return isFalse
+		}
+
+		if len(f.ItemClasses) > 0 && !containsItemClass(f.ItemClasses, item.ItemClass) {
+			return isFalse
+		}
+		if len(f.Qualities) > 0 && !containsInt(f.Qualities, item.Quality) {
+			return isFalse
+		}
+	}
+
+	return out
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+func containsInt(values []int, value int) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+func containsItemClass(values []blizzard.ItemClassClass, value blizzard.ItemClassClass) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
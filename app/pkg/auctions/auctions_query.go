@@ -0,0 +1,140 @@
+package auctions
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/blizzard"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/sotah"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/sotah/sortdirections"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/sotah/sortkinds"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/util"
+)
+
+const maxQueryCount = 1000
+
+func NewQueryRequest(data []byte) (QueryRequest, error) {
+	req := &QueryRequest{}
+	if err := json.Unmarshal(data, &req); err != nil {
+		return QueryRequest{}, err
+	}
+
+	return *req, nil
+}
+
+// QueryRequest extends the auctions request with a filter expression
+type QueryRequest struct {
+	RegionName    blizzard.RegionName          `json:"region_name"`
+	RealmSlug     blizzard.RealmSlug           `json:"realm_slug"`
+	Page          int                          `json:"page"`
+	Count         int                          `json:"count"`
+	SortDirection sortdirections.SortDirection `json:"sort_direction"`
+	SortKind      sortkinds.SortKind           `json:"sort_kind"`
+	OwnerFilters  []sotah.OwnerName            `json:"owner_filters"`
+	ItemFilters   []blizzard.ItemID            `json:"item_filters"`
+
+	Filter *Filter `json:"filter"`
+}
+
+func (req QueryRequest) Validate() error {
+	if req.Page < 0 {
+		return errors.New("page must be >=0")
+	}
+	if req.Count <= 0 {
+		return errors.New("count must be >0")
+	} else if req.Count > maxQueryCount {
+		return errors.New("count must be <=1000")
+	}
+
+	if req.Filter != nil {
+		return req.Filter.Validate()
+	}
+
+	return nil
+}
+
+// NeedsItems reports whether the request's filter joins against the items database
+func (req QueryRequest) NeedsItems() bool {
+	return req.Filter != nil && req.Filter.NeedsItems()
+}
+
+// Candidates narrows a realm's auctions to those whose items need joining, applying every filter but item details
+func (req QueryRequest) Candidates(maList sotah.MiniAuctionList) sotah.MiniAuctionList {
+	out := maList
+	if len(req.OwnerFilters) > 0 {
+		out = out.FilterByOwnerNames(req.OwnerFilters)
+	}
+	if len(req.ItemFilters) > 0 {
+		out = out.FilterByItemIDs(req.ItemFilters)
+	}
+	if req.Filter != nil {
+		out = req.Filter.Candidates(out, NewContext(maList, sotah.ItemsMap{}))
+	}
+
+	return out
+}
+
+// Query filters, sorts and pages a realm's auctions, where item details are only needed when NeedsItems holds
+func (req QueryRequest) Query(maList sotah.MiniAuctionList, iMap sotah.ItemsMap) (QueryResponse, error) {
+	resp := QueryResponse{AuctionList: maList}
+
+	// filtering in auctions by owners or items
+	if len(req.OwnerFilters) > 0 {
+		resp.AuctionList = resp.AuctionList.FilterByOwnerNames(req.OwnerFilters)
+	}
+	if len(req.ItemFilters) > 0 {
+		resp.AuctionList = resp.AuctionList.FilterByItemIDs(req.ItemFilters)
+	}
+
+	// medians are taken over the whole realm rather than the filtered list
+	if req.Filter != nil {
+		resp.AuctionList = req.Filter.Apply(resp.AuctionList, NewContext(maList, iMap))
+	}
+
+	// calculating totals over every matching auction
+	resp.Total = len(resp.AuctionList)
+	resp.TotalAuctions = resp.AuctionList.TotalAuctions()
+	resp.TotalQuantity = resp.AuctionList.TotalQuantity()
+	resp.TotalCount = maList.TotalAuctions()
+
+	// optionally sorting
+	if req.SortKind != sortkinds.None && req.SortDirection != sortdirections.None {
+		if err := resp.AuctionList.Sort(req.SortKind, req.SortDirection); err != nil {
+			return QueryResponse{}, err
+		}
+	}
+
+	// truncating the list
+	truncated, err := resp.AuctionList.Limit(req.Count, req.Page)
+	if err != nil {
+		return QueryResponse{}, err
+	}
+	resp.AuctionList = truncated
+
+	return resp, nil
+}
+
+type QueryResponse struct {
+	AuctionList sotah.MiniAuctionList `json:"auctions"`
+
+	// matching mini-auctions, the auctions and units they represent, and every auction on the realm
+	Total         int `json:"total"`
+	TotalAuctions int `json:"total_auctions"`
+	TotalQuantity int `json:"total_quantity"`
+	TotalCount    int `json:"total_count"`
+}
+
+func (resp QueryResponse) EncodeForDelivery() (string, error) {
+	jsonEncoded, err := json.Marshal(resp)
+	if err != nil {
+		return "", err
+	}
+
+	gzipEncoded, err := util.GzipEncode(jsonEncoded)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.StdEncoding.EncodeToString(gzipEncoded), nil
+}
@@ -0,0 +1,157 @@
+package auctions
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/blizzard"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/sotah"
+)
+
+func float(value float64) *float64 {
+	return &value
+}
+
+func newTestList() sotah.MiniAuctionList {
+	return sotah.MiniAuctionList{
+		{ItemID: 1, Owner: "a", Buyout: 100, BuyoutPer: 100, Quantity: 1, TimeLeft: "LONG", AucList: []int64{1}},
+		{ItemID: 1, Owner: "b", Buyout: 200, BuyoutPer: 200, Quantity: 1, TimeLeft: "SHORT", AucList: []int64{2}},
+		{ItemID: 2, Owner: "a", Buyout: 0, BuyoutPer: 0, Quantity: 5, TimeLeft: "LONG", AucList: []int64{3}},
+		{ItemID: 3, Owner: "c", Buyout: 50, BuyoutPer: 10, Quantity: 5, TimeLeft: "VERY_LONG", AucList: []int64{4}},
+	}
+}
+
+func newTestItems() sotah.ItemsMap {
+	return sotah.ItemsMap{
+		1: sotah.Item{Item: blizzard.Item{ID: 1, ItemClass: 2, Quality: 4}},
+		2: sotah.Item{Item: blizzard.Item{ID: 2, ItemClass: 7, Quality: 1}},
+		3: sotah.Item{Item: blizzard.Item{ID: 3, ItemClass: 7, Quality: 4}},
+	}
+}
+
+func sortedItemIds(maList sotah.MiniAuctionList) []blizzard.ItemID {
+	out := maList.ItemIds()
+	sort.Slice(out, func(i, j int) bool {
+		return out[i] < out[j]
+	})
+
+	return out
+}
+
+func TestFilterValidate(t *testing.T) {
+	valid := Filter{
+		BuyoutPer: &Range{Min: float(1), Max: float(2)},
+		TimeLeft:  []string{"LONG"},
+		Any:       []Filter{{BuyoutKind: WithBuyout}},
+	}
+	if err := valid.Validate(); err != nil {
+		t.Fatalf("expected a valid filter, got %s", err)
+	}
+
+	invalids := []Filter{
+		{BuyoutPer: &Range{Min: float(2), Max: float(1)}},
+		{TimeLeft: []string{"FOREVER"}},
+		{BuyoutKind: "free"},
+		{All: []Filter{{Quantity: &Range{Min: float(3), Max: float(1)}}}},
+		{Not: &Filter{UndercutPercent: &Range{Min: float(10), Max: float(-10)}}},
+	}
+	for i, f := range invalids {
+		if err := f.Validate(); err == nil {
+			t.Fatalf("expected filter %d to be invalid", i)
+		}
+	}
+}
+
+func TestFilterNeedsItems(t *testing.T) {
+	if (Filter{Quantity: &Range{Min: float(1)}}).NeedsItems() {
+		t.Fatal("expected a quantity filter to not need items")
+	}
+	if !(Filter{Not: &Filter{Qualities: []int{4}}}).NeedsItems() {
+		t.Fatal("expected a nested quality filter to need items")
+	}
+}
+
+func TestFilterApply(t *testing.T) {
+	maList := newTestList()
+	ctx := NewContext(maList, newTestItems())
+
+	f := Filter{
+		Any: []Filter{{TimeLeft: []string{"LONG"}}, {ItemClasses: []blizzard.ItemClassClass{7}}},
+		Not: &Filter{BuyoutKind: BidOnly},
+	}
+	if got := sortedItemIds(f.Apply(maList, ctx)); len(got) != 2 || got[0] != 1 || got[1] != 3 {
+		t.Fatalf("expected items 1 and 3, got %v", got)
+	}
+
+	// item 1's median buyout-per is 150, so only the 100 listing undercuts it by 25 percent or more
+	undercuts := Filter{UndercutPercent: &Range{Min: float(25)}}
+	if got := undercuts.Apply(maList, ctx); len(got) != 1 || got[0].Buyout != 100 {
+		t.Fatalf("expected the cheaper item 1 auction, got %v", got)
+	}
+}
+
+func TestFilterCandidates(t *testing.T) {
+	maList := newTestList()
+	ctx := NewContext(maList, sotah.ItemsMap{})
+
+	// item conditions are undecided, so they keep auctions that pass everything else
+	f := Filter{Quantity: &Range{Min: float(5)}, Qualities: []int{4}}
+	if got := sortedItemIds(f.Candidates(maList, ctx)); len(got) != 2 || got[0] != 2 || got[1] != 3 {
+		t.Fatalf("expected items 2 and 3 as candidates, got %v", got)
+	}
+
+	// an undecided condition under a not cannot rule anything out
+	f = Filter{Not: &Filter{Qualities: []int{4}}, BuyoutKind: WithBuyout}
+	if got := sortedItemIds(f.Candidates(maList, ctx)); len(got) != 2 || got[0] != 1 || got[1] != 3 {
+		t.Fatalf("expected items 1 and 3 as candidates, got %v", got)
+	}
+
+	// every auction that matches once items are joined is a candidate
+	full := f.Apply(maList, NewContext(maList, newTestItems()))
+	candidates := f.Candidates(maList, ctx)
+	for _, mAuction := range full {
+		found := false
+		for _, candidate := range candidates {
+			if candidate.AucList[0] == mAuction.AucList[0] {
+				found = true
+			}
+		}
+		if !found {
+			t.Fatalf("expected auction %d to be a candidate", mAuction.AucList[0])
+		}
+	}
+}
+
+func TestQueryRequestCandidates(t *testing.T) {
+	req := QueryRequest{
+		OwnerFilters: []sotah.OwnerName{"a"},
+		Filter:       &Filter{BuyoutKind: WithBuyout, ItemClasses: []blizzard.ItemClassClass{2}},
+	}
+	if got := sortedItemIds(req.Candidates(newTestList())); len(got) != 1 || got[0] != 1 {
+		t.Fatalf("expected only item 1 to be requested, got %v", got)
+	}
+}
+
+func TestQueryRequestQuery(t *testing.T) {
+	maList := newTestList()
+	req := QueryRequest{
+		Count:  1,
+		Filter: &Filter{Qualities: []int{4}},
+	}
+	if err := req.Validate(); err != nil {
+		t.Fatalf("expected a valid request, got %s", err)
+	}
+
+	resp, err := req.Query(maList, newTestItems())
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	if resp.Total != 3 || resp.TotalQuantity != 7 || resp.TotalCount != 4 || len(resp.AuctionList) != 1 {
+		t.Fatalf("unexpected response totals: %+v", resp)
+	}
+
+	if err := (QueryRequest{Count: maxQueryCount + 1}).Validate(); err == nil {
+		t.Fatal("expected an oversized count to be invalid")
+	}
+}
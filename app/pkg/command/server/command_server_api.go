@@ -0,0 +1,51 @@
+package server
+
+import (
+	"os"
+	"os/signal"
+
+	serverState "github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/state/server"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/logging"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/sotah"
+)
+
+func API(config serverState.APIStateConfig) error {
+	logging.Info("Starting api")
+
+	// establishing a state
+	apiState, err := serverState.NewAPIState(config)
+	if err != nil {
+		logging.WithField("error", err.Error()).Error("Failed to establish api-state")
+
+		return err
+	}
+
+	// opening all listeners
+	if err := apiState.Listeners.Listen(); err != nil {
+		return err
+	}
+
+	// starting up a collector
+	collectorStop := make(sotah.WorkerStopChan)
+	onCollectorStop := apiState.StartCollector(collectorStop)
+
+	// catching SIGINT
+	logging.Info("Waiting for SIGINT")
+	sigIn := make(chan os.Signal, 1)
+	signal.Notify(sigIn, os.Interrupt)
+	<-sigIn
+
+	logging.Info("Caught SIGINT, exiting")
+
+	// stopping listeners
+	apiState.Listeners.Stop()
+
+	logging.Info("Stopping collector")
+	collectorStop <- struct{}{}
+
+	logging.Info("Waiting for collector to stop")
+	<-onCollectorStop
+
+	logging.Info("Exiting")
+	return nil
+}
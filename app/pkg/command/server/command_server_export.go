@@ -0,0 +1,149 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	nats "github.com/nats-io/go-nats"
+	"github.com/sirupsen/logrus"
+	"github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/export"
+	serverState "github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/state/server"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/logging"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/messenger"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/messenger/codes"
+	"github.com/twinj/uuid"
+)
+
+// exportTimeout bounds the wait for each chunk, including the first one while the owning process gathers its data
+const exportTimeout = 60 * time.Second
+
+type ExportConfig struct {
+	MessengerHost string
+	MessengerPort int
+
+	Request export.Request
+
+	// blank output writes to stdout
+	OutputFilepath string
+}
+
+func Export(config ExportConfig) error {
+	logging.Info("Starting export")
+
+	// connecting to the messenger host
+	mess, err := messenger.NewMessenger(config.MessengerHost, config.MessengerPort)
+	if err != nil {
+		return err
+	}
+
+	// resolving the request onto a stream subject unique to this run
+	req := config.Request
+	req.StreamSubject = fmt.Sprintf("%s.%s", serverState.Export, uuid.NewV4().String())
+	if err := req.Validate(); err != nil {
+		return err
+	}
+
+	// resolving the output
+	var out io.Writer = os.Stdout
+	if len(config.OutputFilepath) > 0 {
+		file, err := os.Create(config.OutputFilepath)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		out = file
+	}
+
+	// receiving chunks before publishing the request, so that none are missed
+	received := make(chan receivedChunk, 1)
+	stop := make(chan interface{})
+	defer close(stop)
+	err = mess.Subscribe(req.StreamSubject, stop, func(natsMsg nats.Msg) {
+		c, err := export.NewChunk(natsMsg.Data)
+		if err != nil {
+			c = export.Chunk{Sequence: -1, Done: true, Err: err.Error()}
+		}
+
+		received <- receivedChunk{chunk: c, natsMsg: natsMsg}
+	})
+	if err != nil {
+		return err
+	}
+
+	encodedRequest, err := req.EncodeForDelivery()
+	if err != nil {
+		return err
+	}
+	if err := mess.Publish(string(serverState.Export), encodedRequest); err != nil {
+		return err
+	}
+
+	written := 0
+	for sequence := 0; ; sequence++ {
+		var rc receivedChunk
+		select {
+		case rc = <-received:
+		case <-time.After(exportTimeout):
+			return errors.New("timed out waiting for export chunk")
+		}
+
+		n, done, err := writeChunk(out, rc.chunk, sequence)
+
+		// acknowledging the chunk once written, or aborting the export on failure
+		ack := messenger.NewMessage()
+		ack.Code = codes.Ok
+		if err != nil {
+			ack.Code = codes.GenericError
+			ack.Err = err.Error()
+		}
+		mess.ReplyTo(rc.natsMsg, ack)
+
+		if err != nil {
+			return err
+		}
+		written += n
+
+		if done {
+			break
+		}
+	}
+
+	logging.WithFields(logrus.Fields{
+		"dataset": req.Dataset,
+		"format":  req.Format,
+		"bytes":   written,
+	}).Info("Finished export")
+
+	return nil
+}
+
+type receivedChunk struct {
+	chunk   export.Chunk
+	natsMsg nats.Msg
+}
+
+// writeChunk writes a chunk's data out, reporting whether it was the last chunk
+func writeChunk(out io.Writer, c export.Chunk, sequence int) (int, bool, error) {
+	if len(c.Err) > 0 {
+		return 0, false, errors.New(c.Err)
+	}
+	if c.Sequence != sequence {
+		return 0, false, fmt.Errorf("expected export chunk %d but received %d", sequence, c.Sequence)
+	}
+
+	data, err := c.Decode()
+	if err != nil {
+		return 0, false, err
+	}
+
+	n, err := out.Write(data)
+	if err != nil {
+		return 0, false, err
+	}
+
+	return n, c.Done, nil
+}
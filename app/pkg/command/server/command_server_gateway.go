@@ -0,0 +1,39 @@
+package server
+
+import (
+	"os"
+	"os/signal"
+
+	serverState "github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/state/server"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/logging"
+)
+
+func Gateway(config serverState.GatewayStateConfig) error {
+	logging.Info("Starting prod-gateway")
+
+	// establishing a state
+	gatewayState, err := serverState.NewGatewayState(config)
+	if err != nil {
+		logging.WithField("error", err.Error()).Error("Failed to establish prod-gateway state")
+
+		return err
+	}
+
+	// opening all bus-listeners
+	logging.Info("Opening all bus-listeners")
+	gatewayState.BusListeners.Listen()
+
+	// catching SIGINT
+	logging.Info("Waiting for SIGINT")
+	sigIn := make(chan os.Signal, 1)
+	signal.Notify(sigIn, os.Interrupt)
+	<-sigIn
+
+	logging.Info("Caught SIGINT, exiting")
+
+	// stopping bus-listeners
+	gatewayState.BusListeners.Stop()
+
+	logging.Info("Exiting")
+	return nil
+}
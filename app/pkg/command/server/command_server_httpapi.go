@@ -0,0 +1,48 @@
+package server
+
+import (
+	"os"
+	"os/signal"
+
+	serverState "github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/state/server"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/logging"
+)
+
+func HTTPAPI(config serverState.HTTPAPIStateConfig) error {
+	logging.Info("Starting http-api")
+
+	// establishing a state
+	httpState, err := serverState.NewHTTPAPIState(config)
+	if err != nil {
+		logging.WithField("error", err.Error()).Error("Failed to establish http-api state")
+
+		return err
+	}
+
+	// starting up the http server
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- httpState.Serve()
+	}()
+
+	// catching SIGINT
+	logging.Info("Waiting for SIGINT")
+	sigIn := make(chan os.Signal, 1)
+	signal.Notify(sigIn, os.Interrupt)
+	select {
+	case <-sigIn:
+		logging.Info("Caught SIGINT, exiting")
+	case err := <-serveErr:
+		if err != nil {
+			return err
+		}
+	}
+
+	// stopping the http server
+	if err := httpState.Shutdown(); err != nil {
+		return err
+	}
+
+	logging.Info("Exiting")
+	return nil
+}
@@ -0,0 +1,38 @@
+package server
+
+import (
+	"os"
+	"os/signal"
+
+	serverState "github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/state/server"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/logging"
+)
+
+func LiveAuctions(config serverState.LiveAuctionsStateConfig) error {
+	logging.Info("Starting live-auctions")
+
+	// establishing a state
+	laState, err := serverState.NewLiveAuctionsState(config)
+	if err != nil {
+		return err
+	}
+
+	// opening all listeners
+	if err := laState.Listeners.Listen(); err != nil {
+		return err
+	}
+
+	// catching SIGINT
+	logging.Info("Waiting for SIGINT")
+	sigIn := make(chan os.Signal, 1)
+	signal.Notify(sigIn, os.Interrupt)
+	<-sigIn
+
+	logging.Info("Caught SIGINT, exiting")
+
+	// stopping listeners
+	laState.Listeners.Stop()
+
+	logging.Info("Exiting")
+	return nil
+}
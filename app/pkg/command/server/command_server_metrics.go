@@ -0,0 +1,47 @@
+package server
+
+import (
+	"os"
+	"os/signal"
+
+	serverState "github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/state/server"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/logging"
+)
+
+func Metrics(config serverState.MetricsStateConfig) error {
+	logging.Info("Starting prod-metrics")
+
+	// establishing a state
+	metricsState, err := serverState.NewMetricsState(config)
+	if err != nil {
+		logging.WithField("error", err.Error()).Error("Failed to establish prod-metrics state")
+
+		return err
+	}
+
+	// opening all listeners
+	if err := metricsState.Listeners.Listen(); err != nil {
+		return err
+	}
+
+	// opening all bus-listeners
+	logging.Info("Opening all bus-listeners")
+	metricsState.BusListeners.Listen()
+
+	// catching SIGINT
+	logging.Info("Waiting for SIGINT")
+	sigIn := make(chan os.Signal, 1)
+	signal.Notify(sigIn, os.Interrupt)
+	<-sigIn
+
+	logging.Info("Caught SIGINT, exiting")
+
+	// stopping listeners
+	metricsState.Listeners.Stop()
+
+	// stopping bus-listeners
+	metricsState.BusListeners.Stop()
+
+	logging.Info("Exiting")
+	return nil
+}
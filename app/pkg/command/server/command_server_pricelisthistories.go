@@ -0,0 +1,74 @@
+package server
+
+import (
+	"os"
+	"os/signal"
+
+	"github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/pricelists"
+	serverState "github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/state/server"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/logging"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/sotah"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/state"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/state/subjects"
+)
+
+func PricelistHistories(config serverState.PricelistHistoriesStateConfig) error {
+	logging.Info("Starting pricelist-histories")
+
+	// establishing a state
+	phState, err := serverState.NewPricelistHistoriesState(config)
+	if err != nil {
+		return err
+	}
+
+	// loading the pricelist-histories databases
+	phDatabases, err := pricelists.NewDatabases(
+		config.PricelistHistoriesDatabaseDir,
+		phState.Statuses,
+		phState.RealmGroups,
+	)
+	if err != nil {
+		return err
+	}
+	phState.PricelistHistoryDatabases = phDatabases
+
+	// starting up a compactor, which folds aged shards into the archives rather than deleting them
+	logging.Info("Starting up the pricelist-histories compactor")
+	compactorStop := make(sotah.WorkerStopChan)
+	onCompactorStop := phDatabases.StartCompactor(compactorStop, config.Retention)
+
+	// establishing listeners
+	phState.Listeners = state.NewListeners(state.SubjectListeners{
+		subjects.PriceListHistory:         phState.ListenForPriceListHistory,
+		subjects.PricelistHistoriesIntake: phState.ListenForPricelistHistoriesIntake,
+		serverState.PriceForecast:         phState.ListenForPriceForecast,
+		serverState.Export:                phState.ListenForExport,
+	})
+
+	// opening all listeners
+	logging.Info("Opening all listeners")
+	if err := phState.Listeners.Listen(); err != nil {
+		return err
+	}
+
+	// catching SIGINT
+	logging.Info("Waiting for SIGINT")
+	sigIn := make(chan os.Signal, 1)
+	signal.Notify(sigIn, os.Interrupt)
+	<-sigIn
+
+	logging.Info("Caught SIGINT, exiting")
+
+	// stopping listeners
+	phState.Listeners.Stop()
+
+	// stopping compactor
+	logging.Info("Stopping compactor")
+	compactorStop <- struct{}{}
+
+	logging.Info("Waiting for compactor to stop")
+	<-onCompactorStop
+
+	logging.Info("Exiting")
+	return nil
+}
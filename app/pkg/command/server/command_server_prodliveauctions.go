@@ -0,0 +1,47 @@
+package server
+
+import (
+	"os"
+	"os/signal"
+
+	serverState "github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/state/server"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/logging"
+)
+
+func ProdLiveAuctions(config serverState.ProdLiveAuctionsStateConfig) error {
+	logging.Info("Starting prod-liveauctions")
+
+	// establishing a state
+	liveAuctionsState, err := serverState.NewProdLiveAuctionsState(config)
+	if err != nil {
+		logging.WithField("error", err.Error()).Error("Failed to establish prod-liveauctions state")
+
+		return err
+	}
+
+	// opening all listeners
+	if err := liveAuctionsState.Listeners.Listen(); err != nil {
+		return err
+	}
+
+	// opening all bus-listeners
+	logging.Info("Opening all bus-listeners")
+	liveAuctionsState.BusListeners.Listen()
+
+	// catching SIGINT
+	logging.Info("Waiting for SIGINT")
+	sigIn := make(chan os.Signal, 1)
+	signal.Notify(sigIn, os.Interrupt)
+	<-sigIn
+
+	logging.Info("Caught SIGINT, exiting")
+
+	// stopping listeners
+	liveAuctionsState.Listeners.Stop()
+
+	// stopping bus-listeners
+	liveAuctionsState.BusListeners.Stop()
+
+	logging.Info("Exiting")
+	return nil
+}
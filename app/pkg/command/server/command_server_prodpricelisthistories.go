@@ -0,0 +1,60 @@
+package server
+
+import (
+	"os"
+	"os/signal"
+
+	serverState "github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/state/server"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/logging"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/sotah"
+)
+
+func ProdPricelistHistories(config serverState.ProdPricelistHistoriesStateConfig) error {
+	logging.Info("Starting prod-pricelisthistories")
+
+	// establishing a state
+	pricelistHistoriesState, err := serverState.NewProdPricelistHistoriesState(config)
+	if err != nil {
+		logging.WithField("error", err.Error()).Error("Failed to establish prod-pricelisthistories state")
+
+		return err
+	}
+
+	// starting up a pruner
+	logging.Info("Starting up the pricelist-histories file pruner")
+	prunerStop := make(sotah.WorkerStopChan)
+	onPrunerStop := pricelistHistoriesState.IO.Databases.PricelistHistoryDatabases.StartPruner(prunerStop)
+
+	// opening all listeners
+	if err := pricelistHistoriesState.Listeners.Listen(); err != nil {
+		return err
+	}
+
+	// opening all bus-listeners
+	logging.Info("Opening all bus-listeners")
+	pricelistHistoriesState.BusListeners.Listen()
+
+	// catching SIGINT
+	logging.Info("Waiting for SIGINT")
+	sigIn := make(chan os.Signal, 1)
+	signal.Notify(sigIn, os.Interrupt)
+	<-sigIn
+
+	logging.Info("Caught SIGINT, exiting")
+
+	// stopping listeners
+	pricelistHistoriesState.Listeners.Stop()
+
+	// stopping bus-listeners
+	pricelistHistoriesState.BusListeners.Stop()
+
+	// stopping pruner
+	logging.Info("Stopping pruner")
+	prunerStop <- struct{}{}
+
+	logging.Info("Waiting for pruner to stop")
+	<-onPrunerStop
+
+	logging.Info("Exiting")
+	return nil
+}
@@ -0,0 +1,155 @@
+package server
+
+import (
+	"errors"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/connectedrealms"
+	"github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/pricelists"
+	"github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/replay"
+	serverState "github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/state/server"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/blizzard"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/logging"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/sotah"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/state"
+)
+
+type ReplayConfig struct {
+	LiveAuctionsStateConfig       serverState.LiveAuctionsStateConfig
+	PricelistHistoriesStateConfig serverState.PricelistHistoriesStateConfig
+
+	// which databases to rebuild
+	ReplayLiveAuctions       bool
+	ReplayPricelistHistories bool
+
+	// directory holding <region>/<realm>/<timestamp>.json.gz dumps, narrowed by the optional criteria
+	DumpsDir    string
+	RegionName  blizzard.RegionName
+	RealmSlugs  []blizzard.RealmSlug
+	LowerBounds time.Time
+	UpperBounds time.Time
+}
+
+type replayTarget func(in chan state.GetAuctionsFromTimesOutJob) int
+
+// Replay feeds archived auction dumps in time order through the intake load path, which must not run alongside the
+// live-auctions or pricelist-histories services since they hold the databases open
+func Replay(config ReplayConfig) error {
+	logging.Info("Starting replay")
+
+	if !config.ReplayLiveAuctions && !config.ReplayPricelistHistories {
+		return errors.New("nothing to replay into")
+	}
+
+	// gathering the dumps
+	dumps, err := replay.NewDumps(config.DumpsDir)
+	if err != nil {
+		return err
+	}
+	dumps = dumps.Filter(config.RegionName, config.RealmSlugs, config.LowerBounds, config.UpperBounds)
+	if len(dumps) == 0 {
+		return errors.New("no dumps found")
+	}
+	logging.WithField("dumps", len(dumps)).Info("Found dumps")
+
+	// establishing the states to replay into
+	targets := []replayTarget{}
+	statuses := sotah.Statuses{}
+	if config.ReplayLiveAuctions {
+		laState, err := serverState.NewLiveAuctionsState(config.LiveAuctionsStateConfig)
+		if err != nil {
+			return err
+		}
+
+		targets = append(targets, laState.ReplayAuctions)
+		statuses = laState.Statuses
+	}
+	if config.ReplayPricelistHistories {
+		phState, err := serverState.NewPricelistHistoriesState(config.PricelistHistoriesStateConfig)
+		if err != nil {
+			return err
+		}
+
+		phDatabases, err := pricelists.NewDatabases(
+			config.PricelistHistoriesStateConfig.PricelistHistoriesDatabaseDir,
+			phState.Statuses,
+			phState.RealmGroups,
+		)
+		if err != nil {
+			return err
+		}
+		phState.PricelistHistoryDatabases = phDatabases
+
+		targets = append(targets, phState.ReplayAuctions)
+		statuses = phState.Statuses
+	}
+
+	realmsMap := statuses.RegionRealmsMap()
+	groups := connectedrealms.NewGroups(statuses)
+
+	// loading each round fully before the next, so that a realm's dumps load in order
+	rounds := dumps.Rounds()
+	for i, round := range rounds {
+		ins := make([]chan state.GetAuctionsFromTimesOutJob, len(targets))
+		loadedCounts := make(chan int, len(targets))
+		for j, target := range targets {
+			ins[j] = make(chan state.GetAuctionsFromTimesOutJob)
+			go func(target replayTarget, in chan state.GetAuctionsFromTimesOutJob) {
+				loadedCounts <- target(in)
+			}(target, ins[j])
+		}
+
+		for _, d := range round {
+			entry := logging.WithFields(logrus.Fields{
+				"region":      d.RegionName,
+				"realm":       d.RealmSlug,
+				"target-time": d.TargetTime.Unix(),
+			})
+
+			rea, ok := realmsMap[d.RegionName][d.RealmSlug]
+			if !ok {
+				entry.Warn("Skipping dump of unknown realm")
+
+				continue
+			}
+
+			// connected realms share a single auction house, which is loaded under its primary realm
+			if !groups.IsPrimary(d.RegionName, d.RealmSlug) {
+				entry.Warn("Skipping dump of connected realm that is not primary")
+
+				continue
+			}
+
+			aucs, err := d.Auctions()
+			if err != nil {
+				entry.WithField("error", err.Error()).Error("Failed to read dump")
+
+				continue
+			}
+
+			job := state.GetAuctionsFromTimesOutJob{Realm: rea, TargetTime: d.TargetTime, Auctions: aucs}
+			for _, in := range ins {
+				in <- job
+			}
+		}
+
+		for _, in := range ins {
+			close(in)
+		}
+		loaded := 0
+		for range targets {
+			loaded += <-loadedCounts
+		}
+
+		logging.WithFields(logrus.Fields{
+			"round":  i + 1,
+			"rounds": len(rounds),
+			"dumps":  len(round),
+			"loaded": loaded,
+		}).Info("Replayed round")
+	}
+
+	logging.Info("Exiting")
+	return nil
+}
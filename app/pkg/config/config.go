@@ -0,0 +1,165 @@
+package config
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/blizzard"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/logging"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/sotah"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/util"
+)
+
+const (
+	defaultRawRetentionDays     = 30
+	defaultArchiveRetentionDays = 365
+
+	// blizzard's published api quotas
+	defaultRequestsPerSecond = 100
+	defaultRequestsPerHour   = 36000
+	defaultMaxRetries        = 3
+)
+
+func NewConfigFromFilepath(relativePath string) (Config, error) {
+	logging.WithField("path", relativePath).Info("Reading Config")
+
+	body, err := util.ReadFile(relativePath)
+	if err != nil {
+		return Config{}, err
+	}
+
+	return NewConfig(body)
+}
+
+func NewConfig(body []byte) (Config, error) {
+	c := &Config{}
+	if err := json.Unmarshal(body, &c); err != nil {
+		return Config{}, err
+	}
+
+	return *c, nil
+}
+
+// Config extends sotah.Config with settings only this app reads, from the same config file
+type Config struct {
+	sotah.Config
+
+	Retention RetentionConfig `json:"retention"`
+	Recipes   RecipesConfig   `json:"recipes"`
+	Yields    YieldsConfig    `json:"yields"`
+	Blizzard  BlizzardConfig  `json:"blizzard"`
+}
+
+// RetentionConfig determines how long pricelist-histories are kept at each resolution, where the raw limit also
+// applies to the sales, owners, market-share and anomalies databases
+type RetentionConfig struct {
+	// days that raw pricelist-history shards and live-auctions derived data are kept
+	RawDays int `json:"raw_days"`
+
+	// days that compacted daily points are kept, where a negative value keeps them forever
+	ArchiveDays int `json:"archive_days"`
+}
+
+func (c RetentionConfig) RawLimit() time.Time {
+	return c.RawLimitAt(time.Now())
+}
+
+// RawLimitAt measures the raw limit back from the given time, so that replayed snapshots are kept relative to their own
+// time rather than to now
+func (c RetentionConfig) RawLimitAt(at time.Time) time.Time {
+	days := c.RawDays
+	if days <= 0 {
+		days = defaultRawRetentionDays
+	}
+
+	return at.Add(-1 * time.Hour * 24 * time.Duration(days))
+}
+
+// ArchiveLimit returns a zero time when compacted points are never pruned
+func (c RetentionConfig) ArchiveLimit() time.Time {
+	days := c.ArchiveDays
+	if days < 0 {
+		return time.Time{}
+	}
+	if days == 0 {
+		days = defaultArchiveRetentionDays
+	}
+
+	return time.Now().Add(-1 * time.Hour * 24 * time.Duration(days))
+}
+
+// RecipesConfig determines where crafting recipes are loaded from, where both sources may be combined
+type RecipesConfig struct {
+	// optional path to a local json file of recipes
+	Filepath string `json:"filepath"`
+
+	// recipe ids to resolve from the blizzard api, keyed by profession name
+	RecipeIds map[string][]int `json:"recipe_ids"`
+}
+
+// YieldsConfig determines where the disenchant, mill and prospect yield table is loaded from
+type YieldsConfig struct {
+	// optional path to a local json file of yields
+	Filepath string `json:"filepath"`
+}
+
+// BlizzardConfig determines how hard the blizzard api is queried, where unset values fall back to blizzard's quotas
+type BlizzardConfig struct {
+	// api that realms, auctions and items are resolved from, either community (the default) or game-data
+	API string `json:"api"`
+
+	RequestsPerSecond int `json:"requests_per_second"`
+	RequestsPerHour   int `json:"requests_per_hour"`
+
+	// retries of a request after a rate-limited, server-error or failed response
+	MaxRetries int `json:"max_retries"`
+
+	// credentials by region in place of the client-id and client-secret flags, where china issues its own
+	Credentials map[blizzard.RegionName]BlizzardCredentials `json:"credentials"`
+}
+
+type BlizzardCredentials struct {
+	ClientID     string `json:"client_id"`
+	ClientSecret string `json:"client_secret"`
+}
+
+// ResolveCredentials returns the region's credentials, falling back to the provided ones
+func (c BlizzardConfig) ResolveCredentials(
+	regionName blizzard.RegionName,
+	fallback BlizzardCredentials,
+) BlizzardCredentials {
+	regionCredentials, ok := c.Credentials[regionName]
+	if !ok {
+		return fallback
+	}
+
+	return regionCredentials
+}
+
+func (c BlizzardConfig) ResolveRequestsPerSecond() int {
+	if c.RequestsPerSecond <= 0 {
+		return defaultRequestsPerSecond
+	}
+
+	return c.RequestsPerSecond
+}
+
+func (c BlizzardConfig) ResolveRequestsPerHour() int {
+	if c.RequestsPerHour <= 0 {
+		return defaultRequestsPerHour
+	}
+
+	return c.RequestsPerHour
+}
+
+// ResolveMaxRetries returns no retries when max-retries is negative
+func (c BlizzardConfig) ResolveMaxRetries() int {
+	if c.MaxRetries < 0 {
+		return 0
+	}
+	if c.MaxRetries == 0 {
+		return defaultMaxRetries
+	}
+
+	return c.MaxRetries
+}
@@ -0,0 +1,37 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func daysAgo(limit time.Time) int {
+	return int(time.Since(limit).Hours()/24 + 0.5)
+}
+
+func TestRetentionConfigRawLimit(t *testing.T) {
+	if days := daysAgo(RetentionConfig{}.RawLimit()); days != defaultRawRetentionDays {
+		t.Errorf("expected the default of %d days, got %d", defaultRawRetentionDays, days)
+	}
+
+	if days := daysAgo(RetentionConfig{RawDays: 7}.RawLimit()); days != 7 {
+		t.Errorf("expected 7 days, got %d", days)
+	}
+}
+
+func TestRetentionConfigRawLimitAt(t *testing.T) {
+	at := time.Date(2019, 1, 31, 0, 0, 0, 0, time.UTC)
+	if limit := (RetentionConfig{RawDays: 30}).RawLimitAt(at); !limit.Equal(time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected the limit to be measured back from the given time, got %s", limit)
+	}
+}
+
+func TestRetentionConfigArchiveLimit(t *testing.T) {
+	if days := daysAgo(RetentionConfig{}.ArchiveLimit()); days != defaultArchiveRetentionDays {
+		t.Errorf("expected the default of %d days, got %d", defaultArchiveRetentionDays, days)
+	}
+
+	if limit := (RetentionConfig{ArchiveDays: -1}).ArchiveLimit(); !limit.IsZero() {
+		t.Errorf("expected archives to be kept forever, got a limit of %s", limit)
+	}
+}
@@ -0,0 +1,142 @@
+package connectedrealms
+
+import (
+	"sort"
+
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/blizzard"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/sotah"
+)
+
+// NewGroups joins each region's realms into groups that share an auction house, where the lowest slug in a group
+// is its primary realm
+func NewGroups(statuses sotah.Statuses) Groups {
+	out := Groups{}
+	for regionName, status := range statuses {
+		out[regionName] = newRegionGroups(status.Realms)
+	}
+
+	return out
+}
+
+func newRegionGroups(realms sotah.Realms) RegionGroups {
+	// only realms present in the status may take part
+	known := map[blizzard.RealmSlug]struct{}{}
+	for _, rea := range realms {
+		known[rea.Slug] = struct{}{}
+	}
+
+	// union-find over connected slugs
+	parents := map[blizzard.RealmSlug]blizzard.RealmSlug{}
+	var find func(slug blizzard.RealmSlug) blizzard.RealmSlug
+	find = func(slug blizzard.RealmSlug) blizzard.RealmSlug {
+		parent, ok := parents[slug]
+		if !ok || parent == slug {
+			parents[slug] = slug
+
+			return slug
+		}
+
+		root := find(parent)
+		parents[slug] = root
+
+		return root
+	}
+	union := func(a blizzard.RealmSlug, b blizzard.RealmSlug) {
+		rootA := find(a)
+		rootB := find(b)
+		if rootA == rootB {
+			return
+		}
+
+		// keeping the lowest slug as the root so that primaries are stable across boots
+		if rootA < rootB {
+			parents[rootB] = rootA
+		} else {
+			parents[rootA] = rootB
+		}
+	}
+
+	for _, rea := range realms {
+		find(rea.Slug)
+		for _, connectedSlug := range rea.ConnectedRealms {
+			if _, ok := known[connectedSlug]; !ok {
+				continue
+			}
+
+			union(rea.Slug, connectedSlug)
+		}
+	}
+
+	out := RegionGroups{}
+	for _, rea := range realms {
+		out[rea.Slug] = find(rea.Slug)
+	}
+
+	return out
+}
+
+// RegionGroups maps every realm slug onto its group's primary slug
+type RegionGroups map[blizzard.RealmSlug]blizzard.RealmSlug
+
+type Groups map[blizzard.RegionName]RegionGroups
+
+// Primary resolves a slug onto its group's primary, or itself when it is not grouped
+func (groups Groups) Primary(regionName blizzard.RegionName, realmSlug blizzard.RealmSlug) blizzard.RealmSlug {
+	primarySlug, ok := groups[regionName][realmSlug]
+	if !ok {
+		return realmSlug
+	}
+
+	return primarySlug
+}
+
+func (groups Groups) IsPrimary(regionName blizzard.RegionName, realmSlug blizzard.RealmSlug) bool {
+	return groups.Primary(regionName, realmSlug) == realmSlug
+}
+
+// Members lists every slug in the group of the given slug, including itself
+func (groups Groups) Members(regionName blizzard.RegionName, realmSlug blizzard.RealmSlug) []blizzard.RealmSlug {
+	primarySlug := groups.Primary(regionName, realmSlug)
+
+	out := []blizzard.RealmSlug{}
+	for memberSlug, memberPrimarySlug := range groups[regionName] {
+		if memberPrimarySlug != primarySlug {
+			continue
+		}
+
+		out = append(out, memberSlug)
+	}
+	if len(out) == 0 {
+		out = append(out, realmSlug)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return out[i] < out[j]
+	})
+
+	return out
+}
+
+// PrimaryRealms filters the realms down to one per group
+func (groups Groups) PrimaryRealms(realms sotah.Realms) sotah.Realms {
+	out := sotah.Realms{}
+	for _, rea := range realms {
+		if !groups.IsPrimary(rea.Region.Name, rea.Slug) {
+			continue
+		}
+
+		out = append(out, rea)
+	}
+
+	return out
+}
+
+// PrimaryStatuses filters each status's realms down to one per group
+func (groups Groups) PrimaryStatuses(statuses sotah.Statuses) sotah.Statuses {
+	out := sotah.Statuses{}
+	for regionName, status := range statuses {
+		status.Realms = groups.PrimaryRealms(status.Realms)
+		out[regionName] = status
+	}
+
+	return out
+}
@@ -0,0 +1,75 @@
+package connectedrealms
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/blizzard"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/sotah"
+)
+
+func newRealm(slug blizzard.RealmSlug, connectedSlugs ...blizzard.RealmSlug) sotah.Realm {
+	return sotah.Realm{
+		Realm:  blizzard.Realm{Slug: slug, ConnectedRealms: connectedSlugs},
+		Region: sotah.Region{Name: "us"},
+	}
+}
+
+func newTestGroups() Groups {
+	return NewGroups(sotah.Statuses{
+		"us": sotah.Status{Realms: sotah.Realms{
+			newRealm("zuljin", "zuljin", "azgalor"),
+			newRealm("azgalor", "azgalor", "zuljin", "thunderhorn"),
+			newRealm("thunderhorn", "thunderhorn", "azgalor"),
+			newRealm("earthen-ring"),
+			newRealm("moonguard", "moonguard", "unlisted"),
+		}},
+	})
+}
+
+func TestPrimaryIsLowestSlugOfGroup(t *testing.T) {
+	groups := newTestGroups()
+
+	for _, slug := range []blizzard.RealmSlug{"zuljin", "azgalor", "thunderhorn"} {
+		if primary := groups.Primary("us", slug); primary != "azgalor" {
+			t.Errorf("%s: expected azgalor, got %s", slug, primary)
+		}
+	}
+
+	if primary := groups.Primary("us", "earthen-ring"); primary != "earthen-ring" {
+		t.Errorf("expected an ungrouped realm to be its own primary, got %s", primary)
+	}
+	if primary := groups.Primary("us", "moonguard"); primary != "moonguard" {
+		t.Errorf("expected realms missing from the status to be ignored, got %s", primary)
+	}
+	if primary := groups.Primary("eu", "unknown"); primary != "unknown" {
+		t.Errorf("expected an unknown realm to be its own primary, got %s", primary)
+	}
+}
+
+func TestMembers(t *testing.T) {
+	groups := newTestGroups()
+
+	expected := []blizzard.RealmSlug{"azgalor", "thunderhorn", "zuljin"}
+	if actual := groups.Members("us", "zuljin"); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+
+	if actual := groups.Members("eu", "unknown"); !reflect.DeepEqual(actual, []blizzard.RealmSlug{"unknown"}) {
+		t.Errorf("expected an unknown realm to be its only member, got %v", actual)
+	}
+}
+
+func TestPrimaryRealms(t *testing.T) {
+	groups := newTestGroups()
+	realms := sotah.Realms{newRealm("zuljin"), newRealm("azgalor"), newRealm("earthen-ring")}
+
+	actual := []blizzard.RealmSlug{}
+	for _, rea := range groups.PrimaryRealms(realms) {
+		actual = append(actual, rea.Slug)
+	}
+
+	if expected := []blizzard.RealmSlug{"azgalor", "earthen-ring"}; !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+}
@@ -0,0 +1,100 @@
+package downloads
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/blizzard"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/sotah"
+)
+
+// keying
+func entryKeyName(realmSlug blizzard.RealmSlug) []byte {
+	return []byte(realmSlug)
+}
+
+// bucketing
+func entriesBucketName(regionName blizzard.RegionName) []byte {
+	return []byte(fmt.Sprintf("downloads/%s", regionName))
+}
+
+// db, kept apart from the meta database since bolt locks a database file to a single process
+func databasePath(dirPath string) string {
+	return fmt.Sprintf("%s/downloads.db", dirPath)
+}
+
+func NewEntry(data []byte) (Entry, error) {
+	e := &Entry{}
+	if err := json.Unmarshal(data, &e); err != nil {
+		return Entry{}, err
+	}
+
+	return *e, nil
+}
+
+// Entry records the auctions file last downloaded for a realm
+type Entry struct {
+	// last-modified of the auctions file, as reported by blizzard
+	LastModified int64 `json:"last_modified"`
+
+	// when the file was last downloaded, and when blizzard was last asked whether it had changed
+	Downloaded int64 `json:"downloaded"`
+	Checked    int64 `json:"checked"`
+
+	// checks since the last download where the file was unchanged
+	UnchangedChecks int `json:"unchanged_checks"`
+}
+
+func (e Entry) EncodeForStorage() ([]byte, error) {
+	return json.Marshal(e)
+}
+
+// Staleness reports how far behind blizzard a realm's auctions may be
+func (e Entry) Staleness(realmSlug blizzard.RealmSlug, now time.Time, staleAfter time.Duration) Staleness {
+	out := Staleness{
+		RealmSlug:       realmSlug,
+		LastModified:    e.LastModified,
+		Downloaded:      e.Downloaded,
+		Checked:         e.Checked,
+		UnchangedChecks: e.UnchangedChecks,
+		IsStale:         true,
+	}
+	if e.LastModified == 0 {
+		return out
+	}
+
+	out.Age = int64(now.Sub(time.Unix(e.LastModified, 0)) / time.Second)
+	out.IsStale = time.Duration(out.Age)*time.Second > staleAfter
+
+	return out
+}
+
+// Entries are keyed by realm
+type Entries map[blizzard.RealmSlug]Entry
+
+// RegionEntries are keyed by region
+type RegionEntries map[blizzard.RegionName]Entries
+
+// ModificationDates seeds the realm modification dates, where downloaded is the last-modified of the file
+func (regionEntries RegionEntries) ModificationDates() sotah.RegionRealmModificationDates {
+	out := sotah.RegionRealmModificationDates{}
+	for regionName, entries := range regionEntries {
+		for realmSlug, e := range entries {
+			out = out.Set(regionName, realmSlug, sotah.RealmModificationDates{Downloaded: e.LastModified})
+		}
+	}
+
+	return out
+}
+
+// Staleness of a realm, where age is the number of seconds since its auctions file was last modified
+type Staleness struct {
+	RealmSlug       blizzard.RealmSlug `json:"realm_slug"`
+	LastModified    int64              `json:"last_modified"`
+	Downloaded      int64              `json:"downloaded"`
+	Checked         int64              `json:"checked"`
+	UnchangedChecks int                `json:"unchanged_checks"`
+	Age             int64              `json:"age"`
+	IsStale         bool               `json:"is_stale"`
+}
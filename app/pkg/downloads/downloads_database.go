@@ -0,0 +1,133 @@
+package downloads
+
+import (
+	"time"
+
+	"github.com/boltdb/bolt"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/blizzard"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/util"
+)
+
+// NewDatabase opens the download ledger
+func NewDatabase(dirPath string) (Database, error) {
+	if err := util.EnsureDirsExist([]string{dirPath}); err != nil {
+		return Database{}, err
+	}
+
+	db, err := bolt.Open(databasePath(dirPath), 0600, nil)
+	if err != nil {
+		return Database{}, err
+	}
+
+	return Database{db}, nil
+}
+
+type Database struct {
+	db *bolt.DB
+}
+
+func (dBase Database) update(
+	regionName blizzard.RegionName,
+	realmSlugs []blizzard.RealmSlug,
+	apply func(e Entry) Entry,
+) error {
+	return dBase.db.Update(func(tx *bolt.Tx) error {
+		bkt, err := tx.CreateBucketIfNotExists(entriesBucketName(regionName))
+		if err != nil {
+			return err
+		}
+
+		for _, realmSlug := range realmSlugs {
+			e := Entry{}
+			if data := bkt.Get(entryKeyName(realmSlug)); data != nil {
+				e, err = NewEntry(data)
+				if err != nil {
+					return err
+				}
+			}
+
+			encoded, err := apply(e).EncodeForStorage()
+			if err != nil {
+				return err
+			}
+
+			if err := bkt.Put(entryKeyName(realmSlug), encoded); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// RecordDownload stores the last-modified of a downloaded file for every realm sharing it
+func (dBase Database) RecordDownload(
+	regionName blizzard.RegionName,
+	realmSlugs []blizzard.RealmSlug,
+	lastModified time.Time,
+	downloadedAt time.Time,
+) error {
+	return dBase.update(regionName, realmSlugs, func(e Entry) Entry {
+		return Entry{
+			LastModified:    lastModified.Unix(),
+			Downloaded:      downloadedAt.Unix(),
+			Checked:         downloadedAt.Unix(),
+			UnchangedChecks: 0,
+		}
+	})
+}
+
+// RecordUnchanged notes a check where the file had not changed since the last download
+func (dBase Database) RecordUnchanged(
+	regionName blizzard.RegionName,
+	realmSlugs []blizzard.RealmSlug,
+	checkedAt time.Time,
+) error {
+	return dBase.update(regionName, realmSlugs, func(e Entry) Entry {
+		e.Checked = checkedAt.Unix()
+		e.UnchangedChecks++
+
+		return e
+	})
+}
+
+func (dBase Database) GetEntries(regionName blizzard.RegionName) (Entries, error) {
+	out := Entries{}
+
+	err := dBase.db.View(func(tx *bolt.Tx) error {
+		bkt := tx.Bucket(entriesBucketName(regionName))
+		if bkt == nil {
+			return nil
+		}
+
+		return bkt.ForEach(func(k, v []byte) error {
+			e, err := NewEntry(v)
+			if err != nil {
+				return err
+			}
+
+			out[blizzard.RealmSlug(k)] = e
+
+			return nil
+		})
+	})
+	if err != nil {
+		return Entries{}, err
+	}
+
+	return out, nil
+}
+
+func (dBase Database) GetRegionEntries(regionNames []blizzard.RegionName) (RegionEntries, error) {
+	out := RegionEntries{}
+	for _, regionName := range regionNames {
+		entries, err := dBase.GetEntries(regionName)
+		if err != nil {
+			return RegionEntries{}, err
+		}
+
+		out[regionName] = entries
+	}
+
+	return out, nil
+}
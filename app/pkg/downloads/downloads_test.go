@@ -0,0 +1,114 @@
+package downloads
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/boltdb/bolt"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/blizzard"
+)
+
+func newTestDatabase(t *testing.T) (Database, string, func()) {
+	dirPath, err := ioutil.TempDir("", "downloads")
+	if err != nil {
+		t.Fatalf("could not create temp dir: %s", err)
+	}
+
+	dBase, err := NewDatabase(dirPath)
+	if err != nil {
+		os.RemoveAll(dirPath)
+		t.Fatalf("could not open database: %s", err)
+	}
+
+	return dBase, dirPath, func() {
+		dBase.db.Close()
+		os.RemoveAll(dirPath)
+	}
+}
+
+func TestEntryStaleness(t *testing.T) {
+	now := time.Unix(10000, 0)
+
+	s := Entry{}.Staleness("zuljin", now, time.Hour)
+	if !s.IsStale || s.Age != 0 {
+		t.Fatalf("expected a realm never downloaded to be stale, got %+v", s)
+	}
+
+	s = Entry{LastModified: now.Add(-30 * time.Minute).Unix(), UnchangedChecks: 2}.Staleness("zuljin", now, time.Hour)
+	if s.IsStale || s.Age != 30*60 || s.UnchangedChecks != 2 || s.RealmSlug != "zuljin" {
+		t.Fatalf("expected a recent file to be fresh, got %+v", s)
+	}
+
+	s = Entry{LastModified: now.Add(-2 * time.Hour).Unix()}.Staleness("zuljin", now, time.Hour)
+	if !s.IsStale || s.Age != 2*60*60 {
+		t.Fatalf("expected an old file to be stale, got %+v", s)
+	}
+}
+
+func TestDatabaseRecording(t *testing.T) {
+	dBase, _, cleanup := newTestDatabase(t)
+	defer cleanup()
+
+	lastModified := time.Unix(1000, 0)
+	downloadedAt := time.Unix(1100, 0)
+	slugs := []blizzard.RealmSlug{"zuljin", "azgalor"}
+	if err := dBase.RecordDownload("us", slugs, lastModified, downloadedAt); err != nil {
+		t.Fatalf("could not record download: %s", err)
+	}
+	if err := dBase.RecordUnchanged("us", slugs, time.Unix(1200, 0)); err != nil {
+		t.Fatalf("could not record unchanged: %s", err)
+	}
+	if err := dBase.RecordUnchanged("us", []blizzard.RealmSlug{"zuljin"}, time.Unix(1300, 0)); err != nil {
+		t.Fatalf("could not record unchanged: %s", err)
+	}
+
+	regionEntries, err := dBase.GetRegionEntries([]blizzard.RegionName{"us", "eu"})
+	if err != nil {
+		t.Fatalf("could not get entries: %s", err)
+	}
+
+	if len(regionEntries["eu"]) != 0 {
+		t.Fatalf("expected no entries for an unrecorded region, got %+v", regionEntries["eu"])
+	}
+	expected := Entry{LastModified: 1000, Downloaded: 1100, Checked: 1300, UnchangedChecks: 2}
+	if actual := regionEntries["us"]["zuljin"]; actual != expected {
+		t.Fatalf("expected %+v, got %+v", expected, actual)
+	}
+	expected = Entry{LastModified: 1000, Downloaded: 1100, Checked: 1200, UnchangedChecks: 1}
+	if actual := regionEntries["us"]["azgalor"]; actual != expected {
+		t.Fatalf("expected %+v, got %+v", expected, actual)
+	}
+
+	// a new download resets the unchanged checks
+	if err := dBase.RecordDownload("us", slugs, time.Unix(2000, 0), time.Unix(2100, 0)); err != nil {
+		t.Fatalf("could not record download: %s", err)
+	}
+	entries, err := dBase.GetEntries("us")
+	if err != nil {
+		t.Fatalf("could not get entries: %s", err)
+	}
+	expected = Entry{LastModified: 2000, Downloaded: 2100, Checked: 2100, UnchangedChecks: 0}
+	if actual := entries["zuljin"]; actual != expected {
+		t.Fatalf("expected %+v, got %+v", expected, actual)
+	}
+
+	modDates := regionEntries.ModificationDates()
+	if downloaded := modDates["us"]["zuljin"].Downloaded; downloaded != 1000 {
+		t.Fatalf("expected the last-modified to seed the modification dates, got %d", downloaded)
+	}
+}
+
+func TestDatabaseSharesDirWithMeta(t *testing.T) {
+	_, dirPath, cleanup := newTestDatabase(t)
+	defer cleanup()
+
+	// the meta database is held open alongside the ledger, where a shared file would block on its lock
+	meta, err := bolt.Open(filepath.Join(dirPath, "meta.db"), 0600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		t.Fatalf("could not open the meta database alongside the ledger: %s", err)
+	}
+	defer meta.Close()
+}
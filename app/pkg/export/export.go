@@ -0,0 +1,201 @@
+package export
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/pricelists"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/blizzard"
+)
+
+// Format - typehint for these enums
+type Format string
+
+/*
+Formats - file formats that rows may be written as
+*/
+const (
+	CSV     Format = "csv"
+	NDJSON  Format = "ndjson"
+	Parquet Format = "parquet"
+)
+
+func (f Format) Validate() error {
+	switch f {
+	case CSV, NDJSON, Parquet:
+		return nil
+	default:
+		return fmt.Errorf("invalid format: %s", f)
+	}
+}
+
+// Dataset - typehint for these enums
+type Dataset string
+
+/*
+Datasets - what may be exported, each served by the process owning its data
+*/
+const (
+	Auctions       Dataset = "auctions"
+	Prices         Dataset = "prices"
+	PriceHistories Dataset = "price-histories"
+	VendorFlips    Dataset = "vendor-flips"
+	YieldValues    Dataset = "yield-values"
+)
+
+func (d Dataset) Validate() error {
+	switch d {
+	case Auctions, Prices, PriceHistories, VendorFlips, YieldValues:
+		return nil
+	default:
+		return fmt.Errorf("invalid dataset: %s", d)
+	}
+}
+
+// ColumnKind - typehint for these enums
+type ColumnKind string
+
+/*
+ColumnKinds - the value types a column may hold
+*/
+const (
+	IntColumn    ColumnKind = "int"
+	FloatColumn  ColumnKind = "float"
+	StringColumn ColumnKind = "string"
+)
+
+type Column struct {
+	Name string
+	Kind ColumnKind
+}
+
+// accepts reports whether the value is of the column's kind
+func (col Column) accepts(value interface{}) bool {
+	switch value.(type) {
+	case int64:
+		return col.Kind == IntColumn
+	case float64:
+		return col.Kind == FloatColumn
+	case string:
+		return col.Kind == StringColumn
+	default:
+		return false
+	}
+}
+
+type Schema []Column
+
+// Row holds an int64, float64 or string per column of its schema
+type Row []interface{}
+
+type Writer interface {
+	Write(row Row) error
+
+	// Close flushes anything buffered, without closing the underlying writer
+	Close() error
+}
+
+func NewWriter(f Format, schema Schema, w io.Writer) (Writer, error) {
+	switch f {
+	case CSV:
+		return newCSVWriter(schema, w)
+	case NDJSON:
+		return newNDJSONWriter(schema, w), nil
+	case Parquet:
+		return newParquetWriter(schema, w), nil
+	default:
+		return nil, fmt.Errorf("invalid format: %s", f)
+	}
+}
+
+func NewRequest(payload []byte) (Request, error) {
+	req := &Request{}
+	if err := json.Unmarshal(payload, &req); err != nil {
+		return Request{}, err
+	}
+
+	return *req, nil
+}
+
+// Request selects a dataset for realms, items and a time range, where blank items match every item
+type Request struct {
+	Dataset    Dataset              `json:"dataset"`
+	Format     Format               `json:"format"`
+	RegionName blizzard.RegionName  `json:"region_name"`
+	RealmSlugs []blizzard.RealmSlug `json:"realm_slugs"`
+	ItemIds    []blizzard.ItemID    `json:"item_ids"`
+
+	// bounds of price-histories, defaulting to everything retained
+	LowerBounds int64 `json:"lower_bounds"`
+	UpperBounds int64 `json:"upper_bounds"`
+
+	// basis of yield-values, defaulting to the market price
+	PriceBasis pricelists.PriceBasis `json:"price_basis"`
+
+	// subject the chunks are published onto
+	StreamSubject string `json:"stream_subject"`
+}
+
+func (req Request) Validate() error {
+	if err := req.Dataset.Validate(); err != nil {
+		return err
+	}
+	if err := req.Format.Validate(); err != nil {
+		return err
+	}
+	if len(req.RealmSlugs) == 0 {
+		return errors.New("realm slugs cannot be blank")
+	}
+	if err := req.Basis().Validate(); err != nil {
+		return err
+	}
+	if len(req.StreamSubject) == 0 {
+		return errors.New("stream subject cannot be blank")
+	}
+
+	return nil
+}
+
+func (req Request) EncodeForDelivery() ([]byte, error) {
+	return json.Marshal(req)
+}
+
+func (req Request) Bounds() (time.Time, time.Time) {
+	upperBounds := time.Now()
+	if req.UpperBounds > 0 {
+		upperBounds = time.Unix(req.UpperBounds, 0)
+	}
+
+	return time.Unix(req.LowerBounds, 0), upperBounds
+}
+
+func (req Request) Basis() pricelists.PriceBasis {
+	if len(req.PriceBasis) == 0 {
+		return pricelists.MarketPrice
+	}
+
+	return req.PriceBasis
+}
+
+// ItemFilter reports whether an item was requested
+func (req Request) ItemFilter() func(blizzard.ItemID) bool {
+	if len(req.ItemIds) == 0 {
+		return func(blizzard.ItemID) bool {
+			return true
+		}
+	}
+
+	requested := map[blizzard.ItemID]struct{}{}
+	for _, itemId := range req.ItemIds {
+		requested[itemId] = struct{}{}
+	}
+
+	return func(itemId blizzard.ItemID) bool {
+		_, ok := requested[itemId]
+
+		return ok
+	}
+}
@@ -0,0 +1,55 @@
+package export
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+func newCSVWriter(schema Schema, w io.Writer) (Writer, error) {
+	out := csvWriter{schema: schema, w: csv.NewWriter(w)}
+
+	header := make([]string, len(schema))
+	for i, col := range schema {
+		header[i] = col.Name
+	}
+	if err := out.w.Write(header); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+type csvWriter struct {
+	schema Schema
+	w      *csv.Writer
+}
+
+func (cw csvWriter) Write(row Row) error {
+	if len(row) != len(cw.schema) {
+		return fmt.Errorf("row has %d values but schema has %d columns", len(row), len(cw.schema))
+	}
+
+	record := make([]string, len(row))
+	for i, value := range row {
+		switch v := value.(type) {
+		case int64:
+			record[i] = strconv.FormatInt(v, 10)
+		case float64:
+			record[i] = strconv.FormatFloat(v, 'f', -1, 64)
+		case string:
+			record[i] = v
+		default:
+			return fmt.Errorf("invalid value for column %s", cw.schema[i].Name)
+		}
+	}
+
+	return cw.w.Write(record)
+}
+
+func (cw csvWriter) Close() error {
+	cw.w.Flush()
+
+	return cw.w.Error()
+}
@@ -0,0 +1,250 @@
+package export
+
+import (
+	"sort"
+
+	"github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/flips"
+	"github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/pricelists"
+	"github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/yields"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/blizzard"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/sotah"
+)
+
+var realmColumns = Schema{
+	{Name: "region", Kind: StringColumn},
+	{Name: "realm", Kind: StringColumn},
+}
+
+var pricesColumns = Schema{
+	{Name: "min_buyout_per", Kind: FloatColumn},
+	{Name: "max_buyout_per", Kind: FloatColumn},
+	{Name: "average_buyout_per", Kind: FloatColumn},
+	{Name: "median_buyout_per", Kind: FloatColumn},
+	{Name: "volume", Kind: IntColumn},
+	{Name: "volume_weighted_average_buyout_per", Kind: FloatColumn},
+	{Name: "p10_buyout_per", Kind: FloatColumn},
+	{Name: "p25_buyout_per", Kind: FloatColumn},
+	{Name: "p75_buyout_per", Kind: FloatColumn},
+	{Name: "p90_buyout_per", Kind: FloatColumn},
+	{Name: "market_price", Kind: FloatColumn},
+}
+
+func joinSchemas(schemas ...Schema) Schema {
+	out := Schema{}
+	for _, s := range schemas {
+		out = append(out, s...)
+	}
+
+	return out
+}
+
+var datasetSchemas = map[Dataset]Schema{
+	Auctions: joinSchemas(realmColumns, Schema{
+		{Name: "item_id", Kind: IntColumn},
+		{Name: "owner", Kind: StringColumn},
+		{Name: "owner_realm", Kind: StringColumn},
+		{Name: "bid", Kind: IntColumn},
+		{Name: "buyout", Kind: IntColumn},
+		{Name: "buyout_per", Kind: FloatColumn},
+		{Name: "quantity", Kind: IntColumn},
+		{Name: "time_left", Kind: StringColumn},
+		{Name: "auctions", Kind: IntColumn},
+	}),
+	Prices: joinSchemas(realmColumns, Schema{{Name: "item_id", Kind: IntColumn}}, pricesColumns),
+	PriceHistories: joinSchemas(
+		realmColumns,
+		Schema{{Name: "item_id", Kind: IntColumn}, {Name: "timestamp", Kind: IntColumn}},
+		pricesColumns,
+	),
+	VendorFlips: joinSchemas(realmColumns, Schema{
+		{Name: "item_id", Kind: IntColumn},
+		{Name: "sell_price", Kind: IntColumn},
+		{Name: "min_buyout_per", Kind: FloatColumn},
+		{Name: "auctions", Kind: IntColumn},
+		{Name: "quantity", Kind: IntColumn},
+		{Name: "cost", Kind: IntColumn},
+		{Name: "profit", Kind: IntColumn},
+	}),
+	YieldValues: joinSchemas(realmColumns, Schema{
+		{Name: "item_id", Kind: IntColumn},
+		{Name: "method", Kind: StringColumn},
+		{Name: "price", Kind: FloatColumn},
+		{Name: "yield_value", Kind: FloatColumn},
+		{Name: "margin", Kind: FloatColumn},
+		{Name: "unpriced_items", Kind: IntColumn},
+	}),
+}
+
+func (d Dataset) Schema() Schema {
+	return datasetSchemas[d]
+}
+
+func realmValues(regionName blizzard.RegionName, realmSlug blizzard.RealmSlug) Row {
+	return Row{string(regionName), string(realmSlug)}
+}
+
+func pricesValues(p pricelists.Prices) Row {
+	return Row{
+		p.MinBuyoutPer,
+		p.MaxBuyoutPer,
+		p.AverageBuyoutPer,
+		p.MedianBuyoutPer,
+		p.Volume,
+		p.VolumeWeightedAverageBuyoutPer,
+		p.P10BuyoutPer,
+		p.P25BuyoutPer,
+		p.P75BuyoutPer,
+		p.P90BuyoutPer,
+		p.MarketPrice,
+	}
+}
+
+func sortedItemIds(itemIds []blizzard.ItemID) []blizzard.ItemID {
+	sort.Slice(itemIds, func(i, j int) bool {
+		return itemIds[i] < itemIds[j]
+	})
+
+	return itemIds
+}
+
+func WriteAuctions(
+	w Writer,
+	regionName blizzard.RegionName,
+	realmSlug blizzard.RealmSlug,
+	maList sotah.MiniAuctionList,
+	filter func(blizzard.ItemID) bool,
+) error {
+	for _, mAuction := range maList {
+		if !filter(mAuction.ItemID) {
+			continue
+		}
+
+		row := append(realmValues(regionName, realmSlug), Row{
+			int64(mAuction.ItemID),
+			string(mAuction.Owner),
+			mAuction.OwnerRealm,
+			mAuction.Bid,
+			mAuction.Buyout,
+			float64(mAuction.BuyoutPer),
+			mAuction.Quantity,
+			mAuction.TimeLeft,
+			int64(len(mAuction.AucList)),
+		}...)
+		if err := w.Write(row); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func WritePrices(
+	w Writer,
+	regionName blizzard.RegionName,
+	realmSlug blizzard.RealmSlug,
+	iPrices pricelists.ItemPrices,
+	filter func(blizzard.ItemID) bool,
+) error {
+	for _, itemId := range sortedItemIds(iPrices.ItemIds()) {
+		if !filter(itemId) {
+			continue
+		}
+
+		row := append(realmValues(regionName, realmSlug), int64(itemId))
+		if err := w.Write(append(row, pricesValues(iPrices[itemId])...)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func WritePriceHistories(
+	w Writer,
+	regionName blizzard.RegionName,
+	realmSlug blizzard.RealmSlug,
+	ipHistories pricelists.ItemPriceHistories,
+) error {
+	itemIds := []blizzard.ItemID{}
+	for itemId := range ipHistories {
+		itemIds = append(itemIds, itemId)
+	}
+
+	for _, itemId := range sortedItemIds(itemIds) {
+		pHistory := ipHistories[itemId]
+
+		timestamps := make([]sotah.UnixTimestamp, 0, len(pHistory))
+		for targetTimestamp := range pHistory {
+			timestamps = append(timestamps, targetTimestamp)
+		}
+		sort.Slice(timestamps, func(i, j int) bool {
+			return timestamps[i] < timestamps[j]
+		})
+
+		for _, targetTimestamp := range timestamps {
+			row := append(realmValues(regionName, realmSlug), int64(itemId), int64(targetTimestamp))
+			if err := w.Write(append(row, pricesValues(pHistory[targetTimestamp])...)); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func WriteVendorFlips(
+	w Writer,
+	regionName blizzard.RegionName,
+	realmSlug blizzard.RealmSlug,
+	fs flips.Flips,
+	filter func(blizzard.ItemID) bool,
+) error {
+	for _, f := range fs {
+		if !filter(f.ItemID) {
+			continue
+		}
+
+		row := append(realmValues(regionName, realmSlug), Row{
+			int64(f.ItemID),
+			f.SellPrice,
+			f.MinBuyoutPer,
+			int64(f.Auctions),
+			f.Quantity,
+			f.Cost,
+			f.Profit,
+		}...)
+		if err := w.Write(row); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func WriteYieldValues(
+	w Writer,
+	regionName blizzard.RegionName,
+	realmSlug blizzard.RealmSlug,
+	values yields.Values,
+	filter func(blizzard.ItemID) bool,
+) error {
+	for _, v := range values {
+		if !filter(v.ItemID) {
+			continue
+		}
+
+		row := append(realmValues(regionName, realmSlug), Row{
+			int64(v.ItemID),
+			string(v.Method),
+			v.Price,
+			v.YieldValue,
+			v.Margin,
+			int64(len(v.UnpricedItems)),
+		}...)
+		if err := w.Write(row); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
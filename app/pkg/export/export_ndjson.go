@@ -0,0 +1,63 @@
+package export
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+func newNDJSONWriter(schema Schema, w io.Writer) Writer {
+	// encoding column names once, so that every line keeps the schema's column order
+	names := make([][]byte, len(schema))
+	for i, col := range schema {
+		names[i], _ = json.Marshal(col.Name)
+	}
+
+	return ndjsonWriter{schema: schema, names: names, w: bufio.NewWriter(w)}
+}
+
+type ndjsonWriter struct {
+	schema Schema
+	names  [][]byte
+	w      *bufio.Writer
+}
+
+func (nw ndjsonWriter) Write(row Row) error {
+	if len(row) != len(nw.schema) {
+		return fmt.Errorf("row has %d values but schema has %d columns", len(row), len(nw.schema))
+	}
+
+	if err := nw.w.WriteByte('{'); err != nil {
+		return err
+	}
+	for i, value := range row {
+		if i > 0 {
+			if err := nw.w.WriteByte(','); err != nil {
+				return err
+			}
+		}
+
+		encodedValue, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+
+		if _, err := nw.w.Write(nw.names[i]); err != nil {
+			return err
+		}
+		if err := nw.w.WriteByte(':'); err != nil {
+			return err
+		}
+		if _, err := nw.w.Write(encodedValue); err != nil {
+			return err
+		}
+	}
+	_, err := nw.w.WriteString("}\n")
+
+	return err
+}
+
+func (nw ndjsonWriter) Close() error {
+	return nw.w.Flush()
+}
@@ -0,0 +1,272 @@
+package export
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+)
+
+const parquetMagic = "PAR1"
+
+/*
+parquet enums, as numbered by the format's thrift definitions
+*/
+const (
+	parquetInt64     int32 = 2
+	parquetDouble    int32 = 5
+	parquetByteArray int32 = 6
+
+	parquetRequired int32 = 0
+	parquetUTF8     int32 = 0
+
+	parquetPlain int32 = 0
+	parquetRLE   int32 = 3
+
+	parquetDataPage     int32 = 0
+	parquetUncompressed int32 = 0
+)
+
+func parquetType(kind ColumnKind) int32 {
+	switch kind {
+	case IntColumn:
+		return parquetInt64
+	case FloatColumn:
+		return parquetDouble
+	default:
+		return parquetByteArray
+	}
+}
+
+/*
+row-group limits, keeping what is buffered bounded and every page within the int32 sizes of its header
+*/
+const (
+	parquetRowGroupRows  = 64 * 1024
+	parquetRowGroupBytes = 64 * 1024 * 1024
+)
+
+func newParquetWriter(schema Schema, w io.Writer) Writer {
+	return &parquetWriter{schema: schema, columns: make([]bytes.Buffer, len(schema)), w: w}
+}
+
+// parquetWriter buffers plain-encoded, required columns and writes them as an uncompressed row group every so many
+// rows, with the footer describing every row group written on close
+type parquetWriter struct {
+	schema Schema
+	w      io.Writer
+
+	// the row group being buffered
+	columns []bytes.Buffer
+	rows    int64
+
+	// what has been written so far
+	started   bool
+	offset    int64
+	totalRows int64
+	rowGroups []parquetRowGroup
+}
+
+type parquetChunk struct {
+	offset int64
+	size   int64
+}
+
+type parquetRowGroup struct {
+	chunks []parquetChunk
+	rows   int64
+}
+
+func (pw *parquetWriter) Write(row Row) error {
+	if len(row) != len(pw.schema) {
+		return fmt.Errorf("row has %d values but schema has %d columns", len(row), len(pw.schema))
+	}
+
+	// checking every value first, since a rejected row must not leave values in the columns
+	for i, value := range row {
+		if !pw.schema[i].accepts(value) {
+			return fmt.Errorf("invalid value for column %s", pw.schema[i].Name)
+		}
+	}
+
+	encoded := make([]byte, 8)
+	for i, value := range row {
+		col := &pw.columns[i]
+
+		switch v := value.(type) {
+		case int64:
+			binary.LittleEndian.PutUint64(encoded, uint64(v))
+			col.Write(encoded)
+		case float64:
+			binary.LittleEndian.PutUint64(encoded, math.Float64bits(v))
+			col.Write(encoded)
+		case string:
+			binary.LittleEndian.PutUint32(encoded, uint32(len(v)))
+			col.Write(encoded[:4])
+			col.WriteString(v)
+		default:
+			return fmt.Errorf("invalid value for column %s", pw.schema[i].Name)
+		}
+	}
+	pw.rows++
+
+	if pw.rows >= parquetRowGroupRows || pw.bufferedBytes() >= parquetRowGroupBytes {
+		return pw.flushRowGroup()
+	}
+
+	return nil
+}
+
+func (pw *parquetWriter) bufferedBytes() int {
+	out := 0
+	for i := range pw.columns {
+		out += pw.columns[i].Len()
+	}
+
+	return out
+}
+
+func (pw *parquetWriter) write(data []byte) error {
+	n, err := pw.w.Write(data)
+	pw.offset += int64(n)
+
+	return err
+}
+
+func (pw *parquetWriter) start() error {
+	if pw.started {
+		return nil
+	}
+	pw.started = true
+
+	return pw.write([]byte(parquetMagic))
+}
+
+// flushRowGroup writes each buffered column as a single data page
+func (pw *parquetWriter) flushRowGroup() error {
+	if err := pw.start(); err != nil {
+		return err
+	}
+
+	rg := parquetRowGroup{chunks: make([]parquetChunk, len(pw.columns)), rows: pw.rows}
+	for i := range pw.columns {
+		data := pw.columns[i].Bytes()
+		if len(data) > math.MaxInt32 {
+			return fmt.Errorf("column %s exceeds the page size limit", pw.schema[i].Name)
+		}
+
+		header := newThriftWriter()
+		header.i32Field(1, parquetDataPage)
+		header.i32Field(2, int32(len(data)))
+		header.i32Field(3, int32(len(data)))
+		header.structField(5)
+		header.i32Field(1, int32(pw.rows))
+		header.i32Field(2, parquetPlain)
+		header.i32Field(3, parquetRLE)
+		header.i32Field(4, parquetRLE)
+		header.structEnd()
+		header.buf.WriteByte(0)
+
+		chunkOffset := pw.offset
+		if err := pw.write(header.Bytes()); err != nil {
+			return err
+		}
+		if err := pw.write(data); err != nil {
+			return err
+		}
+
+		rg.chunks[i] = parquetChunk{offset: chunkOffset, size: pw.offset - chunkOffset}
+		pw.columns[i].Reset()
+	}
+
+	pw.rowGroups = append(pw.rowGroups, rg)
+	pw.totalRows += pw.rows
+	pw.rows = 0
+
+	return nil
+}
+
+func (pw *parquetWriter) Close() error {
+	if err := pw.start(); err != nil {
+		return err
+	}
+
+	if pw.rows > 0 {
+		if err := pw.flushRowGroup(); err != nil {
+			return err
+		}
+	}
+
+	footer := pw.footer()
+	if err := pw.write(footer); err != nil {
+		return err
+	}
+
+	footerLength := make([]byte, 4)
+	binary.LittleEndian.PutUint32(footerLength, uint32(len(footer)))
+	if err := pw.write(footerLength); err != nil {
+		return err
+	}
+
+	return pw.write([]byte(parquetMagic))
+}
+
+// footer encodes the file metadata
+func (pw *parquetWriter) footer() []byte {
+	t := newThriftWriter()
+	t.i32Field(1, 1)
+
+	// schema, flattened with the root first
+	t.listHeader(2, thriftStruct, len(pw.schema)+1)
+	t.structBegin()
+	t.stringField(4, "schema")
+	t.i32Field(5, int32(len(pw.schema)))
+	t.structEnd()
+	for _, col := range pw.schema {
+		t.structBegin()
+		t.i32Field(1, parquetType(col.Kind))
+		t.i32Field(3, parquetRequired)
+		t.stringField(4, col.Name)
+		if col.Kind == StringColumn {
+			t.i32Field(6, parquetUTF8)
+		}
+		t.structEnd()
+	}
+
+	t.i64Field(3, pw.totalRows)
+
+	t.listHeader(4, thriftStruct, len(pw.rowGroups))
+	for _, rg := range pw.rowGroups {
+		totalSize := int64(0)
+		for _, chunk := range rg.chunks {
+			totalSize += chunk.size
+		}
+
+		t.structBegin()
+		t.listHeader(1, thriftStruct, len(pw.schema))
+		for i, col := range pw.schema {
+			t.structBegin()
+			t.i64Field(2, rg.chunks[i].offset)
+			t.structField(3)
+			t.i32Field(1, parquetType(col.Kind))
+			t.i32ListField(2, []int32{parquetPlain, parquetRLE})
+			t.stringListField(3, []string{col.Name})
+			t.i32Field(4, parquetUncompressed)
+			t.i64Field(5, rg.rows)
+			t.i64Field(6, rg.chunks[i].size)
+			t.i64Field(7, rg.chunks[i].size)
+			t.i64Field(9, rg.chunks[i].offset)
+			t.structEnd()
+			t.structEnd()
+		}
+		t.i64Field(2, totalSize)
+		t.i64Field(3, rg.rows)
+		t.structEnd()
+	}
+
+	t.stringField(6, "steamwheedle-cartel-server")
+	t.buf.WriteByte(0)
+
+	return t.Bytes()
+}
@@ -0,0 +1,171 @@
+package export
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"testing"
+)
+
+type parquetFile struct {
+	columns   []string
+	types     []int64
+	numRows   int64
+	rowGroups int
+	rows      []Row
+}
+
+// readParquet reads back a file of plain-encoded, required and uncompressed columns
+func readParquet(data []byte) (parquetFile, error) {
+	if len(data) < 12 || string(data[:4]) != parquetMagic || string(data[len(data)-4:]) != parquetMagic {
+		return parquetFile{}, fmt.Errorf("missing magic")
+	}
+
+	footerLength := int(binary.LittleEndian.Uint32(data[len(data)-8:]))
+	footerStart := len(data) - 8 - footerLength
+	meta, _, err := readThriftStruct(data[footerStart : len(data)-8])
+	if err != nil {
+		return parquetFile{}, err
+	}
+
+	out := parquetFile{numRows: meta[3].(int64)}
+	schema := meta[2].([]interface{})
+	if schema[0].(map[int16]interface{})[5].(int64) != int64(len(schema)-1) {
+		return parquetFile{}, fmt.Errorf("root does not count every column")
+	}
+	for _, element := range schema[1:] {
+		fields := element.(map[int16]interface{})
+		out.columns = append(out.columns, fields[4].(string))
+		out.types = append(out.types, fields[1].(int64))
+	}
+
+	rowGroups := []interface{}{}
+	if v, ok := meta[4]; ok {
+		rowGroups = v.([]interface{})
+	}
+	out.rowGroups = len(rowGroups)
+
+	for _, rowGroup := range rowGroups {
+		rgFields := rowGroup.(map[int16]interface{})
+		rgRows := int(rgFields[3].(int64))
+		rows := make([]Row, rgRows)
+		for i := range rows {
+			rows[i] = make(Row, len(out.columns))
+		}
+
+		for col, chunk := range rgFields[1].([]interface{}) {
+			chunkMeta := chunk.(map[int16]interface{})[3].(map[int16]interface{})
+			if chunkMeta[5].(int64) != int64(rgRows) {
+				return parquetFile{}, fmt.Errorf("column %d has %d values", col, chunkMeta[5])
+			}
+
+			pageOffset := int(chunkMeta[9].(int64))
+			header, headerLength, err := readThriftStruct(data[pageOffset:])
+			if err != nil {
+				return parquetFile{}, err
+			}
+			if int64(headerLength)+header[2].(int64) != chunkMeta[7].(int64) {
+				return parquetFile{}, fmt.Errorf("column %d chunk size does not match its page", col)
+			}
+			if header[5].(map[int16]interface{})[1].(int64) != int64(rgRows) {
+				return parquetFile{}, fmt.Errorf("column %d page has the wrong value count", col)
+			}
+
+			page := data[pageOffset+headerLength : pageOffset+headerLength+int(header[2].(int64))]
+			for i := 0; i < rgRows; i++ {
+				switch out.types[col] {
+				case int64(parquetInt64):
+					rows[i][col] = int64(binary.LittleEndian.Uint64(page))
+					page = page[8:]
+				case int64(parquetDouble):
+					rows[i][col] = math.Float64frombits(binary.LittleEndian.Uint64(page))
+					page = page[8:]
+				default:
+					size := int(binary.LittleEndian.Uint32(page))
+					rows[i][col] = string(page[4 : 4+size])
+					page = page[4+size:]
+				}
+			}
+			if len(page) != 0 {
+				return parquetFile{}, fmt.Errorf("column %d page has %d trailing bytes", col, len(page))
+			}
+		}
+
+		out.rows = append(out.rows, rows...)
+	}
+
+	return out, nil
+}
+
+func newTestSchema() Schema {
+	return Schema{{Name: "id", Kind: IntColumn}, {Name: "price", Kind: FloatColumn}, {Name: "realm", Kind: StringColumn}}
+}
+
+func TestParquetRoundTrip(t *testing.T) {
+	buf := &bytes.Buffer{}
+	w, err := NewWriter(Parquet, newTestSchema(), buf)
+	if err != nil {
+		t.Fatalf("could not create writer: %s", err)
+	}
+
+	// spilling over two full row groups
+	total := 2*parquetRowGroupRows + 10
+	for i := 0; i < total; i++ {
+		if err := w.Write(Row{int64(i), float64(i) / 4, fmt.Sprintf("realm-%d", i%7)}); err != nil {
+			t.Fatalf("could not write row: %s", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("could not close writer: %s", err)
+	}
+
+	f, err := readParquet(buf.Bytes())
+	if err != nil {
+		t.Fatalf("could not read file: %s", err)
+	}
+
+	if f.rowGroups != 3 || f.numRows != int64(total) || len(f.rows) != total {
+		t.Fatalf("expected %d rows over 3 row groups, got %d rows over %d", total, f.numRows, f.rowGroups)
+	}
+	if f.columns[2] != "realm" || f.types[1] != int64(parquetDouble) {
+		t.Fatalf("unexpected schema: %v %v", f.columns, f.types)
+	}
+	for _, i := range []int{0, parquetRowGroupRows - 1, parquetRowGroupRows, total - 1} {
+		expected := Row{int64(i), float64(i) / 4, fmt.Sprintf("realm-%d", i%7)}
+		for col := range expected {
+			if f.rows[i][col] != expected[col] {
+				t.Fatalf("row %d: expected %v, got %v", i, expected, f.rows[i])
+			}
+		}
+	}
+}
+
+func TestParquetEmpty(t *testing.T) {
+	buf := &bytes.Buffer{}
+	w, err := NewWriter(Parquet, newTestSchema(), buf)
+	if err != nil {
+		t.Fatalf("could not create writer: %s", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("could not close writer: %s", err)
+	}
+
+	f, err := readParquet(buf.Bytes())
+	if err != nil {
+		t.Fatalf("could not read file: %s", err)
+	}
+	if f.numRows != 0 || f.rowGroups != 0 || len(f.columns) != 3 {
+		t.Fatalf("unexpected empty file: %+v", f)
+	}
+}
+
+func TestParquetRejectsMismatchedRows(t *testing.T) {
+	w, _ := NewWriter(Parquet, newTestSchema(), &bytes.Buffer{})
+	if err := w.Write(Row{int64(1)}); err == nil {
+		t.Fatal("expected a short row to be rejected")
+	}
+	if err := w.Write(Row{"1", 1.0, "realm"}); err == nil {
+		t.Fatal("expected a mistyped value to be rejected")
+	}
+}
@@ -0,0 +1,84 @@
+package export
+
+import (
+	"encoding/base64"
+	"encoding/json"
+)
+
+// chunkSize keeps each published chunk well under the default nats payload limit once base64-encoded
+const chunkSize = 512 * 1024
+
+func NewChunk(payload []byte) (Chunk, error) {
+	c := &Chunk{}
+	if err := json.Unmarshal(payload, &c); err != nil {
+		return Chunk{}, err
+	}
+
+	return *c, nil
+}
+
+// Chunk is a piece of an export's encoded file, where the last chunk is flagged as done or carries an error
+type Chunk struct {
+	Sequence int    `json:"sequence"`
+	Data     string `json:"data"`
+	Done     bool   `json:"done"`
+	Err      string `json:"error"`
+}
+
+func (c Chunk) EncodeForDelivery() ([]byte, error) {
+	return json.Marshal(c)
+}
+
+func (c Chunk) Decode() ([]byte, error) {
+	return base64.StdEncoding.DecodeString(c.Data)
+}
+
+// NewStream buffers written bytes into chunks handed to the publish func
+func NewStream(publish func(Chunk) error) *Stream {
+	return &Stream{publish: publish}
+}
+
+type Stream struct {
+	publish  func(Chunk) error
+	buf      []byte
+	sequence int
+}
+
+func (s *Stream) Write(p []byte) (int, error) {
+	s.buf = append(s.buf, p...)
+	for len(s.buf) >= chunkSize {
+		if err := s.flush(s.buf[:chunkSize], false, ""); err != nil {
+			return 0, err
+		}
+		s.buf = s.buf[chunkSize:]
+	}
+
+	return len(p), nil
+}
+
+func (s *Stream) flush(data []byte, done bool, errMessage string) error {
+	c := Chunk{
+		Sequence: s.sequence,
+		Data:     base64.StdEncoding.EncodeToString(data),
+		Done:     done,
+		Err:      errMessage,
+	}
+	s.sequence++
+
+	return s.publish(c)
+}
+
+// Finish publishes whatever is buffered as the last chunk
+func (s *Stream) Finish() error {
+	data := s.buf
+	s.buf = nil
+
+	return s.flush(data, true, "")
+}
+
+// Fail publishes the error as the last chunk, dropping whatever is buffered
+func (s *Stream) Fail(err error) error {
+	s.buf = nil
+
+	return s.flush(nil, true, err.Error())
+}
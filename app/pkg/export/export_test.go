@@ -0,0 +1,153 @@
+package export
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/blizzard"
+)
+
+func TestCSVWriter(t *testing.T) {
+	buf := &bytes.Buffer{}
+	w, err := NewWriter(CSV, newTestSchema(), buf)
+	if err != nil {
+		t.Fatalf("could not create writer: %s", err)
+	}
+
+	if err := w.Write(Row{int64(1), 2.5, "earthen-ring, us"}); err != nil {
+		t.Fatalf("could not write row: %s", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("could not close writer: %s", err)
+	}
+
+	expected := "id,price,realm\n1,2.5,\"earthen-ring, us\"\n"
+	if buf.String() != expected {
+		t.Fatalf("expected %q, got %q", expected, buf.String())
+	}
+}
+
+func TestNDJSONWriter(t *testing.T) {
+	buf := &bytes.Buffer{}
+	w, err := NewWriter(NDJSON, newTestSchema(), buf)
+	if err != nil {
+		t.Fatalf("could not create writer: %s", err)
+	}
+
+	for _, row := range []Row{{int64(1), 2.5, "a"}, {int64(2), 0.0, "b\"c"}} {
+		if err := w.Write(row); err != nil {
+			t.Fatalf("could not write row: %s", err)
+		}
+	}
+	if err := w.Write(Row{int64(3)}); err == nil {
+		t.Fatal("expected a short row to be rejected")
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("could not close writer: %s", err)
+	}
+
+	expected := `{"id":1,"price":2.5,"realm":"a"}` + "\n" + `{"id":2,"price":0,"realm":"b\"c"}` + "\n"
+	if buf.String() != expected {
+		t.Fatalf("expected %q, got %q", expected, buf.String())
+	}
+}
+
+func TestStreamChunks(t *testing.T) {
+	chunks := []Chunk{}
+	s := NewStream(func(c Chunk) error {
+		chunks = append(chunks, c)
+
+		return nil
+	})
+
+	if _, err := s.Write(bytes.Repeat([]byte("a"), chunkSize+10)); err != nil {
+		t.Fatalf("could not write: %s", err)
+	}
+	if _, err := s.Write([]byte("b")); err != nil {
+		t.Fatalf("could not write: %s", err)
+	}
+	if err := s.Finish(); err != nil {
+		t.Fatalf("could not finish: %s", err)
+	}
+
+	if len(chunks) != 2 || chunks[0].Done || !chunks[1].Done || chunks[1].Sequence != 1 {
+		t.Fatalf("expected a full chunk followed by the last one, got %d chunks", len(chunks))
+	}
+
+	data := []byte{}
+	for _, c := range chunks {
+		decoded, err := c.Decode()
+		if err != nil {
+			t.Fatalf("could not decode chunk: %s", err)
+		}
+
+		data = append(data, decoded...)
+	}
+	if len(data) != chunkSize+11 || !strings.HasSuffix(string(data), "ab") {
+		t.Fatalf("unexpected reassembled data of %d bytes", len(data))
+	}
+}
+
+func TestStreamFail(t *testing.T) {
+	chunks := []Chunk{}
+	s := NewStream(func(c Chunk) error {
+		chunks = append(chunks, c)
+
+		return nil
+	})
+
+	if _, err := s.Write([]byte("partial")); err != nil {
+		t.Fatalf("could not write: %s", err)
+	}
+	if err := s.Fail(errors.New("failed")); err != nil {
+		t.Fatalf("could not fail: %s", err)
+	}
+
+	if len(chunks) != 1 || !chunks[0].Done || chunks[0].Err != "failed" || len(chunks[0].Data) != 0 {
+		t.Fatalf("expected a single failed chunk without data, got %+v", chunks)
+	}
+}
+
+func TestRequestValidate(t *testing.T) {
+	req := Request{
+		Dataset:       PriceHistories,
+		Format:        Parquet,
+		RegionName:    "us",
+		RealmSlugs:    []blizzard.RealmSlug{"earthen-ring"},
+		StreamSubject: "export.test",
+	}
+	if err := req.Validate(); err != nil {
+		t.Fatalf("expected a valid request, got %s", err)
+	}
+
+	invalid := req
+	invalid.Format = "xlsx"
+	if err := invalid.Validate(); err == nil {
+		t.Fatal("expected an unknown format to be invalid")
+	}
+
+	invalid = req
+	invalid.RealmSlugs = nil
+	if err := invalid.Validate(); err == nil {
+		t.Fatal("expected blank realms to be invalid")
+	}
+
+	invalid = req
+	invalid.StreamSubject = ""
+	if err := invalid.Validate(); err == nil {
+		t.Fatal("expected a blank stream subject to be invalid")
+	}
+}
+
+func TestRequestItemFilter(t *testing.T) {
+	if !(Request{}).ItemFilter()(5) {
+		t.Fatal("expected blank items to match every item")
+	}
+
+	filter := Request{ItemIds: []blizzard.ItemID{1, 2}}.ItemFilter()
+	if !filter(2) || filter(3) {
+		t.Fatal("expected only the requested items to match")
+	}
+}
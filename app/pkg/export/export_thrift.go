@@ -0,0 +1,115 @@
+package export
+
+import (
+	"bytes"
+	"encoding/binary"
+)
+
+/*
+compact protocol type ids
+*/
+const (
+	thriftI32    byte = 5
+	thriftI64    byte = 6
+	thriftBinary byte = 8
+	thriftList   byte = 9
+	thriftStruct byte = 12
+)
+
+// thriftWriter encodes the thrift compact protocol, as used by parquet for its headers and footer
+type thriftWriter struct {
+	buf bytes.Buffer
+
+	// last field id written at each struct depth, since field ids are delta-encoded
+	lastFields []int16
+}
+
+func newThriftWriter() *thriftWriter {
+	return &thriftWriter{lastFields: []int16{0}}
+}
+
+func (t *thriftWriter) Bytes() []byte {
+	return t.buf.Bytes()
+}
+
+func (t *thriftWriter) varint(v uint64) {
+	encoded := make([]byte, binary.MaxVarintLen64)
+	t.buf.Write(encoded[:binary.PutUvarint(encoded, v)])
+}
+
+func (t *thriftWriter) zigzag(v int64) {
+	t.varint(uint64((v << 1) ^ (v >> 63)))
+}
+
+func (t *thriftWriter) fieldHeader(id int16, typ byte) {
+	depth := len(t.lastFields) - 1
+	delta := id - t.lastFields[depth]
+	if delta > 0 && delta <= 15 {
+		t.buf.WriteByte(byte(delta)<<4 | typ)
+	} else {
+		t.buf.WriteByte(typ)
+		t.zigzag(int64(id))
+	}
+	t.lastFields[depth] = id
+}
+
+func (t *thriftWriter) i32Field(id int16, v int32) {
+	t.fieldHeader(id, thriftI32)
+	t.zigzag(int64(v))
+}
+
+func (t *thriftWriter) i64Field(id int16, v int64) {
+	t.fieldHeader(id, thriftI64)
+	t.zigzag(v)
+}
+
+func (t *thriftWriter) stringValue(v string) {
+	t.varint(uint64(len(v)))
+	t.buf.WriteString(v)
+}
+
+func (t *thriftWriter) stringField(id int16, v string) {
+	t.fieldHeader(id, thriftBinary)
+	t.stringValue(v)
+}
+
+func (t *thriftWriter) listHeader(id int16, elemType byte, size int) {
+	t.fieldHeader(id, thriftList)
+	if size < 15 {
+		t.buf.WriteByte(byte(size)<<4 | elemType)
+
+		return
+	}
+
+	t.buf.WriteByte(0xf0 | elemType)
+	t.varint(uint64(size))
+}
+
+func (t *thriftWriter) i32ListField(id int16, values []int32) {
+	t.listHeader(id, thriftI32, len(values))
+	for _, v := range values {
+		t.zigzag(int64(v))
+	}
+}
+
+func (t *thriftWriter) stringListField(id int16, values []string) {
+	t.listHeader(id, thriftBinary, len(values))
+	for _, v := range values {
+		t.stringValue(v)
+	}
+}
+
+// structBegin opens a struct, where a list element has no field header
+func (t *thriftWriter) structBegin() {
+	t.lastFields = append(t.lastFields, 0)
+}
+
+func (t *thriftWriter) structField(id int16) {
+	t.fieldHeader(id, thriftStruct)
+	t.structBegin()
+}
+
+func (t *thriftWriter) structEnd() {
+	t.buf.WriteByte(0)
+	t.lastFields = t.lastFields[:len(t.lastFields)-1]
+}
@@ -0,0 +1,187 @@
+package export
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"testing"
+)
+
+// thriftReader decodes the thrift compact protocol into field maps, independently of the writer
+type thriftReader struct {
+	data []byte
+	pos  int
+}
+
+func (r *thriftReader) byte() (byte, error) {
+	if r.pos >= len(r.data) {
+		return 0, fmt.Errorf("unexpected end of data at %d", r.pos)
+	}
+	b := r.data[r.pos]
+	r.pos++
+
+	return b, nil
+}
+
+func (r *thriftReader) varint() (uint64, error) {
+	v, n := binary.Uvarint(r.data[r.pos:])
+	if n <= 0 {
+		return 0, fmt.Errorf("invalid varint at %d", r.pos)
+	}
+	r.pos += n
+
+	return v, nil
+}
+
+func (r *thriftReader) zigzag() (int64, error) {
+	v, err := r.varint()
+	if err != nil {
+		return 0, err
+	}
+
+	return int64(v>>1) ^ -int64(v&1), nil
+}
+
+func (r *thriftReader) value(typ byte) (interface{}, error) {
+	switch typ {
+	case 1:
+		return true, nil
+	case 2:
+		return false, nil
+	case 3:
+		return r.byte()
+	case 4, thriftI32, thriftI64:
+		return r.zigzag()
+	case 7:
+		if r.pos+8 > len(r.data) {
+			return nil, fmt.Errorf("unexpected end of data at %d", r.pos)
+		}
+		v := math.Float64frombits(binary.LittleEndian.Uint64(r.data[r.pos:]))
+		r.pos += 8
+
+		return v, nil
+	case thriftBinary:
+		size, err := r.varint()
+		if err != nil {
+			return nil, err
+		}
+		if r.pos+int(size) > len(r.data) {
+			return nil, fmt.Errorf("unexpected end of data at %d", r.pos)
+		}
+		v := string(r.data[r.pos : r.pos+int(size)])
+		r.pos += int(size)
+
+		return v, nil
+	case thriftList:
+		return r.list()
+	case thriftStruct:
+		return r.structValue()
+	default:
+		return nil, fmt.Errorf("unsupported type %d at %d", typ, r.pos)
+	}
+}
+
+func (r *thriftReader) list() ([]interface{}, error) {
+	header, err := r.byte()
+	if err != nil {
+		return nil, err
+	}
+
+	size := int(header >> 4)
+	if size == 15 {
+		longSize, err := r.varint()
+		if err != nil {
+			return nil, err
+		}
+		size = int(longSize)
+	}
+
+	out := []interface{}{}
+	for i := 0; i < size; i++ {
+		v, err := r.value(header & 0x0f)
+		if err != nil {
+			return nil, err
+		}
+
+		out = append(out, v)
+	}
+
+	return out, nil
+}
+
+func (r *thriftReader) structValue() (map[int16]interface{}, error) {
+	out := map[int16]interface{}{}
+	lastField := int16(0)
+	for {
+		header, err := r.byte()
+		if err != nil {
+			return nil, err
+		}
+		if header == 0 {
+			return out, nil
+		}
+
+		id := lastField + int16(header>>4)
+		if header>>4 == 0 {
+			longId, err := r.zigzag()
+			if err != nil {
+				return nil, err
+			}
+			id = int16(longId)
+		}
+		lastField = id
+
+		v, err := r.value(header & 0x0f)
+		if err != nil {
+			return nil, err
+		}
+		out[id] = v
+	}
+}
+
+func readThriftStruct(data []byte) (map[int16]interface{}, int, error) {
+	r := &thriftReader{data: data}
+	out, err := r.structValue()
+
+	return out, r.pos, err
+}
+
+func TestThriftWriterRoundTrip(t *testing.T) {
+	names := []string{}
+	for i := 0; i < 20; i++ {
+		names = append(names, fmt.Sprintf("name-%d", i))
+	}
+
+	w := newThriftWriter()
+	w.i32Field(1, -5)
+	w.i64Field(2, math.MaxInt64)
+	w.structField(4)
+	w.stringField(1, "nested")
+	w.structEnd()
+
+	// a field id too far from the last to be delta-encoded, and a list too long for the short header
+	w.stringListField(40, names)
+	w.i32ListField(41, []int32{1, -2, 3})
+	w.buf.WriteByte(0)
+
+	decoded, n, err := readThriftStruct(w.Bytes())
+	if err != nil {
+		t.Fatalf("could not read struct: %s", err)
+	}
+	if n != len(w.Bytes()) {
+		t.Fatalf("expected every byte to be read, read %d of %d", n, len(w.Bytes()))
+	}
+
+	if decoded[1] != int64(-5) || decoded[2] != int64(math.MaxInt64) {
+		t.Fatalf("unexpected integers: %v %v", decoded[1], decoded[2])
+	}
+	if nested := decoded[4].(map[int16]interface{}); nested[1] != "nested" {
+		t.Fatalf("unexpected nested struct: %v", nested)
+	}
+	if list := decoded[40].([]interface{}); len(list) != 20 || list[19] != "name-19" {
+		t.Fatalf("unexpected string list: %v", list)
+	}
+	if list := decoded[41].([]interface{}); len(list) != 3 || list[1] != int64(-2) {
+		t.Fatalf("unexpected i32 list: %v", list)
+	}
+}
@@ -0,0 +1,96 @@
+package flips
+
+import (
+	"sort"
+
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/blizzard"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/sotah"
+)
+
+// Flip gathers the auctions of an item whose buyout is below what a vendor pays for it
+type Flip struct {
+	ItemID    blizzard.ItemID `json:"item_id"`
+	SellPrice int64           `json:"sell_price"`
+
+	// lowest buyout-per among the flippable auctions
+	MinBuyoutPer float64 `json:"min_buyout_per"`
+
+	Auctions int   `json:"auctions"`
+	Quantity int64 `json:"quantity"`
+
+	// total buyout of the flippable auctions and what vendoring them returns on top of it
+	Cost   int64 `json:"cost"`
+	Profit int64 `json:"profit"`
+}
+
+type Flips []Flip
+
+// NewFlips finds the buyouts below vendor sell price, ranked by total profit
+func NewFlips(maList sotah.MiniAuctionList, iMap sotah.ItemsMap) Flips {
+	itemFlips := map[blizzard.ItemID]Flip{}
+	for _, mAuction := range maList {
+		if mAuction.Buyout == 0 || mAuction.Quantity == 0 {
+			continue
+		}
+
+		item, ok := iMap[mAuction.ItemID]
+		if !ok || item.SellPrice == 0 {
+			continue
+		}
+
+		sellPrice := int64(item.SellPrice)
+		if mAuction.Buyout >= sellPrice*mAuction.Quantity {
+			continue
+		}
+
+		f, ok := itemFlips[mAuction.ItemID]
+		if !ok {
+			f = Flip{ItemID: mAuction.ItemID, SellPrice: sellPrice}
+		}
+
+		buyoutPer := float64(mAuction.Buyout) / float64(mAuction.Quantity)
+		if f.MinBuyoutPer == 0 || buyoutPer < f.MinBuyoutPer {
+			f.MinBuyoutPer = buyoutPer
+		}
+
+		count := int64(len(mAuction.AucList))
+		f.Auctions += len(mAuction.AucList)
+		f.Quantity += mAuction.Quantity * count
+		f.Cost += mAuction.Buyout * count
+		f.Profit += (sellPrice*mAuction.Quantity - mAuction.Buyout) * count
+
+		itemFlips[mAuction.ItemID] = f
+	}
+
+	out := Flips{}
+	for _, f := range itemFlips {
+		out = append(out, f)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Profit != out[j].Profit {
+			return out[i].Profit > out[j].Profit
+		}
+
+		return out[i].ItemID < out[j].ItemID
+	})
+
+	return out
+}
+
+// TotalProfit sums the profit of every flip
+func (fs Flips) TotalProfit() int64 {
+	out := int64(0)
+	for _, f := range fs {
+		out += f.Profit
+	}
+
+	return out
+}
+
+func (fs Flips) Limit(count int) Flips {
+	if len(fs) <= count {
+		return fs
+	}
+
+	return fs[:count]
+}
@@ -0,0 +1,50 @@
+package flips
+
+import (
+	"testing"
+
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/blizzard"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/sotah"
+)
+
+func newTestItem(ID blizzard.ItemID, sellPrice int) sotah.Item {
+	return sotah.Item{Item: blizzard.Item{ID: ID, SellPrice: sellPrice}}
+}
+
+func TestNewFlips(t *testing.T) {
+	iMap := sotah.ItemsMap{1: newTestItem(1, 10), 2: newTestItem(2, 100), 3: newTestItem(3, 0)}
+	maList := sotah.MiniAuctionList{
+		{ItemID: 1, Buyout: 10, Quantity: 2, AucList: []int64{1, 2}},
+		{ItemID: 1, Buyout: 8, Quantity: 1, AucList: []int64{3}},
+		{ItemID: 1, Buyout: 20, Quantity: 2, AucList: []int64{4}},
+		{ItemID: 1, Bid: 1, Quantity: 1, AucList: []int64{5}},
+		{ItemID: 2, Buyout: 50, Quantity: 1, AucList: []int64{6}},
+		{ItemID: 3, Buyout: 1, Quantity: 1, AucList: []int64{7}},
+		{ItemID: 4, Buyout: 1, Quantity: 1, AucList: []int64{8}},
+	}
+
+	fs := NewFlips(maList, iMap)
+	if len(fs) != 2 {
+		t.Fatalf("expected flips for items with a sell price only, got %+v", fs)
+	}
+
+	// buyouts at or above the sell price and bid-only auctions are left out
+	expected := Flip{ItemID: 2, SellPrice: 100, MinBuyoutPer: 50, Auctions: 1, Quantity: 1, Cost: 50, Profit: 50}
+	if fs[0] != expected {
+		t.Fatalf("expected %+v to rank first, got %+v", expected, fs[0])
+	}
+	expected = Flip{ItemID: 1, SellPrice: 10, MinBuyoutPer: 5, Auctions: 3, Quantity: 5, Cost: 28, Profit: 22}
+	if fs[1] != expected {
+		t.Fatalf("expected %+v, got %+v", expected, fs[1])
+	}
+
+	if fs.TotalProfit() != 72 {
+		t.Fatalf("unexpected total profit: %d", fs.TotalProfit())
+	}
+	if limited := fs.Limit(1); len(limited) != 1 || limited[0].ItemID != 2 {
+		t.Fatalf("unexpected limited flips: %+v", limited)
+	}
+	if limited := fs.Limit(5); len(limited) != 2 {
+		t.Fatalf("expected a limit beyond the flips to keep them all, got %+v", limited)
+	}
+}
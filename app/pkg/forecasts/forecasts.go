@@ -0,0 +1,100 @@
+package forecasts
+
+import (
+	"sort"
+	"time"
+
+	"github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/pricelists"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/blizzard"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/sotah"
+)
+
+// MinimumSamples is the fewest history points an item needs before it is forecast
+const MinimumSamples = 24
+
+// bandZScore widens predictions into an 80% band
+const bandZScore = 1.2816
+
+// Point is a predicted median buyout-per and volume, each with a band
+type Point struct {
+	MedianBuyoutPer     float64 `json:"median_buyout_per"`
+	MedianBuyoutPerLow  float64 `json:"median_buyout_per_low"`
+	MedianBuyoutPerHigh float64 `json:"median_buyout_per_high"`
+	Volume              float64 `json:"volume"`
+	VolumeLow           float64 `json:"volume_low"`
+	VolumeHigh          float64 `json:"volume_high"`
+}
+
+type Points map[sotah.UnixTimestamp]Point
+
+type Forecast struct {
+	// number of history points the forecast was fit to, where too few leave the points blank
+	Samples int `json:"samples"`
+
+	// change in median buyout-per per day
+	Trend float64 `json:"trend"`
+
+	Points Points `json:"points"`
+}
+
+type ItemForecasts map[blizzard.ItemID]Forecast
+
+// NewForecast fits the history and predicts every hour of the days following the from time
+func NewForecast(pHistory pricelists.PriceHistory, from time.Time, days int) Forecast {
+	timestamps := make([]sotah.UnixTimestamp, 0, len(pHistory))
+	for targetTimestamp := range pHistory {
+		timestamps = append(timestamps, targetTimestamp)
+	}
+	sort.Slice(timestamps, func(i, j int) bool {
+		return timestamps[i] < timestamps[j]
+	})
+
+	priceObs := []observation{}
+	volumeObs := []observation{}
+	for _, targetTimestamp := range timestamps {
+		p := pHistory[targetTimestamp]
+		at := time.Unix(int64(targetTimestamp), 0)
+
+		volumeObs = append(volumeObs, observation{at: at, value: float64(p.Volume)})
+		if p.MedianBuyoutPer > 0 {
+			priceObs = append(priceObs, observation{at: at, value: p.MedianBuyoutPer})
+		}
+	}
+
+	out := Forecast{Samples: len(priceObs), Points: Points{}}
+	if len(priceObs) < MinimumSamples {
+		return out
+	}
+
+	origin := time.Unix(int64(timestamps[0]), 0)
+	priceModel := fitModel(origin, priceObs)
+	volumeModel := fitModel(origin, volumeObs)
+	out.Trend = priceModel.slope
+
+	start := from.UTC().Truncate(time.Hour).Add(time.Hour)
+	for i := 0; i < days*24; i++ {
+		at := start.Add(time.Duration(i) * time.Hour)
+
+		price, priceLow, priceHigh := priceModel.band(at, bandZScore)
+		volume, volumeLow, volumeHigh := volumeModel.band(at, bandZScore)
+		out.Points[sotah.UnixTimestamp(at.Unix())] = Point{
+			MedianBuyoutPer:     price,
+			MedianBuyoutPerLow:  priceLow,
+			MedianBuyoutPerHigh: priceHigh,
+			Volume:              volume,
+			VolumeLow:           volumeLow,
+			VolumeHigh:          volumeHigh,
+		}
+	}
+
+	return out
+}
+
+func NewItemForecasts(ipHistories pricelists.ItemPriceHistories, from time.Time, days int) ItemForecasts {
+	out := ItemForecasts{}
+	for itemId, pHistory := range ipHistories {
+		out[itemId] = NewForecast(pHistory, from, days)
+	}
+
+	return out
+}
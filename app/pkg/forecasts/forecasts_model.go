@@ -0,0 +1,119 @@
+package forecasts
+
+import (
+	"math"
+	"time"
+)
+
+type observation struct {
+	at    time.Time
+	value float64
+}
+
+// model is a linear trend with additive hour-of-day and day-of-week offsets
+type model struct {
+	origin    time.Time
+	intercept float64
+	slope     float64
+	hourly    [24]float64
+	weekday   [7]float64
+
+	// standard deviation of what the model leaves unexplained
+	spread float64
+}
+
+func (m model) days(at time.Time) float64 {
+	return at.Sub(m.origin).Hours() / 24
+}
+
+func (m model) trend(at time.Time) float64 {
+	return m.intercept + m.slope*m.days(at)
+}
+
+func (m model) seasonal(at time.Time) float64 {
+	at = at.UTC()
+
+	return m.hourly[at.Hour()] + m.weekday[int(at.Weekday())]
+}
+
+func (m model) predict(at time.Time) float64 {
+	return m.trend(at) + m.seasonal(at)
+}
+
+// band widens the prediction to the given z-score, where neither bound goes below zero
+func (m model) band(at time.Time, z float64) (float64, float64, float64) {
+	predicted := math.Max(0, m.predict(at))
+
+	return predicted, math.Max(0, predicted-z*m.spread), predicted + z*m.spread
+}
+
+// fitModel fits the trend by least squares before averaging residuals by hour and then by weekday
+func fitModel(origin time.Time, obs []observation) model {
+	m := model{origin: origin}
+	if len(obs) == 0 {
+		return m
+	}
+
+	n := float64(len(obs))
+	sumX, sumY, sumXX, sumXY := float64(0), float64(0), float64(0), float64(0)
+	for _, o := range obs {
+		x := m.days(o.at)
+		sumX += x
+		sumY += o.value
+		sumXX += x * x
+		sumXY += x * o.value
+	}
+
+	denominator := n*sumXX - sumX*sumX
+	if denominator != 0 {
+		m.slope = (n*sumXY - sumX*sumY) / denominator
+	}
+	m.intercept = (sumY - m.slope*sumX) / n
+
+	residuals := make([]float64, len(obs))
+	for i, o := range obs {
+		residuals[i] = o.value - m.trend(o.at)
+	}
+
+	// hour-of-day offsets
+	hourlyTotals, hourlyCounts := [24]float64{}, [24]int{}
+	for i, o := range obs {
+		hour := o.at.UTC().Hour()
+		hourlyTotals[hour] += residuals[i]
+		hourlyCounts[hour]++
+	}
+	for hour := range m.hourly {
+		if hourlyCounts[hour] < 2 {
+			continue
+		}
+
+		m.hourly[hour] = hourlyTotals[hour] / float64(hourlyCounts[hour])
+	}
+	for i, o := range obs {
+		residuals[i] -= m.hourly[o.at.UTC().Hour()]
+	}
+
+	// day-of-week offsets on what the hourly offsets leave over
+	weekdayTotals, weekdayCounts := [7]float64{}, [7]int{}
+	for i, o := range obs {
+		weekday := int(o.at.UTC().Weekday())
+		weekdayTotals[weekday] += residuals[i]
+		weekdayCounts[weekday]++
+	}
+	for weekday := range m.weekday {
+		if weekdayCounts[weekday] < 2 {
+			continue
+		}
+
+		m.weekday[weekday] = weekdayTotals[weekday] / float64(weekdayCounts[weekday])
+	}
+
+	sumSquares := float64(0)
+	for i, o := range obs {
+		remaining := residuals[i] - m.weekday[int(o.at.UTC().Weekday())]
+		sumSquares += remaining * remaining
+	}
+	m.spread = math.Sqrt(sumSquares / n)
+
+	return m
+}
@@ -0,0 +1,154 @@
+package forecasts
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/pricelists"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/sotah"
+)
+
+var testOrigin = time.Date(2019, 1, 7, 0, 0, 0, 0, time.UTC)
+
+func newTestObservations(days int, value func(at time.Time) float64) []observation {
+	out := []observation{}
+	for i := 0; i < days*24; i++ {
+		at := testOrigin.Add(time.Duration(i) * time.Hour)
+		out = append(out, observation{at: at, value: value(at)})
+	}
+
+	return out
+}
+
+func isNear(a float64, b float64, tolerance float64) bool {
+	return math.Abs(a-b) <= tolerance
+}
+
+func TestFitModelTrend(t *testing.T) {
+	m := fitModel(testOrigin, newTestObservations(14, func(at time.Time) float64 {
+		return 100 + 2*at.Sub(testOrigin).Hours()/24
+	}))
+
+	if !isNear(m.slope, 2, 1e-9) || !isNear(m.intercept, 100, 1e-9) || !isNear(m.spread, 0, 1e-9) {
+		t.Fatalf("expected the linear trend to be recovered exactly, got %+v", m)
+	}
+
+	at := testOrigin.Add(20 * 24 * time.Hour)
+	if predicted := m.predict(at); !isNear(predicted, 140, 1e-9) {
+		t.Fatalf("expected the trend to extrapolate, got %f", predicted)
+	}
+}
+
+func TestFitModelSeasonal(t *testing.T) {
+	m := fitModel(testOrigin, newTestObservations(14, func(at time.Time) float64 {
+		if at.Hour() == 12 {
+			return 150
+		}
+
+		return 100
+	}))
+
+	nextDay := testOrigin.Add(15 * 24 * time.Hour)
+	if predicted := m.predict(nextDay.Add(12 * time.Hour)); !isNear(predicted, 150, 1) {
+		t.Fatalf("expected the hourly offset to be predicted, got %f", predicted)
+	}
+	if predicted := m.predict(nextDay.Add(3 * time.Hour)); !isNear(predicted, 100, 1) {
+		t.Fatalf("expected other hours to be unaffected, got %f", predicted)
+	}
+}
+
+func TestFitModelSparse(t *testing.T) {
+	obs := []observation{
+		{at: testOrigin, value: 10},
+		{at: testOrigin.Add(time.Hour), value: 30},
+	}
+	m := fitModel(testOrigin, obs)
+
+	// a single observation per hour is not enough to set an hourly offset
+	for hour, offset := range m.hourly {
+		if offset != 0 {
+			t.Fatalf("expected no hourly offsets, got %f at %d", offset, hour)
+		}
+	}
+	if !isNear(m.slope, 20*24, 1e-9) {
+		t.Fatalf("expected the trend to pass through both observations, got %f", m.slope)
+	}
+
+	if m := fitModel(testOrigin, []observation{}); m.slope != 0 || m.intercept != 0 {
+		t.Fatalf("expected a blank model without observations, got %+v", m)
+	}
+}
+
+func TestModelBand(t *testing.T) {
+	m := model{origin: testOrigin, intercept: 10, slope: -1, spread: 2}
+
+	predicted, low, high := m.band(testOrigin.Add(24*time.Hour), 1.5)
+	if predicted != 9 || low != 6 || high != 12 {
+		t.Fatalf("expected the band to widen by the spread, got %f %f %f", predicted, low, high)
+	}
+
+	predicted, low, high = m.band(testOrigin.Add(30*24*time.Hour), 1.5)
+	if predicted != 0 || low != 0 || high != 3 {
+		t.Fatalf("expected the band to not go below zero, got %f %f %f", predicted, low, high)
+	}
+}
+
+func newTestPriceHistory(hours int, medianBuyoutPer func(i int) float64) pricelists.PriceHistory {
+	out := pricelists.PriceHistory{}
+	for i := 0; i < hours; i++ {
+		at := testOrigin.Add(time.Duration(i) * time.Hour)
+		out[sotah.UnixTimestamp(at.Unix())] = pricelists.Prices{MedianBuyoutPer: medianBuyoutPer(i), Volume: 5}
+	}
+
+	return out
+}
+
+func TestNewForecastTooFewSamples(t *testing.T) {
+	pHistory := newTestPriceHistory(MinimumSamples*2-2, func(i int) float64 {
+		if i%2 == 0 {
+			return 0
+		}
+
+		return 100
+	})
+
+	f := NewForecast(pHistory, testOrigin.Add(48*time.Hour), 2)
+	if f.Samples != MinimumSamples-1 || len(f.Points) != 0 {
+		t.Fatalf("expected zero prices to not count as samples, got %d with %d points", f.Samples, len(f.Points))
+	}
+
+	f = NewForecast(newTestPriceHistory(MinimumSamples-1, func(i int) float64 {
+		return 100
+	}), testOrigin, 2)
+	if f.Samples != MinimumSamples-1 || len(f.Points) != 0 {
+		t.Fatalf("expected too short a history to be left blank, got %d points", len(f.Points))
+	}
+}
+
+func TestNewForecast(t *testing.T) {
+	pHistory := newTestPriceHistory(14*24, func(i int) float64 {
+		return 100 + float64(i)/12
+	})
+
+	from := testOrigin.Add(14*24*time.Hour - 30*time.Minute)
+	f := NewForecast(pHistory, from, 2)
+
+	if f.Samples != 14*24 || !isNear(f.Trend, 2, 1e-9) {
+		t.Fatalf("expected the trend to be fit to every sample, got %d samples with %f", f.Samples, f.Trend)
+	}
+	if len(f.Points) != 2*24 {
+		t.Fatalf("expected a point per hour, got %d", len(f.Points))
+	}
+
+	first, ok := f.Points[sotah.UnixTimestamp(testOrigin.Add(14*24*time.Hour).Unix())]
+	if !ok {
+		t.Fatal("expected the points to begin on the hour after the from time")
+	}
+	if !isNear(first.MedianBuyoutPer, 128, 1e-6) || !isNear(first.Volume, 5, 1e-6) {
+		t.Fatalf("unexpected first point: %+v", first)
+	}
+	if first.MedianBuyoutPerLow > first.MedianBuyoutPer || first.MedianBuyoutPerHigh < first.MedianBuyoutPer {
+		t.Fatalf("expected the band to surround the prediction: %+v", first)
+	}
+}
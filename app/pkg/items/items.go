@@ -0,0 +1,124 @@
+package items
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/blizzard"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/blizzard/itembinds"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/sotah"
+)
+
+// bucketing
+func tokensBucketName() []byte {
+	return []byte("item-tokens")
+}
+
+func entriesBucketName() []byte {
+	return []byte("item-entries")
+}
+
+// keying
+func entryKeyName(ID blizzard.ItemID) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, uint64(ID))
+
+	return key
+}
+
+// token keys are the token, a separator and the item-id, so that every item holding a token sits under one prefix
+func tokenKeyName(token string, ID blizzard.ItemID) []byte {
+	return append(tokenKeyPrefix(token), entryKeyName(ID)...)
+}
+
+func tokenKeyPrefix(token string) []byte {
+	return append([]byte(token), 0)
+}
+
+func itemIdFromTokenKeyName(key []byte) blizzard.ItemID {
+	return blizzard.ItemID(binary.BigEndian.Uint64(key[len(key)-8:]))
+}
+
+// db, where the index cannot live in items.db itself: the library's items database opens that file and keeps its
+// bolt handle unexported, and bolt locks a file exclusively per open, so a second handle on items.db would block
+// forever
+func indexPath(dirPath string) string {
+	return fmt.Sprintf("%s/items-index.db", dirPath)
+}
+
+// Tokenize normalizes a name and splits it into its distinct words
+func Tokenize(name string) ([]string, error) {
+	normalizedName, err := sotah.NormalizeName(name)
+	if err != nil {
+		return []string{}, err
+	}
+
+	out := []string{}
+	seen := map[string]struct{}{}
+	for _, token := range strings.Fields(normalizedName) {
+		if _, ok := seen[token]; ok {
+			continue
+		}
+		seen[token] = struct{}{}
+
+		out = append(out, token)
+	}
+
+	return out, nil
+}
+
+func NewEntry(item sotah.Item) (Entry, error) {
+	name := item.NormalizedName
+	if len(name) == 0 {
+		name = item.Name
+	}
+
+	normalizedName, err := sotah.NormalizeName(name)
+	if err != nil {
+		return Entry{}, err
+	}
+
+	return Entry{
+		ID:             item.ID,
+		NormalizedName: normalizedName,
+		Name:           item.Name,
+		Icon:           item.Icon,
+		ItemClass:      item.ItemClass,
+		ItemSubClass:   item.ItemSubClass,
+		Quality:        item.Quality,
+		ItemLevel:      item.ItemLevel,
+		RequiredLevel:  item.RequiredLevel,
+		ItemBind:       item.ItemBind,
+	}, nil
+}
+
+func newEntryFromStorage(data []byte) (Entry, error) {
+	e := &Entry{}
+	if err := json.Unmarshal(data, &e); err != nil {
+		return Entry{}, err
+	}
+
+	return *e, nil
+}
+
+// Entry is the searchable summary of an item, held in the index so that queries never decode full items
+type Entry struct {
+	ID             blizzard.ItemID            `json:"item_id"`
+	NormalizedName string                     `json:"target"`
+	Name           string                     `json:"name"`
+	Icon           string                     `json:"icon"`
+	ItemClass      blizzard.ItemClassClass    `json:"item_class"`
+	ItemSubClass   blizzard.ItemSubClassClass `json:"item_sub_class"`
+	Quality        int                        `json:"quality"`
+	ItemLevel      int                        `json:"item_level"`
+	RequiredLevel  int                        `json:"required_level"`
+	ItemBind       itembinds.ItemBind         `json:"item_bind"`
+}
+
+func (e Entry) EncodeForStorage() ([]byte, error) {
+	return json.Marshal(e)
+}
+
+type Entries []Entry
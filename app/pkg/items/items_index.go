@@ -0,0 +1,194 @@
+package items
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/boltdb/bolt"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/blizzard"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/logging"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/sotah"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/util"
+)
+
+// NewIndex opens the index in its own file beside items.db, since the items database holds the only handle on
+// items.db and every item passes through the api state, which keeps both in step
+func NewIndex(dirPath string) (Index, error) {
+	if err := util.EnsureDirsExist([]string{dirPath}); err != nil {
+		return Index{}, err
+	}
+
+	logging.WithField("filepath", indexPath(dirPath)).Info("Initializing items index")
+
+	db, err := bolt.Open(indexPath(dirPath), 0600, nil)
+	if err != nil {
+		return Index{}, err
+	}
+
+	return Index{db}, nil
+}
+
+// Index is an inverted index of item name tokens alongside a searchable entry per item
+type Index struct {
+	db *bolt.DB
+}
+
+// IndexItems upserts the entries of the items, dropping the tokens of any previous name
+func (iIndex Index) IndexItems(iMap sotah.ItemsMap) error {
+	logging.WithField("items", len(iMap)).Debug("Indexing items")
+
+	return iIndex.db.Batch(func(tx *bolt.Tx) error {
+		tokensBucket, err := tx.CreateBucketIfNotExists(tokensBucketName())
+		if err != nil {
+			return err
+		}
+
+		entriesBucket, err := tx.CreateBucketIfNotExists(entriesBucketName())
+		if err != nil {
+			return err
+		}
+
+		for id, item := range iMap {
+			e, err := NewEntry(item)
+			if err != nil {
+				return err
+			}
+
+			// unnamed items cannot be searched for
+			if len(e.NormalizedName) == 0 {
+				continue
+			}
+
+			if previousValue := entriesBucket.Get(entryKeyName(id)); previousValue != nil {
+				previous, err := newEntryFromStorage(previousValue)
+				if err != nil {
+					return err
+				}
+
+				previousTokens, err := Tokenize(previous.NormalizedName)
+				if err != nil {
+					return err
+				}
+
+				for _, token := range previousTokens {
+					if err := tokensBucket.Delete(tokenKeyName(token, id)); err != nil {
+						return err
+					}
+				}
+			}
+
+			tokens, err := Tokenize(e.NormalizedName)
+			if err != nil {
+				return err
+			}
+
+			for _, token := range tokens {
+				if err := tokensBucket.Put(tokenKeyName(token, id), []byte{}); err != nil {
+					return err
+				}
+			}
+
+			encoded, err := e.EncodeForStorage()
+			if err != nil {
+				return err
+			}
+
+			if err := entriesBucket.Put(entryKeyName(id), encoded); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// Query matches every query token as a word prefix, filters and sorts the matches, and returns the requested page
+func (iIndex Index) Query(req QueryRequest) (QueryResponse, error) {
+	req, err := req.Normalize()
+	if err != nil {
+		return QueryResponse{}, err
+	}
+
+	tokens, err := Tokenize(req.Query)
+	if err != nil {
+		return QueryResponse{}, err
+	}
+
+	matches := Entries{}
+	err = iIndex.db.View(func(tx *bolt.Tx) error {
+		tokensBucket := tx.Bucket(tokensBucketName())
+		entriesBucket := tx.Bucket(entriesBucketName())
+		if tokensBucket == nil || entriesBucket == nil {
+			return nil
+		}
+
+		// without tokens every entry is a candidate
+		if len(tokens) == 0 {
+			return entriesBucket.ForEach(func(k, v []byte) error {
+				e, err := newEntryFromStorage(v)
+				if err != nil {
+					return err
+				}
+
+				if req.Matches(e) {
+					matches = append(matches, e)
+				}
+
+				return nil
+			})
+		}
+
+		// intersecting the item-ids found under each token
+		var candidates map[blizzard.ItemID]struct{}
+		for _, token := range tokens {
+			found := map[blizzard.ItemID]struct{}{}
+			prefix := []byte(token)
+			c := tokensBucket.Cursor()
+			for k, _ := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, _ = c.Next() {
+				id := itemIdFromTokenKeyName(k)
+				if candidates != nil {
+					if _, ok := candidates[id]; !ok {
+						continue
+					}
+				}
+
+				found[id] = struct{}{}
+			}
+
+			candidates = found
+			if len(candidates) == 0 {
+				return nil
+			}
+		}
+
+		for id := range candidates {
+			value := entriesBucket.Get(entryKeyName(id))
+			if value == nil {
+				continue
+			}
+
+			e, err := newEntryFromStorage(value)
+			if err != nil {
+				return err
+			}
+
+			if req.Matches(e) {
+				matches = append(matches, e)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return QueryResponse{}, err
+	}
+
+	req.Sort(matches, strings.Join(tokens, " "))
+
+	return QueryResponse{
+		Items: req.Paginate(matches),
+		Total: len(matches),
+		Page:  req.Page,
+		Count: req.Count,
+	}, nil
+}
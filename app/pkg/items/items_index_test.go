@@ -0,0 +1,143 @@
+package items
+
+import (
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/blizzard"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/sotah"
+)
+
+func newTestItem(ID blizzard.ItemID, name string, itemLevel int, quality int) sotah.Item {
+	return sotah.Item{Item: blizzard.Item{ID: ID, Name: name, ItemLevel: itemLevel, Quality: quality}}
+}
+
+// newTestIndex returns the index along with a func removing it
+func newTestIndex(t *testing.T) (Index, func()) {
+	dirPath, err := ioutil.TempDir("", "items-index")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cleanup := func() {
+		os.RemoveAll(dirPath)
+	}
+
+	iIndex, err := NewIndex(dirPath)
+	if err != nil {
+		cleanup()
+		t.Fatal(err)
+	}
+
+	err = iIndex.IndexItems(sotah.ItemsMap{
+		1: newTestItem(1, "Linen Cloth", 5, 1),
+		2: newTestItem(2, "Linen Bandage", 8, 1),
+		3: newTestItem(3, "Heavy Linen Bandage", 20, 1),
+		4: newTestItem(4, "Copper Bar", 10, 1),
+		5: newTestItem(5, "Thunderfury", 80, 5),
+	})
+	if err != nil {
+		cleanup()
+		t.Fatal(err)
+	}
+
+	return iIndex, cleanup
+}
+
+func queryIds(t *testing.T, iIndex Index, req QueryRequest) []blizzard.ItemID {
+	res, err := iIndex.Query(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out := []blizzard.ItemID{}
+	for _, e := range res.Items {
+		out = append(out, e.ID)
+	}
+
+	return out
+}
+
+func TestQueryMatchesTokenPrefixes(t *testing.T) {
+	iIndex, cleanup := newTestIndex(t)
+	defer cleanup()
+
+	expected := []blizzard.ItemID{2, 3}
+	if actual := queryIds(t, iIndex, QueryRequest{Query: "lin band"}); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+
+	if actual := queryIds(t, iIndex, QueryRequest{Query: "cloth bar"}); len(actual) != 0 {
+		t.Errorf("expected no items holding both tokens, got %v", actual)
+	}
+}
+
+func TestQueryRanksByRelevance(t *testing.T) {
+	iIndex, cleanup := newTestIndex(t)
+	defer cleanup()
+
+	expected := []blizzard.ItemID{1, 2, 3}
+	if actual := queryIds(t, iIndex, QueryRequest{Query: "linen"}); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+}
+
+func TestQueryFiltersSortsAndPages(t *testing.T) {
+	iIndex, cleanup := newTestIndex(t)
+	defer cleanup()
+
+	req := QueryRequest{MinItemLevel: 6, SortKind: SortByItemLevel, SortDirection: Descending, Count: 2}
+	if actual := queryIds(t, iIndex, req); !reflect.DeepEqual(actual, []blizzard.ItemID{5, 3}) {
+		t.Errorf("expected the first page to be [5 3], got %v", actual)
+	}
+
+	req.Page = 1
+	if actual := queryIds(t, iIndex, req); !reflect.DeepEqual(actual, []blizzard.ItemID{4, 2}) {
+		t.Errorf("expected the second page to be [4 2], got %v", actual)
+	}
+
+	quality := 5
+	if actual := queryIds(t, iIndex, QueryRequest{Quality: &quality}); !reflect.DeepEqual(actual, []blizzard.ItemID{5}) {
+		t.Errorf("expected only the legendary item, got %v", actual)
+	}
+}
+
+func TestIndexItemsDropsPreviousNames(t *testing.T) {
+	iIndex, cleanup := newTestIndex(t)
+	defer cleanup()
+
+	if err := iIndex.IndexItems(sotah.ItemsMap{4: newTestItem(4, "Tin Bar", 10, 1)}); err != nil {
+		t.Fatal(err)
+	}
+
+	if actual := queryIds(t, iIndex, QueryRequest{Query: "copper"}); len(actual) != 0 {
+		t.Errorf("expected the previous name to be dropped, got %v", actual)
+	}
+	if actual := queryIds(t, iIndex, QueryRequest{Query: "tin"}); !reflect.DeepEqual(actual, []blizzard.ItemID{4}) {
+		t.Errorf("expected the new name to be indexed, got %v", actual)
+	}
+}
+
+func TestQueryRequestNormalize(t *testing.T) {
+	req, err := QueryRequest{Count: 1000}.Normalize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if req.Count != maxQueryCount || req.SortKind != SortByName || req.SortDirection != Ascending {
+		t.Errorf("unexpected defaults: %+v", req)
+	}
+
+	invalid := []QueryRequest{
+		{Page: -1},
+		{Count: -1},
+		{MinItemLevel: 10, MaxItemLevel: 5},
+		{SortKind: "price"},
+		{SortDirection: "sideways"},
+	}
+	for _, req := range invalid {
+		if _, err := req.Normalize(); err == nil {
+			t.Errorf("expected %+v to be invalid", req)
+		}
+	}
+}
@@ -0,0 +1,224 @@
+package items
+
+import (
+	"encoding/json"
+	"errors"
+	"sort"
+	"strings"
+
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/blizzard"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/blizzard/itembinds"
+)
+
+const (
+	defaultQueryCount = 10
+	maxQueryCount     = 100
+)
+
+// SortKind - typehint for these enums
+type SortKind string
+
+/*
+SortKinds - orderings a query may request
+*/
+const (
+	SortByRelevance     SortKind = "relevance"
+	SortByName          SortKind = "name"
+	SortByItemLevel     SortKind = "item-level"
+	SortByRequiredLevel SortKind = "required-level"
+	SortByQuality       SortKind = "quality"
+)
+
+// SortDirection - typehint for these enums
+type SortDirection string
+
+/*
+SortDirections - directions a sort may run in
+*/
+const (
+	Ascending  SortDirection = "asc"
+	Descending SortDirection = "desc"
+)
+
+func NewQueryRequest(data []byte) (QueryRequest, error) {
+	req := &QueryRequest{}
+	if err := json.Unmarshal(data, &req); err != nil {
+		return QueryRequest{}, err
+	}
+
+	return *req, nil
+}
+
+type QueryRequest struct {
+	Query string `json:"query"`
+
+	// paging, where page is zero-based
+	Page  int `json:"page"`
+	Count int `json:"count"`
+
+	// optional filters, where nil or zero values do not filter
+	ItemClass        *blizzard.ItemClassClass    `json:"item_class"`
+	ItemSubClass     *blizzard.ItemSubClassClass `json:"item_sub_class"`
+	Quality          *int                        `json:"quality"`
+	MinItemLevel     int                         `json:"min_item_level"`
+	MaxItemLevel     int                         `json:"max_item_level"`
+	MinRequiredLevel int                         `json:"min_required_level"`
+	MaxRequiredLevel int                         `json:"max_required_level"`
+	ItemBind         *itembinds.ItemBind         `json:"item_bind"`
+
+	SortKind      SortKind      `json:"sort_kind"`
+	SortDirection SortDirection `json:"sort_direction"`
+}
+
+// Normalize validates the request and fills in default paging and sorting
+func (req QueryRequest) Normalize() (QueryRequest, error) {
+	if req.Page < 0 {
+		return QueryRequest{}, errors.New("page cannot be negative")
+	}
+
+	switch {
+	case req.Count < 0:
+		return QueryRequest{}, errors.New("count cannot be negative")
+	case req.Count == 0:
+		req.Count = defaultQueryCount
+	case req.Count > maxQueryCount:
+		req.Count = maxQueryCount
+	}
+
+	if req.MaxItemLevel > 0 && req.MinItemLevel > req.MaxItemLevel {
+		return QueryRequest{}, errors.New("min item level cannot exceed max item level")
+	}
+	if req.MaxRequiredLevel > 0 && req.MinRequiredLevel > req.MaxRequiredLevel {
+		return QueryRequest{}, errors.New("min required level cannot exceed max required level")
+	}
+
+	switch req.SortKind {
+	case "":
+		if len(strings.TrimSpace(req.Query)) > 0 {
+			req.SortKind = SortByRelevance
+		} else {
+			req.SortKind = SortByName
+		}
+	case SortByRelevance, SortByName, SortByItemLevel, SortByRequiredLevel, SortByQuality:
+	default:
+		return QueryRequest{}, errors.New("invalid sort kind")
+	}
+
+	switch req.SortDirection {
+	case "":
+		req.SortDirection = Ascending
+	case Ascending, Descending:
+	default:
+		return QueryRequest{}, errors.New("invalid sort direction")
+	}
+
+	return req, nil
+}
+
+// Matches checks an entry against every filter of the request
+func (req QueryRequest) Matches(e Entry) bool {
+	if req.ItemClass != nil && e.ItemClass != *req.ItemClass {
+		return false
+	}
+	if req.ItemSubClass != nil && e.ItemSubClass != *req.ItemSubClass {
+		return false
+	}
+	if req.Quality != nil && e.Quality != *req.Quality {
+		return false
+	}
+	if req.MinItemLevel > 0 && e.ItemLevel < req.MinItemLevel {
+		return false
+	}
+	if req.MaxItemLevel > 0 && e.ItemLevel > req.MaxItemLevel {
+		return false
+	}
+	if req.MinRequiredLevel > 0 && e.RequiredLevel < req.MinRequiredLevel {
+		return false
+	}
+	if req.MaxRequiredLevel > 0 && e.RequiredLevel > req.MaxRequiredLevel {
+		return false
+	}
+	if req.ItemBind != nil && e.ItemBind != *req.ItemBind {
+		return false
+	}
+
+	return true
+}
+
+// relevance ranks exact names first, then names starting with the query, then shorter names
+func relevance(normalizedQuery string, e Entry) int {
+	switch {
+	case e.NormalizedName == normalizedQuery:
+		return 0
+	case strings.HasPrefix(e.NormalizedName, normalizedQuery):
+		return 1
+	default:
+		return 2
+	}
+}
+
+// Sort orders the entries by the request's sort kind and direction, breaking ties by name and then id
+func (req QueryRequest) Sort(entries Entries, normalizedQuery string) {
+	compare := func(a Entry, b Entry) int {
+		switch req.SortKind {
+		case SortByRelevance:
+			if diff := relevance(normalizedQuery, a) - relevance(normalizedQuery, b); diff != 0 {
+				return diff
+			}
+
+			return len(a.NormalizedName) - len(b.NormalizedName)
+		case SortByItemLevel:
+			return a.ItemLevel - b.ItemLevel
+		case SortByRequiredLevel:
+			return a.RequiredLevel - b.RequiredLevel
+		case SortByQuality:
+			return a.Quality - b.Quality
+		default:
+			return strings.Compare(a.NormalizedName, b.NormalizedName)
+		}
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		a := entries[i]
+		b := entries[j]
+
+		result := compare(a, b)
+		if req.SortDirection == Descending {
+			result = -result
+		}
+		if result == 0 {
+			result = strings.Compare(a.NormalizedName, b.NormalizedName)
+		}
+		if result == 0 {
+			result = int(a.ID) - int(b.ID)
+		}
+
+		return result < 0
+	})
+}
+
+// Paginate slices the requested page out of the sorted entries
+func (req QueryRequest) Paginate(entries Entries) Entries {
+	start := req.Page * req.Count
+	if start >= len(entries) {
+		return Entries{}
+	}
+
+	end := start + req.Count
+	if end > len(entries) {
+		end = len(entries)
+	}
+
+	return entries[start:end]
+}
+
+type QueryResponse struct {
+	Items Entries `json:"items"`
+	Total int     `json:"total"`
+	Page  int     `json:"page"`
+	Count int     `json:"count"`
+}
+
+func (r QueryResponse) EncodeForDelivery() ([]byte, error) {
+	return json.Marshal(r)
+}
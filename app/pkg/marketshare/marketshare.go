@@ -0,0 +1,295 @@
+package marketshare
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/blizzard"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/sotah"
+)
+
+// keying
+func reportKeyName(ID blizzard.ItemID) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, uint64(ID))
+
+	return key
+}
+
+func reportKeyItemId(key []byte) blizzard.ItemID {
+	return blizzard.ItemID(binary.BigEndian.Uint64(key))
+}
+
+func activityKeyName(targetDate time.Time) []byte {
+	// big-endian so that keys iterate in day order
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, uint64(targetDate.Unix()))
+
+	return key
+}
+
+func activityKeyTime(key []byte) time.Time {
+	return time.Unix(int64(binary.BigEndian.Uint64(key)), 0)
+}
+
+func lastSnapshotKeyName() []byte {
+	return []byte("last-snapshot")
+}
+
+// bucketing
+func reportsBucketName() []byte {
+	return []byte("item-reports")
+}
+
+func activityBucketName(ID blizzard.ItemID) []byte {
+	return []byte(fmt.Sprintf("item-activity/%d", ID))
+}
+
+func metaBucketName() []byte {
+	return []byte("meta")
+}
+
+// db
+func databaseDirPath(dirPath string) string {
+	return fmt.Sprintf("%s/market-share", dirPath)
+}
+
+// number of sellers kept on each item's report
+const topSellersCount = 5
+
+// SellerKey tells apart same-name sellers of different realms, since the member realms of a connected group share one
+// database
+type SellerKey struct {
+	Owner      sotah.OwnerName
+	OwnerRealm string
+}
+
+type SellerShare struct {
+	Owner       sotah.OwnerName `json:"owner"`
+	OwnerRealm  string          `json:"owner_realm"`
+	Volume      int64           `json:"volume"`
+	Value       int64           `json:"value"`
+	VolumeShare float64         `json:"volume_share"`
+}
+
+func (seller SellerShare) Key() SellerKey {
+	return SellerKey{Owner: seller.Owner, OwnerRealm: seller.OwnerRealm}
+}
+
+func newReportFromBytes(data []byte) (Report, error) {
+	r := &Report{}
+	if err := json.Unmarshal(data, &r); err != nil {
+		return Report{}, err
+	}
+
+	return *r, nil
+}
+
+// Report describes the competition for an item on a realm as of its latest snapshot
+type Report struct {
+	TargetTime  int64         `json:"target_time"`
+	Sellers     int           `json:"sellers"`
+	TopSellers  []SellerShare `json:"top_sellers"`
+	TotalVolume int64         `json:"total_volume"`
+	TotalValue  int64         `json:"total_value"`
+
+	// herfindahl-hirschman index over volume shares, from near zero for a crowded market to 10000 for a monopoly
+	Concentration float64 `json:"concentration"`
+
+	LowestOwner      sotah.OwnerName `json:"lowest_owner"`
+	LowestOwnerRealm string          `json:"lowest_owner_realm"`
+	LowestBuyoutPer  float64         `json:"lowest_buyout_per"`
+}
+
+func (r Report) lowestSeller() SellerKey {
+	return SellerKey{Owner: r.LowestOwner, OwnerRealm: r.LowestOwnerRealm}
+}
+
+func (r Report) EncodeForStorage() ([]byte, error) {
+	return json.Marshal(r)
+}
+
+type ItemReports map[blizzard.ItemID]Report
+
+// HasOwners reports whether any mini-auction of a snapshot is owned, since snapshots of game-data api auctions carry
+// blank owners throughout
+func HasOwners(maList sotah.MiniAuctionList) bool {
+	for _, mAuction := range maList {
+		if len(mAuction.Owner) > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ItemSellerShares holds every seller of each item, where reports only keep the top sellers
+type ItemSellerShares map[blizzard.ItemID]map[SellerKey]SellerShare
+
+// NewItemSellerShares derives each seller's volume, value and share of volume of every item from a snapshot
+func NewItemSellerShares(maList sotah.MiniAuctionList) ItemSellerShares {
+	out := ItemSellerShares{}
+	totalVolumes := map[blizzard.ItemID]int64{}
+
+	for _, mAuction := range maList {
+		id := mAuction.ItemID
+		if _, ok := out[id]; !ok {
+			out[id] = map[SellerKey]SellerShare{}
+		}
+
+		volume := mAuction.Quantity * int64(len(mAuction.AucList))
+
+		key := SellerKey{Owner: mAuction.Owner, OwnerRealm: mAuction.OwnerRealm}
+		seller := out[id][key]
+		seller.Owner = mAuction.Owner
+		seller.OwnerRealm = mAuction.OwnerRealm
+		seller.Volume += volume
+		seller.Value += mAuction.Buyout * int64(len(mAuction.AucList))
+		out[id][key] = seller
+
+		totalVolumes[id] += volume
+	}
+
+	for id, sellers := range out {
+		if totalVolumes[id] == 0 {
+			continue
+		}
+
+		for key, seller := range sellers {
+			seller.VolumeShare = float64(seller.Volume) / float64(totalVolumes[id])
+			sellers[key] = seller
+		}
+	}
+
+	return out
+}
+
+// NewItemReports derives each item's sellers, concentration and lowest listing from a snapshot
+func NewItemReports(targetTime time.Time, maList sotah.MiniAuctionList) ItemReports {
+	out := ItemReports{}
+
+	for _, mAuction := range maList {
+		id := mAuction.ItemID
+		r, ok := out[id]
+		if !ok {
+			r = Report{TargetTime: targetTime.Unix()}
+		}
+
+		r.TotalVolume += mAuction.Quantity * int64(len(mAuction.AucList))
+		r.TotalValue += mAuction.Buyout * int64(len(mAuction.AucList))
+
+		if mAuction.Buyout > 0 {
+			buyoutPer := float64(mAuction.Buyout) / float64(mAuction.Quantity)
+			if r.LowestBuyoutPer == 0 || buyoutPer < r.LowestBuyoutPer {
+				r.LowestBuyoutPer = buyoutPer
+				r.LowestOwner = mAuction.Owner
+				r.LowestOwnerRealm = mAuction.OwnerRealm
+			}
+		}
+
+		out[id] = r
+	}
+
+	for id, sellerShares := range NewItemSellerShares(maList) {
+		r := out[id]
+
+		sellers := []SellerShare{}
+		for _, seller := range sellerShares {
+			r.Concentration += (seller.VolumeShare * 100) * (seller.VolumeShare * 100)
+
+			sellers = append(sellers, seller)
+		}
+
+		sort.Slice(sellers, func(i, j int) bool {
+			if sellers[i].Volume != sellers[j].Volume {
+				return sellers[i].Volume > sellers[j].Volume
+			}
+
+			if sellers[i].Owner != sellers[j].Owner {
+				return sellers[i].Owner < sellers[j].Owner
+			}
+
+			return sellers[i].OwnerRealm < sellers[j].OwnerRealm
+		})
+		if len(sellers) > topSellersCount {
+			sellers = sellers[:topSellersCount]
+		}
+
+		r.Sellers = len(sellerShares)
+		r.TopSellers = sellers
+		out[id] = r
+	}
+
+	return out
+}
+
+func newActivityFromBytes(data []byte) (Activity, error) {
+	a := &Activity{}
+	if err := json.Unmarshal(data, &a); err != nil {
+		return Activity{}, err
+	}
+
+	return *a, nil
+}
+
+// Activity counts competitive moves on an item across snapshots
+type Activity struct {
+	Snapshots     int `json:"snapshots"`
+	Undercuts     int `json:"undercuts"`
+	LowestChanges int `json:"lowest_changes"`
+}
+
+// NewItemActivities compares each item's report against its previous one, where any auction priced below the previous
+// lowest listing must be new, and counts as an undercut unless the previous lowest owner listed it
+func NewItemActivities(previous ItemReports, current ItemReports, maList sotah.MiniAuctionList) ItemActivities {
+	out := ItemActivities{}
+	for itemId, r := range current {
+		a := Activity{Snapshots: 1}
+
+		// the lowest listing changes hands when both snapshots have one and their owners differ
+		previousReport, ok := previous[itemId]
+		changed := ok && r.lowestSeller() != previousReport.lowestSeller()
+		if changed && len(r.LowestOwner) > 0 && len(previousReport.LowestOwner) > 0 {
+			a.LowestChanges++
+		}
+
+		out[itemId] = a
+	}
+
+	for _, mAuction := range maList {
+		previousReport, ok := previous[mAuction.ItemID]
+		if !ok || previousReport.LowestBuyoutPer == 0 {
+			continue
+		}
+
+		seller := SellerKey{Owner: mAuction.Owner, OwnerRealm: mAuction.OwnerRealm}
+		if mAuction.Buyout == 0 || seller == previousReport.lowestSeller() {
+			continue
+		}
+
+		if float64(mAuction.Buyout)/float64(mAuction.Quantity) < previousReport.LowestBuyoutPer {
+			a := out[mAuction.ItemID]
+			a.Undercuts += len(mAuction.AucList)
+			out[mAuction.ItemID] = a
+		}
+	}
+
+	return out
+}
+
+func (a Activity) Add(other Activity) Activity {
+	return Activity{
+		Snapshots:     a.Snapshots + other.Snapshots,
+		Undercuts:     a.Undercuts + other.Undercuts,
+		LowestChanges: a.LowestChanges + other.LowestChanges,
+	}
+}
+
+func (a Activity) EncodeForStorage() ([]byte, error) {
+	return json.Marshal(a)
+}
+
+type ItemActivities map[blizzard.ItemID]Activity
@@ -0,0 +1,202 @@
+package marketshare
+
+import (
+	"bytes"
+	"time"
+
+	"github.com/boltdb/bolt"
+	"github.com/sirupsen/logrus"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/blizzard"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/logging"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/sotah"
+)
+
+type Database struct {
+	db    *bolt.DB
+	realm sotah.Realm
+}
+
+// Persist replaces the item reports with those of the snapshot, adds the activity since the previous reports onto the
+// target day, and drops days beyond the retention limit
+func (mBase Database) Persist(targetTime time.Time, maList sotah.MiniAuctionList, retentionLimit time.Time) error {
+	current := NewItemReports(targetTime, maList)
+
+	logging.WithFields(logrus.Fields{
+		"db":    mBase.db.Path(),
+		"items": len(current),
+	}).Debug("Persisting market-share reports")
+
+	targetKey := activityKeyName(sotah.NormalizeTargetDate(targetTime))
+
+	return mBase.db.Update(func(tx *bolt.Tx) error {
+		metaBucket, err := tx.CreateBucketIfNotExists(metaBucketName())
+		if err != nil {
+			return err
+		}
+
+		// an out-of-order snapshot would count moves backwards
+		if value := metaBucket.Get(lastSnapshotKeyName()); value != nil && !targetTime.After(activityKeyTime(value)) {
+			return nil
+		}
+
+		// gathering previous reports and starting the reports afresh
+		previous := ItemReports{}
+		if reportsBucket := tx.Bucket(reportsBucketName()); reportsBucket != nil {
+			err := reportsBucket.ForEach(func(k, v []byte) error {
+				r, err := newReportFromBytes(v)
+				if err != nil {
+					return err
+				}
+
+				previous[reportKeyItemId(k)] = r
+
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+
+			if err := tx.DeleteBucket(reportsBucketName()); err != nil {
+				return err
+			}
+		}
+
+		reportsBucket, err := tx.CreateBucket(reportsBucketName())
+		if err != nil {
+			return err
+		}
+
+		for itemId, r := range current {
+			encoded, err := r.EncodeForStorage()
+			if err != nil {
+				return err
+			}
+
+			if err := reportsBucket.Put(reportKeyName(itemId), encoded); err != nil {
+				return err
+			}
+		}
+
+		// merging activity onto the existing activity for the day
+		for itemId, a := range NewItemActivities(previous, current, maList) {
+			bkt, err := tx.CreateBucketIfNotExists(activityBucketName(itemId))
+			if err != nil {
+				return err
+			}
+
+			if value := bkt.Get(targetKey); value != nil {
+				existing, err := newActivityFromBytes(value)
+				if err != nil {
+					return err
+				}
+
+				a = existing.Add(a)
+			}
+
+			encoded, err := a.EncodeForStorage()
+			if err != nil {
+				return err
+			}
+
+			if err := bkt.Put(targetKey, encoded); err != nil {
+				return err
+			}
+		}
+
+		if err := metaBucket.Put(lastSnapshotKeyName(), activityKeyName(targetTime)); err != nil {
+			return err
+		}
+
+		return mBase.prune(tx, retentionLimit)
+	})
+}
+
+// prune drops expired days and the activity buckets left empty by it
+func (mBase Database) prune(tx *bolt.Tx, retentionLimit time.Time) error {
+	activityPrefix := []byte("item-activity/")
+
+	emptyBuckets := [][]byte{}
+	err := tx.ForEach(func(name []byte, bkt *bolt.Bucket) error {
+		if !bytes.HasPrefix(name, activityPrefix) {
+			return nil
+		}
+
+		c := bkt.Cursor()
+		for k, _ := c.First(); k != nil && activityKeyTime(k).Before(retentionLimit); k, _ = c.Next() {
+			if err := c.Delete(); err != nil {
+				return err
+			}
+		}
+
+		if k, _ := c.First(); k == nil {
+			emptyBuckets = append(emptyBuckets, append([]byte{}, name...))
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, name := range emptyBuckets {
+		if err := tx.DeleteBucket(name); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ItemMarket pairs an item's latest report with its activity over the requested days
+type ItemMarket struct {
+	Report   Report   `json:"report"`
+	Activity Activity `json:"activity"`
+}
+
+type ItemMarkets map[blizzard.ItemID]ItemMarket
+
+// GetItemMarkets gathers the report and summed activity since the lower bound of each item currently listed
+func (mBase Database) GetItemMarkets(itemIds []blizzard.ItemID, lowerBound time.Time) (ItemMarkets, error) {
+	out := ItemMarkets{}
+
+	err := mBase.db.View(func(tx *bolt.Tx) error {
+		reportsBucket := tx.Bucket(reportsBucketName())
+		if reportsBucket == nil {
+			return nil
+		}
+
+		for _, itemId := range itemIds {
+			value := reportsBucket.Get(reportKeyName(itemId))
+			if value == nil {
+				continue
+			}
+
+			r, err := newReportFromBytes(value)
+			if err != nil {
+				return err
+			}
+
+			a := Activity{}
+			if bkt := tx.Bucket(activityBucketName(itemId)); bkt != nil {
+				c := bkt.Cursor()
+				for k, v := c.Seek(activityKeyName(lowerBound)); k != nil; k, v = c.Next() {
+					dayActivity, err := newActivityFromBytes(v)
+					if err != nil {
+						return err
+					}
+
+					a = a.Add(dayActivity)
+				}
+			}
+
+			out[itemId] = ItemMarket{Report: r, Activity: a}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return ItemMarkets{}, err
+	}
+
+	return out, nil
+}
@@ -0,0 +1,45 @@
+package marketshare
+
+import (
+	"time"
+
+	"github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/connectedrealms"
+	"github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/realmdb"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/blizzard"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/sotah"
+)
+
+func NewDatabases(dirPath string, stas sotah.Statuses, groups connectedrealms.Groups) (Databases, error) {
+	registry, err := realmdb.Open(databaseDirPath(dirPath), stas, groups)
+	if err != nil {
+		return Databases{}, err
+	}
+
+	mBases := Databases{}
+	for regionName, handles := range registry {
+		mBases[regionName] = map[blizzard.RealmSlug]Database{}
+		for realmSlug, handle := range handles {
+			mBases[regionName][realmSlug] = Database{handle.DB, handle.Realm}
+		}
+	}
+
+	return mBases, nil
+}
+
+type Databases map[blizzard.RegionName]map[blizzard.RealmSlug]Database
+
+// Record persists the market-share reports and activity of a freshly loaded snapshot, where a snapshot without owners
+// is not recorded
+func (mBases Databases) Record(
+	rea sotah.Realm,
+	targetTime time.Time,
+	maList sotah.MiniAuctionList,
+	retentionLimit time.Time,
+) error {
+	mBase, ok := mBases[rea.Region.Name][rea.Slug]
+	if !ok || !HasOwners(maList) {
+		return nil
+	}
+
+	return mBase.Persist(targetTime, maList, retentionLimit)
+}
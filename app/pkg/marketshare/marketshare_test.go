@@ -0,0 +1,152 @@
+package marketshare
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/realmdb"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/blizzard"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/sotah"
+)
+
+func newTestDatabase(t *testing.T) (Database, func()) {
+	handle, cleanup := realmdb.OpenTemp(t, "marketshare")
+
+	return Database{handle.DB, handle.Realm}, cleanup
+}
+
+func TestNewItemReports(t *testing.T) {
+	maList := sotah.MiniAuctionList{
+		{ItemID: 1, Owner: "a", Buyout: 30, Quantity: 3, AucList: []int64{1, 2}},
+		{ItemID: 1, Owner: "b", Buyout: 8, Quantity: 1, AucList: []int64{3}},
+		{ItemID: 1, Owner: "c", Bid: 5, Quantity: 1, AucList: []int64{4}},
+	}
+
+	r := NewItemReports(time.Unix(100, 0), maList)[1]
+	if r.Sellers != 3 || r.TotalVolume != 8 || r.TotalValue != 68 {
+		t.Fatalf("unexpected report totals: %+v", r)
+	}
+	if r.LowestOwner != "b" || r.LowestBuyoutPer != 8 {
+		t.Fatalf("expected b to hold the lowest listing, got %s at %f", r.LowestOwner, r.LowestBuyoutPer)
+	}
+	if r.TopSellers[0].Owner != "a" || r.TopSellers[0].VolumeShare != 0.75 {
+		t.Fatalf("expected a to lead with three quarters of volume, got %+v", r.TopSellers[0])
+	}
+
+	// 75^2 + 12.5^2 + 12.5^2
+	if r.Concentration < 5937.4 || r.Concentration > 5937.6 {
+		t.Fatalf("unexpected concentration: %f", r.Concentration)
+	}
+}
+
+func TestNewItemReportsTellsRealmsApart(t *testing.T) {
+	maList := sotah.MiniAuctionList{
+		{ItemID: 1, Owner: "a", OwnerRealm: "x", Buyout: 10, Quantity: 1, AucList: []int64{1, 2, 3}},
+		{ItemID: 1, Owner: "a", OwnerRealm: "y", Buyout: 5, Quantity: 1, AucList: []int64{4}},
+	}
+
+	// same-name sellers of different realms are separate sellers
+	shares := NewItemSellerShares(maList)[1]
+	if len(shares) != 2 || shares[SellerKey{Owner: "a", OwnerRealm: "y"}].VolumeShare != 0.25 {
+		t.Fatalf("unexpected seller shares: %+v", shares)
+	}
+
+	r := NewItemReports(time.Unix(100, 0), maList)[1]
+	if r.Sellers != 2 || r.TopSellers[0].OwnerRealm != "x" || r.LowestOwnerRealm != "y" {
+		t.Fatalf("unexpected report: %+v", r)
+	}
+
+	// and undercutting a same-name seller of another realm counts
+	previous := ItemReports{1: {LowestOwner: "a", LowestOwnerRealm: "x", LowestBuyoutPer: 10}}
+	if a := NewItemActivities(previous, ItemReports{1: r}, maList)[1]; a.Undercuts != 1 || a.LowestChanges != 1 {
+		t.Fatalf("unexpected activity: %+v", a)
+	}
+}
+
+func TestNewItemReportsKeepsTopSellers(t *testing.T) {
+	maList := sotah.MiniAuctionList{}
+	for i, owner := range []sotah.OwnerName{"a", "b", "c", "d", "e", "f", "g"} {
+		maList = append(maList, sotah.MiniAuctionList{
+			{ItemID: 1, Owner: owner, Buyout: 10, Quantity: int64(i + 1), AucList: []int64{int64(i)}},
+		}...)
+	}
+
+	r := NewItemReports(time.Unix(100, 0), maList)[1]
+	if r.Sellers != 7 || len(r.TopSellers) != topSellersCount || r.TopSellers[0].Owner != "g" {
+		t.Fatalf("unexpected top sellers: %+v", r.TopSellers)
+	}
+}
+
+func TestNewItemActivities(t *testing.T) {
+	previous := ItemReports{1: {LowestOwner: "a", LowestBuyoutPer: 10}}
+	maList := sotah.MiniAuctionList{
+		{ItemID: 1, Owner: "a", Buyout: 5, Quantity: 1, AucList: []int64{1}},
+		{ItemID: 1, Owner: "b", Buyout: 9, Quantity: 1, AucList: []int64{2, 3}},
+		{ItemID: 1, Owner: "c", Buyout: 11, Quantity: 1, AucList: []int64{4}},
+	}
+	current := NewItemReports(time.Unix(100, 0), maList)
+
+	// the previous lowest owner cutting their own price is not an undercut
+	a := NewItemActivities(previous, current, maList)[1]
+	if a.Snapshots != 1 || a.Undercuts != 2 || a.LowestChanges != 0 {
+		t.Fatalf("unexpected activity: %+v", a)
+	}
+
+	previous = ItemReports{1: {LowestOwner: "b", LowestBuyoutPer: 4}}
+	if a := NewItemActivities(previous, current, maList)[1]; a.LowestChanges != 1 || a.Undercuts != 0 {
+		t.Fatalf("expected the lowest listing to change hands, got %+v", a)
+	}
+}
+
+func TestDatabaseGetItemMarkets(t *testing.T) {
+	mBase, cleanup := newTestDatabase(t)
+	defer cleanup()
+
+	first := time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)
+	firstList := sotah.MiniAuctionList{{ItemID: 1, Owner: "a", Buyout: 10, Quantity: 1, AucList: []int64{1}}}
+	secondList := sotah.MiniAuctionList{{ItemID: 1, Owner: "b", Buyout: 5, Quantity: 1, AucList: []int64{2}}}
+
+	if err := mBase.Persist(first, firstList, time.Unix(0, 0)); err != nil {
+		t.Fatalf("could not persist: %s", err)
+	}
+	if err := mBase.Persist(first.Add(time.Hour), secondList, time.Unix(0, 0)); err != nil {
+		t.Fatalf("could not persist: %s", err)
+	}
+
+	markets, err := mBase.GetItemMarkets([]blizzard.ItemID{1, 2}, time.Unix(0, 0))
+	if err != nil {
+		t.Fatalf("could not get markets: %s", err)
+	}
+	if len(markets) != 1 {
+		t.Fatalf("expected only the listed item, got %d", len(markets))
+	}
+
+	m := markets[1]
+	if m.Report.LowestOwner != "b" || m.Activity.Snapshots != 2 || m.Activity.Undercuts != 1 {
+		t.Fatalf("unexpected market: %+v", m)
+	}
+}
+
+func TestDatabasesRecordSkipsSnapshotsWithoutOwners(t *testing.T) {
+	mBase, cleanup := newTestDatabase(t)
+	defer cleanup()
+
+	rea := sotah.Realm{Realm: blizzard.Realm{Slug: "earthen-ring"}, Region: sotah.Region{Name: "us"}}
+	mBases := Databases{"us": {"earthen-ring": mBase}}
+	maList := sotah.MiniAuctionList{{ItemID: 1, Buyout: 10, Quantity: 1, AucList: []int64{1}}}
+	if HasOwners(maList) {
+		t.Fatal("expected auctions with blank owners to have no owners")
+	}
+
+	if err := mBases.Record(rea, time.Unix(1000, 0), maList, time.Unix(0, 0)); err != nil {
+		t.Fatalf("could not record: %s", err)
+	}
+
+	markets, err := mBase.GetItemMarkets([]blizzard.ItemID{1}, time.Unix(0, 0))
+	if err != nil {
+		t.Fatalf("could not get markets: %s", err)
+	}
+	if len(markets) != 0 {
+		t.Fatalf("expected no markets without owners, got %+v", markets)
+	}
+}
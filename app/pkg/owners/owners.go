@@ -0,0 +1,221 @@
+package owners
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/blizzard"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/sotah"
+)
+
+// keying
+func snapshotKeyName(targetTime time.Time) []byte {
+	// big-endian so that keys iterate in time order
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, uint64(targetTime.Unix()))
+
+	return key
+}
+
+func snapshotKeyTime(key []byte) time.Time {
+	return time.Unix(int64(binary.BigEndian.Uint64(key)), 0)
+}
+
+func portfolioKeyName(ownerKey OwnerKey) []byte {
+	return []byte(ownerKey.String())
+}
+
+func portfolioKeyOwner(key []byte) OwnerKey {
+	return newOwnerKey(string(key))
+}
+
+func lastSnapshotKeyName() []byte {
+	return []byte("last-snapshot")
+}
+
+// bucketing
+func historyBucketPrefix() []byte {
+	return []byte("owner-history/")
+}
+
+func historyBucketName(ownerKey OwnerKey) []byte {
+	return append(historyBucketPrefix(), ownerKey.String()...)
+}
+
+func portfoliosBucketName() []byte {
+	return []byte("owner-portfolios")
+}
+
+func metaBucketName() []byte {
+	return []byte("meta")
+}
+
+// db
+func databaseDirPath(dirPath string) string {
+	return fmt.Sprintf("%s/owners", dirPath)
+}
+
+func newOwnerKey(name string) OwnerKey {
+	parts := strings.SplitN(name, "-", 2)
+	if len(parts) == 1 {
+		return OwnerKey{Name: sotah.OwnerName(parts[0])}
+	}
+
+	return OwnerKey{Name: sotah.OwnerName(parts[0]), Realm: parts[1]}
+}
+
+// OwnerKey tells apart same-name owners of different realms, since the member realms of a connected group share one
+// database
+type OwnerKey struct {
+	Name  sotah.OwnerName
+	Realm string
+}
+
+// String joins the name and realm as the game does, where the realm follows the first dash since names have none
+func (ownerKey OwnerKey) String() string {
+	return fmt.Sprintf("%s-%s", ownerKey.Name, ownerKey.Realm)
+}
+
+// Holding is an owner's listings of a single item
+type Holding struct {
+	Auctions     int     `json:"auctions"`
+	Quantity     int64   `json:"quantity"`
+	ListedValue  int64   `json:"listed_value"`
+	MinBuyoutPer float64 `json:"min_buyout_per"`
+}
+
+func newPortfolioFromBytes(data []byte) (Portfolio, error) {
+	p := &Portfolio{}
+	if err := json.Unmarshal(data, &p); err != nil {
+		return Portfolio{}, err
+	}
+
+	return *p, nil
+}
+
+// Portfolio is everything an owner currently lists, by item
+type Portfolio struct {
+	TargetTime int64                       `json:"target_time"`
+	Holdings   map[blizzard.ItemID]Holding `json:"holdings"`
+}
+
+func (p Portfolio) EncodeForStorage() ([]byte, error) {
+	return json.Marshal(p)
+}
+
+func (p Portfolio) ListedValue() int64 {
+	out := int64(0)
+	for _, h := range p.Holdings {
+		out += h.ListedValue
+	}
+
+	return out
+}
+
+func (p Portfolio) Auctions() int {
+	out := 0
+	for _, h := range p.Holdings {
+		out += h.Auctions
+	}
+
+	return out
+}
+
+type Portfolios map[OwnerKey]Portfolio
+
+// NewPortfolios groups the auctions by owner and realm and by item, valuing bid-only auctions at their bid, where
+// auctions without an owner are left out
+func NewPortfolios(targetTime time.Time, aucs blizzard.Auctions) Portfolios {
+	out := Portfolios{}
+	for _, auc := range aucs.Auctions {
+		if len(auc.Owner) == 0 {
+			continue
+		}
+
+		ownerKey := OwnerKey{Name: sotah.OwnerName(auc.Owner), Realm: auc.OwnerRealm}
+		p, ok := out[ownerKey]
+		if !ok {
+			p = Portfolio{TargetTime: targetTime.Unix(), Holdings: map[blizzard.ItemID]Holding{}}
+		}
+
+		h := p.Holdings[auc.Item]
+		h.Auctions++
+		h.Quantity += auc.Quantity
+		if auc.Buyout > 0 {
+			h.ListedValue += auc.Buyout
+
+			buyoutPer := float64(auc.Buyout) / float64(auc.Quantity)
+			if h.MinBuyoutPer == 0 || buyoutPer < h.MinBuyoutPer {
+				h.MinBuyoutPer = buyoutPer
+			}
+		} else {
+			h.ListedValue += auc.Bid
+		}
+		p.Holdings[auc.Item] = h
+
+		out[ownerKey] = p
+	}
+
+	return out
+}
+
+func newSnapshotFromBytes(data []byte) (Snapshot, error) {
+	s := &Snapshot{}
+	if err := json.Unmarshal(data, &s); err != nil {
+		return Snapshot{}, err
+	}
+
+	return *s, nil
+}
+
+// Snapshot summarizes an owner's listings at one intake and how their items changed since the last
+type Snapshot struct {
+	TargetTime    int64             `json:"target_time"`
+	ListedValue   int64             `json:"listed_value"`
+	Auctions      int               `json:"auctions"`
+	DistinctItems int               `json:"distinct_items"`
+	ItemsEntered  []blizzard.ItemID `json:"items_entered"`
+	ItemsLeft     []blizzard.ItemID `json:"items_left"`
+}
+
+// NewSnapshot compares the current portfolio against the previous one, either of which may be empty
+func NewSnapshot(targetTime time.Time, previous Portfolio, current Portfolio) Snapshot {
+	out := Snapshot{
+		TargetTime:    targetTime.Unix(),
+		ListedValue:   current.ListedValue(),
+		Auctions:      current.Auctions(),
+		DistinctItems: len(current.Holdings),
+		ItemsEntered:  []blizzard.ItemID{},
+		ItemsLeft:     []blizzard.ItemID{},
+	}
+
+	for itemId := range current.Holdings {
+		if _, ok := previous.Holdings[itemId]; !ok {
+			out.ItemsEntered = append(out.ItemsEntered, itemId)
+		}
+	}
+	for itemId := range previous.Holdings {
+		if _, ok := current.Holdings[itemId]; !ok {
+			out.ItemsLeft = append(out.ItemsLeft, itemId)
+		}
+	}
+
+	sort.Slice(out.ItemsEntered, func(i, j int) bool {
+		return out.ItemsEntered[i] < out.ItemsEntered[j]
+	})
+	sort.Slice(out.ItemsLeft, func(i, j int) bool {
+		return out.ItemsLeft[i] < out.ItemsLeft[j]
+	})
+
+	return out
+}
+
+func (s Snapshot) EncodeForStorage() ([]byte, error) {
+	return json.Marshal(s)
+}
+
+type Timeline []Snapshot
@@ -0,0 +1,216 @@
+package owners
+
+import (
+	"bytes"
+	"time"
+
+	"github.com/boltdb/bolt"
+	"github.com/sirupsen/logrus"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/logging"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/sotah"
+)
+
+type Database struct {
+	db    *bolt.DB
+	realm sotah.Realm
+}
+
+// Persist appends a snapshot for every owner listing now or at the previous intake, replaces their portfolios, and
+// drops snapshots beyond the retention limit
+func (oBase Database) Persist(targetTime time.Time, current Portfolios, retentionLimit time.Time) (int, error) {
+	logging.WithFields(logrus.Fields{
+		"db":     oBase.db.Path(),
+		"owners": len(current),
+	}).Debug("Persisting owner snapshots")
+
+	recorded := 0
+	err := oBase.db.Update(func(tx *bolt.Tx) error {
+		metaBucket, err := tx.CreateBucketIfNotExists(metaBucketName())
+		if err != nil {
+			return err
+		}
+
+		// an out-of-order snapshot would invert items entered and left
+		if value := metaBucket.Get(lastSnapshotKeyName()); value != nil && !targetTime.After(snapshotKeyTime(value)) {
+			return nil
+		}
+
+		portfoliosBucket, err := tx.CreateBucketIfNotExists(portfoliosBucketName())
+		if err != nil {
+			return err
+		}
+
+		// gathering previous portfolios
+		previous := Portfolios{}
+		err = portfoliosBucket.ForEach(func(k, v []byte) error {
+			p, err := newPortfolioFromBytes(v)
+			if err != nil {
+				return err
+			}
+
+			previous[portfolioKeyOwner(k)] = p
+
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		ownerKeys := map[OwnerKey]struct{}{}
+		for ownerKey := range previous {
+			ownerKeys[ownerKey] = struct{}{}
+		}
+		for ownerKey := range current {
+			ownerKeys[ownerKey] = struct{}{}
+		}
+
+		for ownerKey := range ownerKeys {
+			currentPortfolio, ok := current[ownerKey]
+			if !ok {
+				currentPortfolio = Portfolio{TargetTime: targetTime.Unix()}
+			}
+
+			historyBucket, err := tx.CreateBucketIfNotExists(historyBucketName(ownerKey))
+			if err != nil {
+				return err
+			}
+
+			encodedSnapshot, err := NewSnapshot(targetTime, previous[ownerKey], currentPortfolio).EncodeForStorage()
+			if err != nil {
+				return err
+			}
+
+			if err := historyBucket.Put(snapshotKeyName(targetTime), encodedSnapshot); err != nil {
+				return err
+			}
+
+			recorded++
+
+			// owners who have left the auction house keep their history but no portfolio
+			if len(currentPortfolio.Holdings) == 0 {
+				if err := portfoliosBucket.Delete(portfolioKeyName(ownerKey)); err != nil {
+					return err
+				}
+
+				continue
+			}
+
+			encodedPortfolio, err := currentPortfolio.EncodeForStorage()
+			if err != nil {
+				return err
+			}
+
+			if err := portfoliosBucket.Put(portfolioKeyName(ownerKey), encodedPortfolio); err != nil {
+				return err
+			}
+		}
+
+		if err := metaBucket.Put(lastSnapshotKeyName(), snapshotKeyName(targetTime)); err != nil {
+			return err
+		}
+
+		return oBase.prune(tx, retentionLimit)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return recorded, nil
+}
+
+// prune drops expired snapshots and the histories left empty by it
+func (oBase Database) prune(tx *bolt.Tx, retentionLimit time.Time) error {
+	historyPrefix := historyBucketPrefix()
+
+	emptyBuckets := [][]byte{}
+	err := tx.ForEach(func(name []byte, bkt *bolt.Bucket) error {
+		if !bytes.HasPrefix(name, historyPrefix) {
+			return nil
+		}
+
+		c := bkt.Cursor()
+		for k, _ := c.First(); k != nil && snapshotKeyTime(k).Before(retentionLimit); k, _ = c.Next() {
+			if err := c.Delete(); err != nil {
+				return err
+			}
+		}
+
+		if k, _ := c.First(); k == nil {
+			emptyBuckets = append(emptyBuckets, append([]byte{}, name...))
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, name := range emptyBuckets {
+		if err := tx.DeleteBucket(name); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GetTimeline gathers an owner's snapshots since the lower bound
+func (oBase Database) GetTimeline(ownerKey OwnerKey, lowerBound time.Time) (Timeline, error) {
+	out := Timeline{}
+
+	err := oBase.db.View(func(tx *bolt.Tx) error {
+		bkt := tx.Bucket(historyBucketName(ownerKey))
+		if bkt == nil {
+			return nil
+		}
+
+		c := bkt.Cursor()
+		for k, v := c.Seek(snapshotKeyName(lowerBound)); k != nil; k, v = c.Next() {
+			s, err := newSnapshotFromBytes(v)
+			if err != nil {
+				return err
+			}
+
+			out = append(out, s)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return Timeline{}, err
+	}
+
+	return out, nil
+}
+
+// GetPortfolio returns what the owner currently lists, and whether they list anything
+func (oBase Database) GetPortfolio(ownerKey OwnerKey) (Portfolio, bool, error) {
+	out := Portfolio{}
+	found := false
+
+	err := oBase.db.View(func(tx *bolt.Tx) error {
+		bkt := tx.Bucket(portfoliosBucketName())
+		if bkt == nil {
+			return nil
+		}
+
+		value := bkt.Get(portfolioKeyName(ownerKey))
+		if value == nil {
+			return nil
+		}
+		found = true
+
+		p, err := newPortfolioFromBytes(value)
+		if err != nil {
+			return err
+		}
+		out = p
+
+		return nil
+	})
+	if err != nil {
+		return Portfolio{}, false, err
+	}
+
+	return out, found, nil
+}
@@ -0,0 +1,50 @@
+package owners
+
+import (
+	"time"
+
+	"github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/connectedrealms"
+	"github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/realmdb"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/blizzard"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/sotah"
+)
+
+func NewDatabases(dirPath string, stas sotah.Statuses, groups connectedrealms.Groups) (Databases, error) {
+	registry, err := realmdb.Open(databaseDirPath(dirPath), stas, groups)
+	if err != nil {
+		return Databases{}, err
+	}
+
+	oBases := Databases{}
+	for regionName, handles := range registry {
+		oBases[regionName] = map[blizzard.RealmSlug]Database{}
+		for realmSlug, handle := range handles {
+			oBases[regionName][realmSlug] = Database{handle.DB, handle.Realm}
+		}
+	}
+
+	return oBases, nil
+}
+
+type Databases map[blizzard.RegionName]map[blizzard.RealmSlug]Database
+
+// Record groups the current auctions into portfolios and persists a snapshot per owner, where a snapshot without any
+// owned auction, as the game-data api serves, is not recorded rather than clearing the stored portfolios
+func (oBases Databases) Record(
+	rea sotah.Realm,
+	targetTime time.Time,
+	current blizzard.Auctions,
+	retentionLimit time.Time,
+) (int, error) {
+	oBase, ok := oBases[rea.Region.Name][rea.Slug]
+	if !ok {
+		return 0, nil
+	}
+
+	portfolios := NewPortfolios(targetTime, current)
+	if len(portfolios) == 0 {
+		return 0, nil
+	}
+
+	return oBase.Persist(targetTime, portfolios, retentionLimit)
+}
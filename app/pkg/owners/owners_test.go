@@ -0,0 +1,161 @@
+package owners
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/realmdb"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/blizzard"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/sotah"
+)
+
+func newTestRealm() sotah.Realm {
+	return sotah.Realm{
+		Realm:  blizzard.Realm{Slug: "earthen-ring"},
+		Region: sotah.Region{Name: "us"},
+	}
+}
+
+func newTestDatabase(t *testing.T) (Database, func()) {
+	handle, cleanup := realmdb.OpenTemp(t, "owners")
+
+	return Database{handle.DB, handle.Realm}, cleanup
+}
+
+func TestNewPortfolios(t *testing.T) {
+	aucs := blizzard.Auctions{Auctions: []blizzard.Auction{
+		{Item: 1, Owner: "a", OwnerRealm: "x", Buyout: 100, Quantity: 2},
+		{Item: 1, Owner: "a", OwnerRealm: "x", Buyout: 30, Quantity: 1},
+		{Item: 2, Owner: "a", OwnerRealm: "x", Bid: 40, Quantity: 1},
+		{Item: 1, Owner: "b", OwnerRealm: "x", Buyout: 10, Quantity: 1},
+		{Item: 1, Owner: "a", OwnerRealm: "y", Buyout: 5, Quantity: 1},
+	}}
+	a := OwnerKey{Name: "a", Realm: "x"}
+
+	// same-name owners of different realms hold separate portfolios
+	portfolios := NewPortfolios(time.Unix(100, 0), aucs)
+	if len(portfolios) != 3 {
+		t.Fatalf("expected three owners, got %d", len(portfolios))
+	}
+	if p := portfolios[OwnerKey{Name: "a", Realm: "y"}]; p.ListedValue() != 5 {
+		t.Fatalf("unexpected portfolio of the other realm: %+v", p)
+	}
+
+	h := portfolios[a].Holdings[1]
+	if h.Auctions != 2 || h.Quantity != 3 || h.ListedValue != 130 || h.MinBuyoutPer != 30 {
+		t.Fatalf("unexpected holding: %+v", h)
+	}
+
+	// bid-only auctions are valued at their bid and have no buyout-per
+	if h := portfolios[a].Holdings[2]; h.ListedValue != 40 || h.MinBuyoutPer != 0 {
+		t.Fatalf("unexpected bid-only holding: %+v", h)
+	}
+
+	if portfolios[a].ListedValue() != 170 || portfolios[a].Auctions() != 3 {
+		t.Fatalf("unexpected portfolio totals: %+v", portfolios[a])
+	}
+}
+
+func TestNewSnapshot(t *testing.T) {
+	previous := Portfolio{Holdings: map[blizzard.ItemID]Holding{1: {Auctions: 1}, 2: {Auctions: 1}}}
+	current := Portfolio{Holdings: map[blizzard.ItemID]Holding{2: {Auctions: 2}, 4: {Auctions: 1}, 3: {Auctions: 1}}}
+
+	s := NewSnapshot(time.Unix(100, 0), previous, current)
+	if !reflect.DeepEqual(s.ItemsEntered, []blizzard.ItemID{3, 4}) {
+		t.Fatalf("unexpected items entered: %v", s.ItemsEntered)
+	}
+	if !reflect.DeepEqual(s.ItemsLeft, []blizzard.ItemID{1}) {
+		t.Fatalf("unexpected items left: %v", s.ItemsLeft)
+	}
+	if s.Auctions != 4 || s.DistinctItems != 3 {
+		t.Fatalf("unexpected snapshot totals: %+v", s)
+	}
+}
+
+func TestDatabasePersist(t *testing.T) {
+	oBase, cleanup := newTestDatabase(t)
+	defer cleanup()
+
+	first := time.Unix(1000, 0)
+	second := first.Add(time.Hour)
+	firstAucs := blizzard.Auctions{Auctions: []blizzard.Auction{
+		{Item: 1, Owner: "a", OwnerRealm: "x", Buyout: 10, Quantity: 1},
+		{Item: 2, Owner: "b", OwnerRealm: "x", Buyout: 10, Quantity: 1},
+	}}
+	secondAucs := blizzard.Auctions{Auctions: []blizzard.Auction{
+		{Item: 3, Owner: "a", OwnerRealm: "x", Buyout: 10, Quantity: 1},
+		{Item: 4, Owner: "b", OwnerRealm: "y-z", Buyout: 10, Quantity: 1},
+	}}
+	a := OwnerKey{Name: "a", Realm: "x"}
+	b := OwnerKey{Name: "b", Realm: "x"}
+
+	if _, err := oBase.Persist(first, NewPortfolios(first, firstAucs), time.Unix(0, 0)); err != nil {
+		t.Fatalf("could not persist: %s", err)
+	}
+
+	// owners who left the auction house still get a snapshot
+	recorded, err := oBase.Persist(second, NewPortfolios(second, secondAucs), time.Unix(0, 0))
+	if err != nil {
+		t.Fatalf("could not persist: %s", err)
+	}
+	if recorded != 3 {
+		t.Fatalf("expected three snapshots, got %d", recorded)
+	}
+
+	// an out-of-order snapshot is ignored
+	if recorded, err := oBase.Persist(first, Portfolios{}, time.Unix(0, 0)); err != nil || recorded != 0 {
+		t.Fatalf("expected an out-of-order snapshot to be skipped, got %d %v", recorded, err)
+	}
+
+	timeline, err := oBase.GetTimeline(a, time.Unix(0, 0))
+	if err != nil {
+		t.Fatalf("could not get timeline: %s", err)
+	}
+	if len(timeline) != 2 || !reflect.DeepEqual(timeline[1].ItemsEntered, []blizzard.ItemID{3}) {
+		t.Fatalf("unexpected timeline: %+v", timeline)
+	}
+
+	if _, found, err := oBase.GetPortfolio(b); err != nil || found {
+		t.Fatalf("expected no portfolio for an owner who left, got %v %v", found, err)
+	}
+
+	// the same-name owner of another realm, whose realm may have a dash, is kept apart
+	p, found, err := oBase.GetPortfolio(OwnerKey{Name: "b", Realm: "y-z"})
+	if err != nil || !found || p.ListedValue() != 10 {
+		t.Fatalf("expected the portfolio of the other realm, got %+v %v %v", p, found, err)
+	}
+
+	// pruning drops expired snapshots along with the histories left empty
+	if _, err := oBase.Persist(second.Add(time.Hour), Portfolios{}, second); err != nil {
+		t.Fatalf("could not persist: %s", err)
+	}
+	timeline, err = oBase.GetTimeline(a, time.Unix(0, 0))
+	if err != nil {
+		t.Fatalf("could not get timeline: %s", err)
+	}
+	if len(timeline) != 2 || timeline[0].TargetTime != second.Unix() {
+		t.Fatalf("expected the first snapshot to be pruned, got %+v", timeline)
+	}
+}
+
+func TestDatabasesRecordSkipsAuctionsWithoutOwners(t *testing.T) {
+	oBase, cleanup := newTestDatabase(t)
+	defer cleanup()
+
+	oBases := Databases{"us": {"earthen-ring": oBase}}
+	aucs := blizzard.Auctions{Auctions: []blizzard.Auction{{Item: 1, Buyout: 10, Quantity: 1}}}
+	recorded, err := oBases.Record(newTestRealm(), time.Unix(1000, 0), aucs, time.Unix(0, 0))
+	if err != nil || recorded != 0 {
+		t.Fatalf("expected nothing to be recorded, got %d %v", recorded, err)
+	}
+	if _, found, err := oBase.GetPortfolio(OwnerKey{}); err != nil || found {
+		t.Fatalf("expected no portfolio for the blank owner, got %v %v", found, err)
+	}
+
+	aucs.Auctions = append(aucs.Auctions, blizzard.Auction{Item: 1, Owner: "a", Buyout: 10, Quantity: 1})
+	recorded, err = oBases.Record(newTestRealm(), time.Unix(2000, 0), aucs, time.Unix(0, 0))
+	if err != nil || recorded != 1 {
+		t.Fatalf("expected only the owned auctions to be recorded, got %d %v", recorded, err)
+	}
+}
@@ -0,0 +1,283 @@
+package pricelists
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/blizzard"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/sotah"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/util"
+)
+
+// keying
+func pricelistHistoryKeyName() []byte {
+	key := make([]byte, 8)
+	binary.LittleEndian.PutUint64(key, 1)
+
+	return key
+}
+
+// bucketing
+func pricelistHistoryBucketName(ID blizzard.ItemID) []byte {
+	return []byte(fmt.Sprintf("item-prices/%d", ID))
+}
+
+func variantHistoryBucketName(key VariantKey) []byte {
+	return []byte(fmt.Sprintf("variant-prices/%s", key))
+}
+
+func variantHistoryBucketPrefix() []byte {
+	return []byte("variant-prices/")
+}
+
+// variantHistoryItemBucketPrefix prefixes the buckets of every variant of an item
+func variantHistoryItemBucketPrefix(ID blizzard.ItemID) []byte {
+	return []byte(fmt.Sprintf("variant-prices/%d:", ID))
+}
+
+func variantHistoryBucketKey(name []byte) VariantKey {
+	return VariantKey(bytes.TrimPrefix(name, variantHistoryBucketPrefix()))
+}
+
+func pricelistHistoryBucketItemID(name []byte) (blizzard.ItemID, bool) {
+	var ID blizzard.ItemID
+	if _, err := fmt.Sscanf(string(name), "item-prices/%d", &ID); err != nil {
+		return 0, false
+	}
+
+	return ID, true
+}
+
+// db
+func pricelistHistoryDatabaseFilePath(
+	dirPath string,
+	regionName blizzard.RegionName,
+	realmSlug blizzard.RealmSlug,
+	targetTimestamp sotah.UnixTimestamp,
+) string {
+	return fmt.Sprintf(
+		"%s/pricelist-histories/%s/%s/%d.db",
+		dirPath,
+		regionName,
+		realmSlug,
+		targetTimestamp,
+	)
+}
+
+func pricelistHistoryRealmDirPath(dirPath string, regionName blizzard.RegionName, realmSlug blizzard.RealmSlug) string {
+	return fmt.Sprintf("%s/pricelist-histories/%s/%s", dirPath, regionName, realmSlug)
+}
+
+// Prices is a superset of sotah.Prices, so histories written by either decode into the other
+type Prices struct {
+	MinBuyoutPer     float64 `json:"min_buyout_per"`
+	MaxBuyoutPer     float64 `json:"max_buyout_per"`
+	AverageBuyoutPer float64 `json:"average_buyout_per"`
+	MedianBuyoutPer  float64 `json:"median_buyout_per"`
+	Volume           int64   `json:"volume"`
+
+	// weighted by the number of units listed at each buyout-per
+	VolumeWeightedAverageBuyoutPer float64 `json:"volume_weighted_average_buyout_per"`
+	P10BuyoutPer                   float64 `json:"p10_buyout_per"`
+	P25BuyoutPer                   float64 `json:"p25_buyout_per"`
+	P75BuyoutPer                   float64 `json:"p75_buyout_per"`
+	P90BuyoutPer                   float64 `json:"p90_buyout_per"`
+	MarketPrice                    float64 `json:"market_price"`
+}
+
+type buyoutUnits struct {
+	buyoutPer float64
+	units     int64
+}
+
+type buyoutUnitsList []buyoutUnits
+
+func (l buyoutUnitsList) totalUnits() int64 {
+	out := int64(0)
+	for _, bu := range l {
+		out += bu.units
+	}
+
+	return out
+}
+
+func (l buyoutUnitsList) weightedAverage() float64 {
+	total := float64(0)
+	units := int64(0)
+	for _, bu := range l {
+		total += bu.buyoutPer * float64(bu.units)
+		units += bu.units
+	}
+
+	if units == 0 {
+		return 0
+	}
+
+	return total / float64(units)
+}
+
+// percentile expects the list to be sorted by buyout-per and uses the nearest-rank method over units
+func (l buyoutUnitsList) percentile(p float64) float64 {
+	totalUnits := l.totalUnits()
+	if totalUnits == 0 {
+		return 0
+	}
+
+	rank := int64(math.Ceil(p / 100 * float64(totalUnits)))
+	if rank < 1 {
+		rank = 1
+	}
+
+	seen := int64(0)
+	for _, bu := range l {
+		seen += bu.units
+		if seen >= rank {
+			return bu.buyoutPer
+		}
+	}
+
+	return l[len(l)-1].buyoutPer
+}
+
+// marketPrice averages the units that fall within the interquartile fences, dropping outliers on either side
+func (l buyoutUnitsList) marketPrice(p25 float64, p75 float64) float64 {
+	spread := p75 - p25
+	lowerFence := p25 - 1.5*spread
+	upperFence := p75 + 1.5*spread
+
+	inliers := buyoutUnitsList{}
+	for _, bu := range l {
+		if bu.buyoutPer < lowerFence || bu.buyoutPer > upperFence {
+			continue
+		}
+
+		inliers = append(inliers, bu)
+	}
+
+	return inliers.weightedAverage()
+}
+
+// pricesBuilder gathers the buyouts of a group of auctions
+type pricesBuilder struct {
+	p          Prices
+	buyoutPers []float64
+	units      buyoutUnitsList
+}
+
+func (b *pricesBuilder) add(buyout int64, quantity int64, count int64) {
+	if buyout > 0 {
+		auctionBuyoutPer := float64(buyout / quantity)
+
+		b.buyoutPers = append(b.buyoutPers, auctionBuyoutPer)
+		b.units = append(b.units, buyoutUnits{buyoutPer: auctionBuyoutPer, units: quantity * count})
+
+		if b.p.MinBuyoutPer == 0 || auctionBuyoutPer < b.p.MinBuyoutPer {
+			b.p.MinBuyoutPer = auctionBuyoutPer
+		}
+		if b.p.MaxBuyoutPer == 0 || auctionBuyoutPer > b.p.MaxBuyoutPer {
+			b.p.MaxBuyoutPer = auctionBuyoutPer
+		}
+	}
+
+	b.p.Volume += quantity * count
+}
+
+func (b *pricesBuilder) prices() Prices {
+	p := b.p
+	if len(b.buyoutPers) == 0 {
+		return p
+	}
+
+	// gathering total and calculating the per-group average, as sotah.NewItemPrices does
+	buyouts := b.buyoutPers
+	total := float64(0)
+	for _, buyout := range buyouts {
+		total += buyout
+	}
+	p.AverageBuyoutPer = total / float64(len(buyouts))
+
+	// sorting buyouts and calculating median
+	sort.Float64s(buyouts)
+	middle := len(buyouts) / 2
+	if len(buyouts)%2 == 0 {
+		p.MedianBuyoutPer = (buyouts[middle-1] + buyouts[middle]) / 2
+	} else {
+		p.MedianBuyoutPer = buyouts[middle]
+	}
+
+	// calculating unit-weighted statistics
+	units := b.units
+	sort.Slice(units, func(i, j int) bool {
+		return units[i].buyoutPer < units[j].buyoutPer
+	})
+	p.VolumeWeightedAverageBuyoutPer = units.weightedAverage()
+	p.P10BuyoutPer = units.percentile(10)
+	p.P25BuyoutPer = units.percentile(25)
+	p.P75BuyoutPer = units.percentile(75)
+	p.P90BuyoutPer = units.percentile(90)
+	p.MarketPrice = units.marketPrice(p.P25BuyoutPer, p.P75BuyoutPer)
+
+	return p
+}
+
+func NewItemPrices(maList sotah.MiniAuctionList) ItemPrices {
+	builders := map[blizzard.ItemID]*pricesBuilder{}
+	for _, mAuction := range maList {
+		b, ok := builders[mAuction.ItemID]
+		if !ok {
+			b = &pricesBuilder{}
+			builders[mAuction.ItemID] = b
+		}
+
+		b.add(mAuction.Buyout, mAuction.Quantity, int64(len(mAuction.AucList)))
+	}
+
+	iPrices := ItemPrices{}
+	for id, b := range builders {
+		iPrices[id] = b.prices()
+	}
+
+	return iPrices
+}
+
+type ItemPrices map[blizzard.ItemID]Prices
+
+func (iPrices ItemPrices) ItemIds() []blizzard.ItemID {
+	out := []blizzard.ItemID{}
+	for ID := range iPrices {
+		out = append(out, ID)
+	}
+
+	return out
+}
+
+func NewPriceHistoryFromBytes(data []byte) (PriceHistory, error) {
+	gzipDecoded, err := util.GzipDecode(data)
+	if err != nil {
+		return PriceHistory{}, err
+	}
+
+	out := PriceHistory{}
+	if err := json.Unmarshal(gzipDecoded, &out); err != nil {
+		return PriceHistory{}, err
+	}
+
+	return out, nil
+}
+
+type PriceHistory map[sotah.UnixTimestamp]Prices
+
+func (pHistory PriceHistory) EncodeForPersistence() ([]byte, error) {
+	jsonEncoded, err := json.Marshal(pHistory)
+	if err != nil {
+		return []byte{}, err
+	}
+
+	return util.GzipEncode(jsonEncoded)
+}
+
+type ItemPriceHistories map[blizzard.ItemID]PriceHistory
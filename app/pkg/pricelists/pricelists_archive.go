@@ -0,0 +1,287 @@
+package pricelists
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/boltdb/bolt"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/blizzard"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/sotah"
+)
+
+// keying
+func archiveKeyName(targetTimestamp sotah.UnixTimestamp) []byte {
+	// big-endian so that keys iterate in day order
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, uint64(targetTimestamp))
+
+	return key
+}
+
+func archiveKeyTimestamp(key []byte) sotah.UnixTimestamp {
+	return sotah.UnixTimestamp(binary.BigEndian.Uint64(key))
+}
+
+// db
+func archiveDatabasePath(dirPath string, regionName blizzard.RegionName, realmSlug blizzard.RealmSlug) string {
+	return fmt.Sprintf("%s/pricelist-histories-archive/%s/%s.db", dirPath, regionName, realmSlug)
+}
+
+func archiveRegionDirPath(dirPath string, regionName blizzard.RegionName) string {
+	return fmt.Sprintf("%s/pricelist-histories-archive/%s", dirPath, regionName)
+}
+
+// CompactDaily reduces the history to a single point per day, keyed by the start of the day
+func (pHistory PriceHistory) CompactDaily() PriceHistory {
+	days := map[sotah.UnixTimestamp][]Prices{}
+	for targetTimestamp, p := range pHistory {
+		day := sotah.UnixTimestamp(sotah.NormalizeTargetDate(time.Unix(int64(targetTimestamp), 0)).Unix())
+		days[day] = append(days[day], p)
+	}
+
+	out := PriceHistory{}
+	for day, points := range days {
+		out[day] = compactPrices(points)
+	}
+
+	return out
+}
+
+// compactPrices keeps the extremes, averages the prices and sums the volume, so that a compacted day rolls up to the
+// same bucket as the raw points it replaces. Bid-only points carry volume but no prices, so they are left out of the
+// averages the same way RollUp leaves them out.
+func compactPrices(points []Prices) Prices {
+	out := Prices{}
+	totals := Prices{}
+	pricedCount := 0
+	marketPriceCount := 0
+	for _, p := range points {
+		out.Volume += p.Volume
+
+		if p.MarketPrice > 0 {
+			totals.MarketPrice += p.MarketPrice
+			marketPriceCount++
+		}
+
+		if p.MinBuyoutPer == 0 {
+			continue
+		}
+
+		if out.MinBuyoutPer == 0 || p.MinBuyoutPer < out.MinBuyoutPer {
+			out.MinBuyoutPer = p.MinBuyoutPer
+		}
+		if p.MaxBuyoutPer > out.MaxBuyoutPer {
+			out.MaxBuyoutPer = p.MaxBuyoutPer
+		}
+
+		totals.AverageBuyoutPer += p.AverageBuyoutPer
+		totals.MedianBuyoutPer += p.MedianBuyoutPer
+		totals.VolumeWeightedAverageBuyoutPer += p.VolumeWeightedAverageBuyoutPer
+		totals.P10BuyoutPer += p.P10BuyoutPer
+		totals.P25BuyoutPer += p.P25BuyoutPer
+		totals.P75BuyoutPer += p.P75BuyoutPer
+		totals.P90BuyoutPer += p.P90BuyoutPer
+		pricedCount++
+	}
+
+	if pricedCount > 0 {
+		count := float64(pricedCount)
+		out.AverageBuyoutPer = totals.AverageBuyoutPer / count
+		out.MedianBuyoutPer = totals.MedianBuyoutPer / count
+		out.VolumeWeightedAverageBuyoutPer = totals.VolumeWeightedAverageBuyoutPer / count
+		out.P10BuyoutPer = totals.P10BuyoutPer / count
+		out.P25BuyoutPer = totals.P25BuyoutPer / count
+		out.P75BuyoutPer = totals.P75BuyoutPer / count
+		out.P90BuyoutPer = totals.P90BuyoutPer / count
+	}
+	if marketPriceCount > 0 {
+		out.MarketPrice = totals.MarketPrice / float64(marketPriceCount)
+	}
+
+	return out
+}
+
+func newArchiveDatabase(
+	dirPath string,
+	regionName blizzard.RegionName,
+	realmSlug blizzard.RealmSlug,
+) (ArchiveDatabase, error) {
+	db, err := bolt.Open(archiveDatabasePath(dirPath, regionName, realmSlug), 0600, nil)
+	if err != nil {
+		return ArchiveDatabase{}, err
+	}
+
+	return ArchiveDatabase{db}, nil
+}
+
+// ArchiveDatabase holds a realm's daily-resolution points for shards that have been compacted
+type ArchiveDatabase struct {
+	db *bolt.DB
+}
+
+func (aBase ArchiveDatabase) persistItemPriceHistories(ipHistories ItemPriceHistories) error {
+	return aBase.db.Batch(func(tx *bolt.Tx) error {
+		for itemId, pHistory := range ipHistories {
+			bkt, err := tx.CreateBucketIfNotExists(pricelistHistoryBucketName(itemId))
+			if err != nil {
+				return err
+			}
+
+			for targetTimestamp, p := range pHistory {
+				encoded, err := json.Marshal(p)
+				if err != nil {
+					return err
+				}
+
+				if err := bkt.Put(archiveKeyName(targetTimestamp), encoded); err != nil {
+					return err
+				}
+			}
+		}
+
+		return nil
+	})
+}
+
+func (aBase ArchiveDatabase) getItemPriceHistory(
+	itemId blizzard.ItemID,
+	lowerBounds time.Time,
+	upperBounds time.Time,
+) (PriceHistory, error) {
+	out := PriceHistory{}
+
+	err := aBase.db.View(func(tx *bolt.Tx) error {
+		bkt := tx.Bucket(pricelistHistoryBucketName(itemId))
+		if bkt == nil {
+			return nil
+		}
+
+		c := bkt.Cursor()
+		lowerKey := archiveKeyName(sotah.UnixTimestamp(lowerBounds.Unix()))
+		for k, v := c.Seek(lowerKey); k != nil; k, v = c.Next() {
+			targetTimestamp := archiveKeyTimestamp(k)
+			if int64(targetTimestamp) > upperBounds.Unix() {
+				break
+			}
+
+			p := Prices{}
+			if err := json.Unmarshal(v, &p); err != nil {
+				return err
+			}
+
+			out[targetTimestamp] = p
+		}
+
+		return nil
+	})
+	if err != nil {
+		return PriceHistory{}, err
+	}
+
+	return out, nil
+}
+
+func (aBase ArchiveDatabase) persistVariantPriceHistories(vpHistories VariantPriceHistories) error {
+	return aBase.db.Batch(func(tx *bolt.Tx) error {
+		for key, pHistory := range vpHistories {
+			bkt, err := tx.CreateBucketIfNotExists(variantHistoryBucketName(key))
+			if err != nil {
+				return err
+			}
+
+			for targetTimestamp, p := range pHistory {
+				encoded, err := json.Marshal(p)
+				if err != nil {
+					return err
+				}
+
+				if err := bkt.Put(archiveKeyName(targetTimestamp), encoded); err != nil {
+					return err
+				}
+			}
+		}
+
+		return nil
+	})
+}
+
+// getItemVariantPriceHistories gathers the history of every variant of an item between the bounds
+func (aBase ArchiveDatabase) getItemVariantPriceHistories(
+	itemId blizzard.ItemID,
+	lowerBounds time.Time,
+	upperBounds time.Time,
+) (VariantPriceHistories, error) {
+	out := VariantPriceHistories{}
+	prefix := variantHistoryItemBucketPrefix(itemId)
+
+	err := aBase.db.View(func(tx *bolt.Tx) error {
+		buckets := tx.Cursor()
+		for name, _ := buckets.Seek(prefix); name != nil && bytes.HasPrefix(name, prefix); name, _ = buckets.Next() {
+			pHistory := PriceHistory{}
+
+			c := tx.Bucket(name).Cursor()
+			lowerKey := archiveKeyName(sotah.UnixTimestamp(lowerBounds.Unix()))
+			for k, v := c.Seek(lowerKey); k != nil; k, v = c.Next() {
+				targetTimestamp := archiveKeyTimestamp(k)
+				if int64(targetTimestamp) > upperBounds.Unix() {
+					break
+				}
+
+				p := Prices{}
+				if err := json.Unmarshal(v, &p); err != nil {
+					return err
+				}
+
+				pHistory[targetTimestamp] = p
+			}
+
+			if len(pHistory) > 0 {
+				out[variantHistoryBucketKey(name)] = pHistory
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return VariantPriceHistories{}, err
+	}
+
+	return out, nil
+}
+
+func (aBase ArchiveDatabase) getItemIds() ([]blizzard.ItemID, error) {
+	out := []blizzard.ItemID{}
+
+	err := aBase.db.View(func(tx *bolt.Tx) error {
+		return tx.ForEach(func(name []byte, bkt *bolt.Bucket) error {
+			if itemId, ok := pricelistHistoryBucketItemID(name); ok {
+				out = append(out, itemId)
+			}
+
+			return nil
+		})
+	})
+	if err != nil {
+		return []blizzard.ItemID{}, err
+	}
+
+	return out, nil
+}
+
+func (aBase ArchiveDatabase) prune(limit time.Time) error {
+	return aBase.db.Update(func(tx *bolt.Tx) error {
+		return tx.ForEach(func(name []byte, bkt *bolt.Bucket) error {
+			c := bkt.Cursor()
+			for k, _ := c.First(); k != nil && int64(archiveKeyTimestamp(k)) < limit.Unix(); k, _ = c.Next() {
+				if err := c.Delete(); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		})
+	})
+}
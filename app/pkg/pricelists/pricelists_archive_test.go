@@ -0,0 +1,95 @@
+package pricelists
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/sotah"
+)
+
+func TestCompactPrices(t *testing.T) {
+	actual := compactPrices([]Prices{
+		{MinBuyoutPer: 0, MaxBuyoutPer: 30, MarketPrice: 20, Volume: 4},
+		{MinBuyoutPer: 10, MaxBuyoutPer: 50, MarketPrice: 30, Volume: 6},
+		{MinBuyoutPer: 15, MaxBuyoutPer: 40, MarketPrice: 40, Volume: 2},
+	})
+
+	if actual.MinBuyoutPer != 10 || actual.MaxBuyoutPer != 50 {
+		t.Errorf("expected extremes of 10 and 50, got %f and %f", actual.MinBuyoutPer, actual.MaxBuyoutPer)
+	}
+	if actual.MarketPrice != 30 {
+		t.Errorf("expected averaged market price of 30, got %f", actual.MarketPrice)
+	}
+	if actual.Volume != 12 {
+		t.Errorf("expected summed volume of 12, got %d", actual.Volume)
+	}
+}
+
+func TestCompactDailyRollsUpLikeRawPoints(t *testing.T) {
+	day := time.Date(2019, 3, 4, 0, 0, 0, 0, time.Local)
+	pHistory := PriceHistory{}
+	for hour := 0; hour < 24; hour++ {
+		targetTimestamp := sotah.UnixTimestamp(day.Add(time.Duration(hour) * time.Hour).Unix())
+		pHistory[targetTimestamp] = Prices{MinBuyoutPer: 10, MarketPrice: 10, Volume: 5}
+	}
+
+	compacted := pHistory.CompactDaily()
+	if len(compacted) != 1 {
+		t.Fatalf("expected a single daily point, got %d", len(compacted))
+	}
+
+	rawVolume := int64(0)
+	for _, bucket := range pHistory.RollUp(Weekly) {
+		rawVolume += bucket.Volume
+	}
+	compactedVolume := int64(0)
+	for _, bucket := range compacted.RollUp(Weekly) {
+		compactedVolume += bucket.Volume
+	}
+	if rawVolume != compactedVolume {
+		t.Errorf("expected rolled-up volume of %d across tiers, got %d", rawVolume, compactedVolume)
+	}
+}
+
+func TestCompactPricesSkipsBidOnlyPoints(t *testing.T) {
+	actual := compactPrices([]Prices{
+		{MinBuyoutPer: 10, MedianBuyoutPer: 12, P90BuyoutPer: 20, MarketPrice: 11, Volume: 4},
+		{Volume: 3},
+		{MinBuyoutPer: 14, MedianBuyoutPer: 16, P90BuyoutPer: 30, Volume: 2},
+	})
+
+	expected := Prices{MinBuyoutPer: 10, MedianBuyoutPer: 14, P90BuyoutPer: 25, MarketPrice: 11, Volume: 9}
+	if actual != expected {
+		t.Errorf("expected %+v, got %+v", expected, actual)
+	}
+
+	if actual := compactPrices([]Prices{{Volume: 3}}); actual != (Prices{Volume: 3}) {
+		t.Errorf("expected a bid-only day to carry volume only, got %+v", actual)
+	}
+}
+
+func TestCompactDailyWithBidOnlyPointRollsUpLikeRawPoints(t *testing.T) {
+	day := time.Date(2019, 3, 4, 0, 0, 0, 0, time.Local)
+	pHistory := PriceHistory{}
+	for hour := 0; hour < 24; hour++ {
+		targetTimestamp := sotah.UnixTimestamp(day.Add(time.Duration(hour) * time.Hour).Unix())
+		pHistory[targetTimestamp] = Prices{MinBuyoutPer: 10, MarketPrice: 10, Volume: 5}
+	}
+	pHistory[sotah.UnixTimestamp(day.Add(5*time.Hour).Unix())] = Prices{Volume: 3}
+
+	raw := pHistory.RollUp(Weekly)
+	compacted := pHistory.CompactDaily().RollUp(Weekly)
+	if len(raw) != 1 || len(compacted) != 1 {
+		t.Fatalf("expected a single weekly bucket, got %v and %v", raw, compacted)
+	}
+
+	for start, rawBucket := range raw {
+		compactedBucket := compacted[start]
+
+		// the compacted day stands in for every raw point, so only the point count differs
+		compactedBucket.Points = rawBucket.Points
+		if compactedBucket != rawBucket {
+			t.Errorf("expected the compacted day to roll up to %+v, got %+v", rawBucket, compactedBucket)
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package pricelists
+
+import (
+	"errors"
+)
+
+// PriceBasis - typehint for these enums
+type PriceBasis string
+
+/*
+PriceBases - which price items are valued at
+*/
+const (
+	MarketPrice  PriceBasis = "market"
+	MinBuyoutPer PriceBasis = "min-buyout"
+)
+
+func (basis PriceBasis) Validate() error {
+	switch basis {
+	case MarketPrice, MinBuyoutPer:
+		return nil
+	default:
+		return errors.New("invalid price basis")
+	}
+}
+
+// Price falls back onto the min-buyout-per when an item has no market price
+func (basis PriceBasis) Price(p Prices) float64 {
+	if basis == MarketPrice && p.MarketPrice > 0 {
+		return p.MarketPrice
+	}
+
+	return p.MinBuyoutPer
+}
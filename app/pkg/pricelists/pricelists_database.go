@@ -0,0 +1,256 @@
+package pricelists
+
+import (
+	"bytes"
+	"time"
+
+	"github.com/boltdb/bolt"
+	"github.com/sirupsen/logrus"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/blizzard"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/logging"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/sotah"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/util"
+)
+
+func newDatabase(dbFilepath string, targetDate time.Time) (Database, error) {
+	db, err := bolt.Open(dbFilepath, 0600, nil)
+	if err != nil {
+		return Database{}, err
+	}
+
+	return Database{db, targetDate}, nil
+}
+
+// Database is a single daily shard of a realm's pricelist-histories
+type Database struct {
+	db         *bolt.DB
+	targetDate time.Time
+}
+
+// gathering item-price-histories
+type getItemPriceHistoriesJob struct {
+	err     error
+	ItemID  blizzard.ItemID
+	history PriceHistory
+}
+
+func (phdBase Database) getItemPriceHistories(itemIds []blizzard.ItemID) chan getItemPriceHistoriesJob {
+	// drawing channels
+	in := make(chan blizzard.ItemID)
+	out := make(chan getItemPriceHistoriesJob)
+
+	// spinning up workers
+	worker := func() {
+		for itemId := range in {
+			history, err := phdBase.getItemPriceHistory(itemId)
+			out <- getItemPriceHistoriesJob{err, itemId, history}
+		}
+	}
+	postWork := func() {
+		close(out)
+	}
+	util.Work(4, worker, postWork)
+
+	// spinning it up
+	go func() {
+		for _, itemId := range itemIds {
+			in <- itemId
+		}
+
+		close(in)
+	}()
+
+	return out
+}
+
+func (phdBase Database) getItemPriceHistory(itemID blizzard.ItemID) (PriceHistory, error) {
+	out := PriceHistory{}
+
+	err := phdBase.db.View(func(tx *bolt.Tx) error {
+		bkt := tx.Bucket(pricelistHistoryBucketName(itemID))
+		if bkt == nil {
+			return nil
+		}
+
+		value := bkt.Get(pricelistHistoryKeyName())
+		if value == nil {
+			return nil
+		}
+
+		var err error
+		out, err = NewPriceHistoryFromBytes(value)
+
+		return err
+	})
+	if err != nil {
+		return PriceHistory{}, err
+	}
+
+	return out, nil
+}
+
+func (phdBase Database) persistItemPrices(targetTime time.Time, iPrices ItemPrices) error {
+	targetTimestamp := sotah.UnixTimestamp(targetTime.Unix())
+
+	logging.WithFields(logrus.Fields{
+		"target-date": targetTimestamp,
+		"item-prices": len(iPrices),
+	}).Debug("Writing item-prices")
+
+	ipHistories := ItemPriceHistories{}
+	for job := range phdBase.getItemPriceHistories(iPrices.ItemIds()) {
+		if job.err != nil {
+			return job.err
+		}
+
+		ipHistories[job.ItemID] = job.history
+	}
+
+	return phdBase.db.Batch(func(tx *bolt.Tx) error {
+		for ItemID, pricesValue := range iPrices {
+			pHistory, ok := ipHistories[ItemID]
+			if !ok {
+				pHistory = PriceHistory{}
+			}
+			pHistory[targetTimestamp] = pricesValue
+
+			bkt, err := tx.CreateBucketIfNotExists(pricelistHistoryBucketName(ItemID))
+			if err != nil {
+				return err
+			}
+
+			encodedValue, err := pHistory.EncodeForPersistence()
+			if err != nil {
+				return err
+			}
+
+			if err := bkt.Put(pricelistHistoryKeyName(), encodedValue); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func (phdBase Database) getAllItemPriceHistories() (ItemPriceHistories, error) {
+	out := ItemPriceHistories{}
+
+	err := phdBase.db.View(func(tx *bolt.Tx) error {
+		return tx.ForEach(func(name []byte, bkt *bolt.Bucket) error {
+			itemId, ok := pricelistHistoryBucketItemID(name)
+			if !ok {
+				return nil
+			}
+
+			value := bkt.Get(pricelistHistoryKeyName())
+			if value == nil {
+				return nil
+			}
+
+			pHistory, err := NewPriceHistoryFromBytes(value)
+			if err != nil {
+				return err
+			}
+
+			out[itemId] = pHistory
+
+			return nil
+		})
+	})
+	if err != nil {
+		return ItemPriceHistories{}, err
+	}
+
+	return out, nil
+}
+
+func (phdBase Database) getItemIds() ([]blizzard.ItemID, error) {
+	out := []blizzard.ItemID{}
+
+	err := phdBase.db.View(func(tx *bolt.Tx) error {
+		return tx.ForEach(func(name []byte, bkt *bolt.Bucket) error {
+			if itemId, ok := pricelistHistoryBucketItemID(name); ok {
+				out = append(out, itemId)
+			}
+
+			return nil
+		})
+	})
+	if err != nil {
+		return []blizzard.ItemID{}, err
+	}
+
+	return out, nil
+}
+
+func (phdBase Database) persistVariantPrices(targetTime time.Time, vPrices VariantPrices) error {
+	if len(vPrices) == 0 {
+		return nil
+	}
+
+	targetTimestamp := sotah.UnixTimestamp(targetTime.Unix())
+
+	logging.WithFields(logrus.Fields{
+		"target-date":    targetTimestamp,
+		"variant-prices": len(vPrices),
+	}).Debug("Writing variant-prices")
+
+	return phdBase.db.Batch(func(tx *bolt.Tx) error {
+		for key, pricesValue := range vPrices {
+			bkt, err := tx.CreateBucketIfNotExists(variantHistoryBucketName(key))
+			if err != nil {
+				return err
+			}
+
+			pHistory := PriceHistory{}
+			if value := bkt.Get(pricelistHistoryKeyName()); value != nil {
+				pHistory, err = NewPriceHistoryFromBytes(value)
+				if err != nil {
+					return err
+				}
+			}
+			pHistory[targetTimestamp] = pricesValue
+
+			encodedValue, err := pHistory.EncodeForPersistence()
+			if err != nil {
+				return err
+			}
+
+			if err := bkt.Put(pricelistHistoryKeyName(), encodedValue); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// getVariantPriceHistories gathers the histories of the buckets under the prefix
+func (phdBase Database) getVariantPriceHistories(prefix []byte) (VariantPriceHistories, error) {
+	out := VariantPriceHistories{}
+
+	err := phdBase.db.View(func(tx *bolt.Tx) error {
+		c := tx.Cursor()
+		for name, _ := c.Seek(prefix); name != nil && bytes.HasPrefix(name, prefix); name, _ = c.Next() {
+			value := tx.Bucket(name).Get(pricelistHistoryKeyName())
+			if value == nil {
+				continue
+			}
+
+			pHistory, err := NewPriceHistoryFromBytes(value)
+			if err != nil {
+				return err
+			}
+
+			out[variantHistoryBucketKey(name)] = pHistory
+		}
+
+		return nil
+	})
+	if err != nil {
+		return VariantPriceHistories{}, err
+	}
+
+	return out, nil
+}
@@ -0,0 +1,664 @@
+package pricelists
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/config"
+	"github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/connectedrealms"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/blizzard"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/database"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/database/codes"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/logging"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/sotah"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/util"
+)
+
+// NewDatabases opens the shards and archive of each primary realm, pointing every member slug of a connected-realm
+// group at its primary realm's shards and archive
+func NewDatabases(dirPath string, statuses sotah.Statuses, groups connectedrealms.Groups) (Databases, error) {
+	if len(dirPath) == 0 {
+		return Databases{}, errors.New("dir-path cannot be blank")
+	}
+
+	phdBases := Databases{
+		databaseDir: dirPath,
+		Databases:   regionRealmDatabaseShards{},
+		Archives:    map[blizzard.RegionName]map[blizzard.RealmSlug]ArchiveDatabase{},
+		mutex:       &sync.Mutex{},
+	}
+
+	// ensuring archive paths exist
+	archivePaths := []string{}
+	for regionName := range statuses {
+		archivePaths = append(archivePaths, archiveRegionDirPath(dirPath, regionName))
+	}
+	if err := util.EnsureDirsExist(archivePaths); err != nil {
+		return Databases{}, err
+	}
+
+	for regionName, regionStatuses := range statuses {
+		phdBases.Databases[regionName] = realmDatabaseShards{}
+		phdBases.Archives[regionName] = map[blizzard.RealmSlug]ArchiveDatabase{}
+
+		for _, rea := range groups.PrimaryRealms(regionStatuses.Realms) {
+			phdBases.Databases[regionName][rea.Slug] = DatabaseShards{}
+
+			aBase, err := newArchiveDatabase(dirPath, regionName, rea.Slug)
+			if err != nil {
+				return Databases{}, err
+			}
+			phdBases.Archives[regionName][rea.Slug] = aBase
+
+			dbPathPairs, err := database.Paths(pricelistHistoryRealmDirPath(dirPath, regionName, rea.Slug))
+			if err != nil {
+				return Databases{}, err
+			}
+
+			for _, dbPathPair := range dbPathPairs {
+				phdBase, err := newDatabase(dbPathPair.FullPath, dbPathPair.TargetTime)
+				if err != nil {
+					return Databases{}, err
+				}
+
+				phdBases.Databases[regionName][rea.Slug][sotah.UnixTimestamp(dbPathPair.TargetTime.Unix())] = phdBase
+			}
+		}
+
+		for _, rea := range regionStatuses.Realms {
+			primarySlug := groups.Primary(regionName, rea.Slug)
+			if primarySlug == rea.Slug {
+				continue
+			}
+
+			if primaryShards, ok := phdBases.Databases[regionName][primarySlug]; ok {
+				phdBases.Databases[regionName][rea.Slug] = primaryShards
+			}
+			if primaryArchive, ok := phdBases.Archives[regionName][primarySlug]; ok {
+				phdBases.Archives[regionName][rea.Slug] = primaryArchive
+			}
+		}
+	}
+
+	return phdBases, nil
+}
+
+// Databases mirrors database.PricelistHistoryDatabases with the extended Prices and a compacted tier
+type Databases struct {
+	databaseDir string
+	Databases   regionRealmDatabaseShards
+	Archives    map[blizzard.RegionName]map[blizzard.RealmSlug]ArchiveDatabase
+
+	// guards the shard maps, which are written from load workers and the pruner
+	mutex *sync.Mutex
+}
+
+func (phdBases Databases) resolveDatabaseFromLoadInJob(job database.LoadInJob) (Database, error) {
+	phdBases.mutex.Lock()
+	defer phdBases.mutex.Unlock()
+
+	normalizedTargetDate := sotah.NormalizeTargetDate(job.TargetTime)
+	normalizedTargetTimestamp := sotah.UnixTimestamp(normalizedTargetDate.Unix())
+
+	phdBase, ok := phdBases.Databases[job.Realm.Region.Name][job.Realm.Slug][normalizedTargetTimestamp]
+	if ok {
+		return phdBase, nil
+	}
+
+	dbPath := pricelistHistoryDatabaseFilePath(
+		phdBases.databaseDir,
+		job.Realm.Region.Name,
+		job.Realm.Slug,
+		normalizedTargetTimestamp,
+	)
+	phdBase, err := newDatabase(dbPath, normalizedTargetDate)
+	if err != nil {
+		return Database{}, err
+	}
+	phdBases.Databases[job.Realm.Region.Name][job.Realm.Slug][normalizedTargetTimestamp] = phdBase
+
+	return phdBase, nil
+}
+
+type LoadOutJob struct {
+	Err          error
+	Realm        sotah.Realm
+	LastModified time.Time
+}
+
+func (job LoadOutJob) ToLogrusFields() logrus.Fields {
+	return logrus.Fields{
+		"error":         job.Err.Error(),
+		"region":        job.Realm.Region.Name,
+		"realm":         job.Realm.Slug,
+		"last-modified": job.LastModified.Unix(),
+	}
+}
+
+func (phdBases Databases) Load(in chan database.LoadInJob) chan LoadOutJob {
+	// establishing channels
+	out := make(chan LoadOutJob)
+
+	// spinning up workers for receiving auctions and persisting them
+	worker := func() {
+		for job := range in {
+			phdBase, err := phdBases.resolveDatabaseFromLoadInJob(job)
+			if err != nil {
+				logging.WithFields(logrus.Fields{
+					"error":  err.Error(),
+					"region": job.Realm.Region.Name,
+					"realm":  job.Realm.Slug,
+				}).Error("Could not resolve database from load job")
+
+				out <- LoadOutJob{Err: err, Realm: job.Realm, LastModified: job.TargetTime}
+
+				continue
+			}
+
+			iPrices := NewItemPrices(sotah.NewMiniAuctionListFromMiniAuctions(sotah.NewMiniAuctions(job.Auctions)))
+			if err := phdBase.persistItemPrices(job.TargetTime, iPrices); err != nil {
+				logging.WithFields(logrus.Fields{
+					"error":  err.Error(),
+					"region": job.Realm.Region.Name,
+					"realm":  job.Realm.Slug,
+				}).Error("Failed to persist pricelists")
+
+				out <- LoadOutJob{Err: err, Realm: job.Realm, LastModified: job.TargetTime}
+
+				continue
+			}
+
+			if err := phdBase.persistVariantPrices(job.TargetTime, NewVariantPrices(job.Auctions)); err != nil {
+				logging.WithFields(logrus.Fields{
+					"error":  err.Error(),
+					"region": job.Realm.Region.Name,
+					"realm":  job.Realm.Slug,
+				}).Error("Failed to persist variant pricelists")
+
+				out <- LoadOutJob{Err: err, Realm: job.Realm, LastModified: job.TargetTime}
+
+				continue
+			}
+
+			out <- LoadOutJob{Err: nil, Realm: job.Realm, LastModified: job.TargetTime}
+		}
+	}
+	postWork := func() {
+		close(out)
+	}
+	util.Work(2, worker, postWork)
+
+	return out
+}
+
+// compactDatabases folds shards older than the raw limit into the archives and prunes the archives
+func (phdBases Databases) compactDatabases(retention config.RetentionConfig) error {
+	phdBases.mutex.Lock()
+	defer phdBases.mutex.Unlock()
+
+	rawLimit := retention.RawLimit()
+	archiveLimit := retention.ArchiveLimit()
+	logging.WithFields(logrus.Fields{
+		"raw-limit":     rawLimit.Unix(),
+		"archive-limit": archiveLimit.Unix(),
+	}).Info("Checking for databases to compact")
+	for rName, realmDatabases := range phdBases.Databases {
+		for rSlug, databaseShards := range realmDatabases {
+			aBase := phdBases.Archives[rName][rSlug]
+
+			for unixTimestamp, phdBase := range databaseShards {
+				if int64(unixTimestamp) > rawLimit.Unix() {
+					continue
+				}
+
+				entry := logging.WithFields(logrus.Fields{
+					"region":             rName,
+					"realm":              rSlug,
+					"database-timestamp": unixTimestamp,
+				})
+
+				entry.Debug("Compacting database into archive")
+				ipHistories, err := phdBase.getAllItemPriceHistories()
+				if err != nil {
+					entry.WithField("error", err.Error()).Error("Failed to read database for compaction")
+
+					return err
+				}
+
+				compacted := ItemPriceHistories{}
+				for itemId, pHistory := range ipHistories {
+					compacted[itemId] = pHistory.CompactDaily()
+				}
+				if err := aBase.persistItemPriceHistories(compacted); err != nil {
+					entry.WithField("error", err.Error()).Error("Failed to persist compacted history")
+
+					return err
+				}
+
+				vpHistories, err := phdBase.getVariantPriceHistories(variantHistoryBucketPrefix())
+				if err != nil {
+					entry.WithField("error", err.Error()).Error("Failed to read database variants for compaction")
+
+					return err
+				}
+
+				compactedVariants := VariantPriceHistories{}
+				for key, pHistory := range vpHistories {
+					compactedVariants[key] = pHistory.CompactDaily()
+				}
+				if err := aBase.persistVariantPriceHistories(compactedVariants); err != nil {
+					entry.WithField("error", err.Error()).Error("Failed to persist compacted variant history")
+
+					return err
+				}
+
+				entry.Debug("Removing database from shard map")
+				delete(phdBases.Databases[rName][rSlug], unixTimestamp)
+
+				dbPath := phdBase.db.Path()
+
+				entry.Debug("Closing database")
+				if err := phdBase.db.Close(); err != nil {
+					entry.WithField("database", dbPath).Error("Failed to close database")
+
+					return err
+				}
+
+				entry.WithField("filepath", dbPath).Debug("Deleting database file")
+				if err := os.Remove(dbPath); err != nil {
+					entry.WithField("database", dbPath).Error("Failed to remove database file")
+
+					return err
+				}
+			}
+
+			if archiveLimit.IsZero() {
+				continue
+			}
+
+			if err := aBase.prune(archiveLimit); err != nil {
+				logging.WithFields(logrus.Fields{
+					"error":  err.Error(),
+					"region": rName,
+					"realm":  rSlug,
+				}).Error("Failed to prune archive")
+
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (phdBases Databases) StartCompactor(
+	stopChan sotah.WorkerStopChan,
+	retention config.RetentionConfig,
+) sotah.WorkerStopChan {
+	onStop := make(sotah.WorkerStopChan)
+	go func() {
+		ticker := time.NewTicker(20 * time.Minute)
+
+		logging.Info("Starting compactor")
+
+		// compacting once up front, since shards may have aged out while the app was down
+		if err := phdBases.compactDatabases(retention); err != nil {
+			logging.WithField("error", err.Error()).Error("Failed to compact databases")
+		}
+
+	outer:
+		for {
+			select {
+			case <-ticker.C:
+				if err := phdBases.compactDatabases(retention); err != nil {
+					logging.WithField("error", err.Error()).Error("Failed to compact databases")
+
+					continue
+				}
+			case <-stopChan:
+				ticker.Stop()
+
+				break outer
+			}
+		}
+
+		onStop <- struct{}{}
+	}()
+
+	return onStop
+}
+
+func NewGetPricelistHistoryRequest(data []byte) (GetPricelistHistoryRequest, error) {
+	req := &GetPricelistHistoryRequest{}
+	if err := json.Unmarshal(data, &req); err != nil {
+		return GetPricelistHistoryRequest{}, err
+	}
+
+	return *req, nil
+}
+
+type GetPricelistHistoryRequest struct {
+	RegionName  blizzard.RegionName `json:"region_name"`
+	RealmSlug   blizzard.RealmSlug  `json:"realm_slug"`
+	ItemIds     []blizzard.ItemID   `json:"item_ids"`
+	LowerBounds int64               `json:"lower_bounds"`
+	UpperBounds int64               `json:"upper_bounds"`
+	Resolution  Resolution          `json:"resolution"`
+
+	// specific variants, alongside the items whose histories span every variant
+	VariantKeys []VariantKey `json:"variant_keys"`
+}
+
+type GetPricelistHistoryResponse struct {
+	History ItemPriceHistories `json:"history"`
+
+	// only provided when a resolution was requested
+	Buckets ItemPriceBuckets `json:"buckets,omitempty"`
+
+	// only provided when variants were requested
+	VariantHistory VariantPriceHistories `json:"variant_history,omitempty"`
+	VariantBuckets VariantPriceBuckets   `json:"variant_buckets,omitempty"`
+}
+
+func (res GetPricelistHistoryResponse) EncodeForDelivery() (string, error) {
+	jsonEncoded, err := json.Marshal(res)
+	if err != nil {
+		return "", err
+	}
+
+	gzipEncoded, err := util.GzipEncode(jsonEncoded)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.StdEncoding.EncodeToString(gzipEncoded), nil
+}
+
+// resolveRealmHistory copies a realm's shard map so that queries do not hold the lock
+func (phdBases Databases) resolveRealmHistory(
+	regionName blizzard.RegionName,
+	realmSlug blizzard.RealmSlug,
+) (DatabaseShards, ArchiveDatabase, bool) {
+	phdBases.mutex.Lock()
+	defer phdBases.mutex.Unlock()
+
+	realmShards, ok := phdBases.Databases[regionName][realmSlug]
+	if !ok {
+		return DatabaseShards{}, ArchiveDatabase{}, false
+	}
+
+	shards := DatabaseShards{}
+	for unixTimestamp, phdBase := range realmShards {
+		shards[unixTimestamp] = phdBase
+	}
+
+	return shards, phdBases.Archives[regionName][realmSlug], true
+}
+
+// getItemPriceHistory reads the shards and fills in from the compacted tier
+func getItemPriceHistory(
+	shards DatabaseShards,
+	aBase ArchiveDatabase,
+	ID blizzard.ItemID,
+	lowerBounds time.Time,
+	upperBounds time.Time,
+) (PriceHistory, error) {
+	plHistory, err := shards.GetPriceHistory(ID, lowerBounds, upperBounds)
+	if err != nil {
+		return PriceHistory{}, err
+	}
+
+	archivedHistory, err := aBase.getItemPriceHistory(ID, lowerBounds, upperBounds)
+	if err != nil {
+		return PriceHistory{}, err
+	}
+	for targetTimestamp, pricesValue := range archivedHistory {
+		if _, ok := plHistory[targetTimestamp]; ok {
+			continue
+		}
+
+		plHistory[targetTimestamp] = pricesValue
+	}
+
+	return plHistory, nil
+}
+
+// GetItemPriceHistories gathers the full history of each item between the bounds
+func (phdBases Databases) GetItemPriceHistories(
+	regionName blizzard.RegionName,
+	realmSlug blizzard.RealmSlug,
+	itemIds []blizzard.ItemID,
+	lowerBounds time.Time,
+	upperBounds time.Time,
+) (ItemPriceHistories, codes.Code, error) {
+	shards, aBase, ok := phdBases.resolveRealmHistory(regionName, realmSlug)
+	if !ok {
+		return ItemPriceHistories{}, codes.UserError, errors.New("invalid region or realm")
+	}
+
+	out := ItemPriceHistories{}
+	for _, ID := range itemIds {
+		plHistory, err := getItemPriceHistory(shards, aBase, ID, lowerBounds, upperBounds)
+		if err != nil {
+			return ItemPriceHistories{}, codes.GenericError, err
+		}
+
+		out[ID] = plHistory
+	}
+
+	return out, codes.Ok, nil
+}
+
+// getItemVariantPriceHistories reads every variant of an item from the shards and fills in from the compacted tier
+func getItemVariantPriceHistories(
+	shards DatabaseShards,
+	aBase ArchiveDatabase,
+	ID blizzard.ItemID,
+	lowerBounds time.Time,
+	upperBounds time.Time,
+) (VariantPriceHistories, error) {
+	vpHistories, err := shards.GetItemVariantPriceHistories(ID, lowerBounds, upperBounds)
+	if err != nil {
+		return VariantPriceHistories{}, err
+	}
+
+	archivedHistories, err := aBase.getItemVariantPriceHistories(ID, lowerBounds, upperBounds)
+	if err != nil {
+		return VariantPriceHistories{}, err
+	}
+	for key, archivedHistory := range archivedHistories {
+		if _, ok := vpHistories[key]; !ok {
+			vpHistories[key] = PriceHistory{}
+		}
+
+		for targetTimestamp, pricesValue := range archivedHistory {
+			if _, ok := vpHistories[key][targetTimestamp]; ok {
+				continue
+			}
+
+			vpHistories[key][targetTimestamp] = pricesValue
+		}
+	}
+
+	return vpHistories, nil
+}
+
+// GetVariantPriceHistories gathers the history of each variant between the bounds, where the base variant falls back
+// onto the item's history at times the item was listed without variants
+func (phdBases Databases) GetVariantPriceHistories(
+	regionName blizzard.RegionName,
+	realmSlug blizzard.RealmSlug,
+	keys []VariantKey,
+	lowerBounds time.Time,
+	upperBounds time.Time,
+) (VariantPriceHistories, codes.Code, error) {
+	for _, key := range keys {
+		if err := key.Validate(); err != nil {
+			return VariantPriceHistories{}, codes.UserError, err
+		}
+	}
+
+	shards, aBase, ok := phdBases.resolveRealmHistory(regionName, realmSlug)
+	if !ok {
+		return VariantPriceHistories{}, codes.UserError, errors.New("invalid region or realm")
+	}
+
+	itemKeys := map[blizzard.ItemID][]VariantKey{}
+	for _, key := range keys {
+		itemKeys[key.ItemID()] = append(itemKeys[key.ItemID()], key)
+	}
+
+	out := VariantPriceHistories{}
+	for ID, requestedKeys := range itemKeys {
+		vpHistories, err := getItemVariantPriceHistories(shards, aBase, ID, lowerBounds, upperBounds)
+		if err != nil {
+			return VariantPriceHistories{}, codes.GenericError, err
+		}
+
+		// gathering the times at which the item was listed with variants
+		variantTimestamps := map[sotah.UnixTimestamp]struct{}{}
+		for _, pHistory := range vpHistories {
+			for targetTimestamp := range pHistory {
+				variantTimestamps[targetTimestamp] = struct{}{}
+			}
+		}
+
+		for _, key := range requestedKeys {
+			pHistory, ok := vpHistories[key]
+			if !ok {
+				pHistory = PriceHistory{}
+			}
+
+			if key.IsBase() {
+				itemHistory, err := getItemPriceHistory(shards, aBase, ID, lowerBounds, upperBounds)
+				if err != nil {
+					return VariantPriceHistories{}, codes.GenericError, err
+				}
+
+				for targetTimestamp, pricesValue := range itemHistory {
+					if _, ok := variantTimestamps[targetTimestamp]; ok {
+						continue
+					}
+
+					pHistory[targetTimestamp] = pricesValue
+				}
+			}
+
+			out[key] = pHistory
+		}
+	}
+
+	return out, codes.Ok, nil
+}
+
+// GetItemIds gathers every item with history in a realm's shards or archive
+func (phdBases Databases) GetItemIds(
+	regionName blizzard.RegionName,
+	realmSlug blizzard.RealmSlug,
+) ([]blizzard.ItemID, codes.Code, error) {
+	shards, aBase, ok := phdBases.resolveRealmHistory(regionName, realmSlug)
+	if !ok {
+		return []blizzard.ItemID{}, codes.UserError, errors.New("invalid region or realm")
+	}
+
+	seen := map[blizzard.ItemID]struct{}{}
+	out := []blizzard.ItemID{}
+	add := func(itemIds []blizzard.ItemID) {
+		for _, ID := range itemIds {
+			if _, ok := seen[ID]; ok {
+				continue
+			}
+			seen[ID] = struct{}{}
+
+			out = append(out, ID)
+		}
+	}
+
+	for _, phdBase := range shards {
+		itemIds, err := phdBase.getItemIds()
+		if err != nil {
+			return []blizzard.ItemID{}, codes.GenericError, err
+		}
+
+		add(itemIds)
+	}
+
+	archivedItemIds, err := aBase.getItemIds()
+	if err != nil {
+		return []blizzard.ItemID{}, codes.GenericError, err
+	}
+	add(archivedItemIds)
+
+	return out, codes.Ok, nil
+}
+
+func (phdBases Databases) GetPricelistHistory(
+	req GetPricelistHistoryRequest,
+) (GetPricelistHistoryResponse, codes.Code, error) {
+	if err := req.Resolution.Validate(); err != nil {
+		return GetPricelistHistoryResponse{}, codes.UserError, err
+	}
+
+	logging.WithFields(logrus.Fields{
+		"req": fmt.Sprintf("+%v", req),
+	}).Info("Querying shards")
+
+	ipHistories, respCode, err := phdBases.GetItemPriceHistories(
+		req.RegionName,
+		req.RealmSlug,
+		req.ItemIds,
+		time.Unix(req.LowerBounds, 0),
+		time.Unix(req.UpperBounds, 0),
+	)
+	if err != nil {
+		return GetPricelistHistoryResponse{}, respCode, err
+	}
+
+	res := GetPricelistHistoryResponse{History: ItemPriceHistories{}}
+	if req.Resolution != Raw {
+		res.Buckets = ItemPriceBuckets{}
+	}
+	for ID, plHistory := range ipHistories {
+		if req.Resolution != Raw {
+			res.Buckets[ID] = plHistory.RollUp(req.Resolution)
+
+			continue
+		}
+
+		res.History[ID] = plHistory
+	}
+
+	if len(req.VariantKeys) == 0 {
+		return res, codes.Ok, nil
+	}
+
+	vpHistories, respCode, err := phdBases.GetVariantPriceHistories(
+		req.RegionName,
+		req.RealmSlug,
+		req.VariantKeys,
+		time.Unix(req.LowerBounds, 0),
+		time.Unix(req.UpperBounds, 0),
+	)
+	if err != nil {
+		return GetPricelistHistoryResponse{}, respCode, err
+	}
+
+	if req.Resolution != Raw {
+		res.VariantBuckets = VariantPriceBuckets{}
+		for key, pHistory := range vpHistories {
+			res.VariantBuckets[key] = pHistory.RollUp(req.Resolution)
+		}
+
+		return res, codes.Ok, nil
+	}
+
+	res.VariantHistory = vpHistories
+
+	return res, codes.Ok, nil
+}
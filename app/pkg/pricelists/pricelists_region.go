@@ -0,0 +1,89 @@
+package pricelists
+
+import (
+	"sort"
+
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/blizzard"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/sotah"
+)
+
+// RealmSpread marks the cheapest and dearest realms for an item by min-buyout-per
+type RealmSpread struct {
+	LowestRealm         blizzard.RealmSlug `json:"lowest_realm"`
+	LowestMinBuyoutPer  float64            `json:"lowest_min_buyout_per"`
+	HighestRealm        blizzard.RealmSlug `json:"highest_realm"`
+	HighestMinBuyoutPer float64            `json:"highest_min_buyout_per"`
+}
+
+type RegionItemPrices struct {
+	Realms   map[blizzard.RealmSlug]ItemPrices `json:"realms"`
+	Combined ItemPrices                        `json:"combined"`
+	Spreads  map[blizzard.ItemID]RealmSpread   `json:"spreads"`
+}
+
+// NewRegionItemPrices derives per-realm prices, combined prices over every listed unit, and the spread between realms
+func NewRegionItemPrices(
+	realmLists map[blizzard.RealmSlug]sotah.MiniAuctionList,
+	itemIds []blizzard.ItemID,
+) RegionItemPrices {
+	itemIdsMap := map[blizzard.ItemID]struct{}{}
+	for _, itemId := range itemIds {
+		itemIdsMap[itemId] = struct{}{}
+	}
+
+	filter := func(maList sotah.MiniAuctionList) sotah.MiniAuctionList {
+		out := sotah.MiniAuctionList{}
+		for _, mAuction := range maList {
+			if _, ok := itemIdsMap[mAuction.ItemID]; !ok {
+				continue
+			}
+
+			out = append(out, mAuction)
+		}
+
+		return out
+	}
+
+	out := RegionItemPrices{
+		Realms:   map[blizzard.RealmSlug]ItemPrices{},
+		Combined: ItemPrices{},
+		Spreads:  map[blizzard.ItemID]RealmSpread{},
+	}
+	// visiting realms in order so that ties in the spread resolve the same way every time
+	realmSlugs := make([]blizzard.RealmSlug, 0, len(realmLists))
+	for realmSlug := range realmLists {
+		realmSlugs = append(realmSlugs, realmSlug)
+	}
+	sort.Slice(realmSlugs, func(i, j int) bool {
+		return realmSlugs[i] < realmSlugs[j]
+	})
+
+	combinedList := sotah.MiniAuctionList{}
+	for _, realmSlug := range realmSlugs {
+		filtered := filter(realmLists[realmSlug])
+		combinedList = append(combinedList, filtered...)
+
+		iPrices := NewItemPrices(filtered)
+		out.Realms[realmSlug] = iPrices
+
+		for itemId, p := range iPrices {
+			if p.MinBuyoutPer == 0 {
+				continue
+			}
+
+			spread, ok := out.Spreads[itemId]
+			if !ok || p.MinBuyoutPer < spread.LowestMinBuyoutPer {
+				spread.LowestRealm = realmSlug
+				spread.LowestMinBuyoutPer = p.MinBuyoutPer
+			}
+			if !ok || p.MinBuyoutPer > spread.HighestMinBuyoutPer {
+				spread.HighestRealm = realmSlug
+				spread.HighestMinBuyoutPer = p.MinBuyoutPer
+			}
+			out.Spreads[itemId] = spread
+		}
+	}
+	out.Combined = NewItemPrices(combinedList)
+
+	return out
+}
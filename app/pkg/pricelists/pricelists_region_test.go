@@ -0,0 +1,52 @@
+package pricelists
+
+import (
+	"testing"
+
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/blizzard"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/sotah"
+)
+
+func TestNewRegionItemPrices(t *testing.T) {
+	realmLists := map[blizzard.RealmSlug]sotah.MiniAuctionList{
+		"azgalor":      {{ItemID: 1, Buyout: 10, BuyoutPer: 10, Quantity: 1, AucList: []int64{1}}},
+		"earthen-ring": {{ItemID: 1, Buyout: 30, BuyoutPer: 30, Quantity: 1, AucList: []int64{2}}},
+		"zuljin": {
+			{ItemID: 1, Buyout: 20, BuyoutPer: 20, Quantity: 1, AucList: []int64{3}},
+			{ItemID: 2, Buyout: 5, BuyoutPer: 5, Quantity: 1, AucList: []int64{4}},
+		},
+	}
+
+	actual := NewRegionItemPrices(realmLists, []blizzard.ItemID{1})
+
+	if len(actual.Realms) != 3 {
+		t.Errorf("expected 3 realms, got %d", len(actual.Realms))
+	}
+	if _, ok := actual.Combined[2]; ok {
+		t.Error("expected items that were not requested to be filtered out")
+	}
+
+	expected := RealmSpread{
+		LowestRealm:         "azgalor",
+		LowestMinBuyoutPer:  10,
+		HighestRealm:        "earthen-ring",
+		HighestMinBuyoutPer: 30,
+	}
+	if spread := actual.Spreads[1]; spread != expected {
+		t.Errorf("expected %+v, got %+v", expected, spread)
+	}
+}
+
+func TestNewRegionItemPricesBreaksTiesByRealm(t *testing.T) {
+	realmLists := map[blizzard.RealmSlug]sotah.MiniAuctionList{}
+	for _, slug := range []blizzard.RealmSlug{"d", "b", "a", "c"} {
+		realmLists[slug] = sotah.MiniAuctionList{{ItemID: 1, Buyout: 10, BuyoutPer: 10, Quantity: 1, AucList: []int64{1}}}
+	}
+
+	for i := 0; i < 20; i++ {
+		spread := NewRegionItemPrices(realmLists, []blizzard.ItemID{1}).Spreads[1]
+		if spread.LowestRealm != "a" || spread.HighestRealm != "a" {
+			t.Fatalf("expected ties to resolve to the first realm, got %+v", spread)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package pricelists
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/blizzard"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/sotah"
+)
+
+// Resolution - typehint for these enums
+type Resolution string
+
+/*
+Resolutions - bucket sizes that price histories may be rolled up into, where blank means raw points
+*/
+const (
+	Raw       Resolution = ""
+	Hourly    Resolution = "hourly"
+	SixHourly Resolution = "6h"
+	Daily     Resolution = "daily"
+	Weekly    Resolution = "weekly"
+)
+
+var resolutionDurations = map[Resolution]time.Duration{
+	Hourly:    time.Hour,
+	SixHourly: 6 * time.Hour,
+	Daily:     24 * time.Hour,
+	Weekly:    7 * 24 * time.Hour,
+}
+
+func (r Resolution) Validate() error {
+	if r == Raw {
+		return nil
+	}
+
+	if _, ok := resolutionDurations[r]; !ok {
+		return fmt.Errorf("invalid resolution: %s", r)
+	}
+
+	return nil
+}
+
+// bucketStart truncates from the zero time, so weekly buckets begin on mondays
+func (r Resolution) bucketStart(targetTimestamp sotah.UnixTimestamp) sotah.UnixTimestamp {
+	truncated := time.Unix(int64(targetTimestamp), 0).UTC().Truncate(resolutionDurations[r])
+
+	return sotah.UnixTimestamp(truncated.Unix())
+}
+
+// PriceBucket rolls up the min-buyout-per of every point within a bucket
+type PriceBucket struct {
+	Open        float64 `json:"open"`
+	High        float64 `json:"high"`
+	Low         float64 `json:"low"`
+	Close       float64 `json:"close"`
+	MarketPrice float64 `json:"market_price"`
+	Volume      int64   `json:"volume"`
+	Points      int     `json:"points"`
+}
+
+type PriceBuckets map[sotah.UnixTimestamp]PriceBucket
+
+type ItemPriceBuckets map[blizzard.ItemID]PriceBuckets
+
+type VariantPriceBuckets map[VariantKey]PriceBuckets
+
+// RollUp aggregates the history into buckets of the given resolution, where points without a buyout (bid-only
+// snapshots) count towards volume but not prices
+func (pHistory PriceHistory) RollUp(r Resolution) PriceBuckets {
+	// visiting points in order so that open and close are the first and last
+	timestamps := make([]sotah.UnixTimestamp, 0, len(pHistory))
+	for targetTimestamp := range pHistory {
+		timestamps = append(timestamps, targetTimestamp)
+	}
+	sort.Slice(timestamps, func(i, j int) bool {
+		return timestamps[i] < timestamps[j]
+	})
+
+	out := PriceBuckets{}
+	pricedBuckets := map[sotah.UnixTimestamp]struct{}{}
+	marketPriceTotals := map[sotah.UnixTimestamp]float64{}
+	marketPriceCounts := map[sotah.UnixTimestamp]int{}
+	for _, targetTimestamp := range timestamps {
+		p := pHistory[targetTimestamp]
+		start := r.bucketStart(targetTimestamp)
+
+		bucket := out[start]
+		bucket.Volume += p.Volume
+		bucket.Points++
+
+		if p.MinBuyoutPer > 0 {
+			if _, ok := pricedBuckets[start]; !ok {
+				bucket.Open = p.MinBuyoutPer
+				bucket.High = p.MinBuyoutPer
+				bucket.Low = p.MinBuyoutPer
+				pricedBuckets[start] = struct{}{}
+			}
+
+			if p.MinBuyoutPer > bucket.High {
+				bucket.High = p.MinBuyoutPer
+			}
+			if p.MinBuyoutPer < bucket.Low {
+				bucket.Low = p.MinBuyoutPer
+			}
+			bucket.Close = p.MinBuyoutPer
+		}
+
+		if p.MarketPrice > 0 {
+			marketPriceTotals[start] += p.MarketPrice
+			marketPriceCounts[start]++
+		}
+
+		out[start] = bucket
+	}
+
+	for start, bucket := range out {
+		if count := marketPriceCounts[start]; count > 0 {
+			bucket.MarketPrice = marketPriceTotals[start] / float64(count)
+		}
+		out[start] = bucket
+	}
+
+	return out
+}
@@ -0,0 +1,66 @@
+package pricelists
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/sotah"
+)
+
+func hourOf(day int, hour int) sotah.UnixTimestamp {
+	return sotah.UnixTimestamp(time.Date(2019, 3, day, hour, 0, 0, 0, time.UTC).Unix())
+}
+
+func TestRollUpDaily(t *testing.T) {
+	pHistory := PriceHistory{
+		hourOf(4, 1):  {MinBuyoutPer: 10, MarketPrice: 12, Volume: 5},
+		hourOf(4, 6):  {MinBuyoutPer: 15, MarketPrice: 16, Volume: 3},
+		hourOf(4, 12): {MinBuyoutPer: 8, MarketPrice: 14, Volume: 2},
+		hourOf(5, 1):  {MinBuyoutPer: 20, MarketPrice: 20, Volume: 1},
+	}
+
+	buckets := pHistory.RollUp(Daily)
+	if len(buckets) != 2 {
+		t.Fatalf("expected 2 buckets, got %d", len(buckets))
+	}
+
+	expected := PriceBucket{Open: 10, High: 15, Low: 8, Close: 8, MarketPrice: 14, Volume: 10, Points: 3}
+	if actual := buckets[hourOf(4, 0)]; actual != expected {
+		t.Errorf("expected %+v, got %+v", expected, actual)
+	}
+}
+
+func TestRollUpSkipsBidOnlyPoints(t *testing.T) {
+	pHistory := PriceHistory{
+		hourOf(4, 1): {MinBuyoutPer: 0, MarketPrice: 0, Volume: 4},
+		hourOf(4, 2): {MinBuyoutPer: 10, MarketPrice: 11, Volume: 1},
+		hourOf(4, 3): {MinBuyoutPer: 12, MarketPrice: 13, Volume: 1},
+		hourOf(4, 4): {MinBuyoutPer: 0, MarketPrice: 0, Volume: 2},
+	}
+
+	expected := PriceBucket{Open: 10, High: 12, Low: 10, Close: 12, MarketPrice: 12, Volume: 8, Points: 4}
+	if actual := pHistory.RollUp(Daily)[hourOf(4, 0)]; actual != expected {
+		t.Errorf("expected %+v, got %+v", expected, actual)
+	}
+}
+
+func TestRollUpWithoutPrices(t *testing.T) {
+	pHistory := PriceHistory{hourOf(4, 1): {Volume: 4}}
+
+	expected := PriceBucket{Volume: 4, Points: 1}
+	if actual := pHistory.RollUp(Hourly)[hourOf(4, 1)]; actual != expected {
+		t.Errorf("expected %+v, got %+v", expected, actual)
+	}
+}
+
+func TestResolutionValidate(t *testing.T) {
+	for _, r := range []Resolution{Raw, Hourly, SixHourly, Daily, Weekly} {
+		if err := r.Validate(); err != nil {
+			t.Errorf("%s: expected valid, got %s", r, err.Error())
+		}
+	}
+
+	if err := Resolution("monthly").Validate(); err == nil {
+		t.Error("expected monthly to be invalid")
+	}
+}
@@ -0,0 +1,76 @@
+package pricelists
+
+import (
+	"time"
+
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/blizzard"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/sotah"
+)
+
+type regionRealmDatabaseShards map[blizzard.RegionName]realmDatabaseShards
+
+type realmDatabaseShards map[blizzard.RealmSlug]DatabaseShards
+
+type DatabaseShards map[sotah.UnixTimestamp]Database
+
+func (phdShards DatabaseShards) GetPriceHistory(
+	ItemId blizzard.ItemID,
+	lowerBounds time.Time,
+	upperBounds time.Time,
+) (PriceHistory, error) {
+	pHistory := PriceHistory{}
+
+	for _, phdBase := range phdShards {
+		receivedHistory, err := phdBase.getItemPriceHistory(ItemId)
+		if err != nil {
+			return PriceHistory{}, err
+		}
+
+		for targetTimestamp, pricesValue := range receivedHistory {
+			if int64(targetTimestamp) < lowerBounds.Unix() {
+				continue
+			}
+			if int64(targetTimestamp) > upperBounds.Unix() {
+				continue
+			}
+
+			pHistory[targetTimestamp] = pricesValue
+		}
+	}
+
+	return pHistory, nil
+}
+
+// GetItemVariantPriceHistories gathers the history of every variant of an item between the bounds
+func (phdShards DatabaseShards) GetItemVariantPriceHistories(
+	ItemId blizzard.ItemID,
+	lowerBounds time.Time,
+	upperBounds time.Time,
+) (VariantPriceHistories, error) {
+	out := VariantPriceHistories{}
+
+	for _, phdBase := range phdShards {
+		receivedHistories, err := phdBase.getVariantPriceHistories(variantHistoryItemBucketPrefix(ItemId))
+		if err != nil {
+			return VariantPriceHistories{}, err
+		}
+
+		for key, receivedHistory := range receivedHistories {
+			for targetTimestamp, pricesValue := range receivedHistory {
+				if int64(targetTimestamp) < lowerBounds.Unix() {
+					continue
+				}
+				if int64(targetTimestamp) > upperBounds.Unix() {
+					continue
+				}
+
+				if _, ok := out[key]; !ok {
+					out[key] = PriceHistory{}
+				}
+				out[key][targetTimestamp] = pricesValue
+			}
+		}
+	}
+
+	return out, nil
+}
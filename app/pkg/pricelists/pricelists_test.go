@@ -0,0 +1,142 @@
+package pricelists
+
+import (
+	"io/ioutil"
+	"math"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/connectedrealms"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/blizzard"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/sotah"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/util"
+)
+
+func TestNewItemPrices(t *testing.T) {
+	maList := sotah.MiniAuctionList{
+		{ItemID: 1, Buyout: 10, Quantity: 1, AucList: []int64{1, 2, 3, 4, 5, 6, 7, 8}},
+		{ItemID: 1, Buyout: 40, Quantity: 2, AucList: []int64{9}},
+		{ItemID: 1, Buyout: 1000, Quantity: 1, AucList: []int64{10}},
+		{ItemID: 1, Bid: 5, Quantity: 1, AucList: []int64{11}},
+		{ItemID: 2, Bid: 5, Quantity: 3, AucList: []int64{12}},
+	}
+
+	p := NewItemPrices(maList)[1]
+	if p.MinBuyoutPer != 10 || p.MaxBuyoutPer != 1000 || p.Volume != 12 {
+		t.Fatalf("unexpected min, max or volume: %+v", p)
+	}
+
+	// the average and median stay per-group, as in sotah.NewItemPrices
+	if math.Abs(p.AverageBuyoutPer-1030.0/3) > 0.001 || p.MedianBuyoutPer != 20 {
+		t.Fatalf("unexpected per-group average or median: %+v", p)
+	}
+
+	// every listed unit with a buyout weighs in
+	if math.Abs(p.VolumeWeightedAverageBuyoutPer-1120.0/11) > 0.001 {
+		t.Fatalf("unexpected volume-weighted average: %f", p.VolumeWeightedAverageBuyoutPer)
+	}
+	if p.P10BuyoutPer != 10 || p.P25BuyoutPer != 10 || p.P75BuyoutPer != 20 || p.P90BuyoutPer != 20 {
+		t.Fatalf("unexpected percentiles: %+v", p)
+	}
+
+	// the market price leaves out the listing far above the interquartile fence
+	if p.MarketPrice != 12 {
+		t.Fatalf("expected the outlier to be ignored, got %f", p.MarketPrice)
+	}
+
+	// items without buyouts only carry volume
+	if p := NewItemPrices(maList)[2]; p != (Prices{Volume: 3}) {
+		t.Fatalf("unexpected bid-only prices: %+v", p)
+	}
+}
+
+func TestPriceHistoryDecodesOldHistories(t *testing.T) {
+	oldHistory := sotah.PriceHistory{100: sotah.Prices{MinBuyoutPer: 10, MedianBuyoutPer: 12, Volume: 3}}
+	encoded, err := oldHistory.EncodeForPersistence()
+	if err != nil {
+		t.Fatalf("could not encode old history: %s", err)
+	}
+
+	pHistory, err := NewPriceHistoryFromBytes(encoded)
+	if err != nil {
+		t.Fatalf("could not decode old history: %s", err)
+	}
+	if pHistory[100] != (Prices{MinBuyoutPer: 10, MedianBuyoutPer: 12, Volume: 3}) {
+		t.Fatalf("unexpected decoded history: %+v", pHistory)
+	}
+
+	// and the other way around, for readers still on sotah.Prices
+	encoded, err = PriceHistory{100: {MinBuyoutPer: 10, MarketPrice: 11}}.EncodeForPersistence()
+	if err != nil {
+		t.Fatalf("could not encode history: %s", err)
+	}
+	oldHistory, err = sotah.NewPriceHistoryFromBytes(encoded)
+	if err != nil || oldHistory[100].MinBuyoutPer != 10 {
+		t.Fatalf("unexpected old decoded history: %+v %v", oldHistory, err)
+	}
+}
+
+func TestDatabasePersistItemPrices(t *testing.T) {
+	dirPath, err := ioutil.TempDir("", "pricelists")
+	if err != nil {
+		t.Fatalf("could not create temp dir: %s", err)
+	}
+	defer os.RemoveAll(dirPath)
+
+	phdBase, err := newDatabase(dirPath+"/0.db", time.Unix(0, 0))
+	if err != nil {
+		t.Fatalf("could not open database: %s", err)
+	}
+	defer phdBase.db.Close()
+
+	for i, p := range []Prices{{MinBuyoutPer: 10, P90BuyoutPer: 20}, {MinBuyoutPer: 15, MarketPrice: 16}} {
+		if err := phdBase.persistItemPrices(time.Unix(int64(100*(i+1)), 0), ItemPrices{1: p}); err != nil {
+			t.Fatalf("could not persist item-prices: %s", err)
+		}
+	}
+
+	pHistory, err := phdBase.getItemPriceHistory(1)
+	if err != nil {
+		t.Fatalf("could not get item-price-history: %s", err)
+	}
+	if len(pHistory) != 2 || pHistory[100].P90BuyoutPer != 20 || pHistory[200].MarketPrice != 16 {
+		t.Fatalf("expected both intakes with their statistics, got %+v", pHistory)
+	}
+}
+
+func TestNewDatabasesSharesConnectedRealms(t *testing.T) {
+	dirPath, err := ioutil.TempDir("", "pricelists")
+	if err != nil {
+		t.Fatalf("could not create temp dir: %s", err)
+	}
+	defer os.RemoveAll(dirPath)
+
+	realms := sotah.Realms{
+		{Realm: blizzard.Realm{Slug: "a"}, Region: sotah.Region{Name: "us"}},
+		{Realm: blizzard.Realm{Slug: "b"}, Region: sotah.Region{Name: "us"}},
+	}
+	statuses := sotah.Statuses{"us": sotah.Status{Region: sotah.Region{Name: "us"}, Realms: realms}}
+	groups := connectedrealms.Groups{"us": {"a": "a", "b": "a"}}
+	if err := util.EnsureDirsExist([]string{pricelistHistoryRealmDirPath(dirPath, "us", "a")}); err != nil {
+		t.Fatalf("could not create database dir: %s", err)
+	}
+
+	phdBases, err := NewDatabases(dirPath, statuses, groups)
+	if err != nil {
+		t.Fatalf("could not open databases: %s", err)
+	}
+	defer phdBases.Archives["us"]["a"].db.Close()
+
+	// the member slug shares its primary's handles rather than opening its own
+	if phdBases.Archives["us"]["b"].db != phdBases.Archives["us"]["a"].db {
+		t.Fatal("expected the member to share the primary's archive")
+	}
+	phdBases.Databases["us"]["a"][100] = Database{}
+	if _, ok := phdBases.Databases["us"]["b"][100]; !ok {
+		t.Fatal("expected the member to share the primary's shards")
+	}
+	if _, err := os.Stat(archiveDatabasePath(dirPath, "us", "b")); !os.IsNotExist(err) {
+		t.Fatalf("expected no archive file for the member, got %v", err)
+	}
+}
@@ -0,0 +1,157 @@
+package pricelists
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math"
+	"sort"
+	"strconv"
+
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/blizzard"
+)
+
+// VariantKey identifies an item variant by its random suffix and bonus context, as <item>:<rand>:<context>, where the
+// seed is left out since it differs between every instance of an item
+type VariantKey string
+
+func NewVariantKey(ID blizzard.ItemID, rand int64, context int64) VariantKey {
+	return VariantKey(fmt.Sprintf("%d:%d:%d", ID, rand, context))
+}
+
+// NewBonusListsRand stands in for the random suffix of game-data auctions, which have bonus lists instead, as a
+// positive hash of the sorted bonus lists, so that items without bonus lists keep their base variant
+func NewBonusListsRand(bonusLists []int64) int64 {
+	if len(bonusLists) == 0 {
+		return 0
+	}
+
+	sorted := make([]int64, len(bonusLists))
+	copy(sorted, bonusLists)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i] < sorted[j]
+	})
+
+	h := fnv.New64a()
+	for _, bonusList := range sorted {
+		h.Write([]byte(strconv.FormatInt(bonusList, 10)))
+		h.Write([]byte{','})
+	}
+
+	out := int64(h.Sum64() & math.MaxInt64)
+	if out == 0 {
+		return 1
+	}
+
+	return out
+}
+
+func newVariantKeyFromAuction(auc blizzard.Auction) VariantKey {
+	return NewVariantKey(auc.Item, auc.Rand, auc.Context)
+}
+
+func (key VariantKey) Parse() (blizzard.ItemID, int64, int64, error) {
+	var (
+		ID      blizzard.ItemID
+		rand    int64
+		context int64
+	)
+	if _, err := fmt.Sscanf(string(key), "%d:%d:%d", &ID, &rand, &context); err != nil {
+		return 0, 0, 0, fmt.Errorf("invalid variant key: %s", key)
+	}
+
+	return ID, rand, context, nil
+}
+
+func (key VariantKey) Validate() error {
+	_, _, _, err := key.Parse()
+
+	return err
+}
+
+func (key VariantKey) ItemID() blizzard.ItemID {
+	ID, _, _, _ := key.Parse()
+
+	return ID
+}
+
+// IsBase reports whether the key is of an item without a random suffix or bonus context
+func (key VariantKey) IsBase() bool {
+	_, rand, context, err := key.Parse()
+
+	return err == nil && rand == 0 && context == 0
+}
+
+type VariantPrices map[VariantKey]Prices
+
+// NewVariantPrices prices every variant of the items listed as anything but their base variant, since the prices of
+// an item only listed as its base variant are its item prices
+func NewVariantPrices(aucs blizzard.Auctions) VariantPrices {
+	variantItems := map[blizzard.ItemID]struct{}{}
+	for _, auc := range aucs.Auctions {
+		if auc.Rand != 0 || auc.Context != 0 {
+			variantItems[auc.Item] = struct{}{}
+		}
+	}
+
+	builders := map[VariantKey]*pricesBuilder{}
+	for _, auc := range aucs.Auctions {
+		if _, ok := variantItems[auc.Item]; !ok {
+			continue
+		}
+
+		key := newVariantKeyFromAuction(auc)
+		b, ok := builders[key]
+		if !ok {
+			b = &pricesBuilder{}
+			builders[key] = b
+		}
+
+		b.add(auc.Buyout, auc.Quantity, 1)
+	}
+
+	out := VariantPrices{}
+	for key, b := range builders {
+		out[key] = b.prices()
+	}
+
+	return out
+}
+
+func (vPrices VariantPrices) itemsWithVariants() map[blizzard.ItemID]struct{} {
+	out := map[blizzard.ItemID]struct{}{}
+	for key := range vPrices {
+		out[key.ItemID()] = struct{}{}
+	}
+
+	return out
+}
+
+// Resolve gathers the requested variants, where the base variant of an item without variants is the item itself
+func (vPrices VariantPrices) Resolve(keys []VariantKey, iPrices ItemPrices) VariantPrices {
+	variantItems := vPrices.itemsWithVariants()
+
+	out := VariantPrices{}
+	for _, key := range keys {
+		if p, ok := vPrices[key]; ok {
+			out[key] = p
+
+			continue
+		}
+
+		if !key.IsBase() {
+			continue
+		}
+
+		if _, ok := variantItems[key.ItemID()]; ok {
+			continue
+		}
+
+		if p, ok := iPrices[key.ItemID()]; ok {
+			out[key] = p
+		}
+	}
+
+	return out
+}
+
+type VariantPriceHistories map[VariantKey]PriceHistory
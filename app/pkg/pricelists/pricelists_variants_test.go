@@ -0,0 +1,87 @@
+package pricelists
+
+import (
+	"testing"
+
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/blizzard"
+)
+
+func TestVariantKeyParse(t *testing.T) {
+	key := NewVariantKey(123, -45, 6)
+	if key != "123:-45:6" {
+		t.Fatalf("unexpected key: %s", key)
+	}
+
+	ID, rand, context, err := key.Parse()
+	if err != nil || ID != 123 || rand != -45 || context != 6 {
+		t.Fatalf("unexpected parse: %d %d %d %v", ID, rand, context, err)
+	}
+
+	if key.IsBase() || !NewVariantKey(123, 0, 0).IsBase() {
+		t.Fatal("expected only the key without suffix or context to be the base")
+	}
+
+	for _, invalid := range []VariantKey{"", "123", "a:b:c"} {
+		if err := invalid.Validate(); err == nil {
+			t.Fatalf("expected %q to be invalid", invalid)
+		}
+	}
+}
+
+func TestNewVariantPrices(t *testing.T) {
+	aucs := blizzard.Auctions{Auctions: []blizzard.Auction{
+		{Item: 1, Rand: 5, Buyout: 100, Quantity: 1},
+		{Item: 1, Rand: 5, Buyout: 300, Quantity: 1},
+		{Item: 1, Buyout: 10, Quantity: 1},
+		{Item: 2, Buyout: 20, Quantity: 2},
+	}}
+
+	vPrices := NewVariantPrices(aucs)
+	if len(vPrices) != 2 {
+		t.Fatalf("expected the base and suffixed variants of item 1 only, got %v", vPrices)
+	}
+	if p := vPrices[NewVariantKey(1, 5, 0)]; p.MinBuyoutPer != 100 || p.MaxBuyoutPer != 300 || p.Volume != 2 {
+		t.Fatalf("unexpected suffixed prices: %+v", p)
+	}
+	if p := vPrices[NewVariantKey(1, 0, 0)]; p.MinBuyoutPer != 10 {
+		t.Fatalf("unexpected base prices: %+v", p)
+	}
+}
+
+func TestVariantPricesResolve(t *testing.T) {
+	vPrices := VariantPrices{NewVariantKey(1, 5, 0): {MinBuyoutPer: 100}}
+	iPrices := ItemPrices{1: {MinBuyoutPer: 50}, 2: {MinBuyoutPer: 10}}
+
+	keys := []VariantKey{NewVariantKey(1, 5, 0), NewVariantKey(1, 0, 0), NewVariantKey(2, 0, 0), NewVariantKey(2, 1, 0)}
+	out := vPrices.Resolve(keys, iPrices)
+	if len(out) != 2 {
+		t.Fatalf("expected two resolved variants, got %v", out)
+	}
+
+	// an item without variants is priced as its base variant
+	if out[NewVariantKey(2, 0, 0)].MinBuyoutPer != 10 {
+		t.Fatalf("expected item 2's base variant from its item prices, got %v", out)
+	}
+
+	// the base variant of an item with variants is not the item's prices
+	if _, ok := out[NewVariantKey(1, 0, 0)]; ok {
+		t.Fatal("expected no base variant for an item with variants")
+	}
+}
+
+func TestNewBonusListsRand(t *testing.T) {
+	if rand := NewBonusListsRand(nil); rand != 0 {
+		t.Fatalf("expected no bonus lists to be the base variant, got %d", rand)
+	}
+
+	rand := NewBonusListsRand([]int64{4795, 1472})
+	if rand <= 0 {
+		t.Fatalf("expected a positive rand, got %d", rand)
+	}
+	if other := NewBonusListsRand([]int64{1472, 4795}); other != rand {
+		t.Fatalf("expected the order of the bonus lists to not matter, got %d and %d", rand, other)
+	}
+	if other := NewBonusListsRand([]int64{1472}); other == rand {
+		t.Fatal("expected different bonus lists to be different variants")
+	}
+}
@@ -0,0 +1,82 @@
+package realmdb
+
+import (
+	"fmt"
+
+	"github.com/boltdb/bolt"
+	"github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/connectedrealms"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/blizzard"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/sotah"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/util"
+)
+
+// db
+func databasePath(dirPath string, rea sotah.Realm) string {
+	return fmt.Sprintf("%s/%s/%s.db", dirPath, rea.Region.Name, rea.Slug)
+}
+
+func regionDirPath(dirPath string, regionName blizzard.RegionName) string {
+	return fmt.Sprintf("%s/%s", dirPath, regionName)
+}
+
+// Handle is an open realm database, where the realm is the primary realm of the group owning it
+type Handle struct {
+	DB    *bolt.DB
+	Realm sotah.Realm
+}
+
+/*
+Open opens a bolt database per connected-realm group under <dirPath>/<region>/<primary realm>.db, where every
+member slug of a group resolves to its primary realm's handle
+*/
+func Open(dirPath string, stas sotah.Statuses, groups connectedrealms.Groups) (Registry, error) {
+	// ensuring database paths exist
+	databasePaths := []string{}
+	for regionName := range stas {
+		databasePaths = append(databasePaths, regionDirPath(dirPath, regionName))
+	}
+	if err := util.EnsureDirsExist(databasePaths); err != nil {
+		return Registry{}, err
+	}
+
+	registry := Registry{}
+	for regionName, status := range stas {
+		registry[regionName] = map[blizzard.RealmSlug]Handle{}
+
+		// opening the primary realms first so that members have something to resolve onto
+		for _, rea := range groups.PrimaryRealms(status.Realms) {
+			db, err := bolt.Open(databasePath(dirPath, rea), 0600, nil)
+			if err != nil {
+				registry.Close()
+
+				return Registry{}, err
+			}
+
+			registry[regionName][rea.Slug] = Handle{db, rea}
+		}
+
+		for _, rea := range status.Realms {
+			if handle, ok := registry[regionName][groups.Primary(regionName, rea.Slug)]; ok {
+				registry[regionName][rea.Slug] = handle
+			}
+		}
+	}
+
+	return registry, nil
+}
+
+// Registry holds the database of every realm, keyed by region and realm slug
+type Registry map[blizzard.RegionName]map[blizzard.RealmSlug]Handle
+
+// Close closes each database once, skipping the member slugs that share it
+func (registry Registry) Close() {
+	for _, handles := range registry {
+		for realmSlug, handle := range handles {
+			if handle.Realm.Slug != realmSlug {
+				continue
+			}
+
+			handle.DB.Close()
+		}
+	}
+}
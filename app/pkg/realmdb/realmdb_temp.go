@@ -0,0 +1,37 @@
+package realmdb
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/connectedrealms"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/blizzard"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/sotah"
+)
+
+/*
+OpenTemp opens a registry of a single us/earthen-ring realm under a temp dir, for the tests of packages storing their
+data per realm, along with a func that closes the database and removes the dir
+*/
+func OpenTemp(t *testing.T, name string) (Handle, func()) {
+	t.Helper()
+
+	dirPath, err := ioutil.TempDir("", name)
+	if err != nil {
+		t.Fatalf("could not create temp dir: %s", err)
+	}
+
+	rea := sotah.Realm{Realm: blizzard.Realm{Slug: "earthen-ring"}, Region: sotah.Region{Name: "us"}}
+	stas := sotah.Statuses{"us": sotah.Status{Realms: sotah.Realms{rea}}}
+	registry, err := Open(dirPath, stas, connectedrealms.NewGroups(stas))
+	if err != nil {
+		os.RemoveAll(dirPath)
+		t.Fatalf("could not open registry: %s", err)
+	}
+
+	return registry["us"]["earthen-ring"], func() {
+		registry.Close()
+		os.RemoveAll(dirPath)
+	}
+}
@@ -0,0 +1,72 @@
+package realmdb
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/connectedrealms"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/blizzard"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/sotah"
+)
+
+func newRealm(slug blizzard.RealmSlug, connectedSlugs ...blizzard.RealmSlug) sotah.Realm {
+	return sotah.Realm{
+		Realm:  blizzard.Realm{Slug: slug, ConnectedRealms: connectedSlugs},
+		Region: sotah.Region{Name: "us"},
+	}
+}
+
+func newTestStatuses() sotah.Statuses {
+	return sotah.Statuses{
+		"us": sotah.Status{Realms: sotah.Realms{
+			newRealm("bbb", "aaa"),
+			newRealm("aaa", "bbb"),
+			newRealm("ccc"),
+		}},
+	}
+}
+
+func newTestRegistry(t *testing.T) (Registry, string, func()) {
+	dirPath, err := ioutil.TempDir("", "realmdb")
+	if err != nil {
+		t.Fatalf("could not create temp dir: %s", err)
+	}
+
+	stas := newTestStatuses()
+	registry, err := Open(dirPath, stas, connectedrealms.NewGroups(stas))
+	if err != nil {
+		os.RemoveAll(dirPath)
+		t.Fatalf("could not open registry: %s", err)
+	}
+
+	return registry, dirPath, func() {
+		registry.Close()
+		os.RemoveAll(dirPath)
+	}
+}
+
+func TestOpenSharesConnectedRealms(t *testing.T) {
+	registry, dirPath, cleanup := newTestRegistry(t)
+	defer cleanup()
+
+	handles := registry["us"]
+	if len(handles) != 3 {
+		t.Fatalf("expected every realm to have a handle, got %d", len(handles))
+	}
+
+	if handles["bbb"].DB != handles["aaa"].DB || handles["bbb"].Realm.Slug != "aaa" {
+		t.Fatal("expected bbb to share its primary realm's database")
+	}
+	if handles["ccc"].DB == handles["aaa"].DB || handles["ccc"].Realm.Slug != "ccc" {
+		t.Fatal("expected an ungrouped realm to have a database of its own")
+	}
+
+	// only primary realms have a file of their own
+	if _, err := os.Stat(dirPath + "/us/aaa.db"); err != nil {
+		t.Fatalf("expected the primary database file: %s", err)
+	}
+	if _, err := os.Stat(dirPath + "/us/bbb.db"); !os.IsNotExist(err) {
+		t.Fatal("expected no database file for a member realm")
+	}
+}
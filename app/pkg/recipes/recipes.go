@@ -0,0 +1,155 @@
+package recipes
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/blizzard"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/logging"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/sotah"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/util"
+)
+
+type Reagent struct {
+	ItemID   blizzard.ItemID `json:"item_id"`
+	Quantity int             `json:"quantity"`
+}
+
+// Recipe links the reagents consumed by a craft to the item it produces
+type Recipe struct {
+	ID         int    `json:"id"`
+	Name       string `json:"name"`
+	Profession string `json:"profession"`
+
+	ItemID blizzard.ItemID `json:"item_id"`
+
+	// average number of items produced per craft, defaulting to one
+	Quantity float64 `json:"quantity"`
+
+	Reagents []Reagent `json:"reagents"`
+}
+
+func (r Recipe) Validate() error {
+	if r.ItemID == 0 {
+		return fmt.Errorf("recipe %d has no crafted item", r.ID)
+	}
+	if len(r.Profession) == 0 {
+		return fmt.Errorf("recipe %d has no profession", r.ID)
+	}
+	if len(r.Reagents) == 0 {
+		return fmt.Errorf("recipe %d has no reagents", r.ID)
+	}
+	for _, reagent := range r.Reagents {
+		if reagent.ItemID == 0 || reagent.Quantity <= 0 {
+			return fmt.Errorf("recipe %d has an invalid reagent", r.ID)
+		}
+	}
+
+	return nil
+}
+
+func (r Recipe) ProducedQuantity() float64 {
+	if r.Quantity <= 0 {
+		return 1
+	}
+
+	return r.Quantity
+}
+
+func NewRecipesFromFilepath(relativePath string) (Recipes, error) {
+	logging.WithField("path", relativePath).Info("Reading recipes")
+
+	body, err := util.ReadFile(relativePath)
+	if err != nil {
+		return Recipes{}, err
+	}
+
+	return NewRecipes(body)
+}
+
+func NewRecipes(body []byte) (Recipes, error) {
+	out := Recipes{}
+	if err := json.Unmarshal(body, &out); err != nil {
+		return Recipes{}, err
+	}
+
+	return out, nil
+}
+
+type Recipes []Recipe
+
+// Validate checks every recipe and that each belongs to a configured profession
+func (rs Recipes) Validate(professions []sotah.Profession) error {
+	professionNames := map[string]struct{}{}
+	for _, prof := range professions {
+		professionNames[prof.Name] = struct{}{}
+	}
+
+	for _, r := range rs {
+		if err := r.Validate(); err != nil {
+			return err
+		}
+
+		if _, ok := professionNames[r.Profession]; !ok {
+			return fmt.Errorf("recipe %d has unknown profession: %s", r.ID, r.Profession)
+		}
+	}
+
+	return nil
+}
+
+func (rs Recipes) FilterByProfession(profession string) Recipes {
+	out := Recipes{}
+	for _, r := range rs {
+		if r.Profession != profession {
+			continue
+		}
+
+		out = append(out, r)
+	}
+
+	return out
+}
+
+// ItemIds gathers every reagent and crafted item
+func (rs Recipes) ItemIds() []blizzard.ItemID {
+	seen := map[blizzard.ItemID]struct{}{}
+	out := []blizzard.ItemID{}
+	add := func(ID blizzard.ItemID) {
+		if _, ok := seen[ID]; ok {
+			return
+		}
+		seen[ID] = struct{}{}
+
+		out = append(out, ID)
+	}
+
+	for _, r := range rs {
+		add(r.ItemID)
+		for _, reagent := range r.Reagents {
+			add(reagent.ItemID)
+		}
+	}
+
+	return out
+}
+
+// Merge appends the other recipes, where recipes already present by id are kept
+func (rs Recipes) Merge(other Recipes) Recipes {
+	seen := map[int]struct{}{}
+	for _, r := range rs {
+		seen[r.ID] = struct{}{}
+	}
+
+	out := append(Recipes{}, rs...)
+	for _, r := range other {
+		if _, ok := seen[r.ID]; ok {
+			continue
+		}
+		seen[r.ID] = struct{}{}
+
+		out = append(out, r)
+	}
+
+	return out
+}
@@ -0,0 +1,138 @@
+package recipes
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/config"
+	"github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/resolver"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/blizzard"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/logging"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/sotah"
+)
+
+const recipeURLFormat = "https://%s/data/wow/recipe/%d?namespace=static-%s&locale=en_US"
+
+func getRecipeURL(primaryRegion sotah.Region, ID int) string {
+	return fmt.Sprintf(recipeURLFormat, resolver.APIHostname(primaryRegion), ID, primaryRegion.Name)
+}
+
+type blizzardRecipeReference struct {
+	ID int `json:"id"`
+}
+
+type blizzardRecipeReagent struct {
+	Reagent  blizzardRecipeReference `json:"reagent"`
+	Quantity int                     `json:"quantity"`
+}
+
+type blizzardRecipeQuantity struct {
+	Value   float64 `json:"value"`
+	Minimum float64 `json:"minimum"`
+	Maximum float64 `json:"maximum"`
+}
+
+// blizzardRecipe is the subset of the game-data recipe document that describes the craft
+type blizzardRecipe struct {
+	ID              int                     `json:"id"`
+	Name            string                  `json:"name"`
+	CraftedItem     blizzardRecipeReference `json:"crafted_item"`
+	CraftedQuantity blizzardRecipeQuantity  `json:"crafted_quantity"`
+	Reagents        []blizzardRecipeReagent `json:"reagents"`
+}
+
+func (bRecipe blizzardRecipe) toRecipe(profession string) Recipe {
+	quantity := bRecipe.CraftedQuantity.Value
+	if quantity == 0 && bRecipe.CraftedQuantity.Maximum > 0 {
+		quantity = (bRecipe.CraftedQuantity.Minimum + bRecipe.CraftedQuantity.Maximum) / 2
+	}
+
+	out := Recipe{
+		ID:         bRecipe.ID,
+		Name:       bRecipe.Name,
+		Profession: profession,
+		ItemID:     blizzard.ItemID(bRecipe.CraftedItem.ID),
+		Quantity:   quantity,
+		Reagents:   []Reagent{},
+	}
+	for _, bReagent := range bRecipe.Reagents {
+		out.Reagents = append(out.Reagents, Reagent{
+			ItemID:   blizzard.ItemID(bReagent.Reagent.ID),
+			Quantity: bReagent.Quantity,
+		})
+	}
+
+	return out
+}
+
+// NewRecipeFromHTTP resolves a recipe, where a missing recipe is reported as not existing rather than as an error
+func NewRecipeFromHTTP(
+	res resolver.Resolver,
+	primaryRegion sotah.Region,
+	ID int,
+	profession string,
+) (Recipe, bool, error) {
+	resp, err := res.Download(primaryRegion, getRecipeURL(primaryRegion, ID), true)
+	if err != nil {
+		return Recipe{}, false, err
+	}
+	if resp.Status == http.StatusNotFound {
+		return Recipe{}, false, nil
+	}
+	if resp.Status != http.StatusOK {
+		return Recipe{}, false, fmt.Errorf("unexpected status for recipe %d: %d", ID, resp.Status)
+	}
+
+	bRecipe := &blizzardRecipe{}
+	if err := json.Unmarshal(resp.Body, &bRecipe); err != nil {
+		return Recipe{}, false, err
+	}
+
+	return bRecipe.toRecipe(profession), true, nil
+}
+
+// NewRecipesFromConfig reads the local recipes file and resolves the configured recipe ids, preferring local recipes
+// when both carry the same id
+func NewRecipesFromConfig(
+	c config.RecipesConfig,
+	res resolver.Resolver,
+	primaryRegion sotah.Region,
+) (Recipes, error) {
+	out := Recipes{}
+
+	if len(c.Filepath) > 0 {
+		localRecipes, err := NewRecipesFromFilepath(c.Filepath)
+		if err != nil {
+			return Recipes{}, err
+		}
+
+		out = out.Merge(localRecipes)
+	}
+
+	for profession, recipeIds := range c.RecipeIds {
+		resolvedRecipes := Recipes{}
+		for _, ID := range recipeIds {
+			r, exists, err := NewRecipeFromHTTP(res, primaryRegion, ID, profession)
+			if err != nil {
+				return Recipes{}, err
+			}
+
+			if !exists {
+				logging.WithFields(logrus.Fields{
+					"profession": profession,
+					"recipe":     ID,
+				}).Warn("Recipe was not found")
+
+				continue
+			}
+
+			resolvedRecipes = append(resolvedRecipes, r)
+		}
+
+		out = out.Merge(resolvedRecipes)
+	}
+
+	return out, nil
+}
@@ -0,0 +1,98 @@
+package recipes
+
+import (
+	"sort"
+
+	"github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/pricelists"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/blizzard"
+)
+
+// Profit prices a single craft of a recipe
+type Profit struct {
+	Recipe       Recipe  `json:"recipe"`
+	ReagentCost  float64 `json:"reagent_cost"`
+	ProductValue float64 `json:"product_value"`
+	Margin       float64 `json:"margin"`
+
+	// margin relative to the reagent cost
+	MarginPercent float64 `json:"margin_percent"`
+
+	// items without a current price, which leave the profit incomplete
+	UnpricedItems []blizzard.ItemID `json:"unpriced_items"`
+}
+
+func NewProfit(r Recipe, iPrices pricelists.ItemPrices, basis pricelists.PriceBasis) Profit {
+	out := Profit{Recipe: r, UnpricedItems: []blizzard.ItemID{}}
+
+	for _, reagent := range r.Reagents {
+		price := basis.Price(iPrices[reagent.ItemID])
+		if price == 0 {
+			out.UnpricedItems = append(out.UnpricedItems, reagent.ItemID)
+
+			continue
+		}
+
+		out.ReagentCost += price * float64(reagent.Quantity)
+	}
+
+	productPrice := basis.Price(iPrices[r.ItemID])
+	if productPrice == 0 {
+		out.UnpricedItems = append(out.UnpricedItems, r.ItemID)
+	}
+	out.ProductValue = productPrice * r.ProducedQuantity()
+
+	out.Margin = out.ProductValue - out.ReagentCost
+	if out.ReagentCost > 0 {
+		out.MarginPercent = out.Margin / out.ReagentCost * 100
+	}
+
+	return out
+}
+
+func (p Profit) IsComplete() bool {
+	return len(p.UnpricedItems) == 0
+}
+
+type Profits []Profit
+
+// ProfessionProfits ranks each profession's recipes by margin
+type ProfessionProfits map[string]Profits
+
+// NewProfessionProfits prices every recipe, ranking fully priced recipes above incomplete ones and then by margin
+func NewProfessionProfits(rs Recipes, iPrices pricelists.ItemPrices, basis pricelists.PriceBasis) ProfessionProfits {
+	out := ProfessionProfits{}
+	for _, r := range rs {
+		out[r.Profession] = append(out[r.Profession], NewProfit(r, iPrices, basis))
+	}
+
+	for profession, profits := range out {
+		sort.Slice(profits, func(i, j int) bool {
+			if profits[i].IsComplete() != profits[j].IsComplete() {
+				return profits[i].IsComplete()
+			}
+
+			if profits[i].Margin != profits[j].Margin {
+				return profits[i].Margin > profits[j].Margin
+			}
+
+			return profits[i].Recipe.ID < profits[j].Recipe.ID
+		})
+		out[profession] = profits
+	}
+
+	return out
+}
+
+// Limit truncates each profession's ranking
+func (pProfits ProfessionProfits) Limit(count int) ProfessionProfits {
+	out := ProfessionProfits{}
+	for profession, profits := range pProfits {
+		if len(profits) > count {
+			profits = profits[:count]
+		}
+
+		out[profession] = profits
+	}
+
+	return out
+}
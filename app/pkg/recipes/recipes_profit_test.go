@@ -0,0 +1,71 @@
+package recipes
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/pricelists"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/blizzard"
+)
+
+func TestNewProfit(t *testing.T) {
+	r := Recipe{ID: 1, ItemID: 10, Quantity: 2, Reagents: []Reagent{{ItemID: 20, Quantity: 3}}}
+	iPrices := pricelists.ItemPrices{
+		10: {MinBuyoutPer: 50, MarketPrice: 60},
+		20: {MinBuyoutPer: 10},
+	}
+
+	p := NewProfit(r, iPrices, pricelists.MinBuyoutPer)
+	if p.ReagentCost != 30 || p.ProductValue != 100 || p.Margin != 70 || !p.IsComplete() {
+		t.Fatalf("unexpected min-buyout profit: %+v", p)
+	}
+	if p.MarginPercent < 233.33 || p.MarginPercent > 233.34 {
+		t.Fatalf("unexpected margin percent: %f", p.MarginPercent)
+	}
+
+	// reagents without a market price fall back onto their min-buyout
+	p = NewProfit(r, iPrices, pricelists.MarketPrice)
+	if p.ReagentCost != 30 || p.ProductValue != 120 {
+		t.Fatalf("unexpected market profit: %+v", p)
+	}
+
+	p = NewProfit(r, pricelists.ItemPrices{10: {MinBuyoutPer: 50}}, pricelists.MinBuyoutPer)
+	if p.IsComplete() || !reflect.DeepEqual(p.UnpricedItems, []blizzard.ItemID{20}) {
+		t.Fatalf("expected the unpriced reagent to be reported, got %+v", p)
+	}
+}
+
+func TestNewProfessionProfits(t *testing.T) {
+	rs := Recipes{
+		{ID: 1, Profession: "alchemy", ItemID: 10, Reagents: []Reagent{{ItemID: 20, Quantity: 1}}},
+		{ID: 2, Profession: "alchemy", ItemID: 11, Reagents: []Reagent{{ItemID: 20, Quantity: 1}}},
+		{ID: 3, Profession: "alchemy", ItemID: 12, Reagents: []Reagent{{ItemID: 21, Quantity: 1}}},
+		{ID: 4, Profession: "cooking", ItemID: 13, Reagents: []Reagent{{ItemID: 20, Quantity: 1}}},
+	}
+	iPrices := pricelists.ItemPrices{
+		10: {MinBuyoutPer: 15},
+		11: {MinBuyoutPer: 40},
+		12: {MinBuyoutPer: 1000},
+		13: {MinBuyoutPer: 5},
+		20: {MinBuyoutPer: 10},
+	}
+
+	pProfits := NewProfessionProfits(rs, iPrices, pricelists.MinBuyoutPer)
+
+	// incomplete recipes rank below complete ones regardless of margin
+	ranking := []int{}
+	for _, p := range pProfits["alchemy"] {
+		ranking = append(ranking, p.Recipe.ID)
+	}
+	if !reflect.DeepEqual(ranking, []int{2, 1, 3}) {
+		t.Fatalf("unexpected ranking: %v", ranking)
+	}
+	if len(pProfits["cooking"]) != 1 || pProfits["cooking"][0].Margin != -5 {
+		t.Fatalf("unexpected cooking profits: %+v", pProfits["cooking"])
+	}
+
+	limited := pProfits.Limit(1)
+	if len(limited["alchemy"]) != 1 || limited["alchemy"][0].Recipe.ID != 2 || len(pProfits["alchemy"]) != 3 {
+		t.Fatalf("unexpected limited profits: %+v", limited)
+	}
+}
@@ -0,0 +1,113 @@
+package recipes
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/config"
+	"github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/resolver"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/blizzard"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/sotah"
+)
+
+func TestRecipesValidate(t *testing.T) {
+	professions := []sotah.Profession{{Name: "alchemy"}}
+	valid := Recipe{ID: 1, Profession: "alchemy", ItemID: 10, Reagents: []Reagent{{ItemID: 20, Quantity: 2}}}
+	if err := (Recipes{valid}).Validate(professions); err != nil {
+		t.Fatalf("expected the recipe to be valid, got %s", err)
+	}
+
+	unknownProfession := valid
+	unknownProfession.Profession = "cooking"
+	noProduct := valid
+	noProduct.ItemID = 0
+	noReagents := valid
+	noReagents.Reagents = nil
+	badReagent := valid
+	badReagent.Reagents = []Reagent{{ItemID: 20}}
+	for _, r := range []Recipe{unknownProfession, noProduct, noReagents, badReagent} {
+		if err := (Recipes{r}).Validate(professions); err == nil {
+			t.Fatalf("expected %+v to be invalid", r)
+		}
+	}
+
+	if valid.ProducedQuantity() != 1 {
+		t.Fatalf("expected a single product by default, got %f", valid.ProducedQuantity())
+	}
+}
+
+func TestRecipesItemIdsAndMerge(t *testing.T) {
+	rs := Recipes{
+		{ID: 1, Profession: "alchemy", ItemID: 10, Reagents: []Reagent{{ItemID: 20, Quantity: 1}}},
+		{ID: 2, Profession: "cooking", ItemID: 11, Reagents: []Reagent{{ItemID: 20, Quantity: 1}}},
+	}
+
+	if ids := rs.ItemIds(); !reflect.DeepEqual(ids, []blizzard.ItemID{10, 20, 11}) {
+		t.Fatalf("unexpected item-ids: %v", ids)
+	}
+	if filtered := rs.FilterByProfession("cooking"); len(filtered) != 1 || filtered[0].ID != 2 {
+		t.Fatalf("unexpected filtered recipes: %+v", filtered)
+	}
+
+	merged := rs.Merge(Recipes{{ID: 1, Name: "replacement"}, {ID: 3}})
+	if len(merged) != 3 || merged[0].Name != "" || merged[2].ID != 3 {
+		t.Fatalf("expected existing recipes to be kept, got %+v", merged)
+	}
+}
+
+func TestBlizzardRecipeToRecipe(t *testing.T) {
+	body := []byte(`{
+		"id": 1,
+		"name": "Elixir",
+		"crafted_item": {"id": 10},
+		"crafted_quantity": {"minimum": 1, "maximum": 3},
+		"reagents": [{"reagent": {"id": 20}, "quantity": 2}]
+	}`)
+	bRecipe := blizzardRecipe{}
+	if err := json.Unmarshal(body, &bRecipe); err != nil {
+		t.Fatalf("could not decode recipe: %s", err)
+	}
+
+	r := bRecipe.toRecipe("alchemy")
+	expected := Recipe{
+		ID:         1,
+		Name:       "Elixir",
+		Profession: "alchemy",
+		ItemID:     10,
+		Quantity:   2,
+		Reagents:   []Reagent{{ItemID: 20, Quantity: 2}},
+	}
+	if !reflect.DeepEqual(r, expected) {
+		t.Fatalf("expected a ranged quantity to average, got %+v", r)
+	}
+}
+
+func TestNewRecipesFromConfig(t *testing.T) {
+	f, err := ioutil.TempFile("", "recipes")
+	if err != nil {
+		t.Fatalf("could not create temp file: %s", err)
+	}
+	defer os.Remove(f.Name())
+
+	body := `[{"id":1,"profession":"alchemy","item_id":10,"reagents":[{"item_id":20,"quantity":2}]}]`
+	if _, err := f.WriteString(body); err != nil {
+		t.Fatalf("could not write recipes: %s", err)
+	}
+	f.Close()
+
+	rs, err := NewRecipesFromConfig(config.RecipesConfig{Filepath: f.Name()}, resolver.Resolver{}, sotah.Region{})
+	if err != nil {
+		t.Fatalf("could not read recipes: %s", err)
+	}
+	if len(rs) != 1 || rs[0].Reagents[0].Quantity != 2 {
+		t.Fatalf("unexpected recipes: %+v", rs)
+	}
+
+	missingConfig := config.RecipesConfig{Filepath: f.Name() + "-missing"}
+	if _, err := NewRecipesFromConfig(missingConfig, resolver.Resolver{}, sotah.Region{}); err == nil {
+		t.Fatal("expected a missing recipes file to fail")
+	}
+}
@@ -0,0 +1,161 @@
+package replay
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/blizzard"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/logging"
+)
+
+const dumpSuffix = ".json.gz"
+
+// Dump is an archived auctions file of a realm, as written by the storage backend
+type Dump struct {
+	RegionName blizzard.RegionName
+	RealmSlug  blizzard.RealmSlug
+	TargetTime time.Time
+	Filepath   string
+}
+
+func (d Dump) Auctions() (blizzard.Auctions, error) {
+	return blizzard.NewAuctionsFromGzFilepath(d.Filepath)
+}
+
+func newDumpFromRelativePath(dirPath string, relativePath string) (Dump, error) {
+	parts := strings.Split(filepath.ToSlash(relativePath), "/")
+	if len(parts) != 3 || !strings.HasSuffix(parts[2], dumpSuffix) {
+		return Dump{}, fmt.Errorf("path is not <region>/<realm>/<timestamp>%s: %s", dumpSuffix, relativePath)
+	}
+
+	targetTimestamp, err := strconv.ParseInt(strings.TrimSuffix(parts[2], dumpSuffix), 10, 64)
+	if err != nil {
+		return Dump{}, err
+	}
+
+	return Dump{
+		RegionName: blizzard.RegionName(parts[0]),
+		RealmSlug:  blizzard.RealmSlug(parts[1]),
+		TargetTime: time.Unix(targetTimestamp, 0),
+		Filepath:   filepath.Join(dirPath, relativePath),
+	}, nil
+}
+
+// NewDumps gathers every <region>/<realm>/<timestamp>.json.gz file under the directory in time order, skipping
+// anything else
+func NewDumps(dirPath string) (Dumps, error) {
+	out := Dumps{}
+	err := filepath.Walk(dirPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() {
+			return nil
+		}
+
+		relativePath, err := filepath.Rel(dirPath, path)
+		if err != nil {
+			return err
+		}
+
+		d, err := newDumpFromRelativePath(dirPath, relativePath)
+		if err != nil {
+			logging.WithField("path", relativePath).Debug("Skipping file that is not an auctions dump")
+
+			return nil
+		}
+
+		out = append(out, d)
+
+		return nil
+	})
+	if err != nil {
+		return Dumps{}, err
+	}
+
+	out.sort()
+
+	return out, nil
+}
+
+type Dumps []Dump
+
+func (ds Dumps) sort() {
+	sort.SliceStable(ds, func(i, j int) bool {
+		if !ds[i].TargetTime.Equal(ds[j].TargetTime) {
+			return ds[i].TargetTime.Before(ds[j].TargetTime)
+		}
+
+		if ds[i].RegionName != ds[j].RegionName {
+			return ds[i].RegionName < ds[j].RegionName
+		}
+
+		return ds[i].RealmSlug < ds[j].RealmSlug
+	})
+}
+
+// Filter narrows the dumps to a region, realms and time range, where blank criteria match everything
+func (ds Dumps) Filter(
+	regionName blizzard.RegionName,
+	realmSlugs []blizzard.RealmSlug,
+	lowerBounds time.Time,
+	upperBounds time.Time,
+) Dumps {
+	requestedSlugs := map[blizzard.RealmSlug]struct{}{}
+	for _, realmSlug := range realmSlugs {
+		requestedSlugs[realmSlug] = struct{}{}
+	}
+
+	out := Dumps{}
+	for _, d := range ds {
+		if len(regionName) > 0 && d.RegionName != regionName {
+			continue
+		}
+
+		if len(requestedSlugs) > 0 {
+			if _, ok := requestedSlugs[d.RealmSlug]; !ok {
+				continue
+			}
+		}
+
+		if d.TargetTime.Before(lowerBounds) {
+			continue
+		}
+
+		if !upperBounds.IsZero() && d.TargetTime.After(upperBounds) {
+			continue
+		}
+
+		out = append(out, d)
+	}
+
+	return out
+}
+
+// Rounds splits the dumps so that each round holds at most one dump per realm, with the nth round holding each
+// realm's nth dump, since loading is concurrent across the jobs of a round
+func (ds Dumps) Rounds() []Dumps {
+	out := []Dumps{}
+	realmCounts := map[blizzard.RegionName]map[blizzard.RealmSlug]int{}
+	for _, d := range ds {
+		if _, ok := realmCounts[d.RegionName]; !ok {
+			realmCounts[d.RegionName] = map[blizzard.RealmSlug]int{}
+		}
+
+		round := realmCounts[d.RegionName][d.RealmSlug]
+		realmCounts[d.RegionName][d.RealmSlug]++
+
+		if round == len(out) {
+			out = append(out, Dumps{})
+		}
+		out[round] = append(out[round], d)
+	}
+
+	return out
+}
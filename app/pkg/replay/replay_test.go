@@ -0,0 +1,102 @@
+package replay
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/blizzard"
+)
+
+func newTestDumpsDir(t *testing.T, relativePaths []string) (string, func()) {
+	dirPath, err := ioutil.TempDir("", "replay")
+	if err != nil {
+		t.Fatalf("could not create temp dir: %s", err)
+	}
+	cleanup := func() {
+		os.RemoveAll(dirPath)
+	}
+
+	for _, relativePath := range relativePaths {
+		fullPath := filepath.Join(dirPath, relativePath)
+		if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+			cleanup()
+			t.Fatalf("could not create dir: %s", err)
+		}
+		if err := ioutil.WriteFile(fullPath, []byte{}, 0644); err != nil {
+			cleanup()
+			t.Fatalf("could not write file: %s", err)
+		}
+	}
+
+	return dirPath, cleanup
+}
+
+func TestNewDumps(t *testing.T) {
+	dirPath, cleanup := newTestDumpsDir(t, []string{
+		"us/zuljin/200.json.gz",
+		"us/azgalor/200.json.gz",
+		"eu/silvermoon/100.json.gz",
+		"us/zuljin/notes.txt",
+		"us/zuljin/abc.json.gz",
+		"stray.json.gz",
+	})
+	defer cleanup()
+
+	ds, err := NewDumps(dirPath)
+	if err != nil {
+		t.Fatalf("could not gather dumps: %s", err)
+	}
+
+	if len(ds) != 3 {
+		t.Fatalf("expected only the well-formed dumps, got %d", len(ds))
+	}
+	if ds[0].RealmSlug != "silvermoon" || ds[1].RealmSlug != "azgalor" || ds[2].RealmSlug != "zuljin" {
+		t.Fatalf("expected dumps ordered by time, region and realm, got %+v", ds)
+	}
+	if !ds[2].TargetTime.Equal(time.Unix(200, 0)) || ds[2].Filepath != filepath.Join(dirPath, "us/zuljin/200.json.gz") {
+		t.Fatalf("unexpected dump: %+v", ds[2])
+	}
+}
+
+func newTestDumps() Dumps {
+	return Dumps{
+		{RegionName: "us", RealmSlug: "zuljin", TargetTime: time.Unix(100, 0)},
+		{RegionName: "us", RealmSlug: "azgalor", TargetTime: time.Unix(100, 0)},
+		{RegionName: "eu", RealmSlug: "silvermoon", TargetTime: time.Unix(150, 0)},
+		{RegionName: "us", RealmSlug: "zuljin", TargetTime: time.Unix(200, 0)},
+		{RegionName: "us", RealmSlug: "zuljin", TargetTime: time.Unix(300, 0)},
+	}
+}
+
+func TestDumpsFilter(t *testing.T) {
+	ds := newTestDumps()
+
+	if out := ds.Filter("", nil, time.Time{}, time.Time{}); len(out) != len(ds) {
+		t.Fatalf("expected blank criteria to match everything, got %d", len(out))
+	}
+	if out := ds.Filter("us", []blizzard.RealmSlug{"zuljin"}, time.Time{}, time.Time{}); len(out) != 3 {
+		t.Fatalf("expected zuljin's three dumps, got %d", len(out))
+	}
+
+	out := ds.Filter("", nil, time.Unix(150, 0), time.Unix(200, 0))
+	if len(out) != 2 || out[0].RealmSlug != "silvermoon" || !out[1].TargetTime.Equal(time.Unix(200, 0)) {
+		t.Fatalf("expected the dumps within the inclusive bounds, got %+v", out)
+	}
+}
+
+func TestDumpsRounds(t *testing.T) {
+	rounds := newTestDumps().Rounds()
+	if len(rounds) != 3 {
+		t.Fatalf("expected a round per zuljin dump, got %d", len(rounds))
+	}
+
+	if len(rounds[0]) != 3 || len(rounds[1]) != 1 || len(rounds[2]) != 1 {
+		t.Fatalf("unexpected round sizes: %d %d %d", len(rounds[0]), len(rounds[1]), len(rounds[2]))
+	}
+	if !rounds[2][0].TargetTime.Equal(time.Unix(300, 0)) {
+		t.Fatalf("expected zuljin's last dump in the last round, got %+v", rounds[2][0])
+	}
+}
@@ -0,0 +1,134 @@
+package resolver
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/config"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/blizzard"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/metric"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/sotah"
+)
+
+// API - typehint for these enums
+type API string
+
+/*
+APIs - blizzard apis that can be resolved from
+*/
+const (
+	CommunityAPI API = "community"
+	GameDataAPI  API = "game-data"
+)
+
+func NewAPI(name string) (API, error) {
+	switch API(name) {
+	case "", CommunityAPI:
+		return CommunityAPI, nil
+	case GameDataAPI:
+		return GameDataAPI, nil
+	default:
+		return "", fmt.Errorf("invalid blizzard api: %s", name)
+	}
+}
+
+// NewResolver gathers an initial access token for every region, so that bad credentials fail on startup
+func NewResolver(
+	c *Client,
+	re metric.Reporter,
+	api API,
+	regions sotah.RegionList,
+	regionCredentials map[blizzard.RegionName]config.BlizzardCredentials,
+) (Resolver, error) {
+	tokens := map[blizzard.RegionName]*tokenSource{}
+	for _, reg := range regions {
+		ts, err := newTokenSource(reg, regionCredentials[reg.Name])
+		if err != nil {
+			return Resolver{}, err
+		}
+
+		if _, err := c.accessToken(ts); err != nil {
+			return Resolver{}, err
+		}
+
+		tokens[reg.Name] = ts
+	}
+
+	return Resolver{
+		BlizzardClient: c,
+		Reporter:       re,
+		API:            api,
+
+		tokens:          tokens,
+		connectedRealms: newConnectedRealmIndex(),
+
+		GetAuctionInfoURL: blizzard.DefaultGetAuctionInfoURL,
+		GetItemURL:        blizzard.DefaultGetItemURL,
+		GetItemClassesURL: blizzard.DefaultGetItemClassesURL,
+		GetStatusURL:      blizzard.DefaultGetStatusURL,
+	}, nil
+}
+
+// Resolver mirrors the blizzard resolver on top of a rate-limited client
+type Resolver struct {
+	BlizzardClient *Client
+	Reporter       metric.Reporter
+	API            API
+
+	// community api urls, where the game-data api has fixed urls
+	GetAuctionInfoURL blizzard.GetAuctionInfoURLFunc
+	GetItemURL        blizzard.GetItemURLFunc
+	GetItemClassesURL blizzard.GetItemClassesURLFunc
+	GetStatusURL      blizzard.GetStatusURLFunc
+
+	// access tokens by region, since china does not accept the tokens of the other regions
+	tokens map[blizzard.RegionName]*tokenSource
+
+	// connected-realm ids by realm, resolved lazily from the game-data api
+	connectedRealms *connectedRealmIndex
+}
+
+// Download authenticates with the region's access token when requested
+func (r Resolver) Download(reg sotah.Region, uri string, shouldAuthenticate bool) (ResponseMeta, error) {
+	return r.DownloadIfModifiedSince(reg, uri, shouldAuthenticate, time.Time{})
+}
+
+// DownloadIfModifiedSince responds with a not-modified status and no body when nothing has changed since the given
+// time, where a zero time downloads unconditionally
+func (r Resolver) DownloadIfModifiedSince(
+	reg sotah.Region,
+	uri string,
+	shouldAuthenticate bool,
+	ifModifiedSince time.Time,
+) (ResponseMeta, error) {
+	var ts *tokenSource
+	if shouldAuthenticate {
+		regionToken, ok := r.tokens[reg.Name]
+		if !ok {
+			return ResponseMeta{}, fmt.Errorf("no access token for region %s", reg.Name)
+		}
+
+		ts = regionToken
+	}
+
+	resp, err := r.BlizzardClient.download(uri, ts, ifModifiedSince)
+	if resp.RequestDuration > 0 || resp.ConnectionDuration > 0 {
+		r.Reporter.Report(metric.Metrics{
+			"conn_duration":    int(resp.ConnectionDuration / 1000 / 1000),
+			"request_duration": int(resp.RequestDuration / 1000 / 1000),
+		})
+	}
+
+	if err != nil {
+		return ResponseMeta{}, err
+	}
+
+	return resp, nil
+}
+
+// ResponseMeta extends the blizzard response meta with the headers that the game-data api relies on
+type ResponseMeta struct {
+	blizzard.ResponseMeta
+
+	LastModified time.Time
+}
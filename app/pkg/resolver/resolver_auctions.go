@@ -0,0 +1,192 @@
+package resolver
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/blizzard"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/logging"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/sotah"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/util"
+)
+
+func (r Resolver) NewAuctionInfoFromHTTP(reg sotah.Region, uri string) (blizzard.AuctionInfo, error) {
+	resp, err := r.Download(reg, uri, true)
+	if err != nil {
+		return blizzard.AuctionInfo{}, err
+	}
+	if resp.Status != http.StatusOK {
+		return blizzard.AuctionInfo{}, fmt.Errorf("auction-info response status was not 200: %d", resp.Status)
+	}
+
+	return blizzard.NewAuctionInfo(resp.Body)
+}
+
+// NewAuctionsFromHTTP reports whether the file was modified since the given time, where a zero time always downloads
+func (r Resolver) NewAuctionsFromHTTP(
+	reg sotah.Region,
+	uri string,
+	ifModifiedSince time.Time,
+) (blizzard.Auctions, bool, error) {
+	resp, err := r.DownloadIfModifiedSince(reg, uri, false, ifModifiedSince)
+	if err != nil {
+		return blizzard.Auctions{}, false, err
+	}
+	if resp.Status == http.StatusNotModified {
+		return blizzard.Auctions{}, false, nil
+	}
+	if resp.Status != http.StatusOK {
+		return blizzard.Auctions{}, false, fmt.Errorf("auctions response status was not 200: %d", resp.Status)
+	}
+
+	aucs, err := blizzard.NewAuctions(resp.Body)
+	if err != nil {
+		return blizzard.Auctions{}, false, err
+	}
+
+	return aucs, true, nil
+}
+
+// downloadedSince is the last-modified of the realm's last download, where a realm never downloaded has none
+func downloadedSince(realmModDates sotah.RealmModificationDates) time.Time {
+	if realmModDates.Downloaded == 0 {
+		return time.Time{}
+	}
+
+	return time.Unix(realmModDates.Downloaded, 0)
+}
+
+// GetAuctionsForRealm returns a zero last-modified when the realm has nothing newer than its last download
+func (r Resolver) GetAuctionsForRealm(
+	rea sotah.Realm,
+	realmModDates sotah.RealmModificationDates,
+) (blizzard.Auctions, time.Time, error) {
+	if r.API == GameDataAPI {
+		return r.getConnectedRealmAuctions(rea, realmModDates)
+	}
+
+	// resolving auction-info from the api
+	aInfo, err := r.NewAuctionInfoFromHTTP(rea.Region, r.GetAuctionInfoURL(APIHostname(rea.Region), rea.Slug))
+	if err != nil {
+		return blizzard.Auctions{}, time.Time{}, err
+	}
+
+	// validating the list of files
+	if len(aInfo.Files) == 0 {
+		return blizzard.Auctions{}, time.Time{}, errors.New("cannot fetch auctions with blank files")
+	}
+	aFile := aInfo.Files[0]
+
+	entry := logging.WithFields(logrus.Fields{
+		"region":     rea.Region.Name,
+		"realm":      rea.Slug,
+		"downloaded": realmModDates.Downloaded,
+	})
+
+	// optionally downloading where the realm has stale data
+	if realmModDates.Downloaded == 0 || time.Unix(realmModDates.Downloaded, 0).Before(aFile.LastModifiedAsTime()) {
+		entry.Info("Downloading")
+
+		aucs, isModified, err := r.NewAuctionsFromHTTP(rea.Region, aFile.URL, downloadedSince(realmModDates))
+		if err != nil {
+			return blizzard.Auctions{}, time.Time{}, err
+		}
+		if !isModified {
+			entry.Info("Auctions file was not modified, skipping")
+
+			return blizzard.Auctions{}, time.Time{}, nil
+		}
+
+		return aucs, aFile.LastModifiedAsTime(), nil
+	}
+
+	entry.Info("No new auctions found, skipping")
+
+	return blizzard.Auctions{}, time.Time{}, nil
+}
+
+type GetAuctionsJob struct {
+	Err          error
+	Realm        sotah.Realm
+	Auctions     blizzard.Auctions
+	LastModified time.Time
+
+	// the realm had nothing newer than its last download
+	Unchanged bool
+}
+
+func (job GetAuctionsJob) ToLogrusFields() logrus.Fields {
+	return logrus.Fields{
+		"error":         job.Err.Error(),
+		"region":        job.Realm.Region.Name,
+		"realm":         job.Realm.Slug,
+		"last-modified": job.LastModified.Unix(),
+	}
+}
+
+func (r Resolver) GetAuctionsForRealms(
+	reas sotah.Realms,
+	modDates sotah.RegionRealmModificationDates,
+) chan GetAuctionsJob {
+	// establishing channels
+	out := make(chan GetAuctionsJob)
+	in := make(chan sotah.Realm)
+
+	// spinning up the workers for fetching auctions
+	worker := func() {
+		for rea := range in {
+			aucs, lastModified, err := r.GetAuctionsForRealm(rea, modDates.Get(rea.Region.Name, rea.Slug))
+
+			// optionally halting on error
+			if err != nil {
+				out <- GetAuctionsJob{err, rea, blizzard.Auctions{}, lastModified, false}
+
+				continue
+			}
+
+			// optionally draining out an unchanged job due to no new data
+			if lastModified.IsZero() {
+				logging.WithFields(logrus.Fields{
+					"region": rea.Region.Name,
+					"realm":  rea.Slug,
+				}).Info("No auctions received")
+
+				out <- GetAuctionsJob{nil, rea, blizzard.Auctions{}, lastModified, true}
+
+				continue
+			}
+
+			// draining out valid data received
+			logging.WithFields(logrus.Fields{
+				"region":   rea.Region.Name,
+				"realm":    rea.Slug,
+				"auctions": len(aucs.Auctions),
+			}).Debug("Auctions received")
+
+			out <- GetAuctionsJob{nil, rea, aucs, lastModified, false}
+		}
+	}
+	postWork := func() {
+		close(out)
+	}
+	util.Work(4, worker, postWork)
+
+	// queueing up the realms
+	go func() {
+		for _, rea := range reas {
+			logging.WithFields(logrus.Fields{
+				"region": rea.Region.Name,
+				"realm":  rea.Slug,
+			}).Debug("Queueing up auction for downloading")
+
+			in <- rea
+		}
+
+		close(in)
+	}()
+
+	return out
+}
@@ -0,0 +1,218 @@
+package resolver
+
+import (
+	"fmt"
+	"io/ioutil"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/http/httptrace"
+	"strconv"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/config"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/blizzard"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/logging"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/util"
+)
+
+const (
+	retryBaseDelay = 500 * time.Millisecond
+	retryMaxDelay  = 30 * time.Second
+)
+
+// newTransport is shared by every request of a client, so that connections to each host are pooled
+func newTransport() *http.Transport {
+	return &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: (&net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ResponseHeaderTimeout: 60 * time.Second,
+		MaxIdleConns:          100,
+		MaxIdleConnsPerHost:   16,
+		IdleConnTimeout:       90 * time.Second,
+	}
+}
+
+func NewClient(c config.BlizzardConfig) *Client {
+	return &Client{
+		httpClient: &http.Client{Transport: newTransport()},
+		limiter:    NewRateLimiter(c.ResolveRequestsPerSecond(), c.ResolveRequestsPerHour()),
+		maxRetries: c.ResolveMaxRetries(),
+		sleep:      time.Sleep,
+	}
+}
+
+// Client queries the blizzard api within its quotas and retries transient failures, where every region's requests
+// share the quotas
+type Client struct {
+	httpClient *http.Client
+	limiter    *RateLimiter
+	maxRetries int
+	sleep      func(time.Duration)
+}
+
+// download performs a GET request, authenticated when given a token source, where a token rejected ahead of its
+// expiry is refreshed and the request resent once, and a non-zero if-modified-since makes the request conditional
+func (c *Client) download(uri string, ts *tokenSource, ifModifiedSince time.Time) (ResponseMeta, error) {
+	sentToken := ""
+	newRequest := func() (*http.Request, error) {
+		req, err := http.NewRequest("GET", uri, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		if ts != nil {
+			token, err := c.accessToken(ts)
+			if err != nil {
+				return nil, err
+			}
+			sentToken = token
+
+			req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+		}
+
+		if !ifModifiedSince.IsZero() {
+			req.Header.Set("If-Modified-Since", ifModifiedSince.UTC().Format(http.TimeFormat))
+		}
+
+		return req, nil
+	}
+
+	resp, err := c.do(newRequest, true)
+	if err != nil || resp.Status != http.StatusUnauthorized || ts == nil {
+		return resp, err
+	}
+
+	logging.WithField("uri", uri).Warn("Access token was rejected, refreshing")
+	c.invalidateToken(ts, sentToken)
+
+	return c.do(newRequest, true)
+}
+
+// do sends a request until it succeeds, fails permanently or runs out of retries, where the last response is returned
+func (c *Client) do(newRequest func() (*http.Request, error), isLimited bool) (ResponseMeta, error) {
+	for attempt := 0; ; attempt++ {
+		req, err := newRequest()
+		if err != nil {
+			return ResponseMeta{}, err
+		}
+
+		if isLimited {
+			c.limiter.Wait()
+		}
+
+		resp, retryAfter, err := c.send(req)
+		if !isRetryable(resp.Status, err) || attempt >= c.maxRetries {
+			return resp, err
+		}
+
+		delay := retryDelay(attempt, retryAfter)
+		entry := logging.WithFields(logrus.Fields{
+			"host":    req.URL.Host,
+			"path":    req.URL.Path,
+			"attempt": attempt + 1,
+			"delay":   delay.String(),
+		})
+		if err != nil {
+			entry = entry.WithField("error", err.Error())
+		} else {
+			entry = entry.WithField("status", resp.Status)
+		}
+		entry.Warn("Retrying blizzard api request")
+
+		c.sleep(delay)
+	}
+}
+
+// send performs a single request, reading and optionally decoding its body along with any retry-after hint
+func (c *Client) send(req *http.Request) (ResponseMeta, time.Duration, error) {
+	req.Header.Add("Accept-Encoding", "gzip")
+
+	// timing new connections, where a pooled connection has none
+	var connStart, connEnd time.Time
+	trace := &httptrace.ClientTrace{
+		ConnectStart: func(network, addr string) {
+			connStart = time.Now()
+		},
+		ConnectDone: func(network, addr string, err error) {
+			connEnd = time.Now()
+		},
+	}
+	req = req.WithContext(httptrace.WithClientTrace(req.Context(), trace))
+
+	startTime := time.Now()
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return ResponseMeta{}, 0, err
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			logging.WithField("error", err.Error()).Error("Failed to close response body")
+		}
+	}()
+
+	connDuration := connEnd.Sub(connStart)
+	respMeta := ResponseMeta{
+		ResponseMeta: blizzard.ResponseMeta{
+			Body:               []byte{},
+			Status:             resp.StatusCode,
+			ConnectionDuration: connDuration,
+		},
+	}
+	if lastModified, err := http.ParseTime(resp.Header.Get("Last-Modified")); err == nil {
+		respMeta.LastModified = lastModified
+	}
+	retryAfter := parseRetryAfter(resp.Header.Get("Retry-After"))
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return respMeta, retryAfter, err
+	}
+	respMeta.ContentLength = len(body)
+	respMeta.RequestDuration = time.Since(startTime) - connDuration
+
+	// optionally decoding the response body, where a not-modified response has none
+	if len(body) > 0 && resp.Header.Get("Content-Encoding") == "gzip" {
+		body, err = util.GzipDecode(body)
+		if err != nil {
+			return respMeta, retryAfter, err
+		}
+	}
+	respMeta.Body = body
+
+	return respMeta, retryAfter, nil
+}
+
+func isRetryable(status int, err error) bool {
+	return err != nil || status == http.StatusTooManyRequests || status >= http.StatusInternalServerError
+}
+
+// retryDelay backs off exponentially with jitter, so that concurrent workers do not retry in lockstep
+func retryDelay(attempt int, retryAfter time.Duration) time.Duration {
+	delay := retryBaseDelay << uint(attempt)
+	if delay > retryMaxDelay || delay <= 0 {
+		delay = retryMaxDelay
+	}
+	delay = delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+
+	if retryAfter > delay {
+		return retryAfter
+	}
+
+	return delay
+}
+
+// parseRetryAfter reads the seconds form of the header, where blizzard does not send the date form
+func parseRetryAfter(value string) time.Duration {
+	seconds, err := strconv.Atoi(value)
+	if err != nil || seconds <= 0 {
+		return 0
+	}
+
+	return time.Duration(seconds) * time.Second
+}
@@ -0,0 +1,218 @@
+package resolver
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func newTestClient(handler http.Handler) (*Client, string, *fakeClock, func()) {
+	server := httptest.NewServer(handler)
+	clock := &fakeClock{now: time.Unix(1000, 0)}
+
+	c := &Client{
+		httpClient: server.Client(),
+		limiter:    newRateLimiter(100, 100000, clock.Now, clock.Sleep),
+		maxRetries: 3,
+		sleep:      clock.Sleep,
+	}
+
+	return c, server.URL, clock, server.Close
+}
+
+func TestRetryDelay(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		if delay := retryDelay(0, 0); delay < retryBaseDelay/2 || delay > retryBaseDelay {
+			t.Fatalf("expected the first delay to be jittered within the base delay, got %s", delay)
+		}
+		if delay := retryDelay(2, 0); delay < retryBaseDelay*2 || delay > retryBaseDelay*4 {
+			t.Fatalf("expected the delay to back off exponentially, got %s", delay)
+		}
+		if delay := retryDelay(100, 0); delay < retryMaxDelay/2 || delay > retryMaxDelay {
+			t.Fatalf("expected the delay to be capped, got %s", delay)
+		}
+	}
+
+	if delay := retryDelay(0, time.Minute); delay != time.Minute {
+		t.Fatalf("expected a longer retry-after to be honoured, got %s", delay)
+	}
+	if delay := retryDelay(5, time.Millisecond); delay < retryBaseDelay*16 {
+		t.Fatalf("expected a shorter retry-after to not shorten the backoff, got %s", delay)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	tests := map[string]time.Duration{
+		"120":                           2 * time.Minute,
+		"":                              0,
+		"0":                             0,
+		"-5":                            0,
+		"Wed, 21 Oct 2015 07:28:00 GMT": 0,
+	}
+	for value, expected := range tests {
+		if actual := parseRetryAfter(value); actual != expected {
+			t.Errorf("expected %q to parse to %s, got %s", value, expected, actual)
+		}
+	}
+}
+
+func TestClientDownloadRetries(t *testing.T) {
+	requests := 0
+	c, url, clock, cleanup := newTestClient(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		switch requests {
+		case 1:
+			w.Header().Set("Retry-After", "45")
+			w.WriteHeader(http.StatusTooManyRequests)
+		case 2:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		default:
+			fmt.Fprint(w, "ok")
+		}
+	}))
+	defer cleanup()
+
+	resp, err := c.download(url, nil, time.Time{})
+	if err != nil {
+		t.Fatalf("could not download: %s", err)
+	}
+
+	if resp.Status != http.StatusOK || string(resp.Body) != "ok" || requests != 3 {
+		t.Fatalf("expected the request to succeed on its third attempt, got %d after %d", resp.Status, requests)
+	}
+	if len(clock.slept) != 2 || clock.slept[0] != 45*time.Second {
+		t.Fatalf("expected the retry-after to be honoured, got %v", clock.slept)
+	}
+	if clock.slept[1] < retryBaseDelay || clock.slept[1] > retryBaseDelay*2 {
+		t.Fatalf("expected the second retry to back off, got %s", clock.slept[1])
+	}
+}
+
+func TestClientDownloadGivesUp(t *testing.T) {
+	requests := 0
+	c, url, clock, cleanup := newTestClient(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer cleanup()
+
+	resp, err := c.download(url, nil, time.Time{})
+	if err != nil {
+		t.Fatalf("could not download: %s", err)
+	}
+
+	if resp.Status != http.StatusBadGateway || requests != c.maxRetries+1 || len(clock.slept) != c.maxRetries {
+		t.Fatalf("expected the last response after every retry, got %d after %d", resp.Status, requests)
+	}
+}
+
+func TestClientDownloadPermanentFailure(t *testing.T) {
+	requests := 0
+	c, url, clock, cleanup := newTestClient(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer cleanup()
+
+	resp, err := c.download(url, nil, time.Time{})
+	if err != nil {
+		t.Fatalf("could not download: %s", err)
+	}
+
+	if resp.Status != http.StatusNotFound || requests != 1 || len(clock.slept) != 0 {
+		t.Fatalf("expected a permanent failure to not be retried, got %d after %d", resp.Status, requests)
+	}
+}
+
+func TestClientDownloadRefreshesRejectedToken(t *testing.T) {
+	tokenRequests := 0
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		if id, secret, ok := r.BasicAuth(); !ok || id != "id" || secret != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+
+			return
+		}
+
+		tokenRequests++
+		fmt.Fprintf(w, `{"access_token":"token-%d","token_type":"bearer","expires_in":86400}`, tokenRequests)
+	})
+	mux.HandleFunc("/resource", func(w http.ResponseWriter, r *http.Request) {
+		// the first token was revoked ahead of its expiry
+		if r.Header.Get("Authorization") != "Bearer token-2" {
+			w.WriteHeader(http.StatusUnauthorized)
+
+			return
+		}
+
+		fmt.Fprint(w, "ok")
+	})
+	c, url, _, cleanup := newTestClient(mux)
+	defer cleanup()
+
+	ts := &tokenSource{endpoint: url + "/token", id: "id", secret: "secret", mutex: &sync.Mutex{}}
+
+	resp, err := c.download(url+"/resource", ts, time.Time{})
+	if err != nil {
+		t.Fatalf("could not download: %s", err)
+	}
+	if resp.Status != http.StatusOK || tokenRequests != 2 {
+		t.Fatalf("expected the rejected token to be refreshed once, got %d after %d tokens", resp.Status, tokenRequests)
+	}
+
+	resp, err = c.download(url+"/resource", ts, time.Time{})
+	if err != nil {
+		t.Fatalf("could not download: %s", err)
+	}
+	if resp.Status != http.StatusOK || tokenRequests != 2 {
+		t.Fatalf("expected the refreshed token to be reused, got %d after %d tokens", resp.Status, tokenRequests)
+	}
+}
+
+func TestClientDownloadRejectedTwice(t *testing.T) {
+	tokenRequests := 0
+	resourceRequests := 0
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		tokenRequests++
+		fmt.Fprintf(w, `{"access_token":"token-%d","token_type":"bearer","expires_in":86400}`, tokenRequests)
+	})
+	mux.HandleFunc("/resource", func(w http.ResponseWriter, r *http.Request) {
+		resourceRequests++
+		w.WriteHeader(http.StatusUnauthorized)
+	})
+	c, url, _, cleanup := newTestClient(mux)
+	defer cleanup()
+
+	ts := &tokenSource{endpoint: url + "/token", id: "id", secret: "secret", mutex: &sync.Mutex{}}
+
+	resp, err := c.download(url+"/resource", ts, time.Time{})
+	if err != nil {
+		t.Fatalf("could not download: %s", err)
+	}
+	if resp.Status != http.StatusUnauthorized || tokenRequests != 2 || resourceRequests != 2 {
+		t.Fatalf(
+			"expected a single refresh before giving up, got %d after %d tokens and %d requests",
+			resp.Status,
+			tokenRequests,
+			resourceRequests,
+		)
+	}
+}
+
+func TestAccessTokenIsExpiring(t *testing.T) {
+	now := time.Unix(1000, 0)
+
+	if !(accessToken{}).isExpiring(now) {
+		t.Fatal("expected a blank token to be expiring")
+	}
+	if (accessToken{value: "a", expiresAt: now.Add(time.Hour)}).isExpiring(now) {
+		t.Fatal("expected a fresh token to not be expiring")
+	}
+	if !(accessToken{value: "a", expiresAt: now.Add(tokenExpiryMargin - time.Second)}).isExpiring(now) {
+		t.Fatal("expected a token within the margin to be expiring")
+	}
+}
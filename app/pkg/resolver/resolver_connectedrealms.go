@@ -0,0 +1,400 @@
+package resolver
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/blizzard"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/blizzard/realmpopulations"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/blizzard/realmtypes"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/logging"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/sotah"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/util"
+)
+
+func newConnectedRealmIndex() *connectedRealmIndex {
+	return &connectedRealmIndex{ids: map[blizzard.RegionName]map[blizzard.RealmSlug]ConnectedRealmID{}}
+}
+
+type connectedRealmIndex struct {
+	sync.Mutex
+
+	ids map[blizzard.RegionName]map[blizzard.RealmSlug]ConnectedRealmID
+}
+
+func (index *connectedRealmIndex) get(
+	regionName blizzard.RegionName,
+	slug blizzard.RealmSlug,
+) (ConnectedRealmID, bool) {
+	index.Lock()
+	defer index.Unlock()
+
+	ID, ok := index.ids[regionName][slug]
+
+	return ID, ok
+}
+
+func (index *connectedRealmIndex) set(regionName blizzard.RegionName, cRealms []connectedRealm) {
+	index.Lock()
+	defer index.Unlock()
+
+	regionIds := map[blizzard.RealmSlug]ConnectedRealmID{}
+	for _, cRealm := range cRealms {
+		for _, rea := range cRealm.Realms {
+			regionIds[rea.Slug] = cRealm.ID
+		}
+	}
+	index.ids[regionName] = regionIds
+}
+
+// slugs gathers every realm of a connected realm
+func (index *connectedRealmIndex) slugs(regionName blizzard.RegionName, ID ConnectedRealmID) []blizzard.RealmSlug {
+	index.Lock()
+	defer index.Unlock()
+
+	out := []blizzard.RealmSlug{}
+	for slug, realmID := range index.ids[regionName] {
+		if realmID == ID {
+			out = append(out, slug)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return out[i] < out[j]
+	})
+
+	return out
+}
+
+type connectedRealmIndexResponse struct {
+	ConnectedRealms []gameDataHref `json:"connected_realms"`
+}
+
+// ids parses each connected-realm id from the trailing segment of its document link
+func (resp connectedRealmIndexResponse) ids() ([]ConnectedRealmID, error) {
+	out := []ConnectedRealmID{}
+	for _, cRealmHref := range resp.ConnectedRealms {
+		u, err := url.Parse(cRealmHref.Href)
+		if err != nil {
+			return []ConnectedRealmID{}, err
+		}
+
+		ID, err := strconv.ParseInt(path.Base(u.Path), 10, 64)
+		if err != nil {
+			return []ConnectedRealmID{}, fmt.Errorf("invalid connected-realm link: %s", cRealmHref.Href)
+		}
+
+		out = append(out, ConnectedRealmID(ID))
+	}
+
+	return out, nil
+}
+
+type connectedRealmRealm struct {
+	ID       int64              `json:"id"`
+	Name     string             `json:"name"`
+	Slug     blizzard.RealmSlug `json:"slug"`
+	Locale   string             `json:"locale"`
+	Timezone string             `json:"timezone"`
+	Type     gameDataType       `json:"type"`
+}
+
+type connectedRealm struct {
+	ID         ConnectedRealmID      `json:"id"`
+	HasQueue   bool                  `json:"has_queue"`
+	Status     gameDataType          `json:"status"`
+	Population gameDataType          `json:"population"`
+	Realms     []connectedRealmRealm `json:"realms"`
+}
+
+// toRealms maps the connected realm onto the community realm shape, where every realm lists its connected realms
+func (cRealm connectedRealm) toRealms() []blizzard.Realm {
+	slugs := []blizzard.RealmSlug{}
+	for _, rea := range cRealm.Realms {
+		slugs = append(slugs, rea.Slug)
+	}
+
+	out := []blizzard.Realm{}
+	for _, rea := range cRealm.Realms {
+		out = append(out, blizzard.Realm{
+			Type:            newRealmType(rea.Type),
+			Population:      newRealmPopulation(cRealm.Population),
+			Queue:           cRealm.HasQueue,
+			Status:          cRealm.Status.Type == "UP",
+			Name:            rea.Name,
+			Slug:            rea.Slug,
+			Locale:          rea.Locale,
+			Timezone:        rea.Timezone,
+			ConnectedRealms: slugs,
+		})
+	}
+
+	return out
+}
+
+func newRealmType(t gameDataType) realmtypes.RealmType {
+	switch t.Type {
+	case "PVP":
+		return realmtypes.Pvp
+	case "RP":
+		return realmtypes.Rp
+	case "RPPVP", "RP_PVP":
+		return realmtypes.RpPvp
+	default:
+		return realmtypes.Pve
+	}
+}
+
+func newRealmPopulation(t gameDataType) realmpopulations.RealmPopulation {
+	switch t.Type {
+	case "FULL", "HIGH":
+		return realmpopulations.High
+	case "MEDIUM":
+		return realmpopulations.Medium
+	case "LOW", "NEW":
+		return realmpopulations.Low
+	default:
+		return realmpopulations.Unknown
+	}
+}
+
+type realmIndexRealm struct {
+	ID   int64              `json:"id"`
+	Name string             `json:"name"`
+	Slug blizzard.RealmSlug `json:"slug"`
+}
+
+type realmIndexResponse struct {
+	Realms []realmIndexRealm `json:"realms"`
+}
+
+// downloadGameData gathers a game-data document, where a missing document is reported as not existing
+func (r Resolver) downloadGameData(reg sotah.Region, uri string, out interface{}) (bool, error) {
+	resp, err := r.Download(reg, uri, true)
+	if err != nil {
+		return false, err
+	}
+	if resp.Status == http.StatusNotFound {
+		return false, nil
+	}
+	if resp.Status != http.StatusOK {
+		return false, fmt.Errorf("unexpected status for %s: %d", uri, resp.Status)
+	}
+
+	if err := json.Unmarshal(resp.Body, out); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (r Resolver) newRealmIndex(reg sotah.Region) ([]realmIndexRealm, error) {
+	resp := &realmIndexResponse{}
+	exists, err := r.downloadGameData(reg, getRealmIndexURL(reg), resp)
+	if err != nil {
+		return []realmIndexRealm{}, err
+	}
+	if !exists {
+		return []realmIndexRealm{}, fmt.Errorf("realm index not found for region %s", reg.Name)
+	}
+
+	return resp.Realms, nil
+}
+
+// newConnectedRealms gathers every connected realm of a region, keeping their ids for resolving auctions
+func (r Resolver) newConnectedRealms(reg sotah.Region) ([]connectedRealm, error) {
+	indexResp := &connectedRealmIndexResponse{}
+	exists, err := r.downloadGameData(reg, getConnectedRealmIndexURL(reg), indexResp)
+	if err != nil {
+		return []connectedRealm{}, err
+	}
+	if !exists {
+		return []connectedRealm{}, fmt.Errorf("connected-realm index not found for region %s", reg.Name)
+	}
+
+	IDs, err := indexResp.ids()
+	if err != nil {
+		return []connectedRealm{}, err
+	}
+
+	// establishing channels
+	type connectedRealmJob struct {
+		err    error
+		ID     ConnectedRealmID
+		cRealm connectedRealm
+		exists bool
+	}
+	out := make(chan connectedRealmJob)
+	in := make(chan ConnectedRealmID)
+
+	// spinning up the workers for fetching connected realms
+	worker := func() {
+		for ID := range in {
+			cRealm := connectedRealm{}
+			exists, err := r.downloadGameData(reg, getConnectedRealmURL(reg, ID), &cRealm)
+			out <- connectedRealmJob{err, ID, cRealm, exists}
+		}
+	}
+	postWork := func() {
+		close(out)
+	}
+	util.Work(8, worker, postWork)
+
+	// queueing up the connected realms
+	go func() {
+		for _, ID := range IDs {
+			in <- ID
+		}
+
+		close(in)
+	}()
+
+	cRealms := []connectedRealm{}
+	var firstErr error
+	for job := range out {
+		if job.err != nil {
+			if firstErr == nil {
+				firstErr = job.err
+			}
+
+			continue
+		}
+
+		if !job.exists {
+			logging.WithFields(logrus.Fields{
+				"region":          reg.Name,
+				"connected-realm": job.ID,
+			}).Warn("Connected realm was not found")
+
+			continue
+		}
+
+		cRealms = append(cRealms, job.cRealm)
+	}
+	if firstErr != nil {
+		return []connectedRealm{}, firstErr
+	}
+
+	r.connectedRealms.set(reg.Name, cRealms)
+
+	return cRealms, nil
+}
+
+// connectedRealmID resolves the connected realm of a realm, gathering the region's connected realms when unknown
+func (r Resolver) connectedRealmID(rea sotah.Realm) (ConnectedRealmID, error) {
+	if ID, ok := r.connectedRealms.get(rea.Region.Name, rea.Slug); ok {
+		return ID, nil
+	}
+
+	if _, err := r.newConnectedRealms(rea.Region); err != nil {
+		return 0, err
+	}
+
+	ID, ok := r.connectedRealms.get(rea.Region.Name, rea.Slug)
+	if !ok {
+		return 0, fmt.Errorf("realm %s has no connected realm", rea.Slug)
+	}
+
+	return ID, nil
+}
+
+func (r Resolver) NewStatus(reg sotah.Region) (sotah.Status, error) {
+	if r.API == GameDataAPI {
+		return r.newGameDataStatus(reg)
+	}
+
+	resp, err := r.Download(reg, r.GetStatusURL(APIHostname(reg)), true)
+	if err != nil {
+		return sotah.Status{}, err
+	}
+	if resp.Status != http.StatusOK {
+		return sotah.Status{}, fmt.Errorf("status response status was not 200: %d", resp.Status)
+	}
+
+	stat, err := blizzard.NewStatus(resp.Body)
+	if err != nil {
+		return sotah.Status{}, err
+	}
+
+	return sotah.Status{Status: stat, Region: reg, Realms: sotah.NewRealms(reg, stat.Realms)}, nil
+}
+
+// newGameDataStatus lists the realms of the realm index, filled in from their connected realms
+func (r Resolver) newGameDataStatus(reg sotah.Region) (sotah.Status, error) {
+	realmIndex, err := r.newRealmIndex(reg)
+	if err != nil {
+		return sotah.Status{}, err
+	}
+
+	cRealms, err := r.newConnectedRealms(reg)
+	if err != nil {
+		return sotah.Status{}, err
+	}
+
+	connectedRealms := map[blizzard.RealmSlug]blizzard.Realm{}
+	for _, cRealm := range cRealms {
+		for _, rea := range cRealm.toRealms() {
+			connectedRealms[rea.Slug] = rea
+		}
+	}
+
+	realms := []blizzard.Realm{}
+	for _, indexRealm := range realmIndex {
+		rea, ok := connectedRealms[indexRealm.Slug]
+		if !ok {
+			rea = blizzard.Realm{
+				Name:       indexRealm.Name,
+				Slug:       indexRealm.Slug,
+				Type:       realmtypes.Pve,
+				Population: realmpopulations.Unknown,
+			}
+		}
+
+		realms = append(realms, rea)
+	}
+
+	stat := blizzard.Status{Realms: realms}
+
+	return sotah.Status{Status: stat, Region: reg, Realms: sotah.NewRealms(reg, stat.Realms)}, nil
+}
+
+type GetStatusesJob struct {
+	Err    error
+	Region sotah.Region
+	Status sotah.Status
+}
+
+func (r Resolver) GetStatuses(regions sotah.RegionList) chan GetStatusesJob {
+	// establishing channels
+	out := make(chan GetStatusesJob)
+	in := make(chan sotah.Region)
+
+	// spinning up the workers for fetching statuses
+	worker := func() {
+		for region := range in {
+			status, err := r.NewStatus(region)
+			out <- GetStatusesJob{err, region, status}
+		}
+	}
+	postWork := func() {
+		close(out)
+	}
+	util.Work(4, worker, postWork)
+
+	// queueing up the regions
+	go func() {
+		for _, region := range regions {
+			in <- region
+		}
+
+		close(in)
+	}()
+
+	return out
+}
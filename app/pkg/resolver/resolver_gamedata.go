@@ -0,0 +1,73 @@
+package resolver
+
+import (
+	"fmt"
+
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/blizzard"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/sotah"
+)
+
+const (
+	realmIndexURLFormat             = "https://%s/data/wow/realm/index?namespace=dynamic-%s&locale=en_US"
+	connectedRealmIndexURLFormat    = "https://%s/data/wow/connected-realm/index?namespace=dynamic-%s&locale=en_US"
+	connectedRealmURLFormat         = "https://%s/data/wow/connected-realm/%d?namespace=dynamic-%s&locale=en_US"
+	connectedRealmAuctionsURLFormat = "https://%s/data/wow/connected-realm/%d/auctions?namespace=dynamic-%s"
+	gameDataItemURLFormat           = "https://%s/data/wow/item/%d?namespace=static-%s&locale=en_US"
+	gameDataItemMediaURLFormat      = "https://%s/data/wow/media/item/%d?namespace=static-%s&locale=en_US"
+	itemClassIndexURLFormat         = "https://%s/data/wow/item-class/index?namespace=static-%s&locale=en_US"
+	itemClassURLFormat              = "https://%s/data/wow/item-class/%d?namespace=static-%s&locale=en_US"
+)
+
+// ConnectedRealmID is the game-data identifier of realms sharing an auction house
+type ConnectedRealmID int64
+
+func getRealmIndexURL(reg sotah.Region) string {
+	return fmt.Sprintf(realmIndexURLFormat, APIHostname(reg), reg.Name)
+}
+
+func getConnectedRealmIndexURL(reg sotah.Region) string {
+	return fmt.Sprintf(connectedRealmIndexURLFormat, APIHostname(reg), reg.Name)
+}
+
+func getConnectedRealmURL(reg sotah.Region, ID ConnectedRealmID) string {
+	return fmt.Sprintf(connectedRealmURLFormat, APIHostname(reg), ID, reg.Name)
+}
+
+func getConnectedRealmAuctionsURL(reg sotah.Region, ID ConnectedRealmID) string {
+	return fmt.Sprintf(connectedRealmAuctionsURLFormat, APIHostname(reg), ID, reg.Name)
+}
+
+func getGameDataItemURL(primaryRegion sotah.Region, ID blizzard.ItemID) string {
+	return fmt.Sprintf(gameDataItemURLFormat, APIHostname(primaryRegion), ID, primaryRegion.Name)
+}
+
+func getGameDataItemMediaURL(primaryRegion sotah.Region, ID blizzard.ItemID) string {
+	return fmt.Sprintf(gameDataItemMediaURLFormat, APIHostname(primaryRegion), ID, primaryRegion.Name)
+}
+
+func getItemClassIndexURL(primaryRegion sotah.Region) string {
+	return fmt.Sprintf(itemClassIndexURLFormat, APIHostname(primaryRegion), primaryRegion.Name)
+}
+
+func getItemClassURL(primaryRegion sotah.Region, ID blizzard.ItemClassClass) string {
+	return fmt.Sprintf(itemClassURLFormat, APIHostname(primaryRegion), ID, primaryRegion.Name)
+}
+
+// gameDataType is the enum shape of the game-data api, where type is the stable key and name is localized
+type gameDataType struct {
+	Type string `json:"type"`
+	Name string `json:"name"`
+}
+
+type gameDataReference struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+type gameDataHref struct {
+	Href string `json:"href"`
+}
+
+type gameDataValue struct {
+	Value int `json:"value"`
+}
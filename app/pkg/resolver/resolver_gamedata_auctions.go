@@ -0,0 +1,131 @@
+package resolver
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/pricelists"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/blizzard"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/logging"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/sotah"
+)
+
+type gameDataAuctionItem struct {
+	ID         blizzard.ItemID `json:"id"`
+	Context    int64           `json:"context"`
+	BonusLists []int64         `json:"bonus_lists"`
+}
+
+type gameDataAuction struct {
+	ID       int64               `json:"id"`
+	Item     gameDataAuctionItem `json:"item"`
+	Bid      int64               `json:"bid"`
+	Buyout   int64               `json:"buyout"`
+	Quantity int64               `json:"quantity"`
+	TimeLeft string              `json:"time_left"`
+
+	// commodities are priced per unit rather than with a buyout
+	UnitPrice int64 `json:"unit_price"`
+}
+
+// toAuction maps onto the community auction shape, where the game-data api has no owners and its bonus lists stand in
+// for the random suffix
+func (gAuction gameDataAuction) toAuction() blizzard.Auction {
+	buyout := gAuction.Buyout
+	if buyout == 0 && gAuction.UnitPrice > 0 {
+		buyout = gAuction.UnitPrice * gAuction.Quantity
+	}
+
+	return blizzard.Auction{
+		Auc:      gAuction.ID,
+		Item:     gAuction.Item.ID,
+		Bid:      gAuction.Bid,
+		Buyout:   buyout,
+		Quantity: gAuction.Quantity,
+		TimeLeft: gAuction.TimeLeft,
+		Rand:     pricelists.NewBonusListsRand(gAuction.Item.BonusLists),
+		Context:  gAuction.Item.Context,
+	}
+}
+
+type gameDataAuctionsResponse struct {
+	Auctions []gameDataAuction `json:"auctions"`
+}
+
+func newAuctionsFromGameData(body []byte, realms []blizzard.AuctionRealm) (blizzard.Auctions, error) {
+	resp := &gameDataAuctionsResponse{}
+	if err := json.Unmarshal(body, resp); err != nil {
+		return blizzard.Auctions{}, err
+	}
+
+	out := blizzard.Auctions{Realms: realms, Auctions: make([]blizzard.Auction, len(resp.Auctions))}
+	for i, gAuction := range resp.Auctions {
+		out.Auctions[i] = gAuction.toAuction()
+	}
+
+	return out, nil
+}
+
+// getConnectedRealmAuctions downloads the auctions of a realm's connected realm, where the response's last-modified
+// stands in for the community api's auction-file last-modified and the request is conditional on the last download
+func (r Resolver) getConnectedRealmAuctions(
+	rea sotah.Realm,
+	realmModDates sotah.RealmModificationDates,
+) (blizzard.Auctions, time.Time, error) {
+	ID, err := r.connectedRealmID(rea)
+	if err != nil {
+		return blizzard.Auctions{}, time.Time{}, err
+	}
+
+	entry := logging.WithFields(logrus.Fields{
+		"region":          rea.Region.Name,
+		"realm":           rea.Slug,
+		"connected-realm": ID,
+		"downloaded":      realmModDates.Downloaded,
+	})
+	entry.Info("Downloading")
+
+	resp, err := r.DownloadIfModifiedSince(
+		rea.Region,
+		getConnectedRealmAuctionsURL(rea.Region, ID),
+		true,
+		downloadedSince(realmModDates),
+	)
+	if err != nil {
+		return blizzard.Auctions{}, time.Time{}, err
+	}
+	if resp.Status == http.StatusNotModified {
+		entry.Info("Auctions were not modified, skipping")
+
+		return blizzard.Auctions{}, time.Time{}, nil
+	}
+	if resp.Status != http.StatusOK {
+		return blizzard.Auctions{}, time.Time{}, fmt.Errorf("auctions response status was not 200: %d", resp.Status)
+	}
+
+	lastModified := resp.LastModified
+	if lastModified.IsZero() {
+		lastModified = time.Now()
+	}
+
+	if realmModDates.Downloaded > 0 && !time.Unix(realmModDates.Downloaded, 0).Before(lastModified) {
+		entry.Info("No new auctions found, skipping")
+
+		return blizzard.Auctions{}, time.Time{}, nil
+	}
+
+	realms := []blizzard.AuctionRealm{}
+	for _, slug := range r.connectedRealms.slugs(rea.Region.Name, ID) {
+		realms = append(realms, blizzard.AuctionRealm{Slug: slug})
+	}
+
+	aucs, err := newAuctionsFromGameData(resp.Body, realms)
+	if err != nil {
+		return blizzard.Auctions{}, time.Time{}, err
+	}
+
+	return aucs, lastModified, nil
+}
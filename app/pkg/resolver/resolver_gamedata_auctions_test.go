@@ -0,0 +1,45 @@
+package resolver
+
+import (
+	"testing"
+
+	"github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/pricelists"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/blizzard"
+)
+
+func TestNewAuctionsFromGameData(t *testing.T) {
+	body := []byte(`{"auctions":[
+		{"id":1,"item":{"id":19019,"context":5,"bonus_lists":[4795,1472]},"bid":50,"buyout":100,"quantity":1,
+			"time_left":"LONG"},
+		{"id":2,"item":{"id":2589},"unit_price":7,"quantity":20,"time_left":"SHORT"}
+	]}`)
+	realms := []blizzard.AuctionRealm{{Slug: "zuljin"}}
+
+	aucs, err := newAuctionsFromGameData(body, realms)
+	if err != nil {
+		t.Fatalf("could not parse auctions: %s", err)
+	}
+	if len(aucs.Auctions) != 2 || len(aucs.Realms) != 1 {
+		t.Fatalf("unexpected auctions: %+v", aucs)
+	}
+
+	expected := blizzard.Auction{
+		Auc:      1,
+		Item:     19019,
+		Bid:      50,
+		Buyout:   100,
+		Quantity: 1,
+		TimeLeft: "LONG",
+		Rand:     pricelists.NewBonusListsRand([]int64{1472, 4795}),
+		Context:  5,
+	}
+	if aucs.Auctions[0] != expected {
+		t.Fatalf("expected %+v, got %+v", expected, aucs.Auctions[0])
+	}
+
+	// commodities are priced per unit, without bonus lists
+	commodity := aucs.Auctions[1]
+	if commodity.Buyout != 140 || commodity.Rand != 0 || len(commodity.Owner) != 0 {
+		t.Fatalf("unexpected commodity: %+v", commodity)
+	}
+}
@@ -0,0 +1,197 @@
+package resolver
+
+import (
+	"encoding/json"
+	"path"
+	"strings"
+
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/blizzard"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/blizzard/itembinds"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/sotah"
+)
+
+// qualities and inventory-types are numbered as in the community api
+var (
+	gameDataItemQualities = map[string]int{
+		"POOR":      0,
+		"COMMON":    1,
+		"UNCOMMON":  2,
+		"RARE":      3,
+		"EPIC":      4,
+		"LEGENDARY": 5,
+		"ARTIFACT":  6,
+		"HEIRLOOM":  7,
+	}
+	gameDataInventoryTypes = map[string]int{
+		"NON_EQUIP":      0,
+		"HEAD":           1,
+		"NECK":           2,
+		"SHOULDER":       3,
+		"BODY":           4,
+		"CHEST":          5,
+		"WAIST":          6,
+		"LEGS":           7,
+		"FEET":           8,
+		"WRIST":          9,
+		"HAND":           10,
+		"FINGER":         11,
+		"TRINKET":        12,
+		"WEAPON":         13,
+		"SHIELD":         14,
+		"RANGED":         15,
+		"CLOAK":          16,
+		"TWOHWEAPON":     17,
+		"BAG":            18,
+		"TABARD":         19,
+		"ROBE":           20,
+		"WEAPONMAINHAND": 21,
+		"WEAPONOFFHAND":  22,
+		"HOLDABLE":       23,
+		"AMMO":           24,
+		"THROWN":         25,
+		"RANGEDRIGHT":    26,
+		"QUIVER":         27,
+		"RELIC":          28,
+	}
+)
+
+func newItemBind(t gameDataType) itembinds.ItemBind {
+	switch t.Type {
+	case "ON_ACQUIRE":
+		return itembinds.BindOnPickup
+	case "ON_EQUIP":
+		return itembinds.BindOnEquip
+	default:
+		return itembinds.None
+	}
+}
+
+type gameDataPreviewItem struct {
+	Binding     gameDataType  `json:"binding"`
+	Armor       gameDataValue `json:"armor"`
+	Durability  gameDataValue `json:"durability"`
+	Description string        `json:"description"`
+}
+
+type gameDataItem struct {
+	ID            blizzard.ItemID     `json:"id"`
+	Name          string              `json:"name"`
+	Quality       gameDataType        `json:"quality"`
+	Level         int                 `json:"level"`
+	RequiredLevel int                 `json:"required_level"`
+	ItemClass     gameDataReference   `json:"item_class"`
+	ItemSubclass  gameDataReference   `json:"item_subclass"`
+	InventoryType gameDataType        `json:"inventory_type"`
+	SellPrice     int                 `json:"sell_price"`
+	IsEquippable  bool                `json:"is_equippable"`
+	IsStackable   bool                `json:"is_stackable"`
+	PreviewItem   gameDataPreviewItem `json:"preview_item"`
+}
+
+// communityItem is the subset of the community item document that the game-data item fills in
+type communityItem struct {
+	ID            blizzard.ItemID    `json:"id"`
+	Name          string             `json:"name"`
+	Quality       int                `json:"quality"`
+	Icon          string             `json:"icon"`
+	ItemLevel     int                `json:"itemLevel"`
+	ItemClass     int64              `json:"itemClass"`
+	ItemSubClass  int64              `json:"itemSubClass"`
+	InventoryType int                `json:"inventoryType"`
+	ItemBind      itembinds.ItemBind `json:"itemBind"`
+	RequiredLevel int                `json:"requiredLevel"`
+	Armor         int                `json:"armor"`
+	MaxDurability int                `json:"maxDurability"`
+	SellPrice     int                `json:"sellPrice"`
+	Equippable    bool               `json:"equippable"`
+	Stackable     int                `json:"stackable"`
+	Description   string             `json:"description"`
+}
+
+// toItem maps onto the community item shape through its json, since the inventory-type of an item is not exported;
+// stack sizes are not part of the game-data item, so stackable items are left with a zero stack size
+func (gItem gameDataItem) toItem(icon string) (blizzard.Item, error) {
+	cItem := communityItem{
+		ID:            gItem.ID,
+		Name:          gItem.Name,
+		Quality:       gameDataItemQualities[gItem.Quality.Type],
+		Icon:          icon,
+		ItemLevel:     gItem.Level,
+		ItemClass:     gItem.ItemClass.ID,
+		ItemSubClass:  gItem.ItemSubclass.ID,
+		InventoryType: gameDataInventoryTypes[gItem.InventoryType.Type],
+		ItemBind:      newItemBind(gItem.PreviewItem.Binding),
+		RequiredLevel: gItem.RequiredLevel,
+		Armor:         gItem.PreviewItem.Armor.Value,
+		MaxDurability: gItem.PreviewItem.Durability.Value,
+		SellPrice:     gItem.SellPrice,
+		Equippable:    gItem.IsEquippable,
+		Description:   gItem.PreviewItem.Description,
+	}
+	if !gItem.IsStackable {
+		cItem.Stackable = 1
+	}
+
+	encoded, err := json.Marshal(cItem)
+	if err != nil {
+		return blizzard.Item{}, err
+	}
+
+	return blizzard.NewItem(encoded)
+}
+
+type gameDataMediaAsset struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+type gameDataItemMedia struct {
+	Assets []gameDataMediaAsset `json:"assets"`
+}
+
+// iconURL returns the render url of the icon asset, which is served from the host of the region it was resolved in
+func (media gameDataItemMedia) iconURL() string {
+	for _, asset := range media.Assets {
+		if asset.Key == "icon" {
+			return asset.Value
+		}
+	}
+
+	return ""
+}
+
+// iconName returns the name of the icon asset, which is the file name of its render url
+func (media gameDataItemMedia) iconName() string {
+	iconURL := media.iconURL()
+	if iconURL == "" {
+		return ""
+	}
+
+	base := path.Base(iconURL)
+
+	return strings.TrimSuffix(base, path.Ext(base))
+}
+
+// newGameDataItem resolves an item and its icon, where a missing item is returned blank as with the community api
+func (r Resolver) newGameDataItem(primaryRegion sotah.Region, ID blizzard.ItemID) (sotah.Item, error) {
+	gItem := gameDataItem{}
+	exists, err := r.downloadGameData(primaryRegion, getGameDataItemURL(primaryRegion, ID), &gItem)
+	if err != nil {
+		return sotah.Item{}, err
+	}
+	if !exists {
+		return sotah.Item{}, nil
+	}
+
+	media := gameDataItemMedia{}
+	if _, err := r.downloadGameData(primaryRegion, getGameDataItemMediaURL(primaryRegion, ID), &media); err != nil {
+		return sotah.Item{}, err
+	}
+
+	item, err := gItem.toItem(media.iconName())
+	if err != nil {
+		return sotah.Item{}, err
+	}
+
+	return sotah.Item{Item: item, IconURL: media.iconURL()}, nil
+}
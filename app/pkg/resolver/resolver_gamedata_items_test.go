@@ -0,0 +1,36 @@
+package resolver
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestResolverNewGameDataItem(t *testing.T) {
+	iconURL := "https://render-cn.worldofwarcraft.com/icons/56/inv_misc_herb_peacebloom.jpg"
+	r, reg, cleanup := newTestResolver(GameDataAPI, http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/data/wow/item/2447":
+			fmt.Fprint(w, `{"id":2447,"name":"Peacebloom","quality":{"type":"COMMON"},"is_stackable":true}`)
+		case "/data/wow/media/item/2447":
+			fmt.Fprintf(w, `{"assets":[{"key":"icon","value":"%s"}]}`, iconURL)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer cleanup()
+
+	// the icon url is the asset url as served, rather than one rebuilt on the default render host
+	item, err := r.NewItem(reg, 2447)
+	if err != nil {
+		t.Fatalf("could not resolve item: %s", err)
+	}
+	if item.ID != 2447 || item.Icon != "inv_misc_herb_peacebloom" || item.IconURL != iconURL {
+		t.Fatalf("unexpected item: %+v", item)
+	}
+
+	item, err = r.NewItem(reg, 1)
+	if err != nil || item.ID != 0 {
+		t.Fatalf("expected a missing item to be returned blank, got %+v %v", item, err)
+	}
+}
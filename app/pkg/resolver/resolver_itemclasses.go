@@ -0,0 +1,80 @@
+package resolver
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/blizzard"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/sotah"
+)
+
+type itemClassIndexResponse struct {
+	ItemClasses []gameDataReference `json:"item_classes"`
+}
+
+type itemClassResponse struct {
+	ClassID        blizzard.ItemClassClass `json:"class_id"`
+	Name           string                  `json:"name"`
+	ItemSubclasses []gameDataReference     `json:"item_subclasses"`
+}
+
+func (resp itemClassResponse) toItemClass() blizzard.ItemClass {
+	out := blizzard.ItemClass{
+		Class:      resp.ClassID,
+		Name:       resp.Name,
+		SubClasses: []blizzard.SubItemClass{},
+	}
+	for _, subclass := range resp.ItemSubclasses {
+		out.SubClasses = append(out.SubClasses, blizzard.SubItemClass{
+			SubClass: blizzard.ItemSubClassClass(subclass.ID),
+			Name:     subclass.Name,
+		})
+	}
+
+	return out
+}
+
+func (r Resolver) NewItemClasses(primaryRegion sotah.Region) (blizzard.ItemClasses, error) {
+	if r.API == GameDataAPI {
+		return r.newGameDataItemClasses(primaryRegion)
+	}
+
+	resp, err := r.Download(primaryRegion, r.GetItemClassesURL(APIHostname(primaryRegion)), true)
+	if err != nil {
+		return blizzard.ItemClasses{}, err
+	}
+	if resp.Status != http.StatusOK {
+		return blizzard.ItemClasses{}, fmt.Errorf("item-classes response status was not 200: %d", resp.Status)
+	}
+
+	return blizzard.NewItemClasses(resp.Body)
+}
+
+// newGameDataItemClasses gathers each class of the item-class index, since only the classes hold their subclasses
+func (r Resolver) newGameDataItemClasses(primaryRegion sotah.Region) (blizzard.ItemClasses, error) {
+	indexResp := &itemClassIndexResponse{}
+	exists, err := r.downloadGameData(primaryRegion, getItemClassIndexURL(primaryRegion), indexResp)
+	if err != nil {
+		return blizzard.ItemClasses{}, err
+	}
+	if !exists {
+		return blizzard.ItemClasses{}, fmt.Errorf("item-class index not found for region %s", primaryRegion.Name)
+	}
+
+	out := blizzard.ItemClasses{Classes: []blizzard.ItemClass{}}
+	for _, ref := range indexResp.ItemClasses {
+		classResp := itemClassResponse{}
+		uri := getItemClassURL(primaryRegion, blizzard.ItemClassClass(ref.ID))
+		exists, err := r.downloadGameData(primaryRegion, uri, &classResp)
+		if err != nil {
+			return blizzard.ItemClasses{}, err
+		}
+		if !exists {
+			return blizzard.ItemClasses{}, fmt.Errorf("item-class %d not found", ref.ID)
+		}
+
+		out.Classes = append(out.Classes, classResp.toItemClass())
+	}
+
+	return out, nil
+}
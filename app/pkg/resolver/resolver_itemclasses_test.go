@@ -0,0 +1,133 @@
+package resolver
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/blizzard"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/sotah"
+)
+
+// newTestResolver serves the region from the handler, with a token that the handler is expected to be sent
+func newTestResolver(api API, handler http.Handler) (Resolver, sotah.Region, func()) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer test-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+
+			return
+		}
+
+		handler.ServeHTTP(w, r)
+	}))
+	reg := sotah.Region{Name: "us", Hostname: strings.TrimPrefix(server.URL, "https://")}
+
+	c := &Client{
+		httpClient: server.Client(),
+		limiter:    NewRateLimiter(100, 100000),
+		maxRetries: 0,
+		sleep:      func(time.Duration) {},
+	}
+	ts := &tokenSource{
+		endpoint: server.URL + "/token",
+		mutex:    &sync.Mutex{},
+		token:    accessToken{value: "test-token", expiresAt: time.Now().Add(time.Hour)},
+	}
+
+	r := Resolver{
+		BlizzardClient:    c,
+		API:               api,
+		tokens:            map[blizzard.RegionName]*tokenSource{reg.Name: ts},
+		connectedRealms:   newConnectedRealmIndex(),
+		GetAuctionInfoURL: blizzard.DefaultGetAuctionInfoURL,
+		GetItemURL:        blizzard.DefaultGetItemURL,
+		GetItemClassesURL: blizzard.DefaultGetItemClassesURL,
+		GetStatusURL:      blizzard.DefaultGetStatusURL,
+	}
+
+	return r, reg, server.Close
+}
+
+func TestResolverNewItemClasses(t *testing.T) {
+	r, reg, cleanup := newTestResolver(CommunityAPI, http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path != "/wow/data/item/classes" {
+			w.WriteHeader(http.StatusNotFound)
+
+			return
+		}
+
+		fmt.Fprint(w, `{"classes":[{"class":0,"name":"Consumable","subclasses":[{"subclass":1,"name":"Potion"}]}]}`)
+	}))
+	defer cleanup()
+
+	iClasses, err := r.NewItemClasses(reg)
+	if err != nil {
+		t.Fatalf("could not resolve item-classes: %s", err)
+	}
+
+	if len(iClasses.Classes) != 1 || iClasses.Classes[0].SubClasses[0].Name != "Potion" {
+		t.Fatalf("unexpected item-classes: %+v", iClasses)
+	}
+}
+
+func TestResolverNewGameDataItemClasses(t *testing.T) {
+	r, reg, cleanup := newTestResolver(GameDataAPI, http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Query().Get("namespace") != "static-us" {
+			w.WriteHeader(http.StatusBadRequest)
+
+			return
+		}
+
+		switch req.URL.Path {
+		case "/data/wow/item-class/index":
+			fmt.Fprint(w, `{"item_classes":[{"id":0,"name":"Consumable"},{"id":7,"name":"Tradeskill"}]}`)
+		case "/data/wow/item-class/0":
+			fmt.Fprint(w, `{"class_id":0,"name":"Consumable","item_subclasses":[{"id":1,"name":"Potion"}]}`)
+		case "/data/wow/item-class/7":
+			fmt.Fprint(
+				w,
+				`{"class_id":7,"name":"Tradeskill","item_subclasses":[{"id":5,"name":"Cloth"},{"id":7,"name":"Metal"}]}`,
+			)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer cleanup()
+
+	iClasses, err := r.NewItemClasses(reg)
+	if err != nil {
+		t.Fatalf("could not resolve item-classes: %s", err)
+	}
+
+	if len(iClasses.Classes) != 2 {
+		t.Fatalf("expected every class of the index, got %+v", iClasses)
+	}
+	tradeskill := iClasses.Classes[1]
+	if tradeskill.Class != 7 || tradeskill.Name != "Tradeskill" || len(tradeskill.SubClasses) != 2 {
+		t.Fatalf("unexpected class: %+v", tradeskill)
+	}
+	if sub := tradeskill.SubClasses[1]; sub.SubClass != 7 || sub.Name != "Metal" {
+		t.Fatalf("unexpected subclass: %+v", sub)
+	}
+}
+
+func TestResolverNewGameDataItemClassesMissing(t *testing.T) {
+	r, reg, cleanup := newTestResolver(GameDataAPI, http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == "/data/wow/item-class/index" {
+			fmt.Fprint(w, `{"item_classes":[{"id":0,"name":"Consumable"}]}`)
+
+			return
+		}
+
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer cleanup()
+
+	if _, err := r.NewItemClasses(reg); err == nil {
+		t.Fatal("expected a class missing from the index to fail")
+	}
+}
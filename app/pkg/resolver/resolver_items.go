@@ -0,0 +1,96 @@
+package resolver
+
+import (
+	"net/http"
+
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/blizzard"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/sotah"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/util"
+)
+
+// items resolved per collection, where the rest are picked up by later collections
+const itemsPerBatch = 50
+
+// NewItem resolves an item along with its icon url, where the game-data api serves the url of its icon asset and the
+// community api only the icon name
+func (r Resolver) NewItem(primaryRegion sotah.Region, ID blizzard.ItemID) (sotah.Item, error) {
+	if r.API == GameDataAPI {
+		return r.newGameDataItem(primaryRegion, ID)
+	}
+
+	resp, err := r.Download(primaryRegion, r.GetItemURL(APIHostname(primaryRegion), ID), true)
+	if err != nil {
+		return sotah.Item{}, err
+	}
+	if resp.Status == http.StatusNotFound {
+		return sotah.Item{}, nil
+	}
+
+	item, err := blizzard.NewItem(resp.Body)
+	if err != nil {
+		return sotah.Item{}, err
+	}
+
+	itemValue := sotah.Item{Item: item}
+	if len(itemValue.Icon) > 0 {
+		itemValue.IconURL = blizzard.DefaultGetItemIconURL(itemValue.Icon)
+	}
+
+	return itemValue, nil
+}
+
+type GetItemsJob struct {
+	Err    error
+	ItemId blizzard.ItemID
+	Item   sotah.Item
+	Exists bool
+}
+
+func (r Resolver) GetItems(primaryRegion sotah.Region, IDs []blizzard.ItemID) chan GetItemsJob {
+	// establishing channels
+	out := make(chan GetItemsJob)
+	in := make(chan blizzard.ItemID)
+
+	// spinning up the workers for fetching items
+	worker := func() {
+		for itemId := range in {
+			item, err := r.NewItem(primaryRegion, itemId)
+			if err != nil {
+				out <- GetItemsJob{
+					Err:    err,
+					ItemId: itemId,
+					Item:   sotah.Item{},
+					Exists: false,
+				}
+
+				continue
+			}
+
+			out <- GetItemsJob{
+				Err:    nil,
+				ItemId: itemId,
+				Item:   item,
+				Exists: item.ID > 0,
+			}
+		}
+	}
+	postWork := func() {
+		close(out)
+	}
+	util.Work(8, worker, postWork)
+
+	// queueing up the items
+	go func() {
+		for i, ID := range IDs {
+			if i > itemsPerBatch {
+				break
+			}
+
+			in <- ID
+		}
+
+		close(in)
+	}()
+
+	return out
+}
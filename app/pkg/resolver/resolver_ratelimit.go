@@ -0,0 +1,103 @@
+package resolver
+
+import (
+	"sync"
+	"time"
+)
+
+func newTokenBucket(capacity int, per time.Duration, now time.Time) *tokenBucket {
+	return &tokenBucket{
+		capacity: float64(capacity),
+		tokens:   float64(capacity),
+		rate:     float64(capacity) / per.Seconds(),
+		last:     now,
+	}
+}
+
+// tokenBucket refills continuously at its rate up to its capacity
+type tokenBucket struct {
+	capacity float64
+	tokens   float64
+	rate     float64
+	last     time.Time
+}
+
+func (b *tokenBucket) refill(now time.Time) {
+	if now.After(b.last) {
+		b.tokens += now.Sub(b.last).Seconds() * b.rate
+		if b.tokens > b.capacity {
+			b.tokens = b.capacity
+		}
+	}
+	b.last = now
+}
+
+// wait returns how long until a token is available
+func (b *tokenBucket) wait() time.Duration {
+	if b.tokens >= 1 {
+		return 0
+	}
+
+	return time.Duration((1 - b.tokens) / b.rate * float64(time.Second))
+}
+
+func NewRateLimiter(perSecond int, perHour int) *RateLimiter {
+	return newRateLimiter(perSecond, perHour, time.Now, time.Sleep)
+}
+
+func newRateLimiter(perSecond int, perHour int, now func() time.Time, sleep func(time.Duration)) *RateLimiter {
+	startTime := now()
+
+	return &RateLimiter{
+		buckets: []*tokenBucket{
+			newTokenBucket(perSecond, time.Second, startTime),
+			newTokenBucket(perHour, time.Hour, startTime),
+		},
+		now:   now,
+		sleep: sleep,
+	}
+}
+
+// RateLimiter holds requests within every quota, where a request takes a token from each bucket
+type RateLimiter struct {
+	sync.Mutex
+
+	buckets []*tokenBucket
+	now     func() time.Time
+	sleep   func(time.Duration)
+}
+
+// reserve takes a token from every bucket when all have one, otherwise returning how long to wait
+func (l *RateLimiter) reserve(now time.Time) time.Duration {
+	l.Lock()
+	defer l.Unlock()
+
+	longestWait := time.Duration(0)
+	for _, b := range l.buckets {
+		b.refill(now)
+		if wait := b.wait(); wait > longestWait {
+			longestWait = wait
+		}
+	}
+	if longestWait > 0 {
+		return longestWait
+	}
+
+	for _, b := range l.buckets {
+		b.tokens--
+	}
+
+	return 0
+}
+
+// Wait blocks until a request is allowed
+func (l *RateLimiter) Wait() {
+	for {
+		wait := l.reserve(l.now())
+		if wait == 0 {
+			return
+		}
+
+		l.sleep(wait)
+	}
+}
@@ -0,0 +1,77 @@
+package resolver
+
+import (
+	"testing"
+	"time"
+)
+
+// fakeClock advances only when slept on, so that waits can be measured without sleeping
+type fakeClock struct {
+	now   time.Time
+	slept []time.Duration
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func (c *fakeClock) Sleep(d time.Duration) {
+	c.slept = append(c.slept, d)
+	c.now = c.now.Add(d)
+}
+
+func (c *fakeClock) totalSlept() time.Duration {
+	total := time.Duration(0)
+	for _, d := range c.slept {
+		total += d
+	}
+
+	return total
+}
+
+func TestTokenBucket(t *testing.T) {
+	now := time.Unix(1000, 0)
+	b := newTokenBucket(2, time.Second, now)
+
+	b.tokens -= 2
+	if wait := b.wait(); wait != 500*time.Millisecond {
+		t.Fatalf("expected an empty bucket to wait for a token, got %s", wait)
+	}
+
+	b.refill(now.Add(250 * time.Millisecond))
+	if wait := b.wait(); wait != 250*time.Millisecond {
+		t.Fatalf("expected the bucket to refill continuously, got %s", wait)
+	}
+
+	b.refill(now.Add(time.Minute))
+	if b.tokens != 2 || b.wait() != 0 {
+		t.Fatalf("expected the bucket to refill up to its capacity, got %f", b.tokens)
+	}
+
+	b.refill(now)
+	if b.tokens != 2 {
+		t.Fatalf("expected an earlier time to not drain the bucket, got %f", b.tokens)
+	}
+}
+
+func TestRateLimiterWait(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(1000, 0)}
+	l := newRateLimiter(2, 3, clock.Now, clock.Sleep)
+
+	l.Wait()
+	l.Wait()
+	if len(clock.slept) != 0 {
+		t.Fatalf("expected the burst to not wait, got %v", clock.slept)
+	}
+
+	l.Wait()
+	if total := clock.totalSlept(); total < 500*time.Millisecond || total > 501*time.Millisecond {
+		t.Fatalf("expected to wait on the per-second quota, got %s", total)
+	}
+
+	// the hourly quota is exhausted, and refills a token every twenty minutes
+	l.Wait()
+	if total := clock.totalSlept(); total < 20*time.Minute-time.Second || total > 20*time.Minute {
+		t.Fatalf("expected to wait on the per-hour quota, got %s", total)
+	}
+}
@@ -0,0 +1,51 @@
+package resolver
+
+import (
+	"fmt"
+
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/blizzard"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/sotah"
+)
+
+const (
+	apiHostnameFormat   = "%s.api.blizzard.com"
+	tokenEndpointFormat = "https://%s.battle.net/oauth/token?grant_type=client_credentials"
+
+	// korea and taiwan share the asia-pacific battle.net
+	apacTokenEndpoint = "https://apac.battle.net/oauth/token?grant_type=client_credentials"
+
+	// china is served through its own gateway and battle.net, with credentials issued apart from the other regions
+	chinaAPIHostname   = "gateway.battlenet.com.cn"
+	chinaTokenEndpoint = "https://www.battlenet.com.cn/oauth/token?grant_type=client_credentials"
+)
+
+const ChinaRegionName blizzard.RegionName = "cn"
+
+// IsChina reports whether a token of the other regions is rejected by the region
+func IsChina(reg sotah.Region) bool {
+	return reg.Name == ChinaRegionName
+}
+
+// APIHostname prefers the configured hostname, otherwise deriving it from the region name
+func APIHostname(reg sotah.Region) string {
+	if len(reg.Hostname) > 0 {
+		return reg.Hostname
+	}
+
+	if IsChina(reg) {
+		return chinaAPIHostname
+	}
+
+	return fmt.Sprintf(apiHostnameFormat, reg.Name)
+}
+
+func TokenEndpoint(reg sotah.Region) string {
+	switch reg.Name {
+	case ChinaRegionName:
+		return chinaTokenEndpoint
+	case "kr", "tw":
+		return apacTokenEndpoint
+	default:
+		return fmt.Sprintf(tokenEndpointFormat, reg.Name)
+	}
+}
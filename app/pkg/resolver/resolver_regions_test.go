@@ -0,0 +1,69 @@
+package resolver
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/blizzard"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/sotah"
+)
+
+func TestAPIHostname(t *testing.T) {
+	tests := map[string]sotah.Region{
+		"us.api.blizzard.com":      {Name: "us"},
+		"gateway.battlenet.com.cn": {Name: "cn"},
+		"localhost:8080":           {Name: "eu", Hostname: "localhost:8080"},
+	}
+	for expected, reg := range tests {
+		if actual := APIHostname(reg); actual != expected {
+			t.Errorf("expected %s for %+v, got %s", expected, reg, actual)
+		}
+	}
+}
+
+func TestTokenEndpoint(t *testing.T) {
+	tests := map[blizzard.RegionName]string{
+		"us": "https://us.battle.net/oauth/token?grant_type=client_credentials",
+		"eu": "https://eu.battle.net/oauth/token?grant_type=client_credentials",
+		"kr": "https://apac.battle.net/oauth/token?grant_type=client_credentials",
+		"tw": "https://apac.battle.net/oauth/token?grant_type=client_credentials",
+		"cn": "https://www.battlenet.com.cn/oauth/token?grant_type=client_credentials",
+	}
+	for name, expected := range tests {
+		if actual := TokenEndpoint(sotah.Region{Name: name}); actual != expected {
+			t.Errorf("expected %s for %s, got %s", expected, name, actual)
+		}
+	}
+}
+
+func TestResolverDownloadWithoutRegionToken(t *testing.T) {
+	r, _, cleanup := newTestResolver(CommunityAPI, http.NotFoundHandler())
+	defer cleanup()
+
+	if _, err := r.Download(sotah.Region{Name: "cn"}, "https://example.com", true); err == nil {
+		t.Fatal("expected a region without a token to not be authenticated with another region's token")
+	}
+}
+
+func TestResolverNewStatus(t *testing.T) {
+	r, reg, cleanup := newTestResolver(CommunityAPI, http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path != "/wow/realm/status" {
+			w.WriteHeader(http.StatusNotFound)
+
+			return
+		}
+
+		fmt.Fprint(w, `{"realms":[{"name":"Zul'jin","slug":"zuljin","connected_realms":["zuljin"]}]}`)
+	}))
+	defer cleanup()
+
+	status, err := r.NewStatus(reg)
+	if err != nil {
+		t.Fatalf("could not resolve status: %s", err)
+	}
+
+	if len(status.Realms) != 1 || status.Realms[0].Slug != "zuljin" || status.Realms[0].Region.Name != "us" {
+		t.Fatalf("unexpected status: %+v", status)
+	}
+}
@@ -0,0 +1,132 @@
+package resolver
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/config"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/logging"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/sotah"
+)
+
+// tokens are refreshed this long before they expire, so that a request is never sent with an expired token
+const tokenExpiryMargin = 5 * time.Minute
+
+type accessToken struct {
+	value     string
+	expiresAt time.Time
+}
+
+func (t accessToken) isExpiring(now time.Time) bool {
+	return len(t.value) == 0 || !now.Before(t.expiresAt.Add(-tokenExpiryMargin))
+}
+
+type tokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int    `json:"expires_in"`
+}
+
+func newTokenSource(reg sotah.Region, credentials config.BlizzardCredentials) (*tokenSource, error) {
+	if len(credentials.ClientID) == 0 {
+		return nil, fmt.Errorf("client id is blank for region %s", reg.Name)
+	}
+	if len(credentials.ClientSecret) == 0 {
+		return nil, fmt.Errorf("client secret is blank for region %s", reg.Name)
+	}
+
+	return &tokenSource{
+		endpoint: TokenEndpoint(reg),
+		id:       credentials.ClientID,
+		secret:   credentials.ClientSecret,
+		mutex:    &sync.Mutex{},
+	}, nil
+}
+
+// tokenSource keeps the access token of a region
+type tokenSource struct {
+	endpoint string
+	id       string
+	secret   string
+
+	mutex *sync.Mutex
+	token accessToken
+}
+
+// fetchToken gathers an access token from the oauth token endpoint
+func (c *Client) fetchToken(ts *tokenSource) (accessToken, error) {
+	newRequest := func() (*http.Request, error) {
+		req, err := http.NewRequest("GET", ts.endpoint, nil)
+		if err != nil {
+			return nil, err
+		}
+		req.SetBasicAuth(ts.id, ts.secret)
+
+		return req, nil
+	}
+
+	requestedAt := time.Now()
+	resp, err := c.do(newRequest, false)
+	if err != nil {
+		return accessToken{}, err
+	}
+	if resp.Status != http.StatusOK {
+		logging.WithFields(logrus.Fields{
+			"endpoint": ts.endpoint,
+			"status":   resp.Status,
+		}).Error("Received failed oauth token response from Blizzard API")
+
+		return accessToken{}, fmt.Errorf("oauth token response was not 200: %d", resp.Status)
+	}
+
+	r := &tokenResponse{}
+	if err := json.Unmarshal(resp.Body, &r); err != nil {
+		return accessToken{}, err
+	}
+	if len(r.AccessToken) == 0 {
+		return accessToken{}, errors.New("oauth token response had a blank access token")
+	}
+
+	// measuring expiry from before the request, since the lifetime begins when the token is issued
+	return accessToken{
+		value:     r.AccessToken,
+		expiresAt: requestedAt.Add(time.Duration(r.ExpiresIn) * time.Second),
+	}, nil
+}
+
+// accessToken returns the current token, refreshing it ahead of its expiry
+func (c *Client) accessToken(ts *tokenSource) (string, error) {
+	ts.mutex.Lock()
+	defer ts.mutex.Unlock()
+
+	if !ts.token.isExpiring(time.Now()) {
+		return ts.token.value, nil
+	}
+
+	logging.WithField("endpoint", ts.endpoint).Debug("Refreshing blizzard access token")
+	nextToken, err := c.fetchToken(ts)
+	if err != nil {
+		return "", err
+	}
+	ts.token = nextToken
+
+	return ts.token.value, nil
+}
+
+// invalidateToken forces a refresh on the next request, where the token was rejected before it was due to expire
+func (c *Client) invalidateToken(ts *tokenSource, rejected string) {
+	ts.mutex.Lock()
+	defer ts.mutex.Unlock()
+
+	// another request may have already refreshed it
+	if ts.token.value != rejected {
+		return
+	}
+
+	ts.token = accessToken{}
+}
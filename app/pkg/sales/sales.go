@@ -0,0 +1,187 @@
+package sales
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/blizzard"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/sotah"
+)
+
+// keying
+func tallyKeyName(targetDate time.Time) []byte {
+	// big-endian so that keys iterate in day order
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, uint64(targetDate.Unix()))
+
+	return key
+}
+
+func tallyKeyTime(key []byte) time.Time {
+	return time.Unix(int64(binary.BigEndian.Uint64(key)), 0)
+}
+
+func lastSnapshotKeyName() []byte {
+	return []byte("last-snapshot")
+}
+
+// bucketing
+func tallyBucketName(ID blizzard.ItemID) []byte {
+	return []byte(fmt.Sprintf("item-sales/%d", ID))
+}
+
+func metaBucketName() []byte {
+	return []byte("meta")
+}
+
+// db
+func databaseDirPath(dirPath string) string {
+	return fmt.Sprintf("%s/sales", dirPath)
+}
+
+// maximum time remaining on an auction for each time-left bucket
+var timeLeftLimits = map[string]time.Duration{
+	"SHORT":     30 * time.Minute,
+	"MEDIUM":    2 * time.Hour,
+	"LONG":      12 * time.Hour,
+	"VERY_LONG": 48 * time.Hour,
+}
+
+// Outcome - typehint for these enums
+type Outcome string
+
+/*
+Outcomes - the probable fate of an auction that disappeared between snapshots
+*/
+const (
+	Sold    Outcome = "sold"
+	Expired Outcome = "expired"
+)
+
+// ClassifyRemoval guesses whether an auction was bought out or ran out, given its previous time-left bucket
+// and the time elapsed since that snapshot (zero when unknown)
+func ClassifyRemoval(timeLeft string, elapsed time.Duration) Outcome {
+	limit, ok := timeLeftLimits[timeLeft]
+	if !ok {
+		return Sold
+	}
+
+	// short auctions were about to run out anyway
+	if timeLeft == "SHORT" {
+		return Expired
+	}
+
+	// the auction could have run out in the time since the previous snapshot
+	if elapsed > 0 && elapsed >= limit {
+		return Expired
+	}
+
+	return Sold
+}
+
+func NewTallyFromBytes(data []byte) (Tally, error) {
+	t := Tally{}
+	if err := json.Unmarshal(data, &t); err != nil {
+		return Tally{}, err
+	}
+
+	return t, nil
+}
+
+// Tally counts removed auctions for an item over a single day
+type Tally struct {
+	SoldAuctions    int64 `json:"sold_auctions"`
+	SoldVolume      int64 `json:"sold_volume"`
+	ExpiredAuctions int64 `json:"expired_auctions"`
+	ExpiredVolume   int64 `json:"expired_volume"`
+}
+
+func (t Tally) Add(other Tally) Tally {
+	return Tally{
+		SoldAuctions:    t.SoldAuctions + other.SoldAuctions,
+		SoldVolume:      t.SoldVolume + other.SoldVolume,
+		ExpiredAuctions: t.ExpiredAuctions + other.ExpiredAuctions,
+		ExpiredVolume:   t.ExpiredVolume + other.ExpiredVolume,
+	}
+}
+
+func (t Tally) EncodeForStorage() ([]byte, error) {
+	return json.Marshal(t)
+}
+
+type ItemTallies map[blizzard.ItemID]Tally
+
+// NewItemTallies classifies every auction in the previous list that is missing from the current auctions
+func NewItemTallies(previous sotah.MiniAuctionList, current blizzard.Auctions, elapsed time.Duration) ItemTallies {
+	currentIds := map[int64]struct{}{}
+	for _, auc := range current.Auctions {
+		currentIds[auc.Auc] = struct{}{}
+	}
+
+	out := ItemTallies{}
+	for _, mAuction := range previous {
+		for _, aucId := range mAuction.AucList {
+			if _, ok := currentIds[aucId]; ok {
+				continue
+			}
+
+			t := out[mAuction.ItemID]
+			switch ClassifyRemoval(mAuction.TimeLeft, elapsed) {
+			case Expired:
+				t.ExpiredAuctions++
+				t.ExpiredVolume += mAuction.Quantity
+			default:
+				t.SoldAuctions++
+				t.SoldVolume += mAuction.Quantity
+			}
+			out[mAuction.ItemID] = t
+		}
+	}
+
+	return out
+}
+
+// Estimate summarizes an item's tallies over a window of days
+type Estimate struct {
+	SellThrough       float64 `json:"sell_through"`
+	DailySoldVolume   float64 `json:"daily_sold_volume"`
+	DailySoldAuctions float64 `json:"daily_sold_auctions"`
+	Days              int     `json:"days"`
+}
+
+// NewEstimate averages over every day of the window, where days without removals have no tallies but still count
+func NewEstimate(dailyTallies []Tally, days int) Estimate {
+	if days <= 0 {
+		return Estimate{}
+	}
+
+	total := Tally{}
+	for _, t := range dailyTallies {
+		total = total.Add(t)
+	}
+
+	out := Estimate{Days: days}
+	if removed := total.SoldAuctions + total.ExpiredAuctions; removed > 0 {
+		out.SellThrough = float64(total.SoldAuctions) / float64(removed)
+	}
+	out.DailySoldVolume = float64(total.SoldVolume) / float64(out.Days)
+	out.DailySoldAuctions = float64(total.SoldAuctions) / float64(out.Days)
+
+	return out
+}
+
+// WindowDays counts the days from the lower bound through the target date, inclusive of both
+func WindowDays(lowerBound time.Time, targetDate time.Time) int {
+	// rounding, since a day crossing a daylight-saving change is not 24 hours long
+	elapsed := sotah.NormalizeTargetDate(targetDate).Sub(sotah.NormalizeTargetDate(lowerBound))
+	days := int((elapsed+12*time.Hour)/(24*time.Hour)) + 1
+	if days < 1 {
+		return 1
+	}
+
+	return days
+}
+
+type ItemEstimates map[blizzard.ItemID]Estimate
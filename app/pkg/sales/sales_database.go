@@ -0,0 +1,147 @@
+package sales
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/boltdb/bolt"
+	"github.com/sirupsen/logrus"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/blizzard"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/logging"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/sotah"
+)
+
+type Database struct {
+	db    *bolt.DB
+	realm sotah.Realm
+}
+
+// LastSnapshot returns when tallies were last persisted, or a zero time when never
+func (sBase Database) LastSnapshot() (time.Time, error) {
+	out := time.Time{}
+
+	err := sBase.db.View(func(tx *bolt.Tx) error {
+		bkt := tx.Bucket(metaBucketName())
+		if bkt == nil {
+			return nil
+		}
+
+		value := bkt.Get(lastSnapshotKeyName())
+		if value == nil {
+			return nil
+		}
+
+		timestamp, err := strconv.ParseInt(string(value), 10, 64)
+		if err != nil {
+			return err
+		}
+
+		out = time.Unix(timestamp, 0)
+
+		return nil
+	})
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return out, nil
+}
+
+// Persist adds the tallies onto the target day and drops days beyond the retention limit
+func (sBase Database) Persist(targetTime time.Time, tallies ItemTallies, retentionLimit time.Time) error {
+	logging.WithFields(logrus.Fields{
+		"db":    sBase.db.Path(),
+		"items": len(tallies),
+	}).Debug("Persisting sales tallies")
+
+	targetKey := tallyKeyName(sotah.NormalizeTargetDate(targetTime))
+
+	return sBase.db.Update(func(tx *bolt.Tx) error {
+		for itemId, t := range tallies {
+			bkt, err := tx.CreateBucketIfNotExists(tallyBucketName(itemId))
+			if err != nil {
+				return err
+			}
+
+			// merging onto the existing tally for the day
+			if value := bkt.Get(targetKey); value != nil {
+				existing, err := NewTallyFromBytes(value)
+				if err != nil {
+					return err
+				}
+
+				t = existing.Add(t)
+			}
+
+			encoded, err := t.EncodeForStorage()
+			if err != nil {
+				return err
+			}
+
+			if err := bkt.Put(targetKey, encoded); err != nil {
+				return err
+			}
+
+			// pruning expired days
+			c := bkt.Cursor()
+			for k, _ := c.First(); k != nil && tallyKeyTime(k).Before(retentionLimit); k, _ = c.Next() {
+				if err := c.Delete(); err != nil {
+					return err
+				}
+			}
+		}
+
+		metaBkt, err := tx.CreateBucketIfNotExists(metaBucketName())
+		if err != nil {
+			return err
+		}
+
+		return metaBkt.Put(lastSnapshotKeyName(), []byte(strconv.FormatInt(targetTime.Unix(), 10)))
+	})
+}
+
+// GetTallies gathers each item's daily tallies since the lower bound
+func (sBase Database) GetTallies(itemIds []blizzard.ItemID, lowerBound time.Time) (map[blizzard.ItemID][]Tally, error) {
+	out := map[blizzard.ItemID][]Tally{}
+
+	err := sBase.db.View(func(tx *bolt.Tx) error {
+		for _, itemId := range itemIds {
+			bkt := tx.Bucket(tallyBucketName(itemId))
+			if bkt == nil {
+				continue
+			}
+
+			c := bkt.Cursor()
+			for k, v := c.Seek(tallyKeyName(lowerBound)); k != nil; k, v = c.Next() {
+				t, err := NewTallyFromBytes(v)
+				if err != nil {
+					return err
+				}
+
+				out[itemId] = append(out[itemId], t)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return map[blizzard.ItemID][]Tally{}, err
+	}
+
+	return out, nil
+}
+
+func (sBase Database) GetEstimates(itemIds []blizzard.ItemID, lowerBound time.Time) (ItemEstimates, error) {
+	tallies, err := sBase.GetTallies(itemIds, lowerBound)
+	if err != nil {
+		return ItemEstimates{}, err
+	}
+
+	days := WindowDays(lowerBound, time.Now())
+	out := ItemEstimates{}
+	for _, itemId := range itemIds {
+		out[itemId] = NewEstimate(tallies[itemId], days)
+	}
+
+	return out, nil
+}
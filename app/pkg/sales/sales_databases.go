@@ -0,0 +1,61 @@
+package sales
+
+import (
+	"time"
+
+	"github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/connectedrealms"
+	"github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/realmdb"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/blizzard"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/sotah"
+)
+
+func NewDatabases(dirPath string, stas sotah.Statuses, groups connectedrealms.Groups) (Databases, error) {
+	registry, err := realmdb.Open(databaseDirPath(dirPath), stas, groups)
+	if err != nil {
+		return Databases{}, err
+	}
+
+	sBases := Databases{}
+	for regionName, handles := range registry {
+		sBases[regionName] = map[blizzard.RealmSlug]Database{}
+		for realmSlug, handle := range handles {
+			sBases[regionName][realmSlug] = Database{handle.DB, handle.Realm}
+		}
+	}
+
+	return sBases, nil
+}
+
+type Databases map[blizzard.RegionName]map[blizzard.RealmSlug]Database
+
+// Record classifies the auctions removed between the previous list and the current auctions and persists them
+func (sBases Databases) Record(
+	rea sotah.Realm,
+	targetTime time.Time,
+	previous sotah.MiniAuctionList,
+	current blizzard.Auctions,
+	retentionLimit time.Time,
+) (ItemTallies, error) {
+	sBase, ok := sBases[rea.Region.Name][rea.Slug]
+	if !ok {
+		return ItemTallies{}, nil
+	}
+
+	lastSnapshot, err := sBase.LastSnapshot()
+	if err != nil {
+		return ItemTallies{}, err
+	}
+
+	// a first or out-of-order snapshot has no meaningful elapsed time
+	elapsed := time.Duration(0)
+	if !lastSnapshot.IsZero() && targetTime.After(lastSnapshot) {
+		elapsed = targetTime.Sub(lastSnapshot)
+	}
+
+	tallies := NewItemTallies(previous, current, elapsed)
+	if err := sBase.Persist(targetTime, tallies, retentionLimit); err != nil {
+		return ItemTallies{}, err
+	}
+
+	return tallies, nil
+}
@@ -0,0 +1,79 @@
+package sales
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/blizzard"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/sotah"
+)
+
+func TestClassifyRemoval(t *testing.T) {
+	cases := []struct {
+		timeLeft string
+		elapsed  time.Duration
+		expected Outcome
+	}{
+		{"SHORT", 0, Expired},
+		{"MEDIUM", 0, Sold},
+		{"MEDIUM", time.Hour, Sold},
+		{"MEDIUM", 3 * time.Hour, Expired},
+		{"VERY_LONG", 24 * time.Hour, Sold},
+		{"UNKNOWN", 72 * time.Hour, Sold},
+	}
+
+	for _, c := range cases {
+		if actual := ClassifyRemoval(c.timeLeft, c.elapsed); actual != c.expected {
+			t.Errorf("%s after %s: expected %s, got %s", c.timeLeft, c.elapsed, c.expected, actual)
+		}
+	}
+}
+
+func TestNewItemTallies(t *testing.T) {
+	previous := sotah.MiniAuctionList{
+		{ItemID: 1, TimeLeft: "LONG", Quantity: 5, AucList: []int64{10, 11}},
+		{ItemID: 2, TimeLeft: "SHORT", Quantity: 1, AucList: []int64{20}},
+	}
+	current := blizzard.Auctions{Auctions: []blizzard.Auction{{Auc: 11}}}
+
+	tallies := NewItemTallies(previous, current, time.Hour)
+
+	if expected := (Tally{SoldAuctions: 1, SoldVolume: 5}); tallies[1] != expected {
+		t.Errorf("item 1: expected %+v, got %+v", expected, tallies[1])
+	}
+	if expected := (Tally{ExpiredAuctions: 1, ExpiredVolume: 1}); tallies[2] != expected {
+		t.Errorf("item 2: expected %+v, got %+v", expected, tallies[2])
+	}
+}
+
+func TestNewEstimateAveragesOverWindow(t *testing.T) {
+	// a single day of sales in a week long window
+	est := NewEstimate([]Tally{{SoldAuctions: 2, SoldVolume: 14, ExpiredAuctions: 2}}, 7)
+
+	if est.DailySoldVolume != 2 {
+		t.Errorf("expected daily sold volume of 2, got %f", est.DailySoldVolume)
+	}
+	if est.SellThrough != 0.5 {
+		t.Errorf("expected sell-through of 0.5, got %f", est.SellThrough)
+	}
+	if est.Days != 7 {
+		t.Errorf("expected 7 days, got %d", est.Days)
+	}
+}
+
+func TestNewEstimateWithoutTallies(t *testing.T) {
+	if est := NewEstimate(nil, 7); est.DailySoldVolume != 0 || est.Days != 7 {
+		t.Errorf("expected a blank estimate over 7 days, got %+v", est)
+	}
+}
+
+func TestWindowDays(t *testing.T) {
+	targetDate := time.Date(2019, 3, 15, 13, 0, 0, 0, time.Local)
+
+	if days := WindowDays(targetDate.AddDate(0, 0, -6), targetDate); days != 7 {
+		t.Errorf("expected 7 days, got %d", days)
+	}
+	if days := WindowDays(targetDate, targetDate); days != 1 {
+		t.Errorf("expected 1 day, got %d", days)
+	}
+}
@@ -0,0 +1,185 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/storage"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/blizzard"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/logging"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/sotah"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/state"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/util"
+)
+
+func NewIntakeRequest(data []byte) (IntakeRequest, error) {
+	iRequest := &IntakeRequest{}
+	if err := json.Unmarshal(data, &iRequest); err != nil {
+		return IntakeRequest{}, err
+	}
+
+	return *iRequest, nil
+}
+
+// IntakeRequest is the payload of both live-auctions-intake and pricelist-histories-intake
+type IntakeRequest struct {
+	RegionRealmTimestamps sotah.RegionRealmTimestampMaps `json:"realm_timestamps"`
+}
+
+func (iRequest IntakeRequest) EncodeForDelivery() ([]byte, error) {
+	return json.Marshal(iRequest)
+}
+
+func (iRequest IntakeRequest) resolve(statuses sotah.Statuses) (state.RegionRealmTimes, sotah.RegionRealmMap) {
+	included := state.RegionRealmTimes{}
+	excluded := sotah.RegionRealmMap{}
+
+	for regionName, status := range statuses {
+		excluded[regionName] = sotah.RealmMap{}
+		for _, realm := range status.Realms {
+			excluded[regionName][realm.Slug] = realm
+		}
+	}
+	for regionName, realmTimestamps := range iRequest.RegionRealmTimestamps {
+		included[regionName] = state.RealmTimes{}
+		for realmSlug, timestamp := range realmTimestamps {
+			delete(excluded[regionName], realmSlug)
+
+			targetTime := time.Unix(timestamp, 0)
+			for _, realm := range statuses[regionName].Realms {
+				if realm.Slug != realmSlug {
+					continue
+				}
+
+				included[regionName][realmSlug] = state.RealmTimeTuple{
+					Realm:      realm,
+					TargetTime: targetTime,
+				}
+
+				break
+			}
+		}
+	}
+
+	return included, excluded
+}
+
+func GetAuctionsFromTimes(stor storage.Store, times state.RegionRealmTimes) chan state.GetAuctionsFromTimesOutJob {
+	in := make(chan state.RealmTimeTuple)
+	out := make(chan state.GetAuctionsFromTimesOutJob)
+
+	// spinning up the workers for fetching auctions
+	worker := func() {
+		for timeTuple := range in {
+			aucs, err := stor.GetAuctions(timeTuple.Realm, timeTuple.TargetTime)
+			if err != nil {
+				out <- state.GetAuctionsFromTimesOutJob{
+					Err:        err,
+					Realm:      timeTuple.Realm,
+					TargetTime: timeTuple.TargetTime,
+					Auctions:   blizzard.Auctions{},
+				}
+
+				continue
+			}
+
+			out <- state.GetAuctionsFromTimesOutJob{
+				Realm:      timeTuple.Realm,
+				TargetTime: timeTuple.TargetTime,
+				Auctions:   aucs,
+			}
+		}
+	}
+	postWork := func() {
+		close(out)
+	}
+	util.Work(4, worker, postWork)
+
+	// queueing up the realms
+	go func() {
+		for regionName, realmTimes := range times {
+			for realmSlug, timeTuple := range realmTimes {
+				logging.WithFields(logrus.Fields{
+					"region": regionName,
+					"realm":  realmSlug,
+				}).Debug("Queueing up auctions for loading")
+				in <- timeTuple
+			}
+		}
+
+		close(in)
+	}()
+
+	return out
+}
+
+func StoreAuctions(stor storage.Store, in chan state.StoreAuctionsInJob) chan state.StoreAuctionsOutJob {
+	out := make(chan state.StoreAuctionsOutJob)
+
+	// spinning up the workers for persisting auctions
+	worker := func() {
+		for inJob := range in {
+			err := func() error {
+				if err := stor.WriteAuctions(inJob.Realm, inJob.TargetTime, inJob.Auctions); err != nil {
+					return err
+				}
+
+				return stor.AppendAuctionManifest(inJob.Realm, sotah.UnixTimestamp(inJob.TargetTime.Unix()))
+			}()
+			if err != nil {
+				out <- state.StoreAuctionsOutJob{
+					Err:        err,
+					Realm:      inJob.Realm,
+					TargetTime: inJob.TargetTime,
+					ItemIds:    []blizzard.ItemID{},
+				}
+
+				continue
+			}
+
+			out <- state.StoreAuctionsOutJob{
+				Err:        nil,
+				Realm:      inJob.Realm,
+				TargetTime: inJob.TargetTime,
+				ItemIds:    inJob.Auctions.ItemIds(),
+			}
+		}
+	}
+	postWork := func() {
+		close(out)
+	}
+	util.Work(4, worker, postWork)
+
+	return out
+}
+
+// NewStatusesFromStore gathers the realms of each region from the store
+func NewStatusesFromStore(stor storage.Store, regions sotah.RegionList) (sotah.Statuses, error) {
+	statuses := sotah.Statuses{}
+	for _, region := range regions {
+		realms, err := stor.GetRealms(region.Name)
+		if err != nil {
+			return sotah.Statuses{}, err
+		}
+
+		statuses[region.Name] = sotah.Status{Region: region, Realms: realms}
+	}
+
+	return statuses, nil
+}
+
+func resolveRealm(
+	statuses sotah.Statuses,
+	regionName blizzard.RegionName,
+	realmSlug blizzard.RealmSlug,
+) (sotah.Realm, error) {
+	for _, realm := range statuses[regionName].Realms {
+		if realm.Slug == realmSlug {
+			return realm, nil
+		}
+	}
+
+	return sotah.Realm{}, fmt.Errorf("realm not found: %s/%s", regionName, realmSlug)
+}
@@ -0,0 +1,254 @@
+package server
+
+import (
+	"fmt"
+
+	"github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/config"
+	"github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/connectedrealms"
+	"github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/downloads"
+	"github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/items"
+	"github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/recipes"
+	"github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/resolver"
+	"github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/storage"
+	"github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/yields"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/blizzard"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/database"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/diskstore"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/logging"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/messenger"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/metric"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/sotah"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/sotah/gameversions"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/state"
+	devState "github.com/sotah-inc/steamwheedle-cartel/pkg/state/dev"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/state/subjects"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/util"
+	"github.com/twinj/uuid"
+)
+
+type APIStateConfig struct {
+	devState.APIStateConfig
+
+	StorageBackend storage.BackendConfig
+	Recipes        config.RecipesConfig
+	Yields         config.YieldsConfig
+	Blizzard       config.BlizzardConfig
+}
+
+// blizzardCredentials resolves each region's credentials, falling back to the client-id and client-secret
+func (c APIStateConfig) blizzardCredentials(
+	regions sotah.RegionList,
+) map[blizzard.RegionName]config.BlizzardCredentials {
+	defaultCredentials := config.BlizzardCredentials{
+		ClientID:     c.BlizzardClientId,
+		ClientSecret: c.BlizzardClientSecret,
+	}
+
+	out := map[blizzard.RegionName]config.BlizzardCredentials{}
+	for _, reg := range regions {
+		out[reg.Name] = c.Blizzard.ResolveCredentials(reg.Name, defaultCredentials)
+	}
+
+	return out
+}
+
+func NewAPIState(config APIStateConfig) (APIState, error) {
+	// establishing an initial state, where the storage backend supersedes the use-gcloud switch
+	apiState := APIState{
+		APIState: devState.APIState{
+			State: state.NewState(uuid.NewV4(), false),
+		},
+	}
+	apiState.SessionSecret = uuid.NewV4()
+
+	// setting api-state from config, including filtering in regions based on config whitelist
+	apiState.Statuses = sotah.Statuses{}
+	apiState.Regions = config.SotahConfig.FilterInRegions(config.SotahConfig.Regions)
+	apiState.Expansions = config.SotahConfig.Expansions
+	apiState.Professions = config.SotahConfig.Professions
+	apiState.ItemBlacklist = config.SotahConfig.ItemBlacklist
+	apiState.RegionRealmModificationDates = sotah.RegionRealmModificationDates{}
+
+	// establishing a disk store
+	cacheDirs := []string{
+		config.DiskStoreCacheDir,
+		fmt.Sprintf("%s/items", config.DiskStoreCacheDir),
+		fmt.Sprintf("%s/auctions", config.DiskStoreCacheDir),
+		fmt.Sprintf("%s/databases", config.DiskStoreCacheDir),
+	}
+	for _, reg := range apiState.Regions {
+		cacheDirs = append(cacheDirs, fmt.Sprintf("%s/auctions/%s", config.DiskStoreCacheDir, reg.Name))
+	}
+	if err := util.EnsureDirsExist(cacheDirs); err != nil {
+		return APIState{}, err
+	}
+	apiState.IO.DiskStore = diskstore.NewDiskStore(config.DiskStoreCacheDir)
+
+	// connecting to the messenger host
+	mess, err := messenger.NewMessenger(config.MessengerHost, config.MessengerPort)
+	if err != nil {
+		return APIState{}, err
+	}
+	apiState.IO.Messenger = mess
+
+	// initializing a reporter
+	apiState.IO.Reporter = metric.NewReporter(mess)
+
+	// establishing a rate-limited blizzard client holding a token per region
+	logging.Info("Establishing blizzard client")
+	api, err := resolver.NewAPI(config.Blizzard.API)
+	if err != nil {
+		return APIState{}, err
+	}
+	apiState.Resolver, err = resolver.NewResolver(
+		resolver.NewClient(config.Blizzard),
+		apiState.IO.Reporter,
+		api,
+		apiState.Regions,
+		config.blizzardCredentials(apiState.Regions),
+	)
+	if err != nil {
+		return APIState{}, err
+	}
+
+	// filling state with region statuses
+	for job := range apiState.Resolver.GetStatuses(apiState.Regions) {
+		if job.Err != nil {
+			return APIState{}, job.Err
+		}
+
+		job.Status.Realms = config.SotahConfig.FilterInRealms(job.Region, job.Status.Realms)
+		apiState.Statuses[job.Region.Name] = job.Status
+	}
+
+	// filling state with item-classes
+	primaryRegion, err := apiState.Regions.GetPrimaryRegion()
+	if err != nil {
+		return APIState{}, err
+	}
+	itemClasses, err := apiState.Resolver.NewItemClasses(primaryRegion)
+	if err != nil {
+		return APIState{}, err
+	}
+	apiState.ItemClasses = itemClasses
+
+	// loading the items database
+	itemsDatabase, err := database.NewItemsDatabase(config.ItemsDatabaseDir)
+	if err != nil {
+		return APIState{}, err
+	}
+	apiState.IO.Databases.ItemsDatabase = itemsDatabase
+
+	// gathering profession icons
+	for i, prof := range apiState.Professions {
+		apiState.Professions[i].IconURL = blizzard.DefaultGetItemIconURL(prof.Icon)
+	}
+
+	// grouping connected realms so that each auction house is downloaded once
+	apiState.RealmGroups = connectedrealms.NewGroups(apiState.Statuses)
+
+	// loading the download ledger, so that files downloaded before a restart are not downloaded again
+	downloadsDatabase, err := downloads.NewDatabase(config.ItemsDatabaseDir)
+	if err != nil {
+		return APIState{}, err
+	}
+	apiState.DownloadsDatabase = downloadsDatabase
+
+	regionNames := []blizzard.RegionName{}
+	for _, reg := range apiState.Regions {
+		regionNames = append(regionNames, reg.Name)
+	}
+	regionEntries, err := apiState.DownloadsDatabase.GetRegionEntries(regionNames)
+	if err != nil {
+		return APIState{}, err
+	}
+	apiState.RegionRealmModificationDates = regionEntries.ModificationDates()
+
+	// establishing a store
+	logging.WithField("kind", config.StorageBackend.Kind).Info("Connecting to storage backend")
+	stor, err := storage.NewStoreFromConfig(config.StorageBackend, gameversions.Retail)
+	if err != nil {
+		return APIState{}, err
+	}
+	apiState.Store = stor
+
+	// persisting realms for other consumers of the store
+	for _, status := range apiState.Statuses {
+		for _, realm := range status.Realms {
+			if err := apiState.Store.WriteRealm(realm); err != nil {
+				return APIState{}, err
+			}
+		}
+	}
+
+	// loading the items index and catching it up with the items database
+	itemsIndex, err := items.NewIndex(config.ItemsDatabaseDir)
+	if err != nil {
+		return APIState{}, err
+	}
+	apiState.ItemsIndex = itemsIndex
+
+	iMap, err := apiState.IO.Databases.ItemsDatabase.GetItems()
+	if err != nil {
+		return APIState{}, err
+	}
+	if err := apiState.ItemsIndex.IndexItems(iMap); err != nil {
+		return APIState{}, err
+	}
+
+	// loading recipes from the recipes file and the blizzard api
+	loadedRecipes, err := recipes.NewRecipesFromConfig(config.Recipes, apiState.Resolver, primaryRegion)
+	if err != nil {
+		return APIState{}, err
+	}
+	if err := loadedRecipes.Validate(config.SotahConfig.Professions); err != nil {
+		return APIState{}, err
+	}
+	apiState.Recipes = loadedRecipes
+
+	// loading the optional yield table
+	apiState.Yields = yields.Yields{}
+	if len(config.Yields.Filepath) > 0 {
+		loadedYields, err := yields.NewYieldsFromFilepath(config.Yields.Filepath)
+		if err != nil {
+			return APIState{}, err
+		}
+		if err := loadedYields.Validate(); err != nil {
+			return APIState{}, err
+		}
+		apiState.Yields = loadedYields
+	}
+
+	// establishing listeners
+	apiState.Listeners = state.NewListeners(apiState.SubjectListeners())
+
+	return apiState, nil
+}
+
+type APIState struct {
+	devState.APIState
+
+	Store             storage.Store
+	Resolver          resolver.Resolver
+	RealmGroups       connectedrealms.Groups
+	DownloadsDatabase downloads.Database
+	ItemsIndex        items.Index
+	Recipes           recipes.Recipes
+	Yields            yields.Yields
+}
+
+func (sta APIState) SubjectListeners() state.SubjectListeners {
+	return state.SubjectListeners{
+		subjects.Boot:                        sta.ListenForBoot,
+		subjects.SessionSecret:               sta.ListenForSessionSecret,
+		subjects.Status:                      sta.ListenForStatus,
+		subjects.Items:                       sta.ListenForItems,
+		subjects.ItemsQuery:                  sta.ListenForItemsQuery,
+		subjects.QueryRealmModificationDates: sta.ListenForQueryRealmModificationDates,
+		subjects.RealmModificationDates:      sta.ListenForRealmModificationDates,
+		CraftingProfit:                       sta.ListenForCraftingProfit,
+		YieldValues:                          sta.ListenForYieldValues,
+		Export:                               sta.ListenForExport,
+		RealmStaleness:                       sta.ListenForRealmStaleness,
+	}
+}
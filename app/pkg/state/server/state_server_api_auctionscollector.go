@@ -0,0 +1,270 @@
+package server
+
+import (
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/blizzard"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/logging"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/metric"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/sotah"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/state"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/state/subjects"
+)
+
+func (sta APIState) StartCollector(stopChan sotah.WorkerStopChan) sotah.WorkerStopChan {
+	sta.collectRegions()
+
+	onStop := make(sotah.WorkerStopChan)
+	go func() {
+		ticker := time.NewTicker(20 * time.Minute)
+
+		logging.Info("Starting collector")
+	outer:
+		for {
+			select {
+			case <-ticker.C:
+				sta.collectRegions()
+			case <-stopChan:
+				ticker.Stop()
+
+				break outer
+			}
+		}
+
+		onStop <- struct{}{}
+	}()
+
+	return onStop
+}
+
+func (sta APIState) collectRegions() {
+	logging.Info("Collecting regions")
+
+	// for subsequently pushing to the live-auctions-intake listener
+	regionRealmTimestamps := sotah.RegionRealmTimestampMaps{}
+
+	// going over the list of regions
+	startTime := time.Now()
+	totalRealms := 0
+	includedRealmCount := 0
+	groupedRealmCount := 0
+	unchangedRealmCount := 0
+	for regionName, status := range sta.Statuses {
+		totalRealms += len(status.Realms)
+
+		// downloading once per connected-realm group
+		primaryRealms := sta.RealmGroups.PrimaryRealms(status.Realms)
+		groupedRealmCount += len(status.Realms) - len(primaryRealms)
+
+		// misc
+		receivedItemIds := map[blizzard.ItemID]struct{}{}
+
+		// starting channels for persisting auctions
+		storeAuctionsInJobs := make(chan state.StoreAuctionsInJob)
+		storeAuctionsOutJobs := StoreAuctions(sta.Store, storeAuctionsInJobs)
+
+		// queueing up the jobs
+		go func() {
+			logging.WithFields(logrus.Fields{
+				"region":         regionName,
+				"realms":         len(status.Realms),
+				"primary-realms": len(primaryRealms),
+			}).Debug("Downloading region")
+			for getAuctionsJob := range sta.Resolver.GetAuctionsForRealms(
+				primaryRealms,
+				sta.RegionRealmModificationDates,
+			) {
+				if getAuctionsJob.Err != nil {
+					logrus.WithFields(getAuctionsJob.ToLogrusFields()).Error("Failed to fetch auctions")
+
+					continue
+				}
+
+				// noting the check in the download ledger for every realm in the group
+				if getAuctionsJob.Unchanged {
+					unchangedRealmCount++
+
+					err := sta.DownloadsDatabase.RecordUnchanged(
+						getAuctionsJob.Realm.Region.Name,
+						sta.RealmGroups.Members(getAuctionsJob.Realm.Region.Name, getAuctionsJob.Realm.Slug),
+						time.Now(),
+					)
+					if err != nil {
+						logging.WithFields(logrus.Fields{
+							"error":  err.Error(),
+							"region": getAuctionsJob.Realm.Region.Name,
+							"realm":  getAuctionsJob.Realm.Slug,
+						}).Error("Failed to record unchanged auctions in download ledger")
+					}
+
+					continue
+				}
+
+				storeAuctionsInJobs <- state.StoreAuctionsInJob{
+					Realm:      getAuctionsJob.Realm,
+					TargetTime: getAuctionsJob.LastModified,
+					Auctions:   getAuctionsJob.Auctions,
+				}
+			}
+
+			close(storeAuctionsInJobs)
+		}()
+
+		// waiting for the store-load-in jobs to drain out
+		for job := range storeAuctionsOutJobs {
+			// incrementing included-realm count
+			includedRealmCount++
+
+			// optionally skipping on error
+			if job.Err != nil {
+				logging.WithFields(job.ToLogrusFields()).Error("Failed to persist auctions")
+
+				continue
+			}
+
+			if _, ok := regionRealmTimestamps[job.Realm.Region.Name]; !ok {
+				regionRealmTimestamps[job.Realm.Region.Name] = sotah.RealmTimestampMap{}
+			}
+			regionRealmTimestamps[job.Realm.Region.Name][job.Realm.Slug] = job.TargetTime.Unix()
+
+			// updating the last-modified in the download ledger and realm-modification-dates for every realm in the group
+			memberSlugs := sta.RealmGroups.Members(job.Realm.Region.Name, job.Realm.Slug)
+			err := sta.DownloadsDatabase.RecordDownload(job.Realm.Region.Name, memberSlugs, job.TargetTime, time.Now())
+			if err != nil {
+				logging.WithFields(logrus.Fields{
+					"error":  err.Error(),
+					"region": job.Realm.Region.Name,
+					"realm":  job.Realm.Slug,
+				}).Error("Failed to record download in download ledger")
+			}
+
+			for _, memberSlug := range memberSlugs {
+				realmModDates := sta.RegionRealmModificationDates.Get(job.Realm.Region.Name, memberSlug)
+				realmModDates.Downloaded = job.TargetTime.Unix()
+
+				sta.RegionRealmModificationDates = sta.RegionRealmModificationDates.Set(
+					job.Realm.Region.Name,
+					memberSlug,
+					realmModDates,
+				)
+			}
+
+			// appending to received item-ids
+			for _, itemId := range job.ItemIds {
+				receivedItemIds[itemId] = struct{}{}
+			}
+		}
+		logging.WithField("region", regionName).Debug("Downloaded and persisted region")
+
+		// resolving items
+		if err := sta.resolveItems(receivedItemIds); err != nil {
+			logging.WithFields(logrus.Fields{
+				"error":  err.Error(),
+				"region": regionName,
+			}).Error("Failed to process item-ids from batch")
+		}
+	}
+
+	// publishing for live-auctions-intake
+	err := func() error {
+		encodedRequest, err := IntakeRequest{RegionRealmTimestamps: regionRealmTimestamps}.EncodeForDelivery()
+		if err != nil {
+			return err
+		}
+
+		return sta.IO.Messenger.Publish(string(subjects.LiveAuctionsIntake), encodedRequest)
+	}()
+	if err != nil {
+		logging.WithField("error", err.Error()).Error("Failed to publish live-auctions-intake-request")
+
+		return
+	}
+
+	duration := time.Since(startTime)
+	sta.IO.Reporter.Report(metric.Metrics{
+		"auctionscollector_intake_duration": int(duration) / 1000 / 1000 / 1000,
+		"included_realms":                   includedRealmCount,
+		"excluded_realms":                   totalRealms - includedRealmCount - groupedRealmCount,
+		"grouped_realms":                    groupedRealmCount,
+		"unchanged_realms":                  unchangedRealmCount,
+		"total_realms":                      totalRealms,
+	})
+	logging.Info("Finished collector")
+}
+
+func (sta APIState) resolveItems(receivedItemIds map[blizzard.ItemID]struct{}) error {
+	logging.Debug("Fetching items from database")
+
+	// gathering current items
+	iMap, err := sta.IO.Databases.ItemsDatabase.GetItems()
+	if err != nil {
+		return err
+	}
+
+	// gathering new item-ids
+	newItemIds := func() []blizzard.ItemID {
+		out := []blizzard.ItemID{}
+
+		for ID := range receivedItemIds {
+			if sta.ItemBlacklist.IsPresent(ID) {
+				continue
+			}
+
+			if _, ok := iMap[ID]; ok {
+				continue
+			}
+
+			out = append(out, ID)
+		}
+
+		return out
+	}()
+
+	// optionally halting on no new items
+	if len(newItemIds) == 0 {
+		return nil
+	}
+
+	logging.WithField("items", len(newItemIds)).Debug("Resolving new items")
+
+	primaryRegion, err := sta.Regions.GetPrimaryRegion()
+	if err != nil {
+		return err
+	}
+
+	// gathering new items and persisting them to the store
+	newItems := sotah.ItemsMap{}
+	for job := range sta.Resolver.GetItems(primaryRegion, newItemIds) {
+		if job.Err != nil {
+			logging.WithFields(logrus.Fields{
+				"error":   job.Err.Error(),
+				"item-id": job.ItemId,
+			}).Error("Failed to fetch item")
+
+			continue
+		}
+
+		itemValue := job.Item
+		iMap[job.ItemId] = itemValue
+		newItems[job.ItemId] = itemValue
+
+		if err := sta.Store.WriteItem(itemValue); err != nil {
+			logging.WithFields(logrus.Fields{
+				"error":   err.Error(),
+				"item-id": job.ItemId,
+			}).Error("Failed to write item to store")
+		}
+	}
+
+	// optionally persisting
+	if len(newItems) == 0 {
+		return nil
+	}
+
+	if err := sta.IO.Databases.ItemsDatabase.PersistItems(iMap); err != nil {
+		return err
+	}
+
+	return sta.ItemsIndex.IndexItems(newItems)
+}
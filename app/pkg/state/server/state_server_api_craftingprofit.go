@@ -0,0 +1,178 @@
+package server
+
+import (
+	"encoding/base64"
+	"encoding/json"
+
+	nats "github.com/nats-io/go-nats"
+	"github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/pricelists"
+	"github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/recipes"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/blizzard"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/messenger"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/messenger/codes"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/state"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/state/subjects"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/util"
+)
+
+const defaultCraftingProfitCount = 25
+
+func newCraftingProfitRequest(payload []byte) (craftingProfitRequest, error) {
+	cpRequest := &craftingProfitRequest{}
+	if err := json.Unmarshal(payload, &cpRequest); err != nil {
+		return craftingProfitRequest{}, err
+	}
+
+	return *cpRequest, nil
+}
+
+type craftingProfitRequest struct {
+	RegionName blizzard.RegionName   `json:"region_name"`
+	RealmSlug  blizzard.RealmSlug    `json:"realm_slug"`
+	Profession string                `json:"profession"`
+	PriceBasis pricelists.PriceBasis `json:"price_basis"`
+	Count      int                   `json:"count"`
+}
+
+func (cpRequest craftingProfitRequest) resolve(sta APIState) (recipes.Recipes, state.RequestError) {
+	if _, ok := sta.Statuses[cpRequest.RegionName]; !ok {
+		return recipes.Recipes{}, state.RequestError{Code: codes.NotFound, Message: "Invalid region"}
+	}
+
+	if err := cpRequest.priceBasis().Validate(); err != nil {
+		return recipes.Recipes{}, state.RequestError{Code: codes.UserError, Message: err.Error()}
+	}
+
+	if len(cpRequest.Profession) == 0 {
+		return sta.Recipes, state.RequestError{Code: codes.Ok, Message: ""}
+	}
+
+	professionRecipes := sta.Recipes.FilterByProfession(cpRequest.Profession)
+	if len(professionRecipes) == 0 {
+		return recipes.Recipes{}, state.RequestError{Code: codes.NotFound, Message: "Invalid profession"}
+	}
+
+	return professionRecipes, state.RequestError{Code: codes.Ok, Message: ""}
+}
+
+func (cpRequest craftingProfitRequest) priceBasis() pricelists.PriceBasis {
+	if len(cpRequest.PriceBasis) == 0 {
+		return pricelists.MarketPrice
+	}
+
+	return cpRequest.PriceBasis
+}
+
+func (cpRequest craftingProfitRequest) count() int {
+	if cpRequest.Count <= 0 {
+		return defaultCraftingProfitCount
+	}
+
+	return cpRequest.Count
+}
+
+type craftingProfitResponse struct {
+	Professions recipes.ProfessionProfits `json:"professions"`
+}
+
+func (cpResponse craftingProfitResponse) EncodeForDelivery() (string, error) {
+	jsonEncoded, err := json.Marshal(cpResponse)
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonEncoded), nil
+}
+
+// findPriceList requests current realm prices from live-auctions, which owns the live-auctions databases
+func (sta APIState) findPriceList(plRequest priceListRequest) (pricelists.ItemPrices, state.RequestError) {
+	encodedMessage, err := json.Marshal(plRequest)
+	if err != nil {
+		return pricelists.ItemPrices{}, state.RequestError{Code: codes.GenericError, Message: err.Error()}
+	}
+
+	msg, err := sta.IO.Messenger.Request(string(subjects.PriceList), encodedMessage)
+	if err != nil {
+		return pricelists.ItemPrices{}, state.RequestError{Code: codes.GenericError, Message: err.Error()}
+	}
+
+	if msg.Code != codes.Ok {
+		return pricelists.ItemPrices{}, state.RequestError{Code: msg.Code, Message: msg.Err}
+	}
+
+	base64Decoded, err := base64.StdEncoding.DecodeString(msg.Data)
+	if err != nil {
+		return pricelists.ItemPrices{}, state.RequestError{Code: codes.GenericError, Message: err.Error()}
+	}
+
+	gzipDecoded, err := util.GzipDecode(base64Decoded)
+	if err != nil {
+		return pricelists.ItemPrices{}, state.RequestError{Code: codes.GenericError, Message: err.Error()}
+	}
+
+	plResponse := priceListResponse{}
+	if err := json.Unmarshal(gzipDecoded, &plResponse); err != nil {
+		return pricelists.ItemPrices{}, state.RequestError{Code: codes.GenericError, Message: err.Error()}
+	}
+
+	return plResponse.PriceList, state.RequestError{Code: codes.Ok, Message: ""}
+}
+
+func (sta APIState) ListenForCraftingProfit(stop state.ListenStopChan) error {
+	err := sta.IO.Messenger.Subscribe(string(CraftingProfit), stop, func(natsMsg nats.Msg) {
+		m := messenger.NewMessage()
+
+		// resolving the request
+		cpRequest, err := newCraftingProfitRequest(natsMsg.Data)
+		if err != nil {
+			m.Err = err.Error()
+			m.Code = codes.MsgJSONParseError
+			sta.IO.Messenger.ReplyTo(natsMsg, m)
+
+			return
+		}
+
+		// resolving the recipes to price
+		requestRecipes, reErr := cpRequest.resolve(sta)
+		if reErr.Code != codes.Ok {
+			m.Err = reErr.Message
+			m.Code = reErr.Code
+			sta.IO.Messenger.ReplyTo(natsMsg, m)
+
+			return
+		}
+
+		// gathering current prices of every reagent and crafted item
+		iPrices, reErr := sta.findPriceList(priceListRequest{
+			RegionName: cpRequest.RegionName,
+			RealmSlug:  cpRequest.RealmSlug,
+			ItemIds:    requestRecipes.ItemIds(),
+		})
+		if reErr.Code != codes.Ok {
+			m.Err = reErr.Message
+			m.Code = reErr.Code
+			sta.IO.Messenger.ReplyTo(natsMsg, m)
+
+			return
+		}
+
+		pProfits := recipes.NewProfessionProfits(requestRecipes, iPrices, cpRequest.priceBasis())
+
+		data, err := craftingProfitResponse{pProfits.Limit(cpRequest.count())}.EncodeForDelivery()
+		if err != nil {
+			m.Err = err.Error()
+			m.Code = codes.GenericError
+			sta.IO.Messenger.ReplyTo(natsMsg, m)
+
+			return
+		}
+
+		m.Data = data
+		sta.IO.Messenger.ReplyTo(natsMsg, m)
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
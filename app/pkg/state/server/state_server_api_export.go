@@ -0,0 +1,43 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/export"
+	"github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/yields"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/messenger/codes"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/state"
+)
+
+func (sta APIState) writeExport(req export.Request, w export.Writer) error {
+	if _, ok := sta.Statuses[req.RegionName]; !ok {
+		return fmt.Errorf("invalid region: %s", req.RegionName)
+	}
+
+	if len(sta.Yields) == 0 {
+		return errors.New("no yield table is configured")
+	}
+
+	for _, realmSlug := range req.RealmSlugs {
+		iPrices, reErr := sta.findPriceList(priceListRequest{
+			RegionName: req.RegionName,
+			RealmSlug:  realmSlug,
+			ItemIds:    sta.Yields.ItemIds(),
+		})
+		if reErr.Code != codes.Ok {
+			return errors.New(reErr.Message)
+		}
+
+		values := yields.NewValues(sta.Yields, iPrices, req.Basis())
+		if err := export.WriteYieldValues(w, req.RegionName, realmSlug, values, req.ItemFilter()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (sta APIState) ListenForExport(stop state.ListenStopChan) error {
+	return listenForExport(sta.IO.Messenger, stop, []export.Dataset{export.YieldValues}, sta.writeExport)
+}
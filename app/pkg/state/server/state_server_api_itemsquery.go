@@ -0,0 +1,64 @@
+package server
+
+import (
+	nats "github.com/nats-io/go-nats"
+	"github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/items"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/messenger"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/messenger/codes"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/state"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/state/subjects"
+)
+
+func (sta APIState) ListenForItemsQuery(stop state.ListenStopChan) error {
+	err := sta.IO.Messenger.Subscribe(string(subjects.ItemsQuery), stop, func(natsMsg nats.Msg) {
+		m := messenger.NewMessage()
+
+		// resolving the request
+		request, err := items.NewQueryRequest(natsMsg.Data)
+		if err != nil {
+			m.Err = err.Error()
+			m.Code = codes.MsgJSONParseError
+			sta.IO.Messenger.ReplyTo(natsMsg, m)
+
+			return
+		}
+
+		request, err = request.Normalize()
+		if err != nil {
+			m.Err = err.Error()
+			m.Code = codes.UserError
+			sta.IO.Messenger.ReplyTo(natsMsg, m)
+
+			return
+		}
+
+		// querying the items-index
+		resp, err := sta.ItemsIndex.Query(request)
+		if err != nil {
+			m.Err = err.Error()
+			m.Code = codes.GenericError
+			sta.IO.Messenger.ReplyTo(natsMsg, m)
+
+			return
+		}
+
+		// marshalling for messenger
+		encodedMessage, err := resp.EncodeForDelivery()
+		if err != nil {
+			m.Err = err.Error()
+			m.Code = codes.GenericError
+			sta.IO.Messenger.ReplyTo(natsMsg, m)
+
+			return
+		}
+
+		// dumping it out
+		m.Data = string(encodedMessage)
+		sta.IO.Messenger.ReplyTo(natsMsg, m)
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,138 @@
+package server
+
+import (
+	"encoding/json"
+	"time"
+
+	nats "github.com/nats-io/go-nats"
+	"github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/downloads"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/blizzard"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/messenger"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/messenger/codes"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/state"
+)
+
+// auctions files are regenerated roughly hourly, so a realm is stale once it has missed a couple of them
+const defaultStaleAfter = 2 * time.Hour
+
+func newRealmStalenessRequest(payload []byte) (realmStalenessRequest, error) {
+	rsRequest := &realmStalenessRequest{}
+	if err := json.Unmarshal(payload, &rsRequest); err != nil {
+		return realmStalenessRequest{}, err
+	}
+
+	return *rsRequest, nil
+}
+
+type realmStalenessRequest struct {
+	RegionName blizzard.RegionName  `json:"region_name"`
+	RealmSlugs []blizzard.RealmSlug `json:"realm_slugs"`
+
+	// seconds since the auctions file was last modified before a realm is stale
+	StaleAfter int64 `json:"stale_after"`
+}
+
+func (rsRequest realmStalenessRequest) staleAfter() time.Duration {
+	if rsRequest.StaleAfter <= 0 {
+		return defaultStaleAfter
+	}
+
+	return time.Duration(rsRequest.StaleAfter) * time.Second
+}
+
+// resolve gathers the requested realms, defaulting to every realm of the region
+func (rsRequest realmStalenessRequest) resolve(sta APIState) ([]blizzard.RealmSlug, state.RequestError) {
+	status, ok := sta.Statuses[rsRequest.RegionName]
+	if !ok {
+		return nil, state.RequestError{Code: codes.NotFound, Message: "Invalid region"}
+	}
+
+	if len(rsRequest.RealmSlugs) == 0 {
+		out := []blizzard.RealmSlug{}
+		for _, rea := range status.Realms {
+			out = append(out, rea.Slug)
+		}
+
+		return out, state.RequestError{Code: codes.Ok, Message: ""}
+	}
+
+	realmMap := status.Realms.ToRealmMap()
+	for _, realmSlug := range rsRequest.RealmSlugs {
+		if _, ok := realmMap[realmSlug]; !ok {
+			return nil, state.RequestError{Code: codes.NotFound, Message: "Invalid realm: " + string(realmSlug)}
+		}
+	}
+
+	return rsRequest.RealmSlugs, state.RequestError{Code: codes.Ok, Message: ""}
+}
+
+type realmStalenessResponse struct {
+	Realms []downloads.Staleness `json:"realms"`
+}
+
+func (rsResponse realmStalenessResponse) EncodeForDelivery() (string, error) {
+	jsonEncoded, err := json.Marshal(rsResponse)
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonEncoded), nil
+}
+
+func (sta APIState) ListenForRealmStaleness(stop state.ListenStopChan) error {
+	err := sta.IO.Messenger.Subscribe(string(RealmStaleness), stop, func(natsMsg nats.Msg) {
+		m := messenger.NewMessage()
+
+		// resolving the request
+		rsRequest, err := newRealmStalenessRequest(natsMsg.Data)
+		if err != nil {
+			m.Err = err.Error()
+			m.Code = codes.MsgJSONParseError
+			sta.IO.Messenger.ReplyTo(natsMsg, m)
+
+			return
+		}
+
+		realmSlugs, reErr := rsRequest.resolve(sta)
+		if reErr.Code != codes.Ok {
+			m.Err = reErr.Message
+			m.Code = reErr.Code
+			sta.IO.Messenger.ReplyTo(natsMsg, m)
+
+			return
+		}
+
+		// gathering the region's download ledger, where realms never downloaded have a blank entry
+		entries, err := sta.DownloadsDatabase.GetEntries(rsRequest.RegionName)
+		if err != nil {
+			m.Err = err.Error()
+			m.Code = codes.GenericError
+			sta.IO.Messenger.ReplyTo(natsMsg, m)
+
+			return
+		}
+
+		now := time.Now()
+		res := realmStalenessResponse{Realms: []downloads.Staleness{}}
+		for _, realmSlug := range realmSlugs {
+			res.Realms = append(res.Realms, entries[realmSlug].Staleness(realmSlug, now, rsRequest.staleAfter()))
+		}
+
+		data, err := res.EncodeForDelivery()
+		if err != nil {
+			m.Err = err.Error()
+			m.Code = codes.GenericError
+			sta.IO.Messenger.ReplyTo(natsMsg, m)
+
+			return
+		}
+
+		m.Data = data
+		sta.IO.Messenger.ReplyTo(natsMsg, m)
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,144 @@
+package server
+
+import (
+	"encoding/json"
+
+	nats "github.com/nats-io/go-nats"
+	"github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/pricelists"
+	"github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/yields"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/blizzard"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/messenger"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/messenger/codes"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/state"
+)
+
+const defaultYieldValuesCount = 50
+
+func newYieldValuesRequest(payload []byte) (yieldValuesRequest, error) {
+	yvRequest := &yieldValuesRequest{}
+	if err := json.Unmarshal(payload, &yvRequest); err != nil {
+		return yieldValuesRequest{}, err
+	}
+
+	return *yvRequest, nil
+}
+
+type yieldValuesRequest struct {
+	RegionName blizzard.RegionName   `json:"region_name"`
+	RealmSlug  blizzard.RealmSlug    `json:"realm_slug"`
+	Method     yields.Method         `json:"method"`
+	PriceBasis pricelists.PriceBasis `json:"price_basis"`
+	Count      int                   `json:"count"`
+}
+
+func (yvRequest yieldValuesRequest) resolve(sta APIState) (yields.Yields, state.RequestError) {
+	if _, ok := sta.Statuses[yvRequest.RegionName]; !ok {
+		return yields.Yields{}, state.RequestError{Code: codes.NotFound, Message: "Invalid region"}
+	}
+
+	if err := yvRequest.priceBasis().Validate(); err != nil {
+		return yields.Yields{}, state.RequestError{Code: codes.UserError, Message: err.Error()}
+	}
+
+	if len(sta.Yields) == 0 {
+		return yields.Yields{}, state.RequestError{Code: codes.NotFound, Message: "No yield table is configured"}
+	}
+
+	if len(yvRequest.Method) == 0 {
+		return sta.Yields, state.RequestError{Code: codes.Ok, Message: ""}
+	}
+
+	if err := yvRequest.Method.Validate(); err != nil {
+		return yields.Yields{}, state.RequestError{Code: codes.UserError, Message: err.Error()}
+	}
+
+	return sta.Yields.FilterByMethod(yvRequest.Method), state.RequestError{Code: codes.Ok, Message: ""}
+}
+
+func (yvRequest yieldValuesRequest) priceBasis() pricelists.PriceBasis {
+	if len(yvRequest.PriceBasis) == 0 {
+		return pricelists.MarketPrice
+	}
+
+	return yvRequest.PriceBasis
+}
+
+func (yvRequest yieldValuesRequest) count() int {
+	if yvRequest.Count <= 0 {
+		return defaultYieldValuesCount
+	}
+
+	return yvRequest.Count
+}
+
+type yieldValuesResponse struct {
+	Values yields.Values `json:"values"`
+}
+
+func (yvResponse yieldValuesResponse) EncodeForDelivery() (string, error) {
+	jsonEncoded, err := json.Marshal(yvResponse)
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonEncoded), nil
+}
+
+func (sta APIState) ListenForYieldValues(stop state.ListenStopChan) error {
+	err := sta.IO.Messenger.Subscribe(string(YieldValues), stop, func(natsMsg nats.Msg) {
+		m := messenger.NewMessage()
+
+		// resolving the request
+		yvRequest, err := newYieldValuesRequest(natsMsg.Data)
+		if err != nil {
+			m.Err = err.Error()
+			m.Code = codes.MsgJSONParseError
+			sta.IO.Messenger.ReplyTo(natsMsg, m)
+
+			return
+		}
+
+		// resolving the yields to value
+		requestYields, reErr := yvRequest.resolve(sta)
+		if reErr.Code != codes.Ok {
+			m.Err = reErr.Message
+			m.Code = reErr.Code
+			sta.IO.Messenger.ReplyTo(natsMsg, m)
+
+			return
+		}
+
+		// gathering current prices of every source and outcome item
+		iPrices, reErr := sta.findPriceList(priceListRequest{
+			RegionName: yvRequest.RegionName,
+			RealmSlug:  yvRequest.RealmSlug,
+			ItemIds:    requestYields.ItemIds(),
+		})
+		if reErr.Code != codes.Ok {
+			m.Err = reErr.Message
+			m.Code = reErr.Code
+			sta.IO.Messenger.ReplyTo(natsMsg, m)
+
+			return
+		}
+
+		values := yields.NewValues(requestYields, iPrices, yvRequest.priceBasis())
+
+		data, err := yieldValuesResponse{values.Limit(yvRequest.count())}.EncodeForDelivery()
+		if err != nil {
+			m.Err = err.Error()
+			m.Code = codes.GenericError
+			sta.IO.Messenger.ReplyTo(natsMsg, m)
+
+			return
+		}
+
+		m.Data = data
+		sta.IO.Messenger.ReplyTo(natsMsg, m)
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
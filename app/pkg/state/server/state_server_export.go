@@ -0,0 +1,111 @@
+package server
+
+import (
+	"errors"
+
+	nats "github.com/nats-io/go-nats"
+	"github.com/sirupsen/logrus"
+	"github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/export"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/logging"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/messenger"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/messenger/codes"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/state"
+)
+
+type exportWriteFunc func(req export.Request, w export.Writer) error
+
+// listenForExport serves export requests for the datasets this process owns, streaming the encoded file onto the
+// request's stream subject since a single reply cannot carry it, one acknowledged chunk at a time
+func listenForExport(
+	mess messenger.Messenger,
+	stop state.ListenStopChan,
+	datasets []export.Dataset,
+	write exportWriteFunc,
+) error {
+	owned := map[export.Dataset]struct{}{}
+	for _, d := range datasets {
+		owned[d] = struct{}{}
+	}
+
+	err := mess.Subscribe(string(Export), stop, func(natsMsg nats.Msg) {
+		req, err := export.NewRequest(natsMsg.Data)
+		if err != nil {
+			logging.WithField("error", err.Error()).Error("Failed to parse export request")
+
+			return
+		}
+
+		// other processes serve the datasets this one does not own
+		if _, ok := owned[req.Dataset]; !ok {
+			return
+		}
+
+		streamExport(mess, req, write)
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func streamExport(mess messenger.Messenger, req export.Request, write exportWriteFunc) {
+	logging.WithFields(logrus.Fields{
+		"dataset":        req.Dataset,
+		"format":         req.Format,
+		"region":         req.RegionName,
+		"realms":         len(req.RealmSlugs),
+		"stream-subject": req.StreamSubject,
+	}).Info("Streaming export")
+
+	stream := export.NewStream(func(c export.Chunk) error {
+		data, err := c.EncodeForDelivery()
+		if err != nil {
+			return err
+		}
+
+		// waiting on the receiver to acknowledge each chunk, so that a slow receiver paces the export
+		ack, err := mess.Request(req.StreamSubject, data)
+		if err != nil {
+			return err
+		}
+		if ack.Code != codes.Ok {
+			return errors.New(ack.Err)
+		}
+
+		return nil
+	})
+
+	err := func() error {
+		if err := req.Validate(); err != nil {
+			return err
+		}
+
+		w, err := export.NewWriter(req.Format, req.Dataset.Schema(), stream)
+		if err != nil {
+			return err
+		}
+
+		if err := write(req, w); err != nil {
+			return err
+		}
+
+		return w.Close()
+	}()
+	if err != nil {
+		logging.WithFields(logrus.Fields{
+			"error":   err.Error(),
+			"dataset": req.Dataset,
+		}).Error("Failed to export dataset")
+
+		if err := stream.Fail(err); err != nil {
+			logging.WithField("error", err.Error()).Error("Failed to publish export failure")
+		}
+
+		return
+	}
+
+	if err := stream.Finish(); err != nil {
+		logging.WithField("error", err.Error()).Error("Failed to publish last export chunk")
+	}
+}
@@ -0,0 +1,108 @@
+package server
+
+import (
+	"github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/transport"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/act"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/hell"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/logging"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/messenger"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/state"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/state/subjects"
+	"github.com/twinj/uuid"
+)
+
+type GatewayStateConfig struct {
+	GCloudProjectID string
+
+	MessengerHost string
+	MessengerPort int
+
+	// gateway act endpoint, fetched from hell when blank
+	ActEndpoint string
+
+	BusTransport transport.Kind
+}
+
+func NewGatewayState(config GatewayStateConfig) (GatewayState, error) {
+	// establishing an initial state
+	sta := GatewayState{
+		State:       state.NewState(uuid.NewV4(), config.BusTransport == transport.Pubsub),
+		actEndpoint: config.ActEndpoint,
+	}
+
+	// resolving the act endpoint from hell
+	if len(sta.actEndpoint) == 0 {
+		hellClient, err := hell.NewClient(config.GCloudProjectID)
+		if err != nil {
+			logging.WithField("error", err.Error()).Error("Failed to connect to hell")
+
+			return GatewayState{}, err
+		}
+		sta.IO.HellClient = hellClient
+
+		actEndpoints, err := hellClient.GetActEndpoints()
+		if err != nil {
+			logging.WithField("error", err.Error()).Error("Failed to fetch act endpoints")
+
+			return GatewayState{}, err
+		}
+		sta.actEndpoint = actEndpoints.Gateway
+		sta.authorizeAct = true
+	}
+
+	// connecting to the messenger host for the nats transport
+	if config.BusTransport == transport.Nats {
+		mess, err := messenger.NewMessenger(config.MessengerHost, config.MessengerPort)
+		if err != nil {
+			return GatewayState{}, err
+		}
+		sta.IO.Messenger = mess
+	}
+
+	// establishing a bus
+	logging.WithField("transport", config.BusTransport).Info("Connecting bus-client")
+	busTransport, err := transport.NewTransport(transport.Config{
+		Kind:            config.BusTransport,
+		GCloudProjectID: config.GCloudProjectID,
+		SubscriberID:    "prod-gateway",
+		Messenger:       sta.IO.Messenger,
+	})
+	if err != nil {
+		return GatewayState{}, err
+	}
+	sta.BusClient = transport.NewClient(busTransport)
+
+	// establishing bus-listeners
+	sta.BusListeners = state.NewBusListeners(state.SubjectBusListeners{
+		subjects.CallDownloadAllAuctions:          sta.ListenForCallDownloadAllAuctions,
+		subjects.CallCleanupAllManifests:          sta.ListenForCallCleanupAllManifests,
+		subjects.CallCleanupAllAuctions:           sta.ListenForCallCleanupAllAuctions,
+		subjects.CallComputeAllLiveAuctions:       sta.ListenForCallComputeAllLiveAuctions,
+		subjects.CallSyncAllItems:                 sta.ListenForCallSyncAllItems,
+		subjects.CallComputeAllPricelistHistories: sta.ListenForCallComputeAllPricelistHistories,
+		subjects.CallCleanupAllPricelistHistories: sta.ListenForCallCleanupAllPricelistHistories,
+	})
+
+	return sta, nil
+}
+
+type GatewayState struct {
+	state.State
+
+	BusClient transport.Client
+
+	actEndpoint string
+
+	// whether calls carry an identity token, which only the gcloud act services require
+	authorizeAct bool
+}
+
+func (sta GatewayState) newActClient() (act.Client, error) {
+	logging.WithField("endpoint-url", sta.actEndpoint).Info("Producing act client for gateway act endpoint")
+
+	if !sta.authorizeAct {
+		return act.Client{ServiceURL: sta.actEndpoint}, nil
+	}
+
+	return act.NewClient(sta.actEndpoint)
+}
@@ -0,0 +1,211 @@
+package server
+
+import (
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/transport"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/act"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/blizzard"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/bus"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/bus/codes"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/logging"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/sotah"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/state/subjects"
+)
+
+// runCall calls a route of the gateway act service
+func (sta GatewayState) runCall(name string, call func(actClient act.Client) error) error {
+	actClient, err := sta.newActClient()
+	if err != nil {
+		return err
+	}
+
+	startTime := time.Now()
+	logging.WithField("call", name).Info("Calling gateway service")
+	if err := call(actClient); err != nil {
+		return err
+	}
+
+	logging.WithFields(logrus.Fields{
+		"call":     name,
+		"duration": int(int64(time.Since(startTime)) / 1000 / 1000 / 1000),
+	}).Info("Done calling gateway service")
+
+	return nil
+}
+
+// listenForCall subscribes to a call subject, handling each decoded payload one at a time on a worker
+func (sta GatewayState) listenForCall(
+	subject subjects.Subject,
+	decode func(busMsg bus.Message) (interface{}, error),
+	handle func(payload interface{}) error,
+	onReady chan interface{},
+	stop chan interface{},
+	onStopped chan interface{},
+) {
+	in := make(chan interface{})
+	go func() {
+		for payload := range in {
+			if err := handle(payload); err != nil {
+				logging.WithFields(logrus.Fields{
+					"error":   err.Error(),
+					"subject": subject,
+				}).Error("Failed to call gateway service")
+
+				continue
+			}
+		}
+	}()
+
+	// establishing subscriber config
+	config := transport.SubscribeConfig{
+		Stop: stop,
+		Callback: func(busMsg bus.Message) {
+			logging.WithField("bus-msg-code", busMsg.Code).Info("Received bus-message")
+
+			// parsing the message body
+			payload, err := decode(busMsg)
+			if err != nil {
+				logging.WithField("error", err.Error()).Error("Failed to parse bus message body")
+
+				if err := sta.BusClient.ReplyToWithError(busMsg, err, codes.GenericError); err != nil {
+					logging.WithField("error", err.Error()).Error("Failed to reply to message")
+
+					return
+				}
+
+				return
+			}
+
+			in <- payload
+		},
+		OnReady:   onReady,
+		OnStopped: onStopped,
+	}
+
+	// starting up worker for the subscription
+	go func() {
+		if err := sta.BusClient.SubscribeToTopic(string(subject), config); err != nil {
+			logging.WithField("error", err.Error()).Fatal("Failed to subscribe to topic")
+		}
+	}()
+}
+
+// decodeTrigger accepts any message, since the trigger-only calls carry no payload
+func decodeTrigger(busMsg bus.Message) (interface{}, error) {
+	return struct{}{}, nil
+}
+
+// ackTuples decodes region-realm-timestamp tuples and acks the message before the call is made
+func (sta GatewayState) ackTuples(busMsg bus.Message) (interface{}, error) {
+	tuples, err := sotah.NewRegionRealmTimestampTuples(busMsg.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := sta.BusClient.ReplyTo(busMsg, bus.NewMessage()); err != nil {
+		return nil, err
+	}
+
+	return tuples, nil
+}
+
+// ackItemIds decodes item-ids and acks the message before the call is made
+func (sta GatewayState) ackItemIds(busMsg bus.Message) (interface{}, error) {
+	ids, err := blizzard.NewItemIds(busMsg.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := sta.BusClient.ReplyTo(busMsg, bus.NewMessage()); err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+func (sta GatewayState) ListenForCallDownloadAllAuctions(
+	onReady chan interface{},
+	stop chan interface{},
+	onStopped chan interface{},
+) {
+	sta.listenForCall(subjects.CallDownloadAllAuctions, decodeTrigger, func(interface{}) error {
+		return sta.runCall("download-all-auctions", func(actClient act.Client) error {
+			return actClient.DownloadAllAuctions()
+		})
+	}, onReady, stop, onStopped)
+}
+
+func (sta GatewayState) ListenForCallCleanupAllManifests(
+	onReady chan interface{},
+	stop chan interface{},
+	onStopped chan interface{},
+) {
+	sta.listenForCall(subjects.CallCleanupAllManifests, decodeTrigger, func(interface{}) error {
+		return sta.runCall("cleanup-all-manifests", func(actClient act.Client) error {
+			return actClient.CleanupAllManifests()
+		})
+	}, onReady, stop, onStopped)
+}
+
+func (sta GatewayState) ListenForCallCleanupAllAuctions(
+	onReady chan interface{},
+	stop chan interface{},
+	onStopped chan interface{},
+) {
+	sta.listenForCall(subjects.CallCleanupAllAuctions, decodeTrigger, func(interface{}) error {
+		return sta.runCall("cleanup-all-auctions", func(actClient act.Client) error {
+			return actClient.CleanupAllAuctions()
+		})
+	}, onReady, stop, onStopped)
+}
+
+func (sta GatewayState) ListenForCallCleanupAllPricelistHistories(
+	onReady chan interface{},
+	stop chan interface{},
+	onStopped chan interface{},
+) {
+	sta.listenForCall(subjects.CallCleanupAllPricelistHistories, decodeTrigger, func(interface{}) error {
+		return sta.runCall("cleanup-all-pricelist-histories", func(actClient act.Client) error {
+			return actClient.CleanupAllPricelistHistories()
+		})
+	}, onReady, stop, onStopped)
+}
+
+func (sta GatewayState) ListenForCallComputeAllLiveAuctions(
+	onReady chan interface{},
+	stop chan interface{},
+	onStopped chan interface{},
+) {
+	sta.listenForCall(subjects.CallComputeAllLiveAuctions, sta.ackTuples, func(payload interface{}) error {
+		return sta.runCall("compute-all-live-auctions", func(actClient act.Client) error {
+			return actClient.ComputeAllLiveAuctions(payload.(sotah.RegionRealmTimestampTuples))
+		})
+	}, onReady, stop, onStopped)
+}
+
+func (sta GatewayState) ListenForCallComputeAllPricelistHistories(
+	onReady chan interface{},
+	stop chan interface{},
+	onStopped chan interface{},
+) {
+	sta.listenForCall(subjects.CallComputeAllPricelistHistories, sta.ackTuples, func(payload interface{}) error {
+		return sta.runCall("compute-all-pricelist-histories", func(actClient act.Client) error {
+			return actClient.ComputeAllPricelistHistories(payload.(sotah.RegionRealmTimestampTuples))
+		})
+	}, onReady, stop, onStopped)
+}
+
+func (sta GatewayState) ListenForCallSyncAllItems(
+	onReady chan interface{},
+	stop chan interface{},
+	onStopped chan interface{},
+) {
+	sta.listenForCall(subjects.CallSyncAllItems, sta.ackItemIds, func(payload interface{}) error {
+		return sta.runCall("sync-all-items", func(actClient act.Client) error {
+			return actClient.SyncAllItems(payload.(blizzard.ItemIds))
+		})
+	}, onReady, stop, onStopped)
+}
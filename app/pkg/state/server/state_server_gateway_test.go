@@ -0,0 +1,130 @@
+package server
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/transport"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/blizzard"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/bus"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/bus/codes"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/state/subjects"
+)
+
+type actCall struct {
+	path string
+	body string
+}
+
+// newTestGatewayState points a gateway state on an in-process bus at a fake act service
+func newTestGatewayState(t *testing.T) (GatewayState, chan actCall, func()) {
+	calls := make(chan actCall, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			t.Errorf("could not read act request body: %s", err)
+		}
+
+		calls <- actCall{path: r.URL.Path, body: string(body)}
+		w.WriteHeader(http.StatusCreated)
+	}))
+
+	sta := GatewayState{
+		BusClient:   transport.NewClient(transport.NewInProcessTransport()),
+		actEndpoint: server.URL,
+	}
+
+	return sta, calls, server.Close
+}
+
+func listen(
+	t *testing.T,
+	listener func(onReady chan interface{}, stop chan interface{}, onStopped chan interface{}),
+) func() {
+	onReady := make(chan interface{})
+	stop := make(chan interface{})
+	onStopped := make(chan interface{})
+	listener(onReady, stop, onStopped)
+
+	select {
+	case <-onReady:
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for the listener to be ready")
+	}
+
+	return func() {
+		stop <- struct{}{}
+		<-onStopped
+	}
+}
+
+func receiveActCall(t *testing.T, calls chan actCall) actCall {
+	select {
+	case call := <-calls:
+		return call
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for an act call")
+	}
+
+	return actCall{}
+}
+
+func TestGatewayStateCallsTrigger(t *testing.T) {
+	sta, calls, cleanup := newTestGatewayState(t)
+	defer cleanup()
+
+	stop := listen(t, sta.ListenForCallDownloadAllAuctions)
+	defer stop()
+
+	if err := sta.BusClient.Publish(string(subjects.CallDownloadAllAuctions), bus.NewMessage()); err != nil {
+		t.Fatalf("could not publish: %s", err)
+	}
+
+	if call := receiveActCall(t, calls); call.path != "/download-all-auctions" {
+		t.Fatalf("unexpected act call: %+v", call)
+	}
+}
+
+func TestGatewayStateAcksPayloadCalls(t *testing.T) {
+	sta, calls, cleanup := newTestGatewayState(t)
+	defer cleanup()
+
+	stop := listen(t, sta.ListenForCallSyncAllItems)
+	defer stop()
+
+	ids := blizzard.ItemIds{1, 2}
+	encodedIds, err := ids.EncodeForDelivery()
+	if err != nil {
+		t.Fatalf("could not encode item-ids: %s", err)
+	}
+
+	reply, err := sta.BusClient.Request(string(subjects.CallSyncAllItems), encodedIds, time.Second)
+	if err != nil {
+		t.Fatalf("could not request: %s", err)
+	}
+	if reply.Code != codes.Ok {
+		t.Fatalf("expected the call to be acked, got %+v", reply)
+	}
+
+	call := receiveActCall(t, calls)
+	if call.path != "/sync-all-items" || call.body != encodedIds {
+		t.Fatalf("unexpected act call: %+v", call)
+	}
+
+	// malformed payloads are rejected without calling the act service
+	reply, err = sta.BusClient.Request(string(subjects.CallSyncAllItems), "not-item-ids", time.Second)
+	if err != nil {
+		t.Fatalf("could not request: %s", err)
+	}
+	if reply.Code != codes.GenericError {
+		t.Fatalf("expected a malformed payload to be rejected, got %+v", reply)
+	}
+	select {
+	case call := <-calls:
+		t.Fatalf("expected no act call for a malformed payload, got %+v", call)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
@@ -0,0 +1,70 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/logging"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/messenger"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/state"
+	"github.com/twinj/uuid"
+)
+
+type HTTPAPIStateConfig struct {
+	MessengerHost string
+	MessengerPort int
+
+	ListenHost string
+	ListenPort int
+}
+
+func NewHTTPAPIState(config HTTPAPIStateConfig) (HTTPAPIState, error) {
+	// establishing an initial state
+	httpState := HTTPAPIState{
+		State:  state.NewState(uuid.NewV4(), false),
+		Routes: NewHTTPRoutes(),
+	}
+
+	// connecting to the messenger host
+	mess, err := messenger.NewMessenger(config.MessengerHost, config.MessengerPort)
+	if err != nil {
+		return HTTPAPIState{}, err
+	}
+	httpState.IO.Messenger = mess
+
+	// establishing an http server
+	httpState.Server = &http.Server{
+		Addr:         fmt.Sprintf("%s:%d", config.ListenHost, config.ListenPort),
+		Handler:      httpState.NewHandler(),
+		ReadTimeout:  10 * time.Second,
+		WriteTimeout: 30 * time.Second,
+	}
+
+	return httpState, nil
+}
+
+type HTTPAPIState struct {
+	state.State
+
+	Routes HTTPRoutes
+	Server *http.Server
+}
+
+func (httpState HTTPAPIState) Serve() error {
+	logging.WithField("address", httpState.Server.Addr).Info("Starting http server")
+
+	if err := httpState.Server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		return err
+	}
+
+	return nil
+}
+
+func (httpState HTTPAPIState) Shutdown() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	return httpState.Server.Shutdown(ctx)
+}
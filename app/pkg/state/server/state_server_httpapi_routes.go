@@ -0,0 +1,215 @@
+package server
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	nats "github.com/nats-io/go-nats"
+	"github.com/sirupsen/logrus"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/logging"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/messenger"
+	mCodes "github.com/sotah-inc/steamwheedle-cartel/pkg/messenger/codes"
+	sCodes "github.com/sotah-inc/steamwheedle-cartel/pkg/sotah/codes"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/state/subjects"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/util"
+)
+
+// ReplyEncoding - typehint for how a subject encodes its reply data
+type ReplyEncoding string
+
+/*
+ReplyEncodings - reply data encodings
+*/
+const (
+	PlainReplyEncoding      ReplyEncoding = "plain"
+	GzipBase64ReplyEncoding ReplyEncoding = "gzip-base64"
+)
+
+type HTTPRoute struct {
+	Subject  subjects.Subject
+	Encoding ReplyEncoding
+
+	// whether the subject may be requested without a payload
+	AllowGet bool
+}
+
+func (route HTTPRoute) Path() string {
+	return "/" + string(route.Subject)
+}
+
+func (route HTTPRoute) decodeReplyData(data string) ([]byte, error) {
+	if route.Encoding == PlainReplyEncoding {
+		return []byte(data), nil
+	}
+
+	base64Decoded, err := base64.StdEncoding.DecodeString(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return util.GzipDecode(base64Decoded)
+}
+
+type HTTPRoutes []HTTPRoute
+
+func NewHTTPRoutes() HTTPRoutes {
+	return HTTPRoutes{
+		{Subject: subjects.Status, Encoding: PlainReplyEncoding},
+		{Subject: subjects.Boot, Encoding: PlainReplyEncoding, AllowGet: true},
+		{Subject: subjects.RealmModificationDates, Encoding: PlainReplyEncoding, AllowGet: true},
+		{Subject: subjects.QueryRealmModificationDates, Encoding: PlainReplyEncoding},
+		{Subject: subjects.Auctions, Encoding: GzipBase64ReplyEncoding},
+		{Subject: subjects.AuctionsQuery, Encoding: GzipBase64ReplyEncoding},
+		{Subject: subjects.Owners, Encoding: PlainReplyEncoding},
+		{Subject: subjects.OwnersQuery, Encoding: PlainReplyEncoding},
+		{Subject: subjects.OwnersQueryByItems, Encoding: PlainReplyEncoding},
+		{Subject: subjects.PriceList, Encoding: GzipBase64ReplyEncoding},
+		{Subject: subjects.PriceListHistory, Encoding: GzipBase64ReplyEncoding},
+		{Subject: subjects.Items, Encoding: GzipBase64ReplyEncoding},
+		{Subject: subjects.ItemsQuery, Encoding: PlainReplyEncoding},
+		{Subject: SalesEstimate, Encoding: PlainReplyEncoding},
+		{Subject: Alerts, Encoding: PlainReplyEncoding, AllowGet: true},
+		{Subject: CreateAlert, Encoding: PlainReplyEncoding},
+		{Subject: DeleteAlert, Encoding: PlainReplyEncoding},
+		{Subject: RegionPriceList, Encoding: GzipBase64ReplyEncoding},
+		{Subject: OwnerHistory, Encoding: PlainReplyEncoding},
+		{Subject: MarketShare, Encoding: PlainReplyEncoding},
+		{Subject: CraftingProfit, Encoding: PlainReplyEncoding},
+		{Subject: VendorFlips, Encoding: PlainReplyEncoding},
+		{Subject: YieldValues, Encoding: PlainReplyEncoding},
+		{Subject: PriceForecast, Encoding: GzipBase64ReplyEncoding},
+		{Subject: QueryAnomalies, Encoding: PlainReplyEncoding},
+		{Subject: RealmStaleness, Encoding: PlainReplyEncoding},
+	}
+}
+
+// MessengerCodeToHTTPStatus - maps messenger reply codes onto http statuses the same way sotah codes are mapped
+func MessengerCodeToHTTPStatus(code mCodes.Code) int {
+	switch code {
+	case mCodes.Ok:
+		return sCodes.CodeToHTTPStatus(sCodes.Ok)
+	case mCodes.NotFound:
+		return sCodes.CodeToHTTPStatus(sCodes.NotFound)
+	case mCodes.UserError:
+		return sCodes.CodeToHTTPStatus(sCodes.UserError)
+	case mCodes.MsgJSONParseError:
+		return sCodes.CodeToHTTPStatus(sCodes.MsgJSONParseError)
+	case mCodes.GenericError:
+		return sCodes.CodeToHTTPStatus(sCodes.GenericError)
+	default:
+		return sCodes.CodeToHTTPStatus(sCodes.Blank)
+	}
+}
+
+type httpErrorResponse struct {
+	Error string      `json:"error"`
+	Code  mCodes.Code `json:"code"`
+}
+
+func writeHTTPError(w http.ResponseWriter, status int, code mCodes.Code, message string) {
+	encoded, err := json.Marshal(httpErrorResponse{Error: message, Code: code})
+	if err != nil {
+		http.Error(w, message, status)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if _, err := w.Write(encoded); err != nil {
+		logging.WithField("error", err.Error()).Error("Failed to write http error response")
+	}
+}
+
+func (httpState HTTPAPIState) NewHandler() http.Handler {
+	mux := http.NewServeMux()
+	for _, route := range httpState.Routes {
+		mux.HandleFunc(route.Path(), httpState.handleRoute(route))
+	}
+
+	return mux
+}
+
+func (httpState HTTPAPIState) handleRoute(route HTTPRoute) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// validating the method
+		switch r.Method {
+		case http.MethodPost:
+		case http.MethodGet:
+			if !route.AllowGet {
+				w.Header().Set("Allow", http.MethodPost)
+				writeHTTPError(w, http.StatusMethodNotAllowed, mCodes.UserError, "subject requires a payload")
+
+				return
+			}
+		default:
+			w.Header().Set("Allow", strings.Join([]string{http.MethodGet, http.MethodPost}, ", "))
+			writeHTTPError(w, http.StatusMethodNotAllowed, mCodes.UserError, "method not allowed")
+
+			return
+		}
+
+		// gathering the request payload
+		payload, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			writeHTTPError(w, http.StatusBadRequest, mCodes.UserError, err.Error())
+
+			return
+		}
+		if len(payload) > 0 && !json.Valid(payload) {
+			writeHTTPError(w, http.StatusBadRequest, mCodes.MsgJSONParseError, "request body is not valid json")
+
+			return
+		}
+
+		// forwarding the request to the subject
+		msg, err := httpState.IO.Messenger.Request(string(route.Subject), payload)
+		if err != nil {
+			logging.WithFields(logrus.Fields{
+				"error":   err.Error(),
+				"subject": route.Subject,
+			}).Error("Failed to request subject")
+
+			status := http.StatusBadGateway
+			if err == nats.ErrTimeout {
+				status = http.StatusGatewayTimeout
+			}
+			writeHTTPError(w, status, mCodes.GenericError, err.Error())
+
+			return
+		}
+
+		httpState.writeReply(w, route, msg)
+	}
+}
+
+func (httpState HTTPAPIState) writeReply(w http.ResponseWriter, route HTTPRoute, msg messenger.Message) {
+	if msg.Code != mCodes.Ok {
+		writeHTTPError(w, MessengerCodeToHTTPStatus(msg.Code), msg.Code, msg.Err)
+
+		return
+	}
+
+	// unwrapping the reply data
+	data, err := route.decodeReplyData(msg.Data)
+	if err != nil {
+		writeHTTPError(w, http.StatusInternalServerError, mCodes.GenericError, err.Error())
+
+		return
+	}
+	if len(data) == 0 {
+		data = []byte("null")
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(MessengerCodeToHTTPStatus(msg.Code))
+	if _, err := w.Write(data); err != nil {
+		logging.WithFields(logrus.Fields{
+			"error":   err.Error(),
+			"subject": route.Subject,
+		}).Error("Failed to write http response")
+	}
+}
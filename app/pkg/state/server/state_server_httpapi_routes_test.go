@@ -0,0 +1,146 @@
+package server
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/messenger"
+	mCodes "github.com/sotah-inc/steamwheedle-cartel/pkg/messenger/codes"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/state/subjects"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/util"
+)
+
+func decodeHTTPError(t *testing.T, rec *httptest.ResponseRecorder) httpErrorResponse {
+	var res httpErrorResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &res); err != nil {
+		t.Fatalf("could not decode error response %s: %s", rec.Body.String(), err)
+	}
+
+	return res
+}
+
+func TestMessengerCodeToHTTPStatus(t *testing.T) {
+	expected := map[mCodes.Code]int{
+		mCodes.Ok:                http.StatusOK,
+		mCodes.NotFound:          http.StatusNotFound,
+		mCodes.UserError:         http.StatusBadRequest,
+		mCodes.MsgJSONParseError: http.StatusInternalServerError,
+		mCodes.GenericError:      http.StatusInternalServerError,
+		mCodes.Blank:             http.StatusInternalServerError,
+	}
+	for code, status := range expected {
+		if actual := MessengerCodeToHTTPStatus(code); actual != status {
+			t.Fatalf("expected code %d to map to %d, got %d", code, status, actual)
+		}
+	}
+}
+
+func TestNewHTTPRoutes(t *testing.T) {
+	paths := map[string]bool{}
+	for _, route := range NewHTTPRoutes() {
+		if paths[route.Path()] {
+			t.Fatalf("duplicate route: %s", route.Path())
+		}
+		paths[route.Path()] = true
+	}
+
+	for _, subject := range []subjects.Subject{subjects.Auctions, subjects.PriceList, subjects.Status, subjects.Boot} {
+		if !paths["/"+string(subject)] {
+			t.Fatalf("expected a route for %s", subject)
+		}
+	}
+}
+
+func TestHTTPRouteDecodeReplyData(t *testing.T) {
+	gzipped, err := util.GzipEncode([]byte(`{"a":1}`))
+	if err != nil {
+		t.Fatalf("could not gzip: %s", err)
+	}
+
+	route := HTTPRoute{Subject: subjects.Auctions, Encoding: GzipBase64ReplyEncoding}
+	data, err := route.decodeReplyData(base64.StdEncoding.EncodeToString(gzipped))
+	if err != nil || string(data) != `{"a":1}` {
+		t.Fatalf("unexpected decoded data: %s %v", data, err)
+	}
+	if _, err := route.decodeReplyData("not-base64!"); err == nil {
+		t.Fatal("expected malformed data to fail")
+	}
+
+	route = HTTPRoute{Subject: subjects.Status, Encoding: PlainReplyEncoding}
+	if data, err := route.decodeReplyData(`{"a":1}`); err != nil || string(data) != `{"a":1}` {
+		t.Fatalf("expected plain data to pass through, got %s %v", data, err)
+	}
+}
+
+func TestHTTPAPIStateRejectsRequests(t *testing.T) {
+	httpState := HTTPAPIState{Routes: NewHTTPRoutes()}
+	handler := httpState.NewHandler()
+
+	// subjects requiring a payload may not be fetched
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/"+string(subjects.Auctions), nil))
+	if rec.Code != http.StatusMethodNotAllowed || rec.Header().Get("Allow") != http.MethodPost {
+		t.Fatalf("expected a get to be refused, got %d %v", rec.Code, rec.Header())
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/"+string(subjects.Boot), nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("expected a delete to be refused, got %d", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/"+string(subjects.Auctions), strings.NewReader("{")))
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected malformed json to be refused, got %d", rec.Code)
+	}
+	if res := decodeHTTPError(t, rec); res.Code != mCodes.MsgJSONParseError {
+		t.Fatalf("unexpected error response: %+v", res)
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/missing", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected an unknown subject to not be found, got %d", rec.Code)
+	}
+}
+
+func TestHTTPAPIStateWriteReply(t *testing.T) {
+	httpState := HTTPAPIState{}
+	route := HTTPRoute{Subject: subjects.Status, Encoding: PlainReplyEncoding}
+
+	rec := httptest.NewRecorder()
+	httpState.writeReply(rec, route, messenger.Message{Code: mCodes.Ok, Data: `{"a":1}`})
+	if rec.Code != http.StatusOK || rec.Body.String() != `{"a":1}` {
+		t.Fatalf("unexpected reply: %d %s", rec.Code, rec.Body.String())
+	}
+	if rec.Header().Get("Content-Type") != "application/json" {
+		t.Fatalf("expected a json reply, got %v", rec.Header())
+	}
+
+	rec = httptest.NewRecorder()
+	httpState.writeReply(rec, route, messenger.Message{Code: mCodes.Ok})
+	if rec.Body.String() != "null" {
+		t.Fatalf("expected an empty reply to be null, got %s", rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	httpState.writeReply(rec, route, messenger.Message{Code: mCodes.NotFound, Err: "realm not found"})
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected the reply code to map onto the status, got %d", rec.Code)
+	}
+	if res := decodeHTTPError(t, rec); res.Error != "realm not found" || res.Code != mCodes.NotFound {
+		t.Fatalf("unexpected error response: %+v", res)
+	}
+
+	rec = httptest.NewRecorder()
+	gzipRoute := HTTPRoute{Subject: subjects.Auctions, Encoding: GzipBase64ReplyEncoding}
+	httpState.writeReply(rec, gzipRoute, messenger.Message{Code: mCodes.Ok, Data: "not-base64!"})
+	if rec.Code != http.StatusInternalServerError {
+		t.Fatalf("expected undecodable data to fail, got %d", rec.Code)
+	}
+}
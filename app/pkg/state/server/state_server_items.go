@@ -0,0 +1,95 @@
+package server
+
+import (
+	"github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/storage"
+	"github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/transport"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/database"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/logging"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/messenger"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/metric"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/sotah/gameversions"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/state"
+	prodState "github.com/sotah-inc/steamwheedle-cartel/pkg/state/prod"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/state/subjects"
+	"github.com/twinj/uuid"
+)
+
+type ItemsStateConfig struct {
+	GCloudProjectID string
+
+	MessengerHost string
+	MessengerPort int
+
+	ItemsDatabaseDir string
+
+	BusTransport   transport.Kind
+	StorageBackend storage.BackendConfig
+}
+
+func NewItemsState(config ItemsStateConfig) (ItemsState, error) {
+	// establishing an initial state
+	itemsState := ItemsState{
+		ItemsState: prodState.ItemsState{
+			State: state.NewState(uuid.NewV4(), config.StorageBackend.Kind == storage.GCloud),
+		},
+	}
+
+	// connecting to the messenger host
+	mess, err := messenger.NewMessenger(config.MessengerHost, config.MessengerPort)
+	if err != nil {
+		return ItemsState{}, err
+	}
+	itemsState.IO.Messenger = mess
+
+	// establishing a bus
+	logging.WithField("transport", config.BusTransport).Info("Connecting bus-client")
+	busTransport, err := transport.NewTransport(transport.Config{
+		Kind:            config.BusTransport,
+		GCloudProjectID: config.GCloudProjectID,
+		SubscriberID:    "prod-items",
+		Messenger:       mess,
+	})
+	if err != nil {
+		return ItemsState{}, err
+	}
+	itemsState.BusClient = transport.NewClient(busTransport)
+
+	// establishing a store
+	logging.WithField("kind", config.StorageBackend.Kind).Info("Connecting to storage backend")
+	itemsState.Store, err = storage.NewStoreFromConfig(config.StorageBackend, gameversions.Retail)
+	if err != nil {
+		return ItemsState{}, err
+	}
+
+	// initializing a reporter
+	itemsState.IO.Reporter = metric.NewReporter(mess)
+
+	// loading the items database, which fills up from synced items when starting out empty
+	logging.Info("Connecting to items database")
+	iBase, err := database.NewItemsDatabase(config.ItemsDatabaseDir)
+	if err != nil {
+		return ItemsState{}, err
+	}
+	itemsState.IO.Databases.ItemsDatabase = iBase
+
+	// establishing bus-listeners
+	itemsState.BusListeners = state.NewBusListeners(state.SubjectBusListeners{
+		subjects.FilterInItemsToSync: itemsState.ListenForFilterIn,
+		subjects.ReceiveSyncedItems:  itemsState.ListenForSyncedItems,
+	})
+
+	// establishing messenger-listeners
+	itemsState.Listeners = state.NewListeners(state.SubjectListeners{
+		subjects.Items:      itemsState.ListenForItems,
+		subjects.ItemsQuery: itemsState.ListenForItemsQuery,
+	})
+
+	return itemsState, nil
+}
+
+type ItemsState struct {
+	prodState.ItemsState
+
+	BusClient transport.Client
+	Store     storage.Store
+}
@@ -0,0 +1,114 @@
+package server
+
+import (
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/storage"
+	"github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/transport"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/bus"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/logging"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/metric"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/sotah"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/state/subjects"
+)
+
+// NewSyncedItems gathers the synced items from the store, naming each after its normalized name when one was provided
+func NewSyncedItems(stor storage.Store, idNameMap sotah.ItemIdNameMap) sotah.ItemsMap {
+	out := sotah.ItemsMap{}
+	for job := range stor.GetItems(idNameMap.ItemIds()) {
+		if job.Err != nil {
+			logging.WithFields(job.ToLogrusFields()).Error("Failed to fetch item")
+
+			continue
+		}
+
+		if !job.Exists {
+			logging.WithField("item", job.ID).Error("Synced item was not found")
+
+			continue
+		}
+
+		if normalizedName := idNameMap[job.ID]; len(normalizedName) > 0 {
+			job.Item.NormalizedName = normalizedName
+		}
+
+		out[job.ID] = job.Item
+	}
+
+	return out
+}
+
+func (itemsState ItemsState) receiveSyncedItems(idNameMap sotah.ItemIdNameMap) error {
+	iMap := NewSyncedItems(itemsState.Store, idNameMap)
+
+	return itemsState.IO.Databases.ItemsDatabase.PersistItems(iMap)
+}
+
+func (itemsState ItemsState) ListenForSyncedItems(
+	onReady chan interface{},
+	stop chan interface{},
+	onStopped chan interface{},
+) {
+	// spinning up a worker
+	in := make(chan sotah.ItemIdNameMap, 50)
+	go func() {
+		for idNameMap := range in {
+			// handling item-ids
+			logging.WithFields(logrus.Fields{
+				"item-ids": len(idNameMap),
+				"capacity": len(in),
+			}).Info("Received synced item-ids")
+
+			startTime := time.Now()
+			if err := itemsState.receiveSyncedItems(idNameMap); err != nil {
+				logging.WithField("error", err.Error()).Error("Failed to receive synced items")
+			}
+			logging.WithFields(logrus.Fields{
+				"item-ids": len(idNameMap),
+				"capacity": len(in),
+			}).Info("Done receiving synced item-ids")
+
+			// reporting metrics
+			m := metric.Metrics{"receive_synced_items": int(int64(time.Since(startTime)) / 1000 / 1000 / 1000)}
+			if err := itemsState.BusClient.PublishMetrics(m); err != nil {
+				logging.WithField("error", err.Error()).Error("Failed to publish metric")
+
+				continue
+			}
+		}
+	}()
+
+	// establishing subscriber config
+	config := transport.SubscribeConfig{
+		Stop: stop,
+		Callback: func(busMsg bus.Message) {
+			logging.WithField("subject", subjects.ReceiveSyncedItems).Info("Received message")
+
+			idNormalizedNameMap, err := sotah.NewItemIdNameMap(busMsg.Data)
+			if err != nil {
+				logging.WithField("error", err.Error()).Error("Failed to decode item-ids")
+
+				return
+			}
+
+			// acking the message
+			if err := itemsState.BusClient.ReplyTo(busMsg, bus.NewMessage()); err != nil {
+				logging.WithField("error", err.Error()).Error("Failed to reply to message")
+
+				return
+			}
+
+			in <- idNormalizedNameMap
+		},
+		OnReady:   onReady,
+		OnStopped: onStopped,
+	}
+
+	// starting up worker for the subscription
+	go func() {
+		if err := itemsState.BusClient.SubscribeToTopic(string(subjects.ReceiveSyncedItems), config); err != nil {
+			logging.WithField("error", err.Error()).Fatal("Failed to subscribe to topic")
+		}
+	}()
+}
@@ -0,0 +1,48 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/blizzard"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/database"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/sotah"
+)
+
+func TestItemsStateReceiveSyncedItems(t *testing.T) {
+	dirPath, cleanup := newTestDir(t)
+	defer cleanup()
+
+	stor := newTestStore(t)
+	for _, item := range []sotah.Item{
+		{Item: blizzard.Item{ID: 1, Name: "Copper Ore", NormalizedName: "copper ore"}},
+		{Item: blizzard.Item{ID: 2, Name: "Tin Ore", NormalizedName: "tin ore"}},
+	} {
+		if err := stor.WriteItem(item); err != nil {
+			t.Fatalf("could not write item: %s", err)
+		}
+	}
+
+	iBase, err := database.NewItemsDatabase(dirPath)
+	if err != nil {
+		t.Fatalf("could not open items database: %s", err)
+	}
+	itemsState := ItemsState{Store: stor}
+	itemsState.IO.Databases.ItemsDatabase = iBase
+
+	// items missing from the store are skipped
+	idNameMap := sotah.ItemIdNameMap{1: "copper-ore", 2: "", 3: "missing"}
+	if err := itemsState.receiveSyncedItems(idNameMap); err != nil {
+		t.Fatalf("could not receive synced items: %s", err)
+	}
+
+	iMap, err := iBase.FindItems([]blizzard.ItemID{1, 2, 3})
+	if err != nil {
+		t.Fatalf("could not find items: %s", err)
+	}
+	if len(iMap) != 2 {
+		t.Fatalf("expected the stored items to be persisted, got %+v", iMap)
+	}
+	if iMap[1].NormalizedName != "copper-ore" || iMap[2].NormalizedName != "tin ore" {
+		t.Fatalf("expected provided names to override stored names, got %+v", iMap)
+	}
+}
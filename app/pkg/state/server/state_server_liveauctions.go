@@ -0,0 +1,226 @@
+package server
+
+import (
+	"fmt"
+
+	"github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/alerts"
+	"github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/anomalies"
+	"github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/config"
+	"github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/connectedrealms"
+	"github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/marketshare"
+	"github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/owners"
+	"github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/sales"
+	"github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/storage"
+	"github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/variants"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/database"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/logging"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/messenger"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/metric"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/sotah"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/sotah/gameversions"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/state"
+	devState "github.com/sotah-inc/steamwheedle-cartel/pkg/state/dev"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/state/subjects"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/util"
+	"github.com/twinj/uuid"
+)
+
+type LiveAuctionsStateConfig struct {
+	devState.LiveAuctionsStateConfig
+
+	StorageBackend storage.BackendConfig
+	Retention      config.RetentionConfig
+}
+
+func NewLiveAuctionsState(config LiveAuctionsStateConfig) (LiveAuctionsState, error) {
+	// establishing an initial state
+	laState := LiveAuctionsState{
+		LiveAuctionsState: devState.LiveAuctionsState{State: state.NewState(uuid.NewV4(), false)},
+		Retention:         config.Retention,
+	}
+	laState.Statuses = sotah.Statuses{}
+
+	// connecting to the messenger host
+	logging.Info("Connecting messenger")
+	mess, err := messenger.NewMessenger(config.MessengerHost, config.MessengerPort)
+	if err != nil {
+		return LiveAuctionsState{}, err
+	}
+	laState.IO.Messenger = mess
+
+	// initializing a reporter
+	laState.IO.Reporter = metric.NewReporter(mess)
+
+	// gathering regions
+	logging.Info("Gathering regions")
+	regions, err := laState.NewRegions()
+	if err != nil {
+		return LiveAuctionsState{}, err
+	}
+	laState.Regions = regions
+
+	// gathering statuses
+	logging.Info("Gathering statuses")
+	for _, reg := range laState.Regions {
+		status, err := laState.IO.Messenger.NewStatus(reg)
+		if err != nil {
+			return LiveAuctionsState{}, err
+		}
+
+		laState.Statuses[reg.Name] = status
+	}
+
+	// establishing a store
+	logging.WithField("kind", config.StorageBackend.Kind).Info("Connecting to storage backend")
+	stor, err := storage.NewStoreFromConfig(config.StorageBackend, gameversions.Retail)
+	if err != nil {
+		return LiveAuctionsState{}, err
+	}
+	laState.Store = stor
+
+	// grouping connected realms so that every member slug resolves to its group's data
+	laState.RealmGroups = connectedrealms.NewGroups(laState.Statuses)
+
+	// loading the live-auctions databases of primary realms only, since members share their primary's auction house
+	logging.Info("Connecting to live-auctions databases")
+	ladBases, err := newLiveAuctionsDatabases(config.LiveAuctionsDatabaseDir, laState.Statuses, laState.RealmGroups)
+	if err != nil {
+		return LiveAuctionsState{}, err
+	}
+	laState.IO.Databases.LiveAuctionsDatabases = ladBases
+
+	// loading the sales databases alongside the live-auctions databases
+	logging.Info("Connecting to sales databases")
+	salesBases, err := sales.NewDatabases(config.LiveAuctionsDatabaseDir, laState.Statuses, laState.RealmGroups)
+	if err != nil {
+		return LiveAuctionsState{}, err
+	}
+	laState.SalesDatabases = salesBases
+
+	// loading the alerts database
+	logging.Info("Connecting to alerts database")
+	alertsBase, err := alerts.NewDatabase(config.LiveAuctionsDatabaseDir)
+	if err != nil {
+		return LiveAuctionsState{}, err
+	}
+	laState.AlertsDatabase = alertsBase
+
+	// loading the owners databases
+	logging.Info("Connecting to owners databases")
+	ownersBases, err := owners.NewDatabases(config.LiveAuctionsDatabaseDir, laState.Statuses, laState.RealmGroups)
+	if err != nil {
+		return LiveAuctionsState{}, err
+	}
+	laState.OwnersDatabases = ownersBases
+
+	// loading the market-share databases
+	logging.Info("Connecting to market-share databases")
+	marketShareBases, err := marketshare.NewDatabases(
+		config.LiveAuctionsDatabaseDir,
+		laState.Statuses,
+		laState.RealmGroups,
+	)
+	if err != nil {
+		return LiveAuctionsState{}, err
+	}
+	laState.MarketShareDatabases = marketShareBases
+
+	// loading the anomalies databases
+	logging.Info("Connecting to anomalies databases")
+	anomaliesBases, err := anomalies.NewDatabases(
+		config.LiveAuctionsDatabaseDir,
+		laState.Statuses,
+		laState.RealmGroups,
+	)
+	if err != nil {
+		return LiveAuctionsState{}, err
+	}
+	laState.AnomaliesDatabases = anomaliesBases
+
+	// loading the variants databases
+	logging.Info("Connecting to variants databases")
+	variantsBases, err := variants.NewDatabases(
+		config.LiveAuctionsDatabaseDir,
+		laState.Statuses,
+		laState.RealmGroups,
+	)
+	if err != nil {
+		return LiveAuctionsState{}, err
+	}
+	laState.VariantsDatabases = variantsBases
+
+	// establishing listeners
+	laState.Listeners = state.NewListeners(laState.SubjectListeners())
+
+	return laState, nil
+}
+
+type LiveAuctionsState struct {
+	devState.LiveAuctionsState
+
+	Store                storage.Store
+	SalesDatabases       sales.Databases
+	AlertsDatabase       alerts.Database
+	OwnersDatabases      owners.Databases
+	MarketShareDatabases marketshare.Databases
+	AnomaliesDatabases   anomalies.Databases
+	VariantsDatabases    variants.Databases
+	RealmGroups          connectedrealms.Groups
+	Retention            config.RetentionConfig
+}
+
+// newLiveAuctionsDatabases opens a live-auctions database per primary realm, pointing every member slug at it
+func newLiveAuctionsDatabases(
+	dirPath string,
+	statuses sotah.Statuses,
+	groups connectedrealms.Groups,
+) (database.LiveAuctionsDatabases, error) {
+	primaryStatuses := groups.PrimaryStatuses(statuses)
+
+	// ensuring database paths exist
+	databasePaths := []string{}
+	for regionName := range primaryStatuses {
+		databasePaths = append(databasePaths, fmt.Sprintf("%s/live-auctions/%s", dirPath, regionName))
+	}
+	if err := util.EnsureDirsExist(databasePaths); err != nil {
+		return database.LiveAuctionsDatabases{}, err
+	}
+
+	ladBases, err := database.NewLiveAuctionsDatabases(dirPath, primaryStatuses)
+	if err != nil {
+		return database.LiveAuctionsDatabases{}, err
+	}
+
+	for regionName, status := range statuses {
+		regionLadBases := ladBases[regionName]
+		for _, rea := range status.Realms {
+			if primaryLadBase, ok := regionLadBases[groups.Primary(regionName, rea.Slug)]; ok {
+				regionLadBases[rea.Slug] = primaryLadBase
+			}
+		}
+	}
+
+	return ladBases, nil
+}
+
+func (laState LiveAuctionsState) SubjectListeners() state.SubjectListeners {
+	return state.SubjectListeners{
+		subjects.Auctions:           laState.ListenForAuctions,
+		subjects.AuctionsQuery:      laState.ListenForAuctionsQuery,
+		subjects.LiveAuctionsIntake: laState.ListenForLiveAuctionsIntake,
+		subjects.PriceList:          laState.ListenForPriceList,
+		subjects.Owners:             laState.ListenForOwners,
+		subjects.OwnersQuery:        laState.ListenForOwnersQuery,
+		subjects.OwnersQueryByItems: laState.ListenForOwnersQueryByItems,
+		SalesEstimate:               laState.ListenForSalesEstimate,
+		Alerts:                      laState.ListenForAlerts,
+		CreateAlert:                 laState.ListenForCreateAlert,
+		DeleteAlert:                 laState.ListenForDeleteAlert,
+		RegionPriceList:             laState.ListenForRegionPriceList,
+		OwnerHistory:                laState.ListenForOwnerHistory,
+		MarketShare:                 laState.ListenForMarketShare,
+		VendorFlips:                 laState.ListenForVendorFlips,
+		QueryAnomalies:              laState.ListenForQueryAnomalies,
+		Export:                      laState.ListenForExport,
+	}
+}
@@ -0,0 +1,252 @@
+package server
+
+import (
+	"encoding/json"
+	"time"
+
+	nats "github.com/nats-io/go-nats"
+	"github.com/sirupsen/logrus"
+	"github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/alerts"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/blizzard"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/logging"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/messenger"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/messenger/codes"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/sotah"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/state"
+)
+
+// evaluateAlerts checks a realm's freshly loaded mini-auction-list against its alerts
+func (laState LiveAuctionsState) evaluateAlerts(rea sotah.Realm, targetTime time.Time) {
+	entry := logging.WithFields(logrus.Fields{
+		"region": rea.Region.Name,
+		"realm":  rea.Slug,
+	})
+
+	// gathering alerts registered against any realm in the group
+	realmAlerts := alerts.Alerts{}
+	for _, memberSlug := range laState.RealmGroups.Members(rea.Region.Name, rea.Slug) {
+		memberAlerts, err := laState.AlertsDatabase.GetRealmAlerts(rea.Region.Name, memberSlug)
+		if err != nil {
+			entry.WithField("error", err.Error()).Error("Failed to get realm alerts")
+
+			return
+		}
+
+		realmAlerts = append(realmAlerts, memberAlerts...)
+	}
+
+	if len(realmAlerts) == 0 {
+		return
+	}
+
+	ladBase, ok := laState.IO.Databases.LiveAuctionsDatabases[rea.Region.Name][rea.Slug]
+	if !ok {
+		return
+	}
+
+	maList, err := ladBase.GetMiniAuctionList()
+	if err != nil {
+		entry.WithField("error", err.Error()).Error("Failed to get mini-auction-list")
+
+		return
+	}
+
+	for _, a := range realmAlerts {
+		match, ok := a.Evaluate(maList, targetTime)
+		if !ok {
+			continue
+		}
+
+		entry.WithField("alert", a.ID).Info("Alert matched")
+
+		encodedMatch, err := match.EncodeForDelivery()
+		if err != nil {
+			entry.WithField("error", err.Error()).Error("Failed to encode alert match")
+
+			continue
+		}
+
+		if err := laState.IO.Messenger.Publish(string(AlertMatches), encodedMatch); err != nil {
+			entry.WithField("error", err.Error()).Error("Failed to publish alert match")
+		}
+
+		// posting to webhooks off the intake path
+		go func(m alerts.Match) {
+			if err := alerts.PostWebhook(m); err != nil {
+				entry.WithFields(logrus.Fields{
+					"error": err.Error(),
+					"alert": m.Alert.ID,
+				}).Error("Failed to post alert match to webhook")
+			}
+		}(match)
+	}
+}
+
+type alertsRequest struct {
+	RegionName blizzard.RegionName `json:"region_name"`
+	RealmSlug  blizzard.RealmSlug  `json:"realm_slug"`
+}
+
+func (laState LiveAuctionsState) ListenForAlerts(stop state.ListenStopChan) error {
+	err := laState.IO.Messenger.Subscribe(string(Alerts), stop, func(natsMsg nats.Msg) {
+		m := messenger.NewMessage()
+
+		// resolving the request
+		aRequest := alertsRequest{}
+		if len(natsMsg.Data) > 0 {
+			if err := json.Unmarshal(natsMsg.Data, &aRequest); err != nil {
+				m.Err = err.Error()
+				m.Code = codes.MsgJSONParseError
+				laState.IO.Messenger.ReplyTo(natsMsg, m)
+
+				return
+			}
+		}
+
+		// gathering alerts, optionally filtered to a realm
+		var as alerts.Alerts
+		var err error
+		if len(aRequest.RegionName) > 0 && len(aRequest.RealmSlug) > 0 {
+			as, err = laState.AlertsDatabase.GetRealmAlerts(aRequest.RegionName, aRequest.RealmSlug)
+		} else {
+			as, err = laState.AlertsDatabase.GetAlerts()
+		}
+		if err != nil {
+			m.Err = err.Error()
+			m.Code = codes.GenericError
+			laState.IO.Messenger.ReplyTo(natsMsg, m)
+
+			return
+		}
+
+		data, err := as.EncodeForDelivery()
+		if err != nil {
+			m.Err = err.Error()
+			m.Code = codes.GenericError
+			laState.IO.Messenger.ReplyTo(natsMsg, m)
+
+			return
+		}
+
+		m.Data = data
+		laState.IO.Messenger.ReplyTo(natsMsg, m)
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (laState LiveAuctionsState) ListenForCreateAlert(stop state.ListenStopChan) error {
+	err := laState.IO.Messenger.Subscribe(string(CreateAlert), stop, func(natsMsg nats.Msg) {
+		m := messenger.NewMessage()
+
+		// resolving the request
+		a, err := alerts.NewAlert(natsMsg.Data)
+		if err != nil {
+			m.Err = err.Error()
+			m.Code = codes.MsgJSONParseError
+			laState.IO.Messenger.ReplyTo(natsMsg, m)
+
+			return
+		}
+
+		// ids are always assigned here
+		a.ID = ""
+		if err := a.Validate(); err != nil {
+			m.Err = err.Error()
+			m.Code = codes.UserError
+			laState.IO.Messenger.ReplyTo(natsMsg, m)
+
+			return
+		}
+
+		status, ok := laState.Statuses[a.RegionName]
+		if !ok {
+			m.Err = "Invalid region"
+			m.Code = codes.NotFound
+			laState.IO.Messenger.ReplyTo(natsMsg, m)
+
+			return
+		}
+
+		if _, ok := status.Realms.ToRealmMap()[a.RealmSlug]; !ok {
+			m.Err = "Invalid realm"
+			m.Code = codes.NotFound
+			laState.IO.Messenger.ReplyTo(natsMsg, m)
+
+			return
+		}
+
+		a, err = laState.AlertsDatabase.Persist(a)
+		if err != nil {
+			m.Err = err.Error()
+			m.Code = codes.GenericError
+			laState.IO.Messenger.ReplyTo(natsMsg, m)
+
+			return
+		}
+
+		encoded, err := a.EncodeForStorage()
+		if err != nil {
+			m.Err = err.Error()
+			m.Code = codes.GenericError
+			laState.IO.Messenger.ReplyTo(natsMsg, m)
+
+			return
+		}
+
+		m.Data = string(encoded)
+		laState.IO.Messenger.ReplyTo(natsMsg, m)
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+type deleteAlertRequest struct {
+	ID string `json:"id"`
+}
+
+func (laState LiveAuctionsState) ListenForDeleteAlert(stop state.ListenStopChan) error {
+	err := laState.IO.Messenger.Subscribe(string(DeleteAlert), stop, func(natsMsg nats.Msg) {
+		m := messenger.NewMessage()
+
+		// resolving the request
+		dRequest := deleteAlertRequest{}
+		if err := json.Unmarshal(natsMsg.Data, &dRequest); err != nil {
+			m.Err = err.Error()
+			m.Code = codes.MsgJSONParseError
+			laState.IO.Messenger.ReplyTo(natsMsg, m)
+
+			return
+		}
+
+		found, err := laState.AlertsDatabase.Delete(dRequest.ID)
+		if err != nil {
+			m.Err = err.Error()
+			m.Code = codes.GenericError
+			laState.IO.Messenger.ReplyTo(natsMsg, m)
+
+			return
+		}
+
+		if !found {
+			m.Err = "Alert not found"
+			m.Code = codes.NotFound
+			laState.IO.Messenger.ReplyTo(natsMsg, m)
+
+			return
+		}
+
+		laState.IO.Messenger.ReplyTo(natsMsg, m)
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
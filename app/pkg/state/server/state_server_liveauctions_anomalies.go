@@ -0,0 +1,195 @@
+package server
+
+import (
+	"encoding/json"
+	"time"
+
+	nats "github.com/nats-io/go-nats"
+	"github.com/sirupsen/logrus"
+	"github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/anomalies"
+	"github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/sales"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/blizzard"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/logging"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/messenger"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/messenger/codes"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/sotah"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/state"
+)
+
+const defaultAnomaliesDays = 1
+
+// recordAnomalies compares the incoming auctions against the previous snapshot and the item baselines
+func (laState LiveAuctionsState) recordAnomalies(
+	job state.GetAuctionsFromTimesOutJob,
+	previous sotah.MiniAuctionList,
+	tallies sales.ItemTallies,
+) anomalies.Events {
+	s := anomalies.Snapshot{
+		TargetTime: job.TargetTime,
+		Previous:   previous,
+		Current:    sotah.NewMiniAuctionListFromMiniAuctions(sotah.NewMiniAuctions(job.Auctions)),
+		Removed:    tallies,
+	}
+	events, err := laState.AnomaliesDatabases.Record(job.Realm, s, laState.Retention.RawLimitAt(job.TargetTime))
+	if err != nil {
+		logging.WithFields(logrus.Fields{
+			"error":  err.Error(),
+			"region": job.Realm.Region.Name,
+			"realm":  job.Realm.Slug,
+		}).Error("Failed to record anomalies")
+
+		return anomalies.Events{}
+	}
+
+	return events
+}
+
+func (laState LiveAuctionsState) publishAnomalies(job state.GetAuctionsFromTimesOutJob, events anomalies.Events) {
+	if len(events) == 0 {
+		return
+	}
+
+	entry := logging.WithFields(logrus.Fields{
+		"region": job.Realm.Region.Name,
+		"realm":  job.Realm.Slug,
+	})
+	entry.WithField("events", len(events)).Info("Detected anomalies")
+
+	encodedReport, err := anomalies.Report{
+		RegionName: job.Realm.Region.Name,
+		RealmSlug:  job.Realm.Slug,
+		TargetTime: job.TargetTime.Unix(),
+		Events:     events,
+	}.EncodeForDelivery()
+	if err != nil {
+		entry.WithField("error", err.Error()).Error("Failed to encode anomalies report")
+
+		return
+	}
+
+	if err := laState.IO.Messenger.Publish(string(Anomalies), encodedReport); err != nil {
+		entry.WithField("error", err.Error()).Error("Failed to publish anomalies report")
+	}
+}
+
+func newQueryAnomaliesRequest(payload []byte) (queryAnomaliesRequest, error) {
+	qaRequest := &queryAnomaliesRequest{}
+	if err := json.Unmarshal(payload, &qaRequest); err != nil {
+		return queryAnomaliesRequest{}, err
+	}
+
+	return *qaRequest, nil
+}
+
+type queryAnomaliesRequest struct {
+	RegionName  blizzard.RegionName `json:"region_name"`
+	RealmSlug   blizzard.RealmSlug  `json:"realm_slug"`
+	ItemIds     []blizzard.ItemID   `json:"item_ids"`
+	Kinds       []anomalies.Kind    `json:"kinds"`
+	MinSeverity float64             `json:"min_severity"`
+	Days        int                 `json:"days"`
+}
+
+func (qaRequest queryAnomaliesRequest) lowerBound(retentionLimit time.Time) time.Time {
+	days := qaRequest.Days
+	if days <= 0 {
+		days = defaultAnomaliesDays
+	}
+
+	lowerBound := time.Now().AddDate(0, 0, -days)
+	if lowerBound.Before(retentionLimit) {
+		return retentionLimit
+	}
+
+	return lowerBound
+}
+
+func (qaRequest queryAnomaliesRequest) resolve(laState LiveAuctionsState) (anomalies.Database, state.RequestError) {
+	regionAnomaliesBases, ok := laState.AnomaliesDatabases[qaRequest.RegionName]
+	if !ok {
+		return anomalies.Database{}, state.RequestError{Code: codes.NotFound, Message: "Invalid region"}
+	}
+
+	aBase, ok := regionAnomaliesBases[qaRequest.RealmSlug]
+	if !ok {
+		return anomalies.Database{}, state.RequestError{Code: codes.NotFound, Message: "Invalid realm"}
+	}
+
+	for _, k := range qaRequest.Kinds {
+		if err := k.Validate(); err != nil {
+			return anomalies.Database{}, state.RequestError{Code: codes.UserError, Message: err.Error()}
+		}
+	}
+
+	return aBase, state.RequestError{Code: codes.Ok, Message: ""}
+}
+
+type queryAnomaliesResponse struct {
+	Events anomalies.Events `json:"events"`
+}
+
+func (qaResponse queryAnomaliesResponse) EncodeForDelivery() (string, error) {
+	jsonEncoded, err := json.Marshal(qaResponse)
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonEncoded), nil
+}
+
+func (laState LiveAuctionsState) ListenForQueryAnomalies(stop state.ListenStopChan) error {
+	err := laState.IO.Messenger.Subscribe(string(QueryAnomalies), stop, func(natsMsg nats.Msg) {
+		m := messenger.NewMessage()
+
+		// resolving the request
+		qaRequest, err := newQueryAnomaliesRequest(natsMsg.Data)
+		if err != nil {
+			m.Err = err.Error()
+			m.Code = codes.MsgJSONParseError
+			laState.IO.Messenger.ReplyTo(natsMsg, m)
+
+			return
+		}
+
+		// resolving the anomalies database
+		aBase, reErr := qaRequest.resolve(laState)
+		if reErr.Code != codes.Ok {
+			m.Err = reErr.Message
+			m.Code = reErr.Code
+			laState.IO.Messenger.ReplyTo(natsMsg, m)
+
+			return
+		}
+
+		events, err := aBase.GetEvents(anomalies.Query{
+			LowerBound:  qaRequest.lowerBound(laState.Retention.RawLimit()),
+			ItemIds:     qaRequest.ItemIds,
+			Kinds:       qaRequest.Kinds,
+			MinSeverity: qaRequest.MinSeverity,
+		})
+		if err != nil {
+			m.Err = err.Error()
+			m.Code = codes.GenericError
+			laState.IO.Messenger.ReplyTo(natsMsg, m)
+
+			return
+		}
+
+		data, err := queryAnomaliesResponse{events}.EncodeForDelivery()
+		if err != nil {
+			m.Err = err.Error()
+			m.Code = codes.GenericError
+			laState.IO.Messenger.ReplyTo(natsMsg, m)
+
+			return
+		}
+
+		m.Data = data
+		laState.IO.Messenger.ReplyTo(natsMsg, m)
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,143 @@
+package server
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+
+	nats "github.com/nats-io/go-nats"
+	"github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/auctions"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/blizzard"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/messenger"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/messenger/codes"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/sotah"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/state"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/state/subjects"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/util"
+)
+
+// findItems requests item details from the api, which owns the items database
+func (laState LiveAuctionsState) findItems(itemIds []blizzard.ItemID) (sotah.ItemsMap, error) {
+	encodedMessage, err := json.Marshal(state.ItemsRequest{ItemIds: itemIds})
+	if err != nil {
+		return sotah.ItemsMap{}, err
+	}
+
+	msg, err := laState.IO.Messenger.Request(string(subjects.Items), encodedMessage)
+	if err != nil {
+		return sotah.ItemsMap{}, err
+	}
+
+	if msg.Code != codes.Ok {
+		return sotah.ItemsMap{}, errors.New(msg.Err)
+	}
+
+	base64Decoded, err := base64.StdEncoding.DecodeString(msg.Data)
+	if err != nil {
+		return sotah.ItemsMap{}, err
+	}
+
+	gzipDecoded, err := util.GzipDecode(base64Decoded)
+	if err != nil {
+		return sotah.ItemsMap{}, err
+	}
+
+	iResponse := state.ItemsResponse{}
+	if err := json.Unmarshal(gzipDecoded, &iResponse); err != nil {
+		return sotah.ItemsMap{}, err
+	}
+
+	return iResponse.Items, nil
+}
+
+func resolveAuctionsQueryRequest(
+	laState LiveAuctionsState,
+	req auctions.QueryRequest,
+) (sotah.MiniAuctionList, sotah.ItemsMap, state.RequestError) {
+	regionLadBases, ok := laState.IO.Databases.LiveAuctionsDatabases[req.RegionName]
+	if !ok {
+		return sotah.MiniAuctionList{}, sotah.ItemsMap{}, state.RequestError{Code: codes.NotFound, Message: "Invalid region"}
+	}
+
+	realmLadbase, ok := regionLadBases[req.RealmSlug]
+	if !ok {
+		return sotah.MiniAuctionList{}, sotah.ItemsMap{}, state.RequestError{Code: codes.NotFound, Message: "Invalid Realm"}
+	}
+
+	if err := req.Validate(); err != nil {
+		return sotah.MiniAuctionList{}, sotah.ItemsMap{}, state.RequestError{Code: codes.UserError, Message: err.Error()}
+	}
+
+	maList, err := realmLadbase.GetMiniAuctionList()
+	if err != nil {
+		return sotah.MiniAuctionList{}, sotah.ItemsMap{}, state.RequestError{Code: codes.GenericError, Message: err.Error()}
+	}
+
+	// joining item details only when the filter asks for them, and only for auctions passing every other filter
+	iMap := sotah.ItemsMap{}
+	if req.NeedsItems() {
+		if itemIds := req.Candidates(maList).ItemIds(); len(itemIds) > 0 {
+			iMap, err = laState.findItems(itemIds)
+			if err != nil {
+				return sotah.MiniAuctionList{}, sotah.ItemsMap{}, state.RequestError{
+					Code:    codes.GenericError,
+					Message: err.Error(),
+				}
+			}
+		}
+	}
+
+	return maList, iMap, state.RequestError{Code: codes.Ok, Message: ""}
+}
+
+func (laState LiveAuctionsState) ListenForAuctionsQuery(stop state.ListenStopChan) error {
+	err := laState.IO.Messenger.Subscribe(string(subjects.AuctionsQuery), stop, func(natsMsg nats.Msg) {
+		m := messenger.NewMessage()
+
+		// resolving the request
+		req, err := auctions.NewQueryRequest(natsMsg.Data)
+		if err != nil {
+			m.Err = err.Error()
+			m.Code = codes.MsgJSONParseError
+			laState.IO.Messenger.ReplyTo(natsMsg, m)
+
+			return
+		}
+
+		maList, iMap, reErr := resolveAuctionsQueryRequest(laState, req)
+		if reErr.Code != codes.Ok {
+			m.Err = reErr.Message
+			m.Code = reErr.Code
+			laState.IO.Messenger.ReplyTo(natsMsg, m)
+
+			return
+		}
+
+		// running the query
+		resp, err := req.Query(maList, iMap)
+		if err != nil {
+			m.Err = err.Error()
+			m.Code = codes.UserError
+			laState.IO.Messenger.ReplyTo(natsMsg, m)
+
+			return
+		}
+
+		data, err := resp.EncodeForDelivery()
+		if err != nil {
+			m.Err = err.Error()
+			m.Code = codes.GenericError
+			laState.IO.Messenger.ReplyTo(natsMsg, m)
+
+			return
+		}
+
+		m.Data = data
+		laState.IO.Messenger.ReplyTo(natsMsg, m)
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,69 @@
+package server
+
+import (
+	"fmt"
+
+	"github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/export"
+	"github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/flips"
+	"github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/pricelists"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/blizzard"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/sotah"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/state"
+)
+
+func (laState LiveAuctionsState) exportRealm(
+	req export.Request,
+	w export.Writer,
+	realmSlug blizzard.RealmSlug,
+	maList sotah.MiniAuctionList,
+) error {
+	switch req.Dataset {
+	case export.Auctions:
+		return export.WriteAuctions(w, req.RegionName, realmSlug, maList, req.ItemFilter())
+	case export.Prices:
+		return export.WritePrices(w, req.RegionName, realmSlug, pricelists.NewItemPrices(maList), req.ItemFilter())
+	case export.VendorFlips:
+		iMap, err := laState.findItems(maList.ItemIds())
+		if err != nil {
+			return err
+		}
+
+		return export.WriteVendorFlips(w, req.RegionName, realmSlug, flips.NewFlips(maList, iMap), req.ItemFilter())
+	default:
+		return fmt.Errorf("unsupported dataset: %s", req.Dataset)
+	}
+}
+
+func (laState LiveAuctionsState) writeExport(req export.Request, w export.Writer) error {
+	regionLadBases, ok := laState.IO.Databases.LiveAuctionsDatabases[req.RegionName]
+	if !ok {
+		return fmt.Errorf("invalid region: %s", req.RegionName)
+	}
+
+	for _, realmSlug := range req.RealmSlugs {
+		ladBase, ok := regionLadBases[realmSlug]
+		if !ok {
+			return fmt.Errorf("invalid realm: %s", realmSlug)
+		}
+
+		maList, err := ladBase.GetMiniAuctionList()
+		if err != nil {
+			return err
+		}
+
+		if err := laState.exportRealm(req, w, realmSlug, maList); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (laState LiveAuctionsState) ListenForExport(stop state.ListenStopChan) error {
+	return listenForExport(
+		laState.IO.Messenger,
+		stop,
+		[]export.Dataset{export.Auctions, export.Prices, export.VendorFlips},
+		laState.writeExport,
+	)
+}
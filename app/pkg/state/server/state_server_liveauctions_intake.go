@@ -0,0 +1,288 @@
+package server
+
+import (
+	"time"
+
+	nats "github.com/nats-io/go-nats"
+	"github.com/sirupsen/logrus"
+	"github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/sales"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/database"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/logging"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/metric"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/metric/kinds"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/sotah"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/state"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/state/subjects"
+)
+
+func (laState LiveAuctionsState) handleLiveAuctionsIntake(iRequest IntakeRequest) {
+	// misc
+	startTime := time.Now()
+
+	// declaring a load-in channel for the live-auctions db and starting it up
+	loadInJobs := make(chan database.LoadInJob)
+	loadOutJobs := laState.IO.Databases.LiveAuctionsDatabases.Load(loadInJobs)
+
+	// resolving included and excluded auctions
+	included, excluded := iRequest.resolve(laState.RealmGroups.PrimaryStatuses(laState.Statuses))
+
+	// counting realms for reporting
+	includedRealmCount := func() int {
+		out := 0
+		for _, realmTimes := range included {
+			out += len(realmTimes)
+		}
+
+		return out
+	}()
+	excludedRealmCount := func() int {
+		out := 0
+		for _, realmsMap := range excluded {
+			out += len(realmsMap)
+		}
+
+		return out
+	}()
+
+	// gathering stats for further data gathering
+	totalPreviousAuctions := 0
+	totalAuctions := 0
+	totalOwners := 0
+	itemIdsMap := sotah.ItemIdsMap{}
+	for _, realmsMap := range excluded {
+		for getStatsJob := range laState.IO.Databases.LiveAuctionsDatabases.GetStats(realmsMap.ToRealms()) {
+			if getStatsJob.Err != nil {
+				logrus.WithFields(getStatsJob.ToLogrusFields()).Error("Failed to get live-auction stats")
+
+				continue
+			}
+
+			totalPreviousAuctions += getStatsJob.Stats.TotalAuctions
+			totalAuctions += getStatsJob.Stats.TotalAuctions
+			totalOwners += len(getStatsJob.Stats.OwnerNames)
+			for _, itemId := range getStatsJob.Stats.ItemIds {
+				itemIdsMap[itemId] = struct{}{}
+			}
+		}
+	}
+
+	// spinning up a goroutine for gathering auctions
+	go func() {
+		for getAuctionsFromTimesJob := range GetAuctionsFromTimes(laState.Store, included) {
+			if getAuctionsFromTimesJob.Err != nil {
+				logrus.WithFields(getAuctionsFromTimesJob.ToLogrusFields()).Error("Failed to fetch auctions")
+
+				continue
+			}
+
+			totalAuctions += len(getAuctionsFromTimesJob.Auctions.Auctions)
+			totalOwners += len(getAuctionsFromTimesJob.Auctions.OwnerNames())
+			for _, auc := range getAuctionsFromTimesJob.Auctions.Auctions {
+				itemIdsMap[auc.Item] = struct{}{}
+			}
+
+			// classifying auctions removed since the previous snapshot before it is overwritten
+			previous, tallies, ok := laState.recordSales(getAuctionsFromTimesJob)
+			laState.recordOwners(getAuctionsFromTimesJob)
+			laState.recordVariants(getAuctionsFromTimesJob)
+			if ok {
+				events := laState.recordAnomalies(getAuctionsFromTimesJob, previous, tallies)
+				laState.publishAnomalies(getAuctionsFromTimesJob, events)
+			}
+
+			loadInJobs <- database.LoadInJob{
+				Realm:      getAuctionsFromTimesJob.Realm,
+				TargetTime: getAuctionsFromTimesJob.TargetTime,
+				Auctions:   getAuctionsFromTimesJob.Auctions,
+			}
+		}
+
+		// closing the load-in channel
+		close(loadInJobs)
+	}()
+
+	// gathering load-out-jobs as they drain
+	totalNewAuctions := 0
+	totalRemovedAuctions := 0
+	for loadOutJob := range loadOutJobs {
+		if loadOutJob.Err != nil {
+			logrus.WithFields(loadOutJob.ToLogrusFields()).Error("Failed to load auctions")
+
+			continue
+		}
+
+		totalNewAuctions += loadOutJob.TotalNewAuctions
+		totalRemovedAuctions += loadOutJob.TotalRemovedAuctions
+
+		// reacting to the freshly loaded realm
+		laState.evaluateAlerts(loadOutJob.Realm, loadOutJob.LastModified)
+		laState.recordMarketShare(loadOutJob.Realm, loadOutJob.LastModified)
+	}
+
+	// publishing for pricelist-histories-intake
+	err := func() error {
+		encodedRequest, err := iRequest.EncodeForDelivery()
+		if err != nil {
+			return err
+		}
+
+		return laState.IO.Messenger.Publish(string(subjects.PricelistHistoriesIntake), encodedRequest)
+	}()
+	if err != nil {
+		logging.WithField("error", err.Error()).Error("Failed to publish pricelist-histories-intake request")
+
+		return
+	}
+
+	duration := time.Since(startTime)
+	laState.IO.Reporter.Report(metric.Metrics{
+		"liveauctions_intake_duration": int(duration) / 1000 / 1000 / 1000,
+		"included_realms":              includedRealmCount,
+		"excluded_realms":              excludedRealmCount,
+		"total_realms":                 includedRealmCount + excludedRealmCount,
+		"total_auctions":               totalAuctions,
+		"total_previous_auctions":      totalPreviousAuctions,
+		"total_owners":                 totalOwners,
+		"total_items":                  len(itemIdsMap),
+		"total_new_auctions":           totalNewAuctions,
+		"total_removed_auctions":       totalRemovedAuctions,
+	})
+}
+
+// recordSales returns the previous mini-auction-list and its classified removals for further comparison
+func (laState LiveAuctionsState) recordSales(
+	job state.GetAuctionsFromTimesOutJob,
+) (sotah.MiniAuctionList, sales.ItemTallies, bool) {
+	entry := logging.WithFields(logrus.Fields{
+		"region": job.Realm.Region.Name,
+		"realm":  job.Realm.Slug,
+	})
+
+	ladBase, ok := laState.IO.Databases.LiveAuctionsDatabases[job.Realm.Region.Name][job.Realm.Slug]
+	if !ok {
+		return sotah.MiniAuctionList{}, sales.ItemTallies{}, false
+	}
+
+	previous, err := ladBase.GetMiniAuctionList()
+	if err != nil {
+		entry.WithField("error", err.Error()).Error("Failed to get previous mini-auction-list")
+
+		return sotah.MiniAuctionList{}, sales.ItemTallies{}, false
+	}
+
+	tallies, err := laState.SalesDatabases.Record(
+		job.Realm,
+		job.TargetTime,
+		previous,
+		job.Auctions,
+		laState.Retention.RawLimitAt(job.TargetTime),
+	)
+	if err != nil {
+		entry.WithField("error", err.Error()).Error("Failed to record sales")
+
+		return sotah.MiniAuctionList{}, sales.ItemTallies{}, false
+	}
+
+	entry.WithField("items", len(tallies)).Debug("Recorded sales")
+
+	return previous, tallies, true
+}
+
+func (laState LiveAuctionsState) recordOwners(job state.GetAuctionsFromTimesOutJob) {
+	entry := logging.WithFields(logrus.Fields{
+		"region": job.Realm.Region.Name,
+		"realm":  job.Realm.Slug,
+	})
+
+	recorded, err := laState.OwnersDatabases.Record(
+		job.Realm,
+		job.TargetTime,
+		job.Auctions,
+		laState.Retention.RawLimitAt(job.TargetTime),
+	)
+	if err != nil {
+		entry.WithField("error", err.Error()).Error("Failed to record owners")
+
+		return
+	}
+
+	entry.WithField("owners", recorded).Debug("Recorded owners")
+}
+
+// recordVariants keeps the current prices of each item variant, which the mini-auction-list does not distinguish
+func (laState LiveAuctionsState) recordVariants(job state.GetAuctionsFromTimesOutJob) {
+	entry := logging.WithFields(logrus.Fields{
+		"region": job.Realm.Region.Name,
+		"realm":  job.Realm.Slug,
+	})
+
+	if err := laState.VariantsDatabases.Record(job.Realm, job.TargetTime, job.Auctions); err != nil {
+		entry.WithField("error", err.Error()).Error("Failed to record variants")
+
+		return
+	}
+
+	entry.Debug("Recorded variants")
+}
+
+func (laState LiveAuctionsState) recordMarketShare(rea sotah.Realm, targetTime time.Time) {
+	entry := logging.WithFields(logrus.Fields{
+		"region": rea.Region.Name,
+		"realm":  rea.Slug,
+	})
+
+	ladBase, ok := laState.IO.Databases.LiveAuctionsDatabases[rea.Region.Name][rea.Slug]
+	if !ok {
+		return
+	}
+
+	maList, err := ladBase.GetMiniAuctionList()
+	if err != nil {
+		entry.WithField("error", err.Error()).Error("Failed to get mini-auction-list")
+
+		return
+	}
+
+	err = laState.MarketShareDatabases.Record(rea, targetTime, maList, laState.Retention.RawLimitAt(targetTime))
+	if err != nil {
+		entry.WithField("error", err.Error()).Error("Failed to record market-share")
+
+		return
+	}
+
+	entry.Debug("Recorded market-share")
+}
+
+func (laState LiveAuctionsState) ListenForLiveAuctionsIntake(stop state.ListenStopChan) error {
+	in := make(chan IntakeRequest, 30)
+
+	// starting up a listener for live-auctions-intake
+	err := laState.IO.Messenger.Subscribe(string(subjects.LiveAuctionsIntake), stop, func(natsMsg nats.Msg) {
+		// resolving the request
+		iRequest, err := NewIntakeRequest(natsMsg.Data)
+		if err != nil {
+			logging.WithField("error", err.Error()).Error("Failed to parse live-auctions-intake-request")
+
+			return
+		}
+
+		laState.IO.Reporter.ReportWithPrefix(metric.Metrics{
+			"buffer_size": len(iRequest.RegionRealmTimestamps),
+		}, kinds.LiveAuctionsIntake)
+		logging.WithField("capacity", len(in)).Info("Received live-auctions-intake-request, pushing onto handle channel")
+
+		in <- iRequest
+	})
+	if err != nil {
+		return err
+	}
+
+	// starting up a worker to handle live-auctions-intake requests
+	go func() {
+		for iRequest := range in {
+			laState.handleLiveAuctionsIntake(iRequest)
+		}
+	}()
+
+	return nil
+}
@@ -0,0 +1,130 @@
+package server
+
+import (
+	"encoding/json"
+	"time"
+
+	nats "github.com/nats-io/go-nats"
+	"github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/marketshare"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/blizzard"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/messenger"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/messenger/codes"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/sotah"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/state"
+)
+
+const defaultMarketShareDays = 7
+
+func newMarketShareRequest(payload []byte) (marketShareRequest, error) {
+	mRequest := &marketShareRequest{}
+	if err := json.Unmarshal(payload, &mRequest); err != nil {
+		return marketShareRequest{}, err
+	}
+
+	return *mRequest, nil
+}
+
+type marketShareRequest struct {
+	RegionName blizzard.RegionName `json:"region_name"`
+	RealmSlug  blizzard.RealmSlug  `json:"realm_slug"`
+	ItemIds    []blizzard.ItemID   `json:"item_ids"`
+	Days       int                 `json:"days"`
+}
+
+func (mRequest marketShareRequest) lowerBound(retentionLimit time.Time) time.Time {
+	days := mRequest.Days
+	if days <= 0 {
+		days = defaultMarketShareDays
+	}
+
+	lowerBound := sotah.NormalizeTargetDate(time.Now()).AddDate(0, 0, -(days - 1))
+	if lowerBound.Before(retentionLimit) {
+		return sotah.NormalizeTargetDate(retentionLimit)
+	}
+
+	return lowerBound
+}
+
+func (mRequest marketShareRequest) resolve(laState LiveAuctionsState) (marketshare.Database, state.RequestError) {
+	regionMarketShareBases, ok := laState.MarketShareDatabases[mRequest.RegionName]
+	if !ok {
+		return marketshare.Database{}, state.RequestError{Code: codes.NotFound, Message: "Invalid region"}
+	}
+
+	mBase, ok := regionMarketShareBases[mRequest.RealmSlug]
+	if !ok {
+		return marketshare.Database{}, state.RequestError{Code: codes.NotFound, Message: "Invalid realm"}
+	}
+
+	if len(mRequest.ItemIds) == 0 {
+		return marketshare.Database{}, state.RequestError{Code: codes.UserError, Message: "Item ids cannot be blank"}
+	}
+
+	return mBase, state.RequestError{Code: codes.Ok, Message: ""}
+}
+
+type marketShareResponse struct {
+	Markets marketshare.ItemMarkets `json:"markets"`
+}
+
+func (mResponse marketShareResponse) EncodeForDelivery() (string, error) {
+	jsonEncoded, err := json.Marshal(mResponse)
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonEncoded), nil
+}
+
+func (laState LiveAuctionsState) ListenForMarketShare(stop state.ListenStopChan) error {
+	err := laState.IO.Messenger.Subscribe(string(MarketShare), stop, func(natsMsg nats.Msg) {
+		m := messenger.NewMessage()
+
+		// resolving the request
+		mRequest, err := newMarketShareRequest(natsMsg.Data)
+		if err != nil {
+			m.Err = err.Error()
+			m.Code = codes.MsgJSONParseError
+			laState.IO.Messenger.ReplyTo(natsMsg, m)
+
+			return
+		}
+
+		// resolving the market-share database
+		mBase, reErr := mRequest.resolve(laState)
+		if reErr.Code != codes.Ok {
+			m.Err = reErr.Message
+			m.Code = reErr.Code
+			laState.IO.Messenger.ReplyTo(natsMsg, m)
+
+			return
+		}
+
+		// gathering reports
+		markets, err := mBase.GetItemMarkets(mRequest.ItemIds, mRequest.lowerBound(laState.Retention.RawLimit()))
+		if err != nil {
+			m.Err = err.Error()
+			m.Code = codes.GenericError
+			laState.IO.Messenger.ReplyTo(natsMsg, m)
+
+			return
+		}
+
+		data, err := marketShareResponse{markets}.EncodeForDelivery()
+		if err != nil {
+			m.Err = err.Error()
+			m.Code = codes.GenericError
+			laState.IO.Messenger.ReplyTo(natsMsg, m)
+
+			return
+		}
+
+		m.Data = data
+		laState.IO.Messenger.ReplyTo(natsMsg, m)
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,164 @@
+package server
+
+import (
+	"encoding/json"
+	"time"
+
+	nats "github.com/nats-io/go-nats"
+	"github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/owners"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/blizzard"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/messenger"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/messenger/codes"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/sotah"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/state"
+)
+
+const defaultOwnerHistoryDays = 7
+
+func newOwnerHistoryRequest(payload []byte) (ownerHistoryRequest, error) {
+	oRequest := &ownerHistoryRequest{}
+	if err := json.Unmarshal(payload, &oRequest); err != nil {
+		return ownerHistoryRequest{}, err
+	}
+
+	return *oRequest, nil
+}
+
+type ownerHistoryRequest struct {
+	RegionName blizzard.RegionName `json:"region_name"`
+	RealmSlug  blizzard.RealmSlug  `json:"realm_slug"`
+	OwnerName  sotah.OwnerName     `json:"owner_name"`
+	OwnerRealm string              `json:"owner_realm"`
+	Days       int                 `json:"days"`
+}
+
+func (oRequest ownerHistoryRequest) lowerBound(retentionLimit time.Time) time.Time {
+	days := oRequest.Days
+	if days <= 0 {
+		days = defaultOwnerHistoryDays
+	}
+
+	lowerBound := time.Now().AddDate(0, 0, -days)
+	if lowerBound.Before(retentionLimit) {
+		return retentionLimit
+	}
+
+	return lowerBound
+}
+
+func (oRequest ownerHistoryRequest) ownerKey() owners.OwnerKey {
+	return owners.OwnerKey{Name: oRequest.OwnerName, Realm: oRequest.OwnerRealm}
+}
+
+func (oRequest ownerHistoryRequest) resolve(laState LiveAuctionsState) (owners.Database, state.RequestError) {
+	regionOwnersBases, ok := laState.OwnersDatabases[oRequest.RegionName]
+	if !ok {
+		return owners.Database{}, state.RequestError{Code: codes.NotFound, Message: "Invalid region"}
+	}
+
+	oBase, ok := regionOwnersBases[oRequest.RealmSlug]
+	if !ok {
+		return owners.Database{}, state.RequestError{Code: codes.NotFound, Message: "Invalid realm"}
+	}
+
+	if len(oRequest.OwnerName) == 0 {
+		return owners.Database{}, state.RequestError{Code: codes.UserError, Message: "Owner name cannot be blank"}
+	}
+
+	if len(oRequest.OwnerRealm) == 0 {
+		return owners.Database{}, state.RequestError{Code: codes.UserError, Message: "Owner realm cannot be blank"}
+	}
+
+	return oBase, state.RequestError{Code: codes.Ok, Message: ""}
+}
+
+type ownerHistoryResponse struct {
+	Timeline owners.Timeline `json:"timeline"`
+
+	// nil when the owner lists nothing at present
+	Portfolio *owners.Portfolio `json:"portfolio"`
+}
+
+func (oResponse ownerHistoryResponse) EncodeForDelivery() (string, error) {
+	jsonEncoded, err := json.Marshal(oResponse)
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonEncoded), nil
+}
+
+func (laState LiveAuctionsState) ListenForOwnerHistory(stop state.ListenStopChan) error {
+	err := laState.IO.Messenger.Subscribe(string(OwnerHistory), stop, func(natsMsg nats.Msg) {
+		m := messenger.NewMessage()
+
+		// resolving the request
+		oRequest, err := newOwnerHistoryRequest(natsMsg.Data)
+		if err != nil {
+			m.Err = err.Error()
+			m.Code = codes.MsgJSONParseError
+			laState.IO.Messenger.ReplyTo(natsMsg, m)
+
+			return
+		}
+
+		// resolving the owners database
+		oBase, reErr := oRequest.resolve(laState)
+		if reErr.Code != codes.Ok {
+			m.Err = reErr.Message
+			m.Code = reErr.Code
+			laState.IO.Messenger.ReplyTo(natsMsg, m)
+
+			return
+		}
+
+		// gathering the timeline and current portfolio
+		timeline, err := oBase.GetTimeline(oRequest.ownerKey(), oRequest.lowerBound(laState.Retention.RawLimit()))
+		if err != nil {
+			m.Err = err.Error()
+			m.Code = codes.GenericError
+			laState.IO.Messenger.ReplyTo(natsMsg, m)
+
+			return
+		}
+
+		portfolio, found, err := oBase.GetPortfolio(oRequest.ownerKey())
+		if err != nil {
+			m.Err = err.Error()
+			m.Code = codes.GenericError
+			laState.IO.Messenger.ReplyTo(natsMsg, m)
+
+			return
+		}
+
+		if len(timeline) == 0 && !found {
+			m.Err = "Owner not found"
+			m.Code = codes.NotFound
+			laState.IO.Messenger.ReplyTo(natsMsg, m)
+
+			return
+		}
+
+		oResponse := ownerHistoryResponse{Timeline: timeline}
+		if found {
+			oResponse.Portfolio = &portfolio
+		}
+
+		data, err := oResponse.EncodeForDelivery()
+		if err != nil {
+			m.Err = err.Error()
+			m.Code = codes.GenericError
+			laState.IO.Messenger.ReplyTo(natsMsg, m)
+
+			return
+		}
+
+		m.Data = data
+		laState.IO.Messenger.ReplyTo(natsMsg, m)
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,167 @@
+package server
+
+import (
+	"encoding/base64"
+	"encoding/json"
+
+	nats "github.com/nats-io/go-nats"
+	"github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/pricelists"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/blizzard"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/messenger"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/messenger/codes"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/sotah"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/state"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/state/subjects"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/util"
+)
+
+func newPriceListRequest(payload []byte) (priceListRequest, error) {
+	pList := &priceListRequest{}
+	if err := json.Unmarshal(payload, &pList); err != nil {
+		return priceListRequest{}, err
+	}
+
+	return *pList, nil
+}
+
+type priceListRequest struct {
+	RegionName blizzard.RegionName `json:"region_name"`
+	RealmSlug  blizzard.RealmSlug  `json:"realm_slug"`
+	ItemIds    []blizzard.ItemID   `json:"item_ids"`
+
+	// variants to price alongside the items, where a base variant falls back to its item's prices
+	VariantKeys []pricelists.VariantKey `json:"variant_keys"`
+}
+
+func (plRequest priceListRequest) resolve(laState LiveAuctionsState) (sotah.MiniAuctionList, state.RequestError) {
+	regionLadBases, ok := laState.IO.Databases.LiveAuctionsDatabases[plRequest.RegionName]
+	if !ok {
+		return sotah.MiniAuctionList{}, state.RequestError{Code: codes.NotFound, Message: "Invalid region"}
+	}
+
+	ladBase, ok := regionLadBases[plRequest.RealmSlug]
+	if !ok {
+		return sotah.MiniAuctionList{}, state.RequestError{Code: codes.NotFound, Message: "Invalid realm"}
+	}
+
+	maList, err := ladBase.GetMiniAuctionList()
+	if err != nil {
+		return sotah.MiniAuctionList{}, state.RequestError{Code: codes.GenericError, Message: err.Error()}
+	}
+
+	return maList, state.RequestError{Code: codes.Ok, Message: ""}
+}
+
+func (plRequest priceListRequest) variantItemIds() []blizzard.ItemID {
+	seen := map[blizzard.ItemID]struct{}{}
+	out := []blizzard.ItemID{}
+	for _, key := range plRequest.VariantKeys {
+		ID := key.ItemID()
+		if _, ok := seen[ID]; ok {
+			continue
+		}
+		seen[ID] = struct{}{}
+
+		out = append(out, ID)
+	}
+
+	return out
+}
+
+type priceListResponse struct {
+	PriceList        pricelists.ItemPrices    `json:"price_list"`
+	VariantPriceList pricelists.VariantPrices `json:"variant_price_list,omitempty"`
+}
+
+func (plResponse priceListResponse) EncodeForDelivery() (string, error) {
+	jsonEncoded, err := json.Marshal(plResponse)
+	if err != nil {
+		return "", err
+	}
+
+	gzipEncoded, err := util.GzipEncode(jsonEncoded)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.StdEncoding.EncodeToString(gzipEncoded), nil
+}
+
+func (laState LiveAuctionsState) ListenForPriceList(stop state.ListenStopChan) error {
+	err := laState.IO.Messenger.Subscribe(string(subjects.PriceList), stop, func(natsMsg nats.Msg) {
+		m := messenger.NewMessage()
+
+		// resolving the request
+		plRequest, err := newPriceListRequest(natsMsg.Data)
+		if err != nil {
+			m.Err = err.Error()
+			m.Code = codes.MsgJSONParseError
+			laState.IO.Messenger.ReplyTo(natsMsg, m)
+
+			return
+		}
+
+		// resolving data from state
+		realmAuctions, reErr := plRequest.resolve(laState)
+		if reErr.Code != codes.Ok {
+			m.Err = reErr.Message
+			m.Code = reErr.Code
+			laState.IO.Messenger.ReplyTo(natsMsg, m)
+
+			return
+		}
+
+		// deriving a pricelist-response from the provided realm auctions
+		iPrices := pricelists.NewItemPrices(realmAuctions)
+		responseItemPrices := pricelists.ItemPrices{}
+		for _, itemId := range plRequest.ItemIds {
+			if iPrice, ok := iPrices[itemId]; ok {
+				responseItemPrices[itemId] = iPrice
+			}
+		}
+
+		plResponse := priceListResponse{PriceList: responseItemPrices}
+
+		// resolving requested variants against the current variant prices
+		if len(plRequest.VariantKeys) > 0 {
+			for _, key := range plRequest.VariantKeys {
+				if err := key.Validate(); err != nil {
+					m.Err = err.Error()
+					m.Code = codes.UserError
+					laState.IO.Messenger.ReplyTo(natsMsg, m)
+
+					return
+				}
+			}
+
+			vBase := laState.VariantsDatabases[plRequest.RegionName][plRequest.RealmSlug]
+			vPrices, err := vBase.GetVariantPrices(plRequest.variantItemIds())
+			if err != nil {
+				m.Err = err.Error()
+				m.Code = codes.GenericError
+				laState.IO.Messenger.ReplyTo(natsMsg, m)
+
+				return
+			}
+
+			plResponse.VariantPriceList = vPrices.Resolve(plRequest.VariantKeys, iPrices)
+		}
+
+		data, err := plResponse.EncodeForDelivery()
+		if err != nil {
+			m.Err = err.Error()
+			m.Code = codes.GenericError
+			laState.IO.Messenger.ReplyTo(natsMsg, m)
+
+			return
+		}
+
+		m.Data = data
+		laState.IO.Messenger.ReplyTo(natsMsg, m)
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,143 @@
+package server
+
+import (
+	"encoding/base64"
+	"encoding/json"
+
+	nats "github.com/nats-io/go-nats"
+	"github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/pricelists"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/blizzard"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/messenger"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/messenger/codes"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/sotah"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/state"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/util"
+)
+
+func newRegionPriceListRequest(payload []byte) (regionPriceListRequest, error) {
+	rRequest := &regionPriceListRequest{}
+	if err := json.Unmarshal(payload, &rRequest); err != nil {
+		return regionPriceListRequest{}, err
+	}
+
+	return *rRequest, nil
+}
+
+type regionPriceListRequest struct {
+	RegionName blizzard.RegionName `json:"region_name"`
+	ItemIds    []blizzard.ItemID   `json:"item_ids"`
+
+	// optional, defaulting to every realm in the region
+	RealmSlugs []blizzard.RealmSlug `json:"realm_slugs"`
+}
+
+// resolve gathers the auctions of each connected-realm group, keyed by the group's primary realm
+func (rRequest regionPriceListRequest) resolve(
+	laState LiveAuctionsState,
+) (map[blizzard.RealmSlug]sotah.MiniAuctionList, map[blizzard.RealmSlug][]blizzard.RealmSlug, state.RequestError) {
+	regionLadBases, ok := laState.IO.Databases.LiveAuctionsDatabases[rRequest.RegionName]
+	if !ok {
+		return nil, nil, state.RequestError{Code: codes.NotFound, Message: "Invalid region"}
+	}
+
+	if len(rRequest.ItemIds) == 0 {
+		return nil, nil, state.RequestError{Code: codes.UserError, Message: "Item ids cannot be blank"}
+	}
+
+	realmSlugs := rRequest.RealmSlugs
+	if len(realmSlugs) == 0 {
+		for realmSlug := range regionLadBases {
+			realmSlugs = append(realmSlugs, realmSlug)
+		}
+	}
+
+	out := map[blizzard.RealmSlug]sotah.MiniAuctionList{}
+	groups := map[blizzard.RealmSlug][]blizzard.RealmSlug{}
+	for _, realmSlug := range realmSlugs {
+		ladBase, ok := regionLadBases[realmSlug]
+		if !ok {
+			return nil, nil, state.RequestError{Code: codes.NotFound, Message: "Invalid realm: " + string(realmSlug)}
+		}
+
+		// connected realms share one auction house, so counting each group once
+		primarySlug := laState.RealmGroups.Primary(rRequest.RegionName, realmSlug)
+		if _, ok := out[primarySlug]; ok {
+			continue
+		}
+
+		maList, err := ladBase.GetMiniAuctionList()
+		if err != nil {
+			return nil, nil, state.RequestError{Code: codes.GenericError, Message: err.Error()}
+		}
+
+		out[primarySlug] = maList
+		groups[primarySlug] = laState.RealmGroups.Members(rRequest.RegionName, primarySlug)
+	}
+
+	return out, groups, state.RequestError{Code: codes.Ok, Message: ""}
+}
+
+type regionPriceListResponse struct {
+	pricelists.RegionItemPrices
+
+	// the realms sharing each primary realm's auction house
+	Groups map[blizzard.RealmSlug][]blizzard.RealmSlug `json:"groups"`
+}
+
+func (rResponse regionPriceListResponse) EncodeForDelivery() (string, error) {
+	jsonEncoded, err := json.Marshal(rResponse)
+	if err != nil {
+		return "", err
+	}
+
+	gzipEncoded, err := util.GzipEncode(jsonEncoded)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.StdEncoding.EncodeToString(gzipEncoded), nil
+}
+
+func (laState LiveAuctionsState) ListenForRegionPriceList(stop state.ListenStopChan) error {
+	err := laState.IO.Messenger.Subscribe(string(RegionPriceList), stop, func(natsMsg nats.Msg) {
+		m := messenger.NewMessage()
+
+		// resolving the request
+		rRequest, err := newRegionPriceListRequest(natsMsg.Data)
+		if err != nil {
+			m.Err = err.Error()
+			m.Code = codes.MsgJSONParseError
+			laState.IO.Messenger.ReplyTo(natsMsg, m)
+
+			return
+		}
+
+		// gathering each realm's auctions
+		realmLists, groups, reErr := rRequest.resolve(laState)
+		if reErr.Code != codes.Ok {
+			m.Err = reErr.Message
+			m.Code = reErr.Code
+			laState.IO.Messenger.ReplyTo(natsMsg, m)
+
+			return
+		}
+
+		rResponse := regionPriceListResponse{pricelists.NewRegionItemPrices(realmLists, rRequest.ItemIds), groups}
+		data, err := rResponse.EncodeForDelivery()
+		if err != nil {
+			m.Err = err.Error()
+			m.Code = codes.GenericError
+			laState.IO.Messenger.ReplyTo(natsMsg, m)
+
+			return
+		}
+
+		m.Data = data
+		laState.IO.Messenger.ReplyTo(natsMsg, m)
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
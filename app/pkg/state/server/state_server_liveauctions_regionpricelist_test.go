@@ -0,0 +1,138 @@
+package server
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/connectedrealms"
+	"github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/pricelists"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/blizzard"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/database"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/messenger/codes"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/sotah"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/util"
+)
+
+// newTestLiveAuctionsState loads one auction of item 1 per realm, with b sharing a's auction house
+func newTestLiveAuctionsState(t *testing.T, dirPath string) LiveAuctionsState {
+	realms := sotah.Realms{newTestRealm("us", "a"), newTestRealm("us", "b"), newTestRealm("us", "c")}
+	statuses := sotah.Statuses{"us": sotah.Status{Region: sotah.Region{Name: "us"}, Realms: realms}}
+
+	groups := connectedrealms.Groups{"us": {"a": "a", "b": "a"}}
+	ladBases, err := newLiveAuctionsDatabases(dirPath, statuses, groups)
+	if err != nil {
+		t.Fatalf("could not open live-auctions databases: %s", err)
+	}
+
+	in := make(chan database.LoadInJob)
+	out := ladBases.Load(in)
+	go func() {
+		for i, rea := range groups.PrimaryRealms(realms) {
+			aucs := blizzard.Auctions{Auctions: []blizzard.Auction{
+				{Auc: int64(i), Item: 1, Owner: "a", Buyout: int64(10 + 20*i), Quantity: 1},
+			}}
+			in <- database.LoadInJob{Realm: rea, TargetTime: time.Unix(100, 0), Auctions: aucs}
+		}
+		close(in)
+	}()
+	for job := range out {
+		if job.Err != nil {
+			t.Fatalf("could not load auctions: %s", job.Err)
+		}
+	}
+
+	laState := LiveAuctionsState{RealmGroups: groups}
+	laState.IO.Databases.LiveAuctionsDatabases = ladBases
+
+	return laState
+}
+
+func TestRegionPriceListRequestResolve(t *testing.T) {
+	dirPath, cleanup := newTestDir(t)
+	defer cleanup()
+
+	laState := newTestLiveAuctionsState(t, dirPath)
+
+	// every realm of the region, counting each connected-realm group once
+	rRequest := regionPriceListRequest{RegionName: "us", ItemIds: []blizzard.ItemID{1}}
+	realmLists, groups, reErr := rRequest.resolve(laState)
+	if reErr.Code != codes.Ok {
+		t.Fatalf("could not resolve request: %s", reErr.Message)
+	}
+	if len(realmLists) != 2 || realmLists["a"][0].Buyout != 10 || realmLists["c"][0].Buyout != 30 {
+		t.Fatalf("expected the primary realms only, got %+v", realmLists)
+	}
+	expectedGroups := map[blizzard.RealmSlug][]blizzard.RealmSlug{"a": {"a", "b"}, "c": {"c"}}
+	if !reflect.DeepEqual(groups, expectedGroups) {
+		t.Fatalf("unexpected groups: %v", groups)
+	}
+
+	// a supplied grouped realm is keyed by its primary
+	rRequest.RealmSlugs = []blizzard.RealmSlug{"b"}
+	if realmLists, _, reErr := rRequest.resolve(laState); reErr.Code != codes.Ok || len(realmLists["a"]) != 1 {
+		t.Fatalf("expected b to resolve onto a, got %+v %+v", realmLists, reErr)
+	}
+
+	for _, invalid := range []struct {
+		request regionPriceListRequest
+		code    codes.Code
+	}{
+		{regionPriceListRequest{RegionName: "eu", ItemIds: []blizzard.ItemID{1}}, codes.NotFound},
+		{regionPriceListRequest{RegionName: "us"}, codes.UserError},
+		{
+			regionPriceListRequest{RegionName: "us", ItemIds: []blizzard.ItemID{1}, RealmSlugs: []blizzard.RealmSlug{"d"}},
+			codes.NotFound,
+		},
+	} {
+		if _, _, reErr := invalid.request.resolve(laState); reErr.Code != invalid.code {
+			t.Fatalf("expected %+v to fail with %d, got %+v", invalid.request, invalid.code, reErr)
+		}
+	}
+}
+
+func TestRegionPriceListResponseEncodeForDelivery(t *testing.T) {
+	rRequest, err := newRegionPriceListRequest([]byte(`{"region_name":"us","item_ids":[1]}`))
+	if err != nil || rRequest.RegionName != "us" || len(rRequest.RealmSlugs) != 0 {
+		t.Fatalf("unexpected request: %+v %v", rRequest, err)
+	}
+	if _, err := newRegionPriceListRequest([]byte("{")); err == nil {
+		t.Fatal("expected a malformed request to fail")
+	}
+
+	dirPath, cleanup := newTestDir(t)
+	defer cleanup()
+
+	realmLists, groups, reErr := rRequest.resolve(newTestLiveAuctionsState(t, dirPath))
+	if reErr.Code != codes.Ok {
+		t.Fatalf("could not resolve request: %s", reErr.Message)
+	}
+
+	rResponse := regionPriceListResponse{pricelists.NewRegionItemPrices(realmLists, rRequest.ItemIds), groups}
+	encoded, err := rResponse.EncodeForDelivery()
+	if err != nil {
+		t.Fatalf("could not encode response: %s", err)
+	}
+
+	base64Decoded, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		t.Fatalf("could not decode base64: %s", err)
+	}
+	gzipDecoded, err := util.GzipDecode(base64Decoded)
+	if err != nil {
+		t.Fatalf("could not decode gzip: %s", err)
+	}
+
+	decoded := regionPriceListResponse{}
+	if err := json.Unmarshal(gzipDecoded, &decoded); err != nil {
+		t.Fatalf("could not decode json: %s", err)
+	}
+	if spread := decoded.Spreads[1]; spread.LowestRealm != "a" || spread.HighestRealm != "c" {
+		t.Fatalf("unexpected spread: %+v", spread)
+	}
+	if !reflect.DeepEqual(decoded.Groups, groups) {
+		t.Fatalf("unexpected groups: %v", decoded.Groups)
+	}
+}
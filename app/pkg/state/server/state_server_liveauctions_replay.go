@@ -0,0 +1,52 @@
+package server
+
+import (
+	"github.com/sirupsen/logrus"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/database"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/state"
+)
+
+// ReplayAuctions records and loads archived auctions the way intake does, where the jobs must hold each realm at most
+// once; alerts are not evaluated and anomalies are not published, since the auctions are not current, and retention
+// is measured from each job's target time so that old dumps are not pruned as soon as they are recorded
+func (laState LiveAuctionsState) ReplayAuctions(in chan state.GetAuctionsFromTimesOutJob) int {
+	// declaring a load-in channel for the live-auctions db and starting it up
+	loadInJobs := make(chan database.LoadInJob)
+	loadOutJobs := laState.IO.Databases.LiveAuctionsDatabases.Load(loadInJobs)
+
+	go func() {
+		for job := range in {
+			// classifying auctions removed since the previous snapshot before it is overwritten
+			previous, tallies, ok := laState.recordSales(job)
+			laState.recordOwners(job)
+			laState.recordVariants(job)
+			if ok {
+				laState.recordAnomalies(job, previous, tallies)
+			}
+
+			loadInJobs <- database.LoadInJob{
+				Realm:      job.Realm,
+				TargetTime: job.TargetTime,
+				Auctions:   job.Auctions,
+			}
+		}
+
+		// closing the load-in channel
+		close(loadInJobs)
+	}()
+
+	// gathering load-out-jobs as they drain
+	loaded := 0
+	for loadOutJob := range loadOutJobs {
+		if loadOutJob.Err != nil {
+			logrus.WithFields(loadOutJob.ToLogrusFields()).Error("Failed to load auctions")
+
+			continue
+		}
+
+		loaded++
+		laState.recordMarketShare(loadOutJob.Realm, loadOutJob.LastModified)
+	}
+
+	return loaded
+}
@@ -0,0 +1,130 @@
+package server
+
+import (
+	"encoding/json"
+	"time"
+
+	nats "github.com/nats-io/go-nats"
+	"github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/sales"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/blizzard"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/messenger"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/messenger/codes"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/sotah"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/state"
+)
+
+const defaultSalesEstimateDays = 7
+
+func newSalesEstimateRequest(payload []byte) (salesEstimateRequest, error) {
+	sRequest := &salesEstimateRequest{}
+	if err := json.Unmarshal(payload, &sRequest); err != nil {
+		return salesEstimateRequest{}, err
+	}
+
+	return *sRequest, nil
+}
+
+type salesEstimateRequest struct {
+	RegionName blizzard.RegionName `json:"region_name"`
+	RealmSlug  blizzard.RealmSlug  `json:"realm_slug"`
+	ItemIds    []blizzard.ItemID   `json:"item_ids"`
+	Days       int                 `json:"days"`
+}
+
+func (sRequest salesEstimateRequest) lowerBound(retentionLimit time.Time) time.Time {
+	days := sRequest.Days
+	if days <= 0 {
+		days = defaultSalesEstimateDays
+	}
+
+	lowerBound := sotah.NormalizeTargetDate(time.Now()).AddDate(0, 0, -(days - 1))
+	if lowerBound.Before(retentionLimit) {
+		return sotah.NormalizeTargetDate(retentionLimit)
+	}
+
+	return lowerBound
+}
+
+func (sRequest salesEstimateRequest) resolve(laState LiveAuctionsState) (sales.Database, state.RequestError) {
+	regionSalesBases, ok := laState.SalesDatabases[sRequest.RegionName]
+	if !ok {
+		return sales.Database{}, state.RequestError{Code: codes.NotFound, Message: "Invalid region"}
+	}
+
+	sBase, ok := regionSalesBases[sRequest.RealmSlug]
+	if !ok {
+		return sales.Database{}, state.RequestError{Code: codes.NotFound, Message: "Invalid realm"}
+	}
+
+	if len(sRequest.ItemIds) == 0 {
+		return sales.Database{}, state.RequestError{Code: codes.UserError, Message: "Item ids cannot be blank"}
+	}
+
+	return sBase, state.RequestError{Code: codes.Ok, Message: ""}
+}
+
+type salesEstimateResponse struct {
+	Estimates sales.ItemEstimates `json:"estimates"`
+}
+
+func (sResponse salesEstimateResponse) EncodeForDelivery() (string, error) {
+	jsonEncoded, err := json.Marshal(sResponse)
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonEncoded), nil
+}
+
+func (laState LiveAuctionsState) ListenForSalesEstimate(stop state.ListenStopChan) error {
+	err := laState.IO.Messenger.Subscribe(string(SalesEstimate), stop, func(natsMsg nats.Msg) {
+		m := messenger.NewMessage()
+
+		// resolving the request
+		sRequest, err := newSalesEstimateRequest(natsMsg.Data)
+		if err != nil {
+			m.Err = err.Error()
+			m.Code = codes.MsgJSONParseError
+			laState.IO.Messenger.ReplyTo(natsMsg, m)
+
+			return
+		}
+
+		// resolving the sales database
+		sBase, reErr := sRequest.resolve(laState)
+		if reErr.Code != codes.Ok {
+			m.Err = reErr.Message
+			m.Code = reErr.Code
+			laState.IO.Messenger.ReplyTo(natsMsg, m)
+
+			return
+		}
+
+		// gathering estimates
+		estimates, err := sBase.GetEstimates(sRequest.ItemIds, sRequest.lowerBound(laState.Retention.RawLimit()))
+		if err != nil {
+			m.Err = err.Error()
+			m.Code = codes.GenericError
+			laState.IO.Messenger.ReplyTo(natsMsg, m)
+
+			return
+		}
+
+		data, err := salesEstimateResponse{estimates}.EncodeForDelivery()
+		if err != nil {
+			m.Err = err.Error()
+			m.Code = codes.GenericError
+			laState.IO.Messenger.ReplyTo(natsMsg, m)
+
+			return
+		}
+
+		m.Data = data
+		laState.IO.Messenger.ReplyTo(natsMsg, m)
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
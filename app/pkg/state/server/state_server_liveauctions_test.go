@@ -0,0 +1,35 @@
+package server
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/connectedrealms"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/sotah"
+)
+
+func TestNewLiveAuctionsDatabases(t *testing.T) {
+	dirPath, cleanup := newTestDir(t)
+	defer cleanup()
+
+	realms := sotah.Realms{newTestRealm("us", "a"), newTestRealm("us", "b"), newTestRealm("us", "c")}
+	statuses := sotah.Statuses{"us": sotah.Status{Region: sotah.Region{Name: "us"}, Realms: realms}}
+	groups := connectedrealms.Groups{"us": {"a": "a", "b": "a"}}
+
+	ladBases, err := newLiveAuctionsDatabases(dirPath, statuses, groups)
+	if err != nil {
+		t.Fatalf("could not open live-auctions databases: %s", err)
+	}
+	if len(ladBases["us"]) != 3 {
+		t.Fatalf("expected every slug to resolve, got %v", ladBases["us"])
+	}
+
+	// members resolve onto their primary's database rather than opening their own file
+	for slug, expectFile := range map[string]bool{"a": true, "b": false, "c": true} {
+		_, err := os.Stat(fmt.Sprintf("%s/live-auctions/us/%s.db", dirPath, slug))
+		if exists := err == nil; exists != expectFile {
+			t.Fatalf("expected the file of %s to exist: %v, got %v", slug, expectFile, err)
+		}
+	}
+}
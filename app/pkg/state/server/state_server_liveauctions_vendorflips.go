@@ -0,0 +1,132 @@
+package server
+
+import (
+	"encoding/json"
+
+	nats "github.com/nats-io/go-nats"
+	"github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/flips"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/blizzard"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/messenger"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/messenger/codes"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/sotah"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/state"
+)
+
+const defaultVendorFlipsCount = 50
+
+func newVendorFlipsRequest(payload []byte) (vendorFlipsRequest, error) {
+	vfRequest := &vendorFlipsRequest{}
+	if err := json.Unmarshal(payload, &vfRequest); err != nil {
+		return vendorFlipsRequest{}, err
+	}
+
+	return *vfRequest, nil
+}
+
+type vendorFlipsRequest struct {
+	RegionName blizzard.RegionName `json:"region_name"`
+	RealmSlug  blizzard.RealmSlug  `json:"realm_slug"`
+	Count      int                 `json:"count"`
+}
+
+func (vfRequest vendorFlipsRequest) resolve(laState LiveAuctionsState) (sotah.MiniAuctionList, state.RequestError) {
+	regionLadBases, ok := laState.IO.Databases.LiveAuctionsDatabases[vfRequest.RegionName]
+	if !ok {
+		return sotah.MiniAuctionList{}, state.RequestError{Code: codes.NotFound, Message: "Invalid region"}
+	}
+
+	ladBase, ok := regionLadBases[vfRequest.RealmSlug]
+	if !ok {
+		return sotah.MiniAuctionList{}, state.RequestError{Code: codes.NotFound, Message: "Invalid realm"}
+	}
+
+	maList, err := ladBase.GetMiniAuctionList()
+	if err != nil {
+		return sotah.MiniAuctionList{}, state.RequestError{Code: codes.GenericError, Message: err.Error()}
+	}
+
+	return maList, state.RequestError{Code: codes.Ok, Message: ""}
+}
+
+func (vfRequest vendorFlipsRequest) count() int {
+	if vfRequest.Count <= 0 {
+		return defaultVendorFlipsCount
+	}
+
+	return vfRequest.Count
+}
+
+type vendorFlipsResponse struct {
+	Flips       flips.Flips `json:"flips"`
+	Total       int         `json:"total"`
+	TotalProfit int64       `json:"total_profit"`
+}
+
+func (vfResponse vendorFlipsResponse) EncodeForDelivery() (string, error) {
+	jsonEncoded, err := json.Marshal(vfResponse)
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonEncoded), nil
+}
+
+func (laState LiveAuctionsState) ListenForVendorFlips(stop state.ListenStopChan) error {
+	err := laState.IO.Messenger.Subscribe(string(VendorFlips), stop, func(natsMsg nats.Msg) {
+		m := messenger.NewMessage()
+
+		// resolving the request
+		vfRequest, err := newVendorFlipsRequest(natsMsg.Data)
+		if err != nil {
+			m.Err = err.Error()
+			m.Code = codes.MsgJSONParseError
+			laState.IO.Messenger.ReplyTo(natsMsg, m)
+
+			return
+		}
+
+		// resolving the realm auctions
+		maList, reErr := vfRequest.resolve(laState)
+		if reErr.Code != codes.Ok {
+			m.Err = reErr.Message
+			m.Code = reErr.Code
+			laState.IO.Messenger.ReplyTo(natsMsg, m)
+
+			return
+		}
+
+		// joining vendor sell prices
+		iMap, err := laState.findItems(maList.ItemIds())
+		if err != nil {
+			m.Err = err.Error()
+			m.Code = codes.GenericError
+			laState.IO.Messenger.ReplyTo(natsMsg, m)
+
+			return
+		}
+
+		realmFlips := flips.NewFlips(maList, iMap)
+		vfResponse := vendorFlipsResponse{
+			Flips:       realmFlips.Limit(vfRequest.count()),
+			Total:       len(realmFlips),
+			TotalProfit: realmFlips.TotalProfit(),
+		}
+
+		data, err := vfResponse.EncodeForDelivery()
+		if err != nil {
+			m.Err = err.Error()
+			m.Code = codes.GenericError
+			laState.IO.Messenger.ReplyTo(natsMsg, m)
+
+			return
+		}
+
+		m.Data = data
+		laState.IO.Messenger.ReplyTo(natsMsg, m)
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,96 @@
+package server
+
+import (
+	"encoding/json"
+
+	"github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/transport"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/bus"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/logging"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/messenger"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/metric"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/state"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/state/subjects"
+	"github.com/twinj/uuid"
+)
+
+type MetricsStateConfig struct {
+	GCloudProjectID string
+
+	MessengerHost string
+	MessengerPort int
+
+	BusTransport transport.Kind
+}
+
+func NewMetricsState(config MetricsStateConfig) (MetricsState, error) {
+	// establishing an initial state
+	metricsState := MetricsState{
+		State: state.NewState(uuid.NewV4(), config.BusTransport == transport.Pubsub),
+	}
+
+	// connecting to the messenger host
+	mess, err := messenger.NewMessenger(config.MessengerHost, config.MessengerPort)
+	if err != nil {
+		return MetricsState{}, err
+	}
+	metricsState.IO.Messenger = mess
+
+	// establishing a bus
+	logging.WithField("transport", config.BusTransport).Info("Connecting bus-client")
+	busTransport, err := transport.NewTransport(transport.Config{
+		Kind:            config.BusTransport,
+		GCloudProjectID: config.GCloudProjectID,
+		SubscriberID:    "prod-metrics",
+		Messenger:       mess,
+	})
+	if err != nil {
+		return MetricsState{}, err
+	}
+	metricsState.BusClient = transport.NewClient(busTransport)
+
+	// initializing a reporter
+	metricsState.IO.Reporter = metric.NewReporter(mess)
+
+	// establishing bus-listeners
+	metricsState.BusListeners = state.NewBusListeners(state.SubjectBusListeners{
+		subjects.AppMetrics: metricsState.ListenForMetrics,
+	})
+
+	return metricsState, nil
+}
+
+type MetricsState struct {
+	state.State
+
+	BusClient transport.Client
+}
+
+func (metricsState MetricsState) ListenForMetrics(
+	onReady chan interface{},
+	stop chan interface{},
+	onStopped chan interface{},
+) {
+	// establishing subscriber config
+	config := transport.SubscribeConfig{
+		Stop: stop,
+		Callback: func(busMsg bus.Message) {
+			var m metric.Metrics
+			if err := json.Unmarshal([]byte(busMsg.Data), &m); err != nil {
+				logging.WithField("error", err.Error()).Error("Failed to marshal metrics")
+
+				return
+			}
+
+			metricsState.IO.Reporter.Report(m)
+		},
+		OnReady:   onReady,
+		OnStopped: onStopped,
+	}
+
+	// starting up worker for the subscription
+	go func() {
+		if err := metricsState.BusClient.SubscribeToTopic(string(subjects.AppMetrics), config); err != nil {
+			logging.WithField("error", err.Error()).Fatal("Failed to subscribe to topic")
+		}
+	}()
+}
@@ -0,0 +1,48 @@
+package server
+
+import (
+	"github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/config"
+	"github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/connectedrealms"
+	"github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/pricelists"
+	"github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/storage"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/logging"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/sotah/gameversions"
+	devState "github.com/sotah-inc/steamwheedle-cartel/pkg/state/dev"
+)
+
+type PricelistHistoriesStateConfig struct {
+	devState.PricelistHistoriesStateConfig
+
+	StorageBackend storage.BackendConfig
+	Retention      config.RetentionConfig
+}
+
+func NewPricelistHistoriesState(config PricelistHistoriesStateConfig) (PricelistHistoriesState, error) {
+	// establishing an initial state
+	devPhState, err := devState.NewPricelistHistoriesState(config.PricelistHistoriesStateConfig)
+	if err != nil {
+		return PricelistHistoriesState{}, err
+	}
+	phState := PricelistHistoriesState{PricelistHistoriesState: devPhState}
+
+	// establishing a store
+	logging.WithField("kind", config.StorageBackend.Kind).Info("Connecting to storage backend")
+	stor, err := storage.NewStoreFromConfig(config.StorageBackend, gameversions.Retail)
+	if err != nil {
+		return PricelistHistoriesState{}, err
+	}
+	phState.Store = stor
+
+	// grouping connected realms so that every member slug resolves to its group's data
+	phState.RealmGroups = connectedrealms.NewGroups(phState.Statuses)
+
+	return phState, nil
+}
+
+type PricelistHistoriesState struct {
+	devState.PricelistHistoriesState
+
+	Store                     storage.Store
+	PricelistHistoryDatabases pricelists.Databases
+	RealmGroups               connectedrealms.Groups
+}
@@ -0,0 +1,59 @@
+package server
+
+import (
+	"sort"
+
+	"github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/export"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/blizzard"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/state"
+)
+
+func (phState PricelistHistoriesState) writeExport(req export.Request, w export.Writer) error {
+	lowerBounds, upperBounds := req.Bounds()
+
+	for _, realmSlug := range req.RealmSlugs {
+		// blank items export every item with history
+		itemIds := req.ItemIds
+		if len(itemIds) == 0 {
+			realmItemIds, _, err := phState.PricelistHistoryDatabases.GetItemIds(req.RegionName, realmSlug)
+			if err != nil {
+				return err
+			}
+
+			itemIds = realmItemIds
+		}
+
+		// reading and writing one item at a time, so that a realm's histories are never held at once
+		sortedItemIds := append([]blizzard.ItemID{}, itemIds...)
+		sort.Slice(sortedItemIds, func(i, j int) bool {
+			return sortedItemIds[i] < sortedItemIds[j]
+		})
+		for _, itemId := range sortedItemIds {
+			ipHistories, _, err := phState.PricelistHistoryDatabases.GetItemPriceHistories(
+				req.RegionName,
+				realmSlug,
+				[]blizzard.ItemID{itemId},
+				lowerBounds,
+				upperBounds,
+			)
+			if err != nil {
+				return err
+			}
+
+			if err := export.WritePriceHistories(w, req.RegionName, realmSlug, ipHistories); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (phState PricelistHistoriesState) ListenForExport(stop state.ListenStopChan) error {
+	return listenForExport(
+		phState.IO.Messenger,
+		stop,
+		[]export.Dataset{export.PriceHistories},
+		phState.writeExport,
+	)
+}
@@ -0,0 +1,117 @@
+package server
+
+import (
+	"time"
+
+	nats "github.com/nats-io/go-nats"
+	"github.com/sirupsen/logrus"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/database"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/logging"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/metric"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/metric/kinds"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/state"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/state/subjects"
+)
+
+func (phState PricelistHistoriesState) handlePricelistHistoriesIntake(pRequest IntakeRequest) {
+	// misc
+	startTime := time.Now()
+
+	// declaring a load-in channel for the pricelist-histories dbs and starting it up
+	loadInJobs := make(chan database.LoadInJob)
+	loadOutJobs := phState.PricelistHistoryDatabases.Load(loadInJobs)
+
+	// resolving included and excluded auctions
+	included, excluded := pRequest.resolve(phState.RealmGroups.PrimaryStatuses(phState.Statuses))
+
+	// counting realms for reporting
+	includedRealmCount := func() int {
+		out := 0
+		for _, realmTimes := range included {
+			out += len(realmTimes)
+		}
+
+		return out
+	}()
+	excludedRealmCount := func() int {
+		out := 0
+		for _, realmsMap := range excluded {
+			out += len(realmsMap)
+		}
+
+		return out
+	}()
+
+	// spinning up a goroutine for gathering auctions
+	go func() {
+		for getAuctionsFromTimesJob := range GetAuctionsFromTimes(phState.Store, included) {
+			if getAuctionsFromTimesJob.Err != nil {
+				logrus.WithFields(getAuctionsFromTimesJob.ToLogrusFields()).Error("Failed to fetch auctions")
+
+				continue
+			}
+
+			loadInJobs <- database.LoadInJob{
+				Realm:      getAuctionsFromTimesJob.Realm,
+				TargetTime: getAuctionsFromTimesJob.TargetTime,
+				Auctions:   getAuctionsFromTimesJob.Auctions,
+			}
+		}
+
+		// closing the load-in channel
+		close(loadInJobs)
+	}()
+
+	// gathering load-out-jobs as they drain
+	for loadOutJob := range loadOutJobs {
+		if loadOutJob.Err != nil {
+			logrus.WithFields(loadOutJob.ToLogrusFields()).Error("Failed to load auctions")
+
+			continue
+		}
+	}
+
+	duration := time.Since(startTime)
+	phState.IO.Reporter.Report(metric.Metrics{
+		"pricelisthistories_intake_duration": int(duration) / 1000 / 1000 / 1000,
+		"included_realms":                    includedRealmCount,
+		"excluded_realms":                    excludedRealmCount,
+		"total_realms":                       includedRealmCount + excludedRealmCount,
+	})
+}
+
+func (phState PricelistHistoriesState) ListenForPricelistHistoriesIntake(stop state.ListenStopChan) error {
+	in := make(chan IntakeRequest, 30)
+
+	// starting up a listener for pricelist-histories-intake
+	err := phState.IO.Messenger.Subscribe(string(subjects.PricelistHistoriesIntake), stop, func(natsMsg nats.Msg) {
+		// resolving the request
+		pRequest, err := NewIntakeRequest(natsMsg.Data)
+		if err != nil {
+			logging.WithField("error", err.Error()).Error("Failed to parse pricelist-histories-intake-request")
+
+			return
+		}
+
+		phState.IO.Reporter.ReportWithPrefix(metric.Metrics{
+			"buffer_size": len(pRequest.RegionRealmTimestamps),
+		}, kinds.PricelistHistoriesIntake)
+		logging.WithField("capacity", len(in)).Info(
+			"Received pricelist-histories-intake-request, pushing onto handle channel",
+		)
+
+		in <- pRequest
+	})
+	if err != nil {
+		return err
+	}
+
+	// starting up a worker to handle pricelist-histories-intake requests
+	go func() {
+		for pRequest := range in {
+			phState.handlePricelistHistoriesIntake(pRequest)
+		}
+	}()
+
+	return nil
+}
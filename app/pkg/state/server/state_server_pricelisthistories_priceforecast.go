@@ -0,0 +1,157 @@
+package server
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"time"
+
+	nats "github.com/nats-io/go-nats"
+	"github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/forecasts"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/blizzard"
+	dCodes "github.com/sotah-inc/steamwheedle-cartel/pkg/database/codes"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/messenger"
+	mCodes "github.com/sotah-inc/steamwheedle-cartel/pkg/messenger/codes"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/state"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/util"
+)
+
+const (
+	defaultForecastDays        = 7
+	maximumForecastDays        = 14
+	defaultForecastHistoryDays = 30
+)
+
+func newPriceForecastRequest(payload []byte) (priceForecastRequest, error) {
+	pfRequest := &priceForecastRequest{}
+	if err := json.Unmarshal(payload, &pfRequest); err != nil {
+		return priceForecastRequest{}, err
+	}
+
+	return *pfRequest, nil
+}
+
+type priceForecastRequest struct {
+	RegionName blizzard.RegionName `json:"region_name"`
+	RealmSlug  blizzard.RealmSlug  `json:"realm_slug"`
+	ItemIds    []blizzard.ItemID   `json:"item_ids"`
+
+	// days to predict and days of history to fit to
+	Days        int `json:"days"`
+	HistoryDays int `json:"history_days"`
+}
+
+func (pfRequest priceForecastRequest) validate() state.RequestError {
+	if len(pfRequest.ItemIds) == 0 {
+		return state.RequestError{Code: mCodes.UserError, Message: "Item ids cannot be blank"}
+	}
+
+	if pfRequest.Days > maximumForecastDays {
+		return state.RequestError{Code: mCodes.UserError, Message: "Days cannot exceed 14"}
+	}
+
+	return state.RequestError{Code: mCodes.Ok, Message: ""}
+}
+
+func (pfRequest priceForecastRequest) days() int {
+	if pfRequest.Days <= 0 {
+		return defaultForecastDays
+	}
+
+	return pfRequest.Days
+}
+
+func (pfRequest priceForecastRequest) lowerBound(now time.Time) time.Time {
+	historyDays := pfRequest.HistoryDays
+	if historyDays <= 0 {
+		historyDays = defaultForecastHistoryDays
+	}
+
+	return now.AddDate(0, 0, -historyDays)
+}
+
+type priceForecastResponse struct {
+	Forecasts forecasts.ItemForecasts `json:"forecasts"`
+}
+
+func (pfResponse priceForecastResponse) EncodeForDelivery() (string, error) {
+	jsonEncoded, err := json.Marshal(pfResponse)
+	if err != nil {
+		return "", err
+	}
+
+	gzipEncoded, err := util.GzipEncode(jsonEncoded)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.StdEncoding.EncodeToString(gzipEncoded), nil
+}
+
+func (phState PricelistHistoriesState) ListenForPriceForecast(stop state.ListenStopChan) error {
+	err := phState.IO.Messenger.Subscribe(string(PriceForecast), stop, func(natsMsg nats.Msg) {
+		m := messenger.NewMessage()
+
+		// resolving the request
+		pfRequest, err := newPriceForecastRequest(natsMsg.Data)
+		if err != nil {
+			m.Err = err.Error()
+			m.Code = mCodes.MsgJSONParseError
+			phState.IO.Messenger.ReplyTo(natsMsg, m)
+
+			return
+		}
+
+		if reErr := pfRequest.validate(); reErr.Code != mCodes.Ok {
+			m.Err = reErr.Message
+			m.Code = reErr.Code
+			phState.IO.Messenger.ReplyTo(natsMsg, m)
+
+			return
+		}
+
+		// gathering the history to fit to
+		now := time.Now()
+		ipHistories, respCode, err := phState.PricelistHistoryDatabases.GetItemPriceHistories(
+			pfRequest.RegionName,
+			pfRequest.RealmSlug,
+			pfRequest.ItemIds,
+			pfRequest.lowerBound(now),
+			now,
+		)
+		if err != nil {
+			m.Err = err.Error()
+			m.Code = state.DatabaseCodeToMessengerCode(respCode)
+			phState.IO.Messenger.ReplyTo(natsMsg, m)
+
+			return
+		}
+		if respCode != dCodes.Ok {
+			m.Err = "response code was not ok but error was nil"
+			m.Code = state.DatabaseCodeToMessengerCode(respCode)
+			phState.IO.Messenger.ReplyTo(natsMsg, m)
+
+			return
+		}
+
+		pfResponse := priceForecastResponse{
+			Forecasts: forecasts.NewItemForecasts(ipHistories, now, pfRequest.days()),
+		}
+
+		data, err := pfResponse.EncodeForDelivery()
+		if err != nil {
+			m.Err = err.Error()
+			m.Code = mCodes.GenericError
+			phState.IO.Messenger.ReplyTo(natsMsg, m)
+
+			return
+		}
+
+		m.Data = data
+		phState.IO.Messenger.ReplyTo(natsMsg, m)
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,63 @@
+package server
+
+import (
+	nats "github.com/nats-io/go-nats"
+	"github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/pricelists"
+	dCodes "github.com/sotah-inc/steamwheedle-cartel/pkg/database/codes"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/messenger"
+	mCodes "github.com/sotah-inc/steamwheedle-cartel/pkg/messenger/codes"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/state"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/state/subjects"
+)
+
+func (phState PricelistHistoriesState) ListenForPriceListHistory(stop state.ListenStopChan) error {
+	err := phState.IO.Messenger.Subscribe(string(subjects.PriceListHistory), stop, func(natsMsg nats.Msg) {
+		m := messenger.NewMessage()
+
+		// resolving the request
+		request, err := pricelists.NewGetPricelistHistoryRequest(natsMsg.Data)
+		if err != nil {
+			m.Err = err.Error()
+			m.Code = mCodes.MsgJSONParseError
+			phState.IO.Messenger.ReplyTo(natsMsg, m)
+
+			return
+		}
+
+		// querying the pricelist-histories databases
+		resp, respCode, err := phState.PricelistHistoryDatabases.GetPricelistHistory(request)
+		if err != nil {
+			m.Err = err.Error()
+			m.Code = state.DatabaseCodeToMessengerCode(respCode)
+			phState.IO.Messenger.ReplyTo(natsMsg, m)
+
+			return
+		}
+		if respCode != dCodes.Ok {
+			m.Err = "response code was not ok but error was nil"
+			m.Code = state.DatabaseCodeToMessengerCode(respCode)
+			phState.IO.Messenger.ReplyTo(natsMsg, m)
+
+			return
+		}
+
+		// marshalling for messenger
+		encodedMessage, err := resp.EncodeForDelivery()
+		if err != nil {
+			m.Err = err.Error()
+			m.Code = mCodes.GenericError
+			phState.IO.Messenger.ReplyTo(natsMsg, m)
+
+			return
+		}
+
+		// dumping it out
+		m.Data = encodedMessage
+		phState.IO.Messenger.ReplyTo(natsMsg, m)
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,41 @@
+package server
+
+import (
+	"github.com/sirupsen/logrus"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/database"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/state"
+)
+
+// ReplayAuctions loads archived auctions into the pricelist-histories the way intake does
+func (phState PricelistHistoriesState) ReplayAuctions(in chan state.GetAuctionsFromTimesOutJob) int {
+	// declaring a load-in channel for the pricelist-histories dbs and starting it up
+	loadInJobs := make(chan database.LoadInJob)
+	loadOutJobs := phState.PricelistHistoryDatabases.Load(loadInJobs)
+
+	go func() {
+		for job := range in {
+			loadInJobs <- database.LoadInJob{
+				Realm:      job.Realm,
+				TargetTime: job.TargetTime,
+				Auctions:   job.Auctions,
+			}
+		}
+
+		// closing the load-in channel
+		close(loadInJobs)
+	}()
+
+	// gathering load-out-jobs as they drain
+	loaded := 0
+	for loadOutJob := range loadOutJobs {
+		if loadOutJob.Err != nil {
+			logrus.WithFields(loadOutJob.ToLogrusFields()).Error("Failed to load auctions")
+
+			continue
+		}
+
+		loaded++
+	}
+
+	return loaded
+}
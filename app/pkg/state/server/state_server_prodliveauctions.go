@@ -0,0 +1,137 @@
+package server
+
+import (
+	"fmt"
+
+	"github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/storage"
+	"github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/transport"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/database"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/hell"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/logging"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/messenger"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/metric"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/sotah"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/sotah/gameversions"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/state"
+	prodState "github.com/sotah-inc/steamwheedle-cartel/pkg/state/prod"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/state/subjects"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/util"
+	"github.com/twinj/uuid"
+)
+
+type ProdLiveAuctionsStateConfig struct {
+	GCloudProjectID string
+
+	MessengerHost string
+	MessengerPort int
+
+	LiveAuctionsDatabaseDir string
+
+	Regions        sotah.RegionList
+	BusTransport   transport.Kind
+	StorageBackend storage.BackendConfig
+}
+
+func NewProdLiveAuctionsState(config ProdLiveAuctionsStateConfig) (ProdLiveAuctionsState, error) {
+	// establishing an initial state
+	liveAuctionsState := ProdLiveAuctionsState{
+		ProdLiveAuctionsState: prodState.ProdLiveAuctionsState{
+			State:   state.NewState(uuid.NewV4(), config.StorageBackend.Kind == storage.GCloud),
+			Regions: config.Regions,
+		},
+	}
+
+	var err error
+
+	// connecting to hell, which only tracks realm timestamps for the gcloud pipeline
+	if config.StorageBackend.Kind == storage.GCloud {
+		liveAuctionsState.IO.HellClient, err = hell.NewClient(config.GCloudProjectID)
+		if err != nil {
+			return ProdLiveAuctionsState{}, err
+		}
+	}
+
+	// connecting to the messenger host
+	liveAuctionsState.IO.Messenger, err = messenger.NewMessenger(config.MessengerHost, config.MessengerPort)
+	if err != nil {
+		return ProdLiveAuctionsState{}, err
+	}
+
+	// initializing a reporter
+	liveAuctionsState.IO.Reporter = metric.NewReporter(liveAuctionsState.IO.Messenger)
+
+	// establishing a bus
+	logging.WithField("transport", config.BusTransport).Info("Connecting bus-client")
+	busTransport, err := transport.NewTransport(transport.Config{
+		Kind:            config.BusTransport,
+		GCloudProjectID: config.GCloudProjectID,
+		SubscriberID:    "prod-liveauctions",
+		Messenger:       liveAuctionsState.IO.Messenger,
+	})
+	if err != nil {
+		return ProdLiveAuctionsState{}, err
+	}
+	liveAuctionsState.BusClient = transport.NewClient(busTransport)
+	if err := liveAuctionsState.BusClient.ResolveTopic(string(subjects.ReceiveRealms)); err != nil {
+		return ProdLiveAuctionsState{}, err
+	}
+
+	// establishing a store
+	logging.WithField("kind", config.StorageBackend.Kind).Info("Connecting to storage backend")
+	liveAuctionsState.Store, err = storage.NewStoreFromConfig(config.StorageBackend, gameversions.Retail)
+	if err != nil {
+		return ProdLiveAuctionsState{}, err
+	}
+
+	// gathering region-realms
+	liveAuctionsState.Statuses, err = NewStatusesFromStore(liveAuctionsState.Store, config.Regions)
+	if err != nil {
+		return ProdLiveAuctionsState{}, err
+	}
+
+	// ensuring database paths exist
+	databasePaths := []string{}
+	for regionName, status := range liveAuctionsState.Statuses {
+		for _, realm := range status.Realms {
+			databasePaths = append(databasePaths, fmt.Sprintf(
+				"%s/live-auctions/%s/%s",
+				config.LiveAuctionsDatabaseDir,
+				regionName,
+				realm.Slug,
+			))
+		}
+	}
+	if err := util.EnsureDirsExist(databasePaths); err != nil {
+		return ProdLiveAuctionsState{}, err
+	}
+
+	// loading the live-auctions databases
+	logging.Info("Connecting to live-auctions databases")
+	ladBases, err := database.NewLiveAuctionsDatabases(config.LiveAuctionsDatabaseDir, liveAuctionsState.Statuses)
+	if err != nil {
+		return ProdLiveAuctionsState{}, err
+	}
+	liveAuctionsState.IO.Databases.LiveAuctionsDatabases = ladBases
+
+	// establishing bus-listeners
+	liveAuctionsState.BusListeners = state.NewBusListeners(state.SubjectBusListeners{
+		subjects.ReceiveComputedLiveAuctions: liveAuctionsState.ListenForComputedLiveAuctions,
+	})
+
+	// establishing messenger-listeners
+	liveAuctionsState.Listeners = state.NewListeners(state.SubjectListeners{
+		subjects.Auctions:           liveAuctionsState.ListenForAuctions,
+		subjects.OwnersQuery:        liveAuctionsState.ListenForOwnersQuery,
+		subjects.PriceList:          liveAuctionsState.ListenForPricelist,
+		subjects.OwnersQueryByItems: liveAuctionsState.ListenForOwnersQueryByItems,
+	})
+
+	return liveAuctionsState, nil
+}
+
+type ProdLiveAuctionsState struct {
+	prodState.ProdLiveAuctionsState
+
+	BusClient transport.Client
+	Store     storage.Store
+}
@@ -0,0 +1,214 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/transport"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/blizzard"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/bus"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/bus/codes"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/database"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/logging"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/metric"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/sotah"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/sotah/gameversions"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/state/subjects"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/util"
+)
+
+// handleComputedLiveAuctions loads the latest stored auctions of each realm into its live-auctions database
+func (liveAuctionsState ProdLiveAuctionsState) handleComputedLiveAuctions(tuples sotah.RegionRealmTuples) {
+	// declaring a load-in channel for the live-auctions db and starting it up
+	loadInJobs := make(chan database.LoadInJob)
+	loadOutJobs := liveAuctionsState.IO.Databases.LiveAuctionsDatabases.Load(loadInJobs)
+
+	// starting workers for handling tuples
+	in := make(chan sotah.RegionRealmTuple)
+	worker := func() {
+		for tuple := range in {
+			// resolving the realm from the request
+			realm, err := resolveRealm(
+				liveAuctionsState.Statuses,
+				blizzard.RegionName(tuple.RegionName),
+				blizzard.RealmSlug(tuple.RealmSlug),
+			)
+			if err != nil {
+				logging.WithField("error", err.Error()).Error("Failed to resolve realm from tuple")
+
+				continue
+			}
+
+			// resolving the auctions
+			aucs, lastModified, err := liveAuctionsState.Store.GetLatestAuctions(realm)
+			if err != nil {
+				logging.WithField("error", err.Error()).Error("Failed to get auctions")
+
+				continue
+			}
+
+			loadInJobs <- database.LoadInJob{
+				Realm:      realm,
+				TargetTime: lastModified,
+				Auctions:   aucs,
+			}
+		}
+	}
+	postWork := func() {
+		close(loadInJobs)
+	}
+	util.Work(4, worker, postWork)
+
+	// queueing it all up
+	go func() {
+		for _, tuple := range tuples {
+			logging.WithFields(logrus.Fields{
+				"region": tuple.RegionName,
+				"realm":  tuple.RealmSlug,
+			}).Info("Loading tuple")
+
+			in <- tuple
+		}
+
+		close(in)
+	}()
+
+	// waiting for the results to drain out
+	for job := range loadOutJobs {
+		if job.Err != nil {
+			logging.WithFields(job.ToLogrusFields()).Error("Failed to load job")
+
+			continue
+		}
+
+		logging.WithFields(logrus.Fields{
+			"region": job.Realm.Region.Name,
+			"realm":  job.Realm.Slug,
+		}).Info("Loaded job")
+	}
+}
+
+// updateHellRealms marks the realms as received for the gcloud functions that read realm timestamps from hell
+func (liveAuctionsState ProdLiveAuctionsState) updateHellRealms(tuples sotah.RegionRealmTuples) error {
+	logging.Info("Fetching region-realms from hell")
+	hellRegionRealms, err := liveAuctionsState.IO.HellClient.GetRegionRealms(
+		tuples.ToRegionRealmSlugs(),
+		gameversions.Retail,
+	)
+	if err != nil {
+		return err
+	}
+
+	logging.WithField(
+		"total",
+		hellRegionRealms.Total(),
+	).Info("Updating region-realms in hell with new downloaded timestamp")
+	for _, tuple := range tuples {
+		hellRealm := hellRegionRealms[blizzard.RegionName(tuple.RegionName)][blizzard.RealmSlug(tuple.RealmSlug)]
+		hellRealm.LiveAuctionsReceived = int(time.Now().Unix())
+		hellRegionRealms[blizzard.RegionName(tuple.RegionName)][blizzard.RealmSlug(tuple.RealmSlug)] = hellRealm
+	}
+
+	return liveAuctionsState.IO.HellClient.WriteRegionRealms(hellRegionRealms, gameversions.Retail)
+}
+
+func (liveAuctionsState ProdLiveAuctionsState) ListenForComputedLiveAuctions(
+	onReady chan interface{},
+	stop chan interface{},
+	onStopped chan interface{},
+) {
+	// establishing subscriber config
+	config := transport.SubscribeConfig{
+		Stop: stop,
+		Callback: func(busMsg bus.Message) {
+			// decoding message body
+			tuples, err := sotah.NewRegionRealmTuples(busMsg.Data)
+			if err != nil {
+				logging.WithField("error", err.Error()).Error("Failed to decode region-realm tuples")
+
+				if err := liveAuctionsState.BusClient.ReplyToWithError(busMsg, err, codes.GenericError); err != nil {
+					logging.WithField("error", err.Error()).Error("Failed to reply to message")
+
+					return
+				}
+
+				return
+			}
+
+			// acking the message
+			if err := liveAuctionsState.BusClient.ReplyTo(busMsg, bus.NewMessage()); err != nil {
+				logging.WithField("error", err.Error()).Error("Failed to reply to message")
+
+				return
+			}
+
+			// handling requests
+			logging.WithField("requests", len(tuples)).Info("Received tuples")
+			startTime := time.Now()
+			liveAuctionsState.handleComputedLiveAuctions(tuples)
+			logging.WithField("requests", len(tuples)).Info("Done handling tuples")
+
+			// reporting metrics
+			m := metric.Metrics{
+				"receive_all_live_auctions_duration": int(int64(time.Since(startTime)) / 1000 / 1000 / 1000),
+			}
+			if err := liveAuctionsState.BusClient.PublishMetrics(m); err != nil {
+				logging.WithField("error", err.Error()).Error("Failed to publish metric")
+
+				return
+			}
+
+			// updating the list of realms' timestamps
+			if liveAuctionsState.UseGCloud {
+				if err := liveAuctionsState.updateHellRealms(tuples); err != nil {
+					logging.WithField("error", err.Error()).Error("Failed to update region-realms in hell")
+
+					return
+				}
+			}
+
+			// publishing region-realm slugs to the receive-realms bus endpoint
+			jsonEncoded, err := json.Marshal(tuples.ToRegionRealmSlugs())
+			if err != nil {
+				logging.WithField("error", err.Error()).Error("Failed to encode region-realm slugs for publishing")
+
+				return
+			}
+
+			logging.Info("Publishing to receive-realms bus endpoint")
+			req, err := liveAuctionsState.BusClient.Request(
+				string(subjects.ReceiveRealms),
+				string(jsonEncoded),
+				10*time.Second,
+			)
+			if err != nil {
+				logging.WithField("error", err.Error()).Error("Failed to publish region-realm slugs")
+
+				return
+			}
+
+			if req.Code != codes.Ok {
+				logging.WithField(
+					"error",
+					errors.New("response code was not ok").Error(),
+				).Error("Publish succeeded but response code was not ok")
+
+				return
+			}
+		},
+		OnReady:   onReady,
+		OnStopped: onStopped,
+	}
+
+	// starting up worker for the subscription
+	go func() {
+		if err := liveAuctionsState.BusClient.SubscribeToTopic(
+			string(subjects.ReceiveComputedLiveAuctions),
+			config,
+		); err != nil {
+			logging.WithField("error", err.Error()).Fatal("Failed to subscribe to topic")
+		}
+	}()
+}
@@ -0,0 +1,58 @@
+package server
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/blizzard"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/database"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/sotah"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/util"
+)
+
+func TestProdLiveAuctionsStateHandleComputedLiveAuctions(t *testing.T) {
+	dirPath, cleanup := newTestDir(t)
+	defer cleanup()
+
+	stor := newTestStore(t)
+	statuses, err := NewStatusesFromStore(stor, sotah.RegionList{{Name: "us"}})
+	if err != nil {
+		t.Fatalf("could not gather statuses: %s", err)
+	}
+	rea := statuses["us"].Realms[0]
+
+	for i, lastModified := range []time.Time{time.Unix(100, 0), time.Unix(200, 0)} {
+		aucs := blizzard.Auctions{Auctions: []blizzard.Auction{
+			{Auc: int64(i), Item: blizzard.ItemID(i + 1), Owner: "a", Buyout: 10, Quantity: 1},
+		}}
+		if err := stor.WriteAuctions(rea, lastModified, aucs); err != nil {
+			t.Fatalf("could not write auctions: %s", err)
+		}
+	}
+
+	if err := util.EnsureDirsExist([]string{fmt.Sprintf("%s/live-auctions/us/earthen-ring", dirPath)}); err != nil {
+		t.Fatalf("could not create database dir: %s", err)
+	}
+	ladBases, err := database.NewLiveAuctionsDatabases(dirPath, statuses)
+	if err != nil {
+		t.Fatalf("could not open live-auctions databases: %s", err)
+	}
+	liveAuctionsState := ProdLiveAuctionsState{Store: stor}
+	liveAuctionsState.Statuses = statuses
+	liveAuctionsState.IO.Databases.LiveAuctionsDatabases = ladBases
+
+	// unknown realms are skipped
+	liveAuctionsState.handleComputedLiveAuctions(sotah.RegionRealmTuples{
+		{RegionName: "us", RealmSlug: "earthen-ring"},
+		{RegionName: "us", RealmSlug: "missing"},
+	})
+
+	maList, err := ladBases["us"]["earthen-ring"].GetMiniAuctionList()
+	if err != nil {
+		t.Fatalf("could not get mini-auction-list: %s", err)
+	}
+	if len(maList) != 1 || maList[0].ItemID != 2 {
+		t.Fatalf("expected the latest auctions to be loaded, got %+v", maList)
+	}
+}
@@ -0,0 +1,120 @@
+package server
+
+import (
+	"fmt"
+
+	"github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/storage"
+	"github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/transport"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/database"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/logging"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/messenger"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/metric"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/sotah"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/sotah/gameversions"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/state"
+	prodState "github.com/sotah-inc/steamwheedle-cartel/pkg/state/prod"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/state/subjects"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/util"
+	"github.com/twinj/uuid"
+)
+
+type ProdPricelistHistoriesStateConfig struct {
+	GCloudProjectID string
+
+	MessengerHost string
+	MessengerPort int
+
+	PricelistHistoriesDatabaseDir string
+
+	Regions        sotah.RegionList
+	BusTransport   transport.Kind
+	StorageBackend storage.BackendConfig
+}
+
+func NewProdPricelistHistoriesState(config ProdPricelistHistoriesStateConfig) (ProdPricelistHistoriesState, error) {
+	// establishing an initial state
+	phState := ProdPricelistHistoriesState{
+		ProdPricelistHistoriesState: prodState.ProdPricelistHistoriesState{
+			State: state.NewState(uuid.NewV4(), config.StorageBackend.Kind == storage.GCloud),
+		},
+	}
+
+	// connecting to the messenger host
+	mess, err := messenger.NewMessenger(config.MessengerHost, config.MessengerPort)
+	if err != nil {
+		return ProdPricelistHistoriesState{}, err
+	}
+	phState.IO.Messenger = mess
+
+	// establishing a bus
+	logging.WithField("transport", config.BusTransport).Info("Connecting bus-client")
+	busTransport, err := transport.NewTransport(transport.Config{
+		Kind:            config.BusTransport,
+		GCloudProjectID: config.GCloudProjectID,
+		SubscriberID:    "prod-pricelisthistories",
+		Messenger:       mess,
+	})
+	if err != nil {
+		return ProdPricelistHistoriesState{}, err
+	}
+	phState.BusClient = transport.NewClient(busTransport)
+
+	// establishing a store
+	logging.WithField("kind", config.StorageBackend.Kind).Info("Connecting to storage backend")
+	phState.Store, err = storage.NewStoreFromConfig(config.StorageBackend, gameversions.Retail)
+	if err != nil {
+		return ProdPricelistHistoriesState{}, err
+	}
+
+	// gathering region-realms
+	phState.Statuses, err = NewStatusesFromStore(phState.Store, config.Regions)
+	if err != nil {
+		return ProdPricelistHistoriesState{}, err
+	}
+
+	// ensuring database paths exist
+	databasePaths := []string{}
+	for regionName, status := range phState.Statuses {
+		for _, realm := range status.Realms {
+			databasePaths = append(databasePaths, fmt.Sprintf(
+				"%s/pricelist-histories/%s/%s",
+				config.PricelistHistoriesDatabaseDir,
+				regionName,
+				realm.Slug,
+			))
+		}
+	}
+	if err := util.EnsureDirsExist(databasePaths); err != nil {
+		return ProdPricelistHistoriesState{}, err
+	}
+
+	// initializing a reporter
+	phState.IO.Reporter = metric.NewReporter(mess)
+
+	// loading the pricelist-histories databases
+	logging.Info("Connecting to pricelist-histories databases")
+	phdBases, err := database.NewPricelistHistoryDatabases(config.PricelistHistoriesDatabaseDir, phState.Statuses)
+	if err != nil {
+		return ProdPricelistHistoriesState{}, err
+	}
+	phState.IO.Databases.PricelistHistoryDatabases = phdBases
+
+	// establishing bus-listeners
+	phState.BusListeners = state.NewBusListeners(state.SubjectBusListeners{
+		subjects.ReceiveComputedPricelistHistories: phState.ListenForComputedPricelistHistories,
+	})
+
+	// establishing messenger-listeners
+	phState.Listeners = state.NewListeners(state.SubjectListeners{
+		subjects.PriceListHistory: phState.ListenForPriceListHistory,
+	})
+
+	return phState, nil
+}
+
+type ProdPricelistHistoriesState struct {
+	prodState.ProdPricelistHistoriesState
+
+	BusClient transport.Client
+	Store     storage.Store
+}
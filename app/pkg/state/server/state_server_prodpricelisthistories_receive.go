@@ -0,0 +1,181 @@
+package server
+
+import (
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/transport"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/blizzard"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/bus"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/bus/codes"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/database"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/logging"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/metric"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/sotah"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/state/subjects"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/util"
+)
+
+// NewEncodedPriceHistories encodes each item's price-history the way the pricelist-histories databases persist them
+func NewEncodedPriceHistories(ipHistories sotah.ItemPriceHistories) (map[blizzard.ItemID][]byte, error) {
+	out := map[blizzard.ItemID][]byte{}
+	for itemId, pHistory := range ipHistories {
+		data, err := pHistory.EncodeForPersistence()
+		if err != nil {
+			return map[blizzard.ItemID][]byte{}, err
+		}
+
+		out[itemId] = data
+	}
+
+	return out, nil
+}
+
+// handleComputedPricelistHistories loads the stored pricelist-histories of each request into the local databases
+func (phState ProdPricelistHistoriesState) handleComputedPricelistHistories(
+	requests database.PricelistHistoriesComputeIntakeRequests,
+) {
+	// declaring a load-in channel for the pricelist-histories db
+	loadInJobs := make(chan database.PricelistHistoryDatabaseEncodedLoadInJob)
+	loadOutJobs := phState.IO.Databases.PricelistHistoryDatabases.LoadEncoded(loadInJobs)
+
+	// starting workers for gathering pricelist-histories
+	in := make(chan database.PricelistHistoriesComputeIntakeRequest)
+	worker := func() {
+		for request := range in {
+			entry := logging.WithFields(logrus.Fields{
+				"region":                      request.RegionName,
+				"realm":                       request.RealmSlug,
+				"normalized-target-timestamp": request.NormalizedTargetTimestamp,
+			})
+
+			// resolving the realm from the request
+			realm, err := resolveRealm(
+				phState.Statuses,
+				blizzard.RegionName(request.RegionName),
+				blizzard.RealmSlug(request.RealmSlug),
+			)
+			if err != nil {
+				entry.WithField("error", err.Error()).Error("Failed to resolve realm from request")
+
+				continue
+			}
+
+			// resolving the data
+			targetDate := time.Unix(int64(request.NormalizedTargetTimestamp), 0)
+			ipHistories, err := phState.Store.GetPricelistHistories(realm, targetDate)
+			if err != nil {
+				entry.WithField("error", err.Error()).Error("Failed to get pricelist-histories")
+
+				continue
+			}
+
+			data, err := NewEncodedPriceHistories(ipHistories)
+			if err != nil {
+				entry.WithField("error", err.Error()).Error("Failed to encode pricelist-histories")
+
+				continue
+			}
+
+			loadInJobs <- database.PricelistHistoryDatabaseEncodedLoadInJob{
+				RegionName:                realm.Region.Name,
+				RealmSlug:                 realm.Slug,
+				NormalizedTargetTimestamp: sotah.UnixTimestamp(request.NormalizedTargetTimestamp),
+				Data:                      data,
+			}
+		}
+	}
+	postWork := func() {
+		close(loadInJobs)
+	}
+	util.Work(4, worker, postWork)
+
+	// queueing it all up
+	go func() {
+		for _, request := range requests {
+			logging.WithFields(logrus.Fields{
+				"region":                      request.RegionName,
+				"realm":                       request.RealmSlug,
+				"normalized-target-timestamp": request.NormalizedTargetTimestamp,
+			}).Info("Loading request")
+
+			in <- request
+		}
+
+		close(in)
+	}()
+
+	// waiting for the results to drain out
+	for job := range loadOutJobs {
+		if job.Err != nil {
+			logging.WithFields(job.ToLogrusFields()).Error("Failed to load job")
+
+			continue
+		}
+
+		logging.WithFields(logrus.Fields{
+			"region": job.RegionName,
+			"realm":  job.RealmSlug,
+		}).Info("Loaded job")
+	}
+}
+
+func (phState ProdPricelistHistoriesState) ListenForComputedPricelistHistories(
+	onReady chan interface{},
+	stop chan interface{},
+	onStopped chan interface{},
+) {
+	// establishing subscriber config
+	config := transport.SubscribeConfig{
+		Stop: stop,
+		Callback: func(busMsg bus.Message) {
+			requests, err := database.NewPricelistHistoriesComputeIntakeRequests(busMsg.Data)
+			if err != nil {
+				logging.WithField("error", err.Error()).Error("Failed to decode compute-intake requests")
+
+				if err := phState.BusClient.ReplyToWithError(busMsg, err, codes.GenericError); err != nil {
+					logging.WithField("error", err.Error()).Error("Failed to reply to message")
+
+					return
+				}
+
+				return
+			}
+
+			// acking the message
+			if err := phState.BusClient.ReplyTo(busMsg, bus.NewMessage()); err != nil {
+				logging.WithField("error", err.Error()).Error("Failed to reply to message")
+
+				return
+			}
+
+			// handling requests
+			logging.WithField("requests", len(requests)).Info("Received requests")
+			startTime := time.Now()
+			phState.handleComputedPricelistHistories(requests)
+			logging.WithField("requests", len(requests)).Info("Done handling requests")
+
+			// reporting metrics
+			m := metric.Metrics{
+				"receive_all_pricelist_histories_duration": int(int64(time.Since(startTime)) / 1000 / 1000 / 1000),
+			}
+			if err := phState.BusClient.PublishMetrics(m); err != nil {
+				logging.WithField("error", err.Error()).Error("Failed to publish metric")
+
+				return
+			}
+		},
+		OnReady:   onReady,
+		OnStopped: onStopped,
+	}
+
+	// starting up worker for the subscription
+	go func() {
+		if err := phState.BusClient.SubscribeToTopic(
+			string(subjects.ReceiveComputedPricelistHistories),
+			config,
+		); err != nil {
+			logging.WithField("error", err.Error()).Fatal("Failed to subscribe to topic")
+		}
+	}()
+}
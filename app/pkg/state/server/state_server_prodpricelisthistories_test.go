@@ -0,0 +1,63 @@
+package server
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/blizzard"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/database"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/sotah"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/util"
+)
+
+func TestProdPricelistHistoriesStateHandleComputedPricelistHistories(t *testing.T) {
+	dirPath, cleanup := newTestDir(t)
+	defer cleanup()
+
+	stor := newTestStore(t)
+	statuses, err := NewStatusesFromStore(stor, sotah.RegionList{{Name: "us"}})
+	if err != nil {
+		t.Fatalf("could not gather statuses: %s", err)
+	}
+	rea := statuses["us"].Realms[0]
+
+	targetDate := sotah.NormalizeTargetDate(time.Unix(1500000000, 0))
+	targetTimestamp := sotah.UnixTimestamp(targetDate.Add(time.Hour).Unix())
+	ipHistories := sotah.ItemPriceHistories{
+		1: sotah.PriceHistory{targetTimestamp: sotah.Prices{MinBuyoutPer: 10, Volume: 2}},
+	}
+	if err := stor.WritePricelistHistories(rea, targetDate, ipHistories); err != nil {
+		t.Fatalf("could not write pricelist-histories: %s", err)
+	}
+
+	if err := util.EnsureDirsExist([]string{fmt.Sprintf("%s/pricelist-histories/us/earthen-ring", dirPath)}); err != nil {
+		t.Fatalf("could not create database dir: %s", err)
+	}
+	phdBases, err := database.NewPricelistHistoryDatabases(dirPath, statuses)
+	if err != nil {
+		t.Fatalf("could not open pricelist-histories databases: %s", err)
+	}
+	phState := ProdPricelistHistoriesState{Store: stor}
+	phState.Statuses = statuses
+	phState.IO.Databases.PricelistHistoryDatabases = phdBases
+
+	phState.handleComputedPricelistHistories(database.PricelistHistoriesComputeIntakeRequests{
+		{RegionName: "us", RealmSlug: "earthen-ring", NormalizedTargetTimestamp: int(targetDate.Unix())},
+		{RegionName: "us", RealmSlug: "missing", NormalizedTargetTimestamp: int(targetDate.Unix())},
+	})
+
+	res, _, err := phdBases.GetPricelistHistory(database.GetPricelistHistoryRequest{
+		RegionName:  "us",
+		RealmSlug:   "earthen-ring",
+		ItemIds:     []blizzard.ItemID{1},
+		LowerBounds: targetDate.Add(-time.Hour).Unix(),
+		UpperBounds: targetDate.Add(48 * time.Hour).Unix(),
+	})
+	if err != nil {
+		t.Fatalf("could not get pricelist-history: %s", err)
+	}
+	if prices := res.History[1][targetTimestamp]; prices.MinBuyoutPer != 10 || prices.Volume != 2 {
+		t.Fatalf("expected the stored pricelist-histories to be loaded, got %+v", res.History)
+	}
+}
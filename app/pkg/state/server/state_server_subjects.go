@@ -0,0 +1,27 @@
+package server
+
+import (
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/state/subjects"
+)
+
+/*
+Subjects - subject names served by this app in addition to the library subjects
+*/
+const (
+	SalesEstimate   subjects.Subject = "salesEstimate"
+	Alerts          subjects.Subject = "alerts"
+	CreateAlert     subjects.Subject = "createAlert"
+	DeleteAlert     subjects.Subject = "deleteAlert"
+	AlertMatches    subjects.Subject = "alertMatches"
+	RegionPriceList subjects.Subject = "regionPriceList"
+	OwnerHistory    subjects.Subject = "ownerHistory"
+	MarketShare     subjects.Subject = "marketShare"
+	CraftingProfit  subjects.Subject = "craftingProfit"
+	VendorFlips     subjects.Subject = "vendorFlips"
+	YieldValues     subjects.Subject = "yieldValues"
+	PriceForecast   subjects.Subject = "priceForecast"
+	Anomalies       subjects.Subject = "anomalies"
+	QueryAnomalies  subjects.Subject = "queryAnomalies"
+	Export          subjects.Subject = "export"
+	RealmStaleness  subjects.Subject = "realmStaleness"
+)
@@ -0,0 +1,66 @@
+package server
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/storage"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/blizzard"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/sotah"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/sotah/gameversions"
+)
+
+func newTestRealm(regionName blizzard.RegionName, realmSlug blizzard.RealmSlug) sotah.Realm {
+	return sotah.Realm{
+		Realm:  blizzard.Realm{Slug: realmSlug, Name: string(realmSlug)},
+		Region: sotah.Region{Name: regionName},
+	}
+}
+
+// newTestStore seeds an in-memory store with a realm in each of two regions
+func newTestStore(t *testing.T) storage.Store {
+	stor := storage.NewStore(storage.NewMemoryBackend(), gameversions.Retail)
+	for _, rea := range []sotah.Realm{newTestRealm("us", "earthen-ring"), newTestRealm("eu", "silvermoon")} {
+		if err := stor.WriteRealm(rea); err != nil {
+			t.Fatalf("could not write realm: %s", err)
+		}
+	}
+
+	return stor
+}
+
+func newTestDir(t *testing.T) (string, func()) {
+	dirPath, err := ioutil.TempDir("", "server")
+	if err != nil {
+		t.Fatalf("could not create temp dir: %s", err)
+	}
+
+	return dirPath, func() {
+		os.RemoveAll(dirPath)
+	}
+}
+
+func TestNewStatusesFromStore(t *testing.T) {
+	stor := newTestStore(t)
+
+	statuses, err := NewStatusesFromStore(stor, sotah.RegionList{{Name: "us"}, {Name: "kr"}})
+	if err != nil {
+		t.Fatalf("could not gather statuses: %s", err)
+	}
+
+	if len(statuses) != 2 || len(statuses["us"].Realms) != 1 || len(statuses["kr"].Realms) != 0 {
+		t.Fatalf("expected only the listed regions, got %+v", statuses)
+	}
+	if statuses["us"].Region.Name != "us" {
+		t.Fatalf("expected the status to carry its region, got %+v", statuses["us"])
+	}
+
+	rea, err := resolveRealm(statuses, "us", "earthen-ring")
+	if err != nil || rea.Slug != "earthen-ring" {
+		t.Fatalf("could not resolve realm: %+v %v", rea, err)
+	}
+	if _, err := resolveRealm(statuses, "eu", "silvermoon"); err == nil {
+		t.Fatal("expected a realm of an unlisted region to not resolve")
+	}
+}
@@ -0,0 +1,105 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/sotah/gameversions"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/store"
+)
+
+// Collection - typehint for these enums
+type Collection string
+
+/*
+Collections - groups of stored objects, each mapping onto a gcloud bucket or a disk directory
+*/
+const (
+	Auctions           Collection = "auctions"
+	AuctionManifests   Collection = "auction-manifests"
+	Items              Collection = "items"
+	ItemIcons          Collection = "item-icons"
+	Realms             Collection = "realms"
+	PricelistHistories Collection = "pricelist-histories"
+)
+
+// Kind - typehint for these enums
+type Kind string
+
+/*
+Kinds - available storage backends
+*/
+const (
+	Disk   Kind = "disk"
+	Memory Kind = "memory"
+	GCloud Kind = "gcloud"
+)
+
+// ErrNotFound is returned by backends when an object does not exist
+var ErrNotFound = errors.New("object not found")
+
+type Object struct {
+	Body        []byte
+	ContentType string
+
+	// whether the body is gzip-encoded
+	Gzipped bool
+}
+
+// Backend stores raw objects by collection and name
+type Backend interface {
+	Read(collection Collection, name string) ([]byte, error)
+	Write(collection Collection, name string, obj Object) error
+	Delete(collection Collection, name string) error
+	List(collection Collection, prefix string) ([]string, error)
+}
+
+type BackendConfig struct {
+	Kind Kind
+
+	// disk backend
+	CacheDir string
+
+	// gcloud backend
+	GCloudProjectID string
+}
+
+// ResolveKind maps the legacy use-gcloud flag onto a kind when none was provided
+func ResolveKind(kind Kind, useGCloud bool) Kind {
+	if len(kind) > 0 {
+		return kind
+	}
+
+	if useGCloud {
+		return GCloud
+	}
+
+	return Disk
+}
+
+func NewBackend(config BackendConfig) (Backend, error) {
+	switch config.Kind {
+	case Disk:
+		return NewDiskBackend(fmt.Sprintf("%s/storage", config.CacheDir))
+	case Memory:
+		return NewMemoryBackend(), nil
+	case GCloud:
+		storeClient, err := store.NewClient(config.GCloudProjectID)
+		if err != nil {
+			return nil, err
+		}
+
+		return NewGCloudBackend(storeClient), nil
+	default:
+		return nil, fmt.Errorf("invalid storage kind: %s", config.Kind)
+	}
+}
+
+func NewStoreFromConfig(config BackendConfig, version gameversions.GameVersion) (Store, error) {
+	backend, err := NewBackend(config)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewStore(backend, version), nil
+}
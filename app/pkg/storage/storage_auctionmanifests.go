@@ -0,0 +1,64 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/sotah"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/util"
+)
+
+func (s backendStore) auctionManifestObjectName(realm sotah.Realm, targetTimestamp sotah.UnixTimestamp) string {
+	return fmt.Sprintf("%s/%d.json", s.realmPrefix(realm), targetTimestamp)
+}
+
+func normalizeTimestamp(targetTimestamp sotah.UnixTimestamp) sotah.UnixTimestamp {
+	return sotah.UnixTimestamp(sotah.NormalizeTargetDate(time.Unix(int64(targetTimestamp), 0)).Unix())
+}
+
+func (s backendStore) GetAuctionManifest(
+	realm sotah.Realm,
+	targetTimestamp sotah.UnixTimestamp,
+) (sotah.AuctionManifest, error) {
+	name := s.auctionManifestObjectName(realm, normalizeTimestamp(targetTimestamp))
+	gzipEncoded, err := s.backend.Read(AuctionManifests, name)
+	if err != nil {
+		if err == ErrNotFound {
+			return sotah.AuctionManifest{}, nil
+		}
+
+		return sotah.AuctionManifest{}, err
+	}
+
+	jsonEncoded, err := util.GzipDecode(gzipEncoded)
+	if err != nil {
+		return sotah.AuctionManifest{}, err
+	}
+
+	var out sotah.AuctionManifest
+	if err := json.Unmarshal(jsonEncoded, &out); err != nil {
+		return sotah.AuctionManifest{}, err
+	}
+
+	return out, nil
+}
+
+func (s backendStore) AppendAuctionManifest(realm sotah.Realm, targetTimestamp sotah.UnixTimestamp) error {
+	manifest, err := s.GetAuctionManifest(realm, targetTimestamp)
+	if err != nil {
+		return err
+	}
+
+	gzipEncoded, err := manifest.Merge(sotah.AuctionManifest{targetTimestamp}).EncodeForPersistence()
+	if err != nil {
+		return err
+	}
+
+	name := s.auctionManifestObjectName(realm, normalizeTimestamp(targetTimestamp))
+	return s.backend.Write(AuctionManifests, name, Object{
+		Body:        gzipEncoded,
+		ContentType: "application/json",
+		Gzipped:     true,
+	})
+}
@@ -0,0 +1,102 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/blizzard"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/sotah"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/util"
+)
+
+func (s backendStore) auctionsObjectName(realm sotah.Realm, lastModified time.Time) string {
+	return fmt.Sprintf("%s/%d.json.gz", s.realmPrefix(realm), lastModified.Unix())
+}
+
+func (s backendStore) WriteAuctions(realm sotah.Realm, lastModified time.Time, aucs blizzard.Auctions) error {
+	jsonEncoded, err := json.Marshal(aucs)
+	if err != nil {
+		return err
+	}
+
+	gzipEncoded, err := util.GzipEncode(jsonEncoded)
+	if err != nil {
+		return err
+	}
+
+	return s.backend.Write(Auctions, s.auctionsObjectName(realm, lastModified), Object{
+		Body:        gzipEncoded,
+		ContentType: "application/json",
+		Gzipped:     true,
+	})
+}
+
+func (s backendStore) GetAuctions(realm sotah.Realm, lastModified time.Time) (blizzard.Auctions, error) {
+	gzipEncoded, err := s.backend.Read(Auctions, s.auctionsObjectName(realm, lastModified))
+	if err != nil {
+		return blizzard.Auctions{}, err
+	}
+
+	jsonEncoded, err := util.GzipDecode(gzipEncoded)
+	if err != nil {
+		return blizzard.Auctions{}, err
+	}
+
+	return blizzard.NewAuctions(jsonEncoded)
+}
+
+func (s backendStore) GetLatestAuctions(realm sotah.Realm) (blizzard.Auctions, time.Time, error) {
+	timestamps, err := s.GetAuctionsTimestamps(realm)
+	if err != nil {
+		return blizzard.Auctions{}, time.Time{}, err
+	}
+
+	if len(timestamps) == 0 {
+		return blizzard.Auctions{}, time.Time{}, ErrNotFound
+	}
+
+	lastModified := time.Unix(int64(timestamps[len(timestamps)-1]), 0)
+	aucs, err := s.GetAuctions(realm, lastModified)
+	if err != nil {
+		return blizzard.Auctions{}, time.Time{}, err
+	}
+
+	return aucs, lastModified, nil
+}
+
+func (s backendStore) GetAuctionsTimestamps(realm sotah.Realm) ([]sotah.UnixTimestamp, error) {
+	return s.listTimestamps(Auctions, s.realmPrefix(realm)+"/")
+}
+
+func (s backendStore) DeleteAuctions(realm sotah.Realm, lastModified time.Time) error {
+	return s.backend.Delete(Auctions, s.auctionsObjectName(realm, lastModified))
+}
+
+// listTimestamps gathers the sorted unix timestamps encoded in object names under a prefix
+func (s backendStore) listTimestamps(collection Collection, prefix string) ([]sotah.UnixTimestamp, error) {
+	names, err := s.backend.List(collection, prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	out := []sotah.UnixTimestamp{}
+	for _, name := range names {
+		base := path.Base(name)
+		parsed, err := strconv.Atoi(base[:strings.Index(base+".", ".")])
+		if err != nil {
+			continue
+		}
+
+		out = append(out, sotah.UnixTimestamp(parsed))
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return out[i] < out[j]
+	})
+
+	return out, nil
+}
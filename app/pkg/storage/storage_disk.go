@@ -0,0 +1,112 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/util"
+)
+
+func NewDiskBackend(dir string) (DiskBackend, error) {
+	if len(dir) == 0 {
+		return DiskBackend{}, errors.New("dir cannot be blank")
+	}
+
+	if err := util.EnsureDirExists(dir); err != nil {
+		return DiskBackend{}, err
+	}
+
+	return DiskBackend{Dir: dir}, nil
+}
+
+type DiskBackend struct {
+	Dir string
+}
+
+func (b DiskBackend) resolvePath(collection Collection, name string) string {
+	return filepath.FromSlash(fmt.Sprintf("%s/%s/%s", b.Dir, collection, name))
+}
+
+func (b DiskBackend) Read(collection Collection, name string) ([]byte, error) {
+	data, err := util.ReadFile(b.resolvePath(collection, name))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrNotFound
+		}
+
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func (b DiskBackend) Write(collection Collection, name string, obj Object) error {
+	dest := b.resolvePath(collection, name)
+	if err := util.EnsureDirExists(filepath.Dir(dest)); err != nil {
+		return err
+	}
+
+	return util.WriteFile(dest, obj.Body)
+}
+
+func (b DiskBackend) Delete(collection Collection, name string) error {
+	if err := os.Remove(b.resolvePath(collection, name)); err != nil {
+		if os.IsNotExist(err) {
+			return ErrNotFound
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+func (b DiskBackend) List(collection Collection, prefix string) ([]string, error) {
+	collectionDir := filepath.FromSlash(fmt.Sprintf("%s/%s", b.Dir, collection))
+
+	// walking from the deepest directory covered by the prefix
+	walkDir := collectionDir
+	if i := strings.LastIndex(prefix, "/"); i > -1 {
+		walkDir = filepath.Join(collectionDir, filepath.FromSlash(prefix[:i]))
+	}
+	if _, err := os.Stat(walkDir); err != nil {
+		if os.IsNotExist(err) {
+			return []string{}, nil
+		}
+
+		return nil, err
+	}
+
+	out := []string{}
+	err := filepath.Walk(walkDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() {
+			return nil
+		}
+
+		relativePath, err := filepath.Rel(collectionDir, path)
+		if err != nil {
+			return err
+		}
+
+		name := filepath.ToSlash(relativePath)
+		if !strings.HasPrefix(name, prefix) {
+			return nil
+		}
+
+		out = append(out, name)
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
@@ -0,0 +1,119 @@
+package storage
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	gStorage "cloud.google.com/go/storage"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/store"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/store/regions"
+	"google.golang.org/api/iterator"
+)
+
+func NewGCloudBackend(c store.Client) GCloudBackend {
+	return GCloudBackend{
+		client: c,
+		buckets: map[Collection]*gStorage.BucketHandle{
+			Auctions:           store.NewAuctionsBaseV2(c, regions.USCentral1, "").GetBucket(),
+			AuctionManifests:   store.NewAuctionManifestBaseV2(c, regions.USCentral1, "").GetBucket(),
+			Items:              store.NewItemsBase(c, regions.USCentral1, "").GetBucket(),
+			ItemIcons:          store.NewItemIconsBase(c, regions.USCentral1, "").GetBucket(),
+			Realms:             store.NewRealmsBase(c, regions.USCentral1, "").GetBucket(),
+			PricelistHistories: store.NewPricelistHistoriesBaseV2(c, regions.USCentral1, "").GetBucket(),
+		},
+	}
+}
+
+// GCloudBackend maps each collection onto the existing gcloud storage buckets
+type GCloudBackend struct {
+	client  store.Client
+	buckets map[Collection]*gStorage.BucketHandle
+}
+
+func (b GCloudBackend) getBucket(collection Collection) (*gStorage.BucketHandle, error) {
+	bkt, ok := b.buckets[collection]
+	if !ok {
+		return nil, fmt.Errorf("invalid collection: %s", collection)
+	}
+
+	return bkt, nil
+}
+
+func (b GCloudBackend) Read(collection Collection, name string) ([]byte, error) {
+	bkt, err := b.getBucket(collection)
+	if err != nil {
+		return nil, err
+	}
+
+	// reading the object as it was stored, without transcoding
+	reader, err := bkt.Object(name).ReadCompressed(true).NewReader(b.client.Context)
+	if err != nil {
+		if err == gStorage.ErrObjectNotExist {
+			return nil, ErrNotFound
+		}
+
+		return nil, err
+	}
+	defer reader.Close()
+
+	return ioutil.ReadAll(reader)
+}
+
+func (b GCloudBackend) Write(collection Collection, name string, obj Object) error {
+	bkt, err := b.getBucket(collection)
+	if err != nil {
+		return err
+	}
+
+	wc := bkt.Object(name).NewWriter(b.client.Context)
+	wc.ContentType = obj.ContentType
+	if obj.Gzipped {
+		wc.ContentEncoding = "gzip"
+	}
+	if _, err := wc.Write(obj.Body); err != nil {
+		return err
+	}
+
+	return wc.Close()
+}
+
+func (b GCloudBackend) Delete(collection Collection, name string) error {
+	bkt, err := b.getBucket(collection)
+	if err != nil {
+		return err
+	}
+
+	if err := bkt.Object(name).Delete(b.client.Context); err != nil {
+		if err == gStorage.ErrObjectNotExist {
+			return ErrNotFound
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+func (b GCloudBackend) List(collection Collection, prefix string) ([]string, error) {
+	bkt, err := b.getBucket(collection)
+	if err != nil {
+		return nil, err
+	}
+
+	out := []string{}
+	it := bkt.Objects(b.client.Context, &gStorage.Query{Prefix: prefix})
+	for {
+		objAttrs, err := it.Next()
+		if err != nil {
+			if err == iterator.Done {
+				break
+			}
+
+			return nil, err
+		}
+
+		out = append(out, objAttrs.Name)
+	}
+
+	return out, nil
+}
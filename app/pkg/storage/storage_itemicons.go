@@ -0,0 +1,29 @@
+package storage
+
+import (
+	"fmt"
+)
+
+func (s backendStore) itemIconObjectName(name string) string {
+	return fmt.Sprintf("%s/%s.jpg", s.version, name)
+}
+
+func (s backendStore) WriteItemIcon(name string, body []byte) error {
+	return s.backend.Write(ItemIcons, s.itemIconObjectName(name), Object{
+		Body:        body,
+		ContentType: "image/jpeg",
+	})
+}
+
+func (s backendStore) GetItemIcon(name string) ([]byte, bool, error) {
+	body, err := s.backend.Read(ItemIcons, s.itemIconObjectName(name))
+	if err != nil {
+		if err == ErrNotFound {
+			return nil, false, nil
+		}
+
+		return nil, false, err
+	}
+
+	return body, true, nil
+}
@@ -0,0 +1,99 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/blizzard"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/sotah"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/util"
+)
+
+func (s backendStore) itemObjectName(id blizzard.ItemID) string {
+	return fmt.Sprintf("%s/%d.json.gz", s.version, id)
+}
+
+func (s backendStore) WriteItem(item sotah.Item) error {
+	jsonEncoded, err := json.Marshal(item)
+	if err != nil {
+		return err
+	}
+
+	gzipEncoded, err := util.GzipEncode(jsonEncoded)
+	if err != nil {
+		return err
+	}
+
+	return s.backend.Write(Items, s.itemObjectName(item.ID), Object{
+		Body:        gzipEncoded,
+		ContentType: "application/json",
+		Gzipped:     true,
+	})
+}
+
+func (s backendStore) GetItem(id blizzard.ItemID) (sotah.Item, bool, error) {
+	gzipEncoded, err := s.backend.Read(Items, s.itemObjectName(id))
+	if err != nil {
+		if err == ErrNotFound {
+			return sotah.Item{}, false, nil
+		}
+
+		return sotah.Item{}, false, err
+	}
+
+	jsonEncoded, err := util.GzipDecode(gzipEncoded)
+	if err != nil {
+		return sotah.Item{}, false, err
+	}
+
+	item, err := sotah.NewItem(jsonEncoded)
+	if err != nil {
+		return sotah.Item{}, false, err
+	}
+
+	return item, true, nil
+}
+
+type GetItemsJob struct {
+	Err    error
+	ID     blizzard.ItemID
+	Item   sotah.Item
+	Exists bool
+}
+
+func (job GetItemsJob) ToLogrusFields() logrus.Fields {
+	return logrus.Fields{
+		"error": job.Err.Error(),
+		"item":  job.ID,
+	}
+}
+
+func (s backendStore) GetItems(ids blizzard.ItemIds) chan GetItemsJob {
+	// establishing channels
+	out := make(chan GetItemsJob)
+	in := make(chan blizzard.ItemID)
+
+	// spinning up the workers for fetching items
+	worker := func() {
+		for id := range in {
+			item, exists, err := s.GetItem(id)
+			out <- GetItemsJob{err, id, item, exists}
+		}
+	}
+	postWork := func() {
+		close(out)
+	}
+	util.Work(8, worker, postWork)
+
+	// queueing up the items
+	go func() {
+		for _, id := range ids {
+			in <- id
+		}
+
+		close(in)
+	}()
+
+	return out
+}
@@ -0,0 +1,78 @@
+package storage
+
+import (
+	"sort"
+	"strings"
+	"sync"
+)
+
+func NewMemoryBackend() *MemoryBackend {
+	return &MemoryBackend{objects: map[Collection]map[string][]byte{}}
+}
+
+// MemoryBackend keeps objects in process memory, intended for tests and local runs
+type MemoryBackend struct {
+	sync.RWMutex
+
+	objects map[Collection]map[string][]byte
+}
+
+func (b *MemoryBackend) Read(collection Collection, name string) ([]byte, error) {
+	b.RLock()
+	defer b.RUnlock()
+
+	data, ok := b.objects[collection][name]
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	out := make([]byte, len(data))
+	copy(out, data)
+
+	return out, nil
+}
+
+func (b *MemoryBackend) Write(collection Collection, name string, obj Object) error {
+	b.Lock()
+	defer b.Unlock()
+
+	if _, ok := b.objects[collection]; !ok {
+		b.objects[collection] = map[string][]byte{}
+	}
+
+	data := make([]byte, len(obj.Body))
+	copy(data, obj.Body)
+	b.objects[collection][name] = data
+
+	return nil
+}
+
+func (b *MemoryBackend) Delete(collection Collection, name string) error {
+	b.Lock()
+	defer b.Unlock()
+
+	if _, ok := b.objects[collection][name]; !ok {
+		return ErrNotFound
+	}
+
+	delete(b.objects[collection], name)
+
+	return nil
+}
+
+func (b *MemoryBackend) List(collection Collection, prefix string) ([]string, error) {
+	b.RLock()
+	defer b.RUnlock()
+
+	out := []string{}
+	for name := range b.objects[collection] {
+		if !strings.HasPrefix(name, prefix) {
+			continue
+		}
+
+		out = append(out, name)
+	}
+	sort.Strings(out)
+
+	return out, nil
+}
@@ -0,0 +1,53 @@
+package storage
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/sotah"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/util"
+)
+
+func (s backendStore) pricelistHistoriesObjectName(realm sotah.Realm, targetDate time.Time) string {
+	return fmt.Sprintf("%s/%d.txt.gz", s.realmPrefix(realm), sotah.NormalizeTargetDate(targetDate).Unix())
+}
+
+func (s backendStore) WritePricelistHistories(
+	realm sotah.Realm,
+	targetDate time.Time,
+	ipHistories sotah.ItemPriceHistories,
+) error {
+	gzipEncoded, err := ipHistories.EncodeForPersistence()
+	if err != nil {
+		return err
+	}
+
+	return s.backend.Write(PricelistHistories, s.pricelistHistoriesObjectName(realm, targetDate), Object{
+		Body:        gzipEncoded,
+		ContentType: "text/plain",
+		Gzipped:     true,
+	})
+}
+
+func (s backendStore) GetPricelistHistories(realm sotah.Realm, targetDate time.Time) (sotah.ItemPriceHistories, error) {
+	gzipEncoded, err := s.backend.Read(PricelistHistories, s.pricelistHistoriesObjectName(realm, targetDate))
+	if err != nil {
+		if err == ErrNotFound {
+			return sotah.ItemPriceHistories{}, nil
+		}
+
+		return sotah.ItemPriceHistories{}, err
+	}
+
+	csvEncoded, err := util.GzipDecode(gzipEncoded)
+	if err != nil {
+		return sotah.ItemPriceHistories{}, err
+	}
+
+	return sotah.NewItemPriceHistoriesFromMinimized(bytes.NewReader(csvEncoded))
+}
+
+func (s backendStore) GetPricelistHistoriesTimestamps(realm sotah.Realm) ([]sotah.UnixTimestamp, error) {
+	return s.listTimestamps(PricelistHistories, s.realmPrefix(realm)+"/")
+}
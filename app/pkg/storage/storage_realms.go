@@ -0,0 +1,62 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/blizzard"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/sotah"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/util"
+)
+
+func (s backendStore) realmsObjectPrefix(regionName blizzard.RegionName) string {
+	return fmt.Sprintf("%s/%s", s.version, regionName)
+}
+
+func (s backendStore) WriteRealm(realm sotah.Realm) error {
+	jsonEncoded, err := json.Marshal(realm)
+	if err != nil {
+		return err
+	}
+
+	gzipEncoded, err := util.GzipEncode(jsonEncoded)
+	if err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("%s/%s.json.gz", s.realmsObjectPrefix(realm.Region.Name), realm.Slug)
+	return s.backend.Write(Realms, name, Object{
+		Body:        gzipEncoded,
+		ContentType: "application/json",
+		Gzipped:     true,
+	})
+}
+
+func (s backendStore) GetRealms(regionName blizzard.RegionName) (sotah.Realms, error) {
+	names, err := s.backend.List(Realms, s.realmsObjectPrefix(regionName)+"/")
+	if err != nil {
+		return sotah.Realms{}, err
+	}
+
+	out := sotah.Realms{}
+	for _, name := range names {
+		gzipEncoded, err := s.backend.Read(Realms, name)
+		if err != nil {
+			return sotah.Realms{}, err
+		}
+
+		jsonEncoded, err := util.GzipDecode(gzipEncoded)
+		if err != nil {
+			return sotah.Realms{}, err
+		}
+
+		var realm sotah.Realm
+		if err := json.Unmarshal(jsonEncoded, &realm); err != nil {
+			return sotah.Realms{}, err
+		}
+
+		out = append(out, realm)
+	}
+
+	return out, nil
+}
@@ -0,0 +1,60 @@
+package storage
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/blizzard"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/sotah"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/sotah/gameversions"
+)
+
+// Store covers every kind of object persisted by the pipeline, regardless of backend
+type Store interface {
+	// auctions
+	WriteAuctions(realm sotah.Realm, lastModified time.Time, aucs blizzard.Auctions) error
+	GetAuctions(realm sotah.Realm, lastModified time.Time) (blizzard.Auctions, error)
+	GetLatestAuctions(realm sotah.Realm) (blizzard.Auctions, time.Time, error)
+	GetAuctionsTimestamps(realm sotah.Realm) ([]sotah.UnixTimestamp, error)
+	DeleteAuctions(realm sotah.Realm, lastModified time.Time) error
+
+	// auction manifests
+	GetAuctionManifest(realm sotah.Realm, targetTimestamp sotah.UnixTimestamp) (sotah.AuctionManifest, error)
+	AppendAuctionManifest(realm sotah.Realm, targetTimestamp sotah.UnixTimestamp) error
+
+	// items
+	WriteItem(item sotah.Item) error
+	GetItem(id blizzard.ItemID) (sotah.Item, bool, error)
+	GetItems(ids blizzard.ItemIds) chan GetItemsJob
+
+	// item icons
+	WriteItemIcon(name string, body []byte) error
+	GetItemIcon(name string) ([]byte, bool, error)
+
+	// realms
+	WriteRealm(realm sotah.Realm) error
+	GetRealms(regionName blizzard.RegionName) (sotah.Realms, error)
+
+	// pricelist histories
+	WritePricelistHistories(realm sotah.Realm, targetDate time.Time, ipHistories sotah.ItemPriceHistories) error
+	GetPricelistHistories(realm sotah.Realm, targetDate time.Time) (sotah.ItemPriceHistories, error)
+	GetPricelistHistoriesTimestamps(realm sotah.Realm) ([]sotah.UnixTimestamp, error)
+}
+
+func NewStore(backend Backend, version gameversions.GameVersion) Store {
+	if len(version) == 0 {
+		version = gameversions.Retail
+	}
+
+	return backendStore{backend: backend, version: version}
+}
+
+// backendStore lays objects out with the same names used by the gcloud store bases
+type backendStore struct {
+	backend Backend
+	version gameversions.GameVersion
+}
+
+func (s backendStore) realmPrefix(realm sotah.Realm) string {
+	return fmt.Sprintf("%s/%s/%s", s.version, realm.Region.Name, realm.Slug)
+}
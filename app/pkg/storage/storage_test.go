@@ -0,0 +1,302 @@
+package storage
+
+import (
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/blizzard"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/sotah"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/sotah/gameversions"
+)
+
+// forEachBackend runs the same checks against every backend that works without a gcloud project
+func forEachBackend(t *testing.T, check func(t *testing.T, backend Backend)) {
+	t.Run(string(Disk), func(t *testing.T) {
+		dirPath, err := ioutil.TempDir("", "storage")
+		if err != nil {
+			t.Fatalf("could not create temp dir: %s", err)
+		}
+		defer os.RemoveAll(dirPath)
+
+		backend, err := NewBackend(BackendConfig{Kind: Disk, CacheDir: dirPath})
+		if err != nil {
+			t.Fatalf("could not create disk backend: %s", err)
+		}
+
+		check(t, backend)
+	})
+
+	t.Run(string(Memory), func(t *testing.T) {
+		backend, err := NewBackend(BackendConfig{Kind: Memory})
+		if err != nil {
+			t.Fatalf("could not create memory backend: %s", err)
+		}
+
+		check(t, backend)
+	})
+}
+
+func forEachStore(t *testing.T, check func(t *testing.T, stor Store)) {
+	forEachBackend(t, func(t *testing.T, backend Backend) {
+		check(t, NewStore(backend, gameversions.Retail))
+	})
+}
+
+func newTestRealm(regionName blizzard.RegionName, realmSlug blizzard.RealmSlug) sotah.Realm {
+	return sotah.Realm{
+		Realm:  blizzard.Realm{Slug: realmSlug, Name: string(realmSlug)},
+		Region: sotah.Region{Name: regionName},
+	}
+}
+
+func TestResolveKind(t *testing.T) {
+	if kind := ResolveKind("", false); kind != Disk {
+		t.Fatalf("expected disk by default, got %s", kind)
+	}
+	if kind := ResolveKind("", true); kind != GCloud {
+		t.Fatalf("expected gcloud when using gcloud, got %s", kind)
+	}
+	if kind := ResolveKind(Memory, true); kind != Memory {
+		t.Fatalf("expected a provided kind to win, got %s", kind)
+	}
+
+	if _, err := NewBackend(BackendConfig{Kind: "nope"}); err == nil {
+		t.Fatal("expected an invalid kind to fail")
+	}
+	if _, err := NewDiskBackend(""); err == nil {
+		t.Fatal("expected a disk backend without a dir to fail")
+	}
+}
+
+func TestBackendObjects(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, backend Backend) {
+		if _, err := backend.Read(Items, "missing"); err != ErrNotFound {
+			t.Fatalf("expected a missing object to not be found, got %v", err)
+		}
+		if err := backend.Delete(Items, "missing"); err != ErrNotFound {
+			t.Fatalf("expected deleting a missing object to not be found, got %v", err)
+		}
+		if names, err := backend.List(Items, ""); err != nil || len(names) != 0 {
+			t.Fatalf("expected an empty collection to list nothing, got %v %v", names, err)
+		}
+
+		for _, name := range []string{"a/1.txt", "a/2.txt", "a/b/3.txt", "ab/4.txt"} {
+			if err := backend.Write(Items, name, Object{Body: []byte(name)}); err != nil {
+				t.Fatalf("could not write %s: %s", name, err)
+			}
+		}
+
+		// objects in other collections are kept apart
+		if err := backend.Write(Realms, "a/5.txt", Object{Body: []byte("5")}); err != nil {
+			t.Fatalf("could not write: %s", err)
+		}
+
+		data, err := backend.Read(Items, "a/b/3.txt")
+		if err != nil || string(data) != "a/b/3.txt" {
+			t.Fatalf("unexpected object: %s %v", data, err)
+		}
+
+		names, err := backend.List(Items, "a/")
+		if err != nil {
+			t.Fatalf("could not list: %s", err)
+		}
+		if !reflect.DeepEqual(names, []string{"a/1.txt", "a/2.txt", "a/b/3.txt"}) {
+			t.Fatalf("unexpected names under prefix: %v", names)
+		}
+
+		if err := backend.Delete(Items, "a/1.txt"); err != nil {
+			t.Fatalf("could not delete: %s", err)
+		}
+		if _, err := backend.Read(Items, "a/1.txt"); err != ErrNotFound {
+			t.Fatalf("expected a deleted object to not be found, got %v", err)
+		}
+	})
+}
+
+func TestStoreAuctions(t *testing.T) {
+	forEachStore(t, func(t *testing.T, stor Store) {
+		rea := newTestRealm("us", "earthen-ring")
+
+		if _, _, err := stor.GetLatestAuctions(rea); err != ErrNotFound {
+			t.Fatalf("expected no latest auctions, got %v", err)
+		}
+
+		for i, lastModified := range []time.Time{time.Unix(200, 0), time.Unix(100, 0)} {
+			aucs := blizzard.Auctions{Auctions: []blizzard.Auction{{Auc: int64(i), Item: 1, Quantity: 1}}}
+			if err := stor.WriteAuctions(rea, lastModified, aucs); err != nil {
+				t.Fatalf("could not write auctions: %s", err)
+			}
+		}
+
+		timestamps, err := stor.GetAuctionsTimestamps(rea)
+		if err != nil {
+			t.Fatalf("could not get timestamps: %s", err)
+		}
+		if !reflect.DeepEqual(timestamps, []sotah.UnixTimestamp{100, 200}) {
+			t.Fatalf("expected sorted timestamps, got %v", timestamps)
+		}
+
+		aucs, lastModified, err := stor.GetLatestAuctions(rea)
+		if err != nil {
+			t.Fatalf("could not get latest auctions: %s", err)
+		}
+		if lastModified.Unix() != 200 || len(aucs.Auctions) != 1 || aucs.Auctions[0].Auc != 0 {
+			t.Fatalf("unexpected latest auctions at %d: %+v", lastModified.Unix(), aucs)
+		}
+
+		// realms do not see each other's auctions
+		if timestamps, err := stor.GetAuctionsTimestamps(newTestRealm("us", "earthen")); err != nil || len(timestamps) != 0 {
+			t.Fatalf("expected no timestamps for another realm, got %v %v", timestamps, err)
+		}
+
+		if err := stor.DeleteAuctions(rea, time.Unix(200, 0)); err != nil {
+			t.Fatalf("could not delete auctions: %s", err)
+		}
+		if _, lastModified, err := stor.GetLatestAuctions(rea); err != nil || lastModified.Unix() != 100 {
+			t.Fatalf("expected the earlier auctions to be latest, got %d %v", lastModified.Unix(), err)
+		}
+	})
+}
+
+func TestStoreAuctionManifest(t *testing.T) {
+	forEachStore(t, func(t *testing.T, stor Store) {
+		rea := newTestRealm("us", "earthen-ring")
+		targetDate := sotah.NormalizeTargetDate(time.Unix(1500000000, 0))
+		first := sotah.UnixTimestamp(targetDate.Add(time.Hour).Unix())
+		second := sotah.UnixTimestamp(targetDate.Add(2 * time.Hour).Unix())
+
+		if manifest, err := stor.GetAuctionManifest(rea, first); err != nil || len(manifest) != 0 {
+			t.Fatalf("expected an empty manifest, got %v %v", manifest, err)
+		}
+
+		for _, targetTimestamp := range []sotah.UnixTimestamp{first, second} {
+			if err := stor.AppendAuctionManifest(rea, targetTimestamp); err != nil {
+				t.Fatalf("could not append to manifest: %s", err)
+			}
+		}
+
+		// both timestamps fall on the same day's manifest
+		manifest, err := stor.GetAuctionManifest(rea, second)
+		if err != nil {
+			t.Fatalf("could not get manifest: %s", err)
+		}
+		if len(manifest) != 2 {
+			t.Fatalf("expected both timestamps in the manifest, got %v", manifest)
+		}
+	})
+}
+
+func TestStoreItems(t *testing.T) {
+	forEachStore(t, func(t *testing.T, stor Store) {
+		item := sotah.Item{Item: blizzard.Item{ID: 1, Name: "Copper Ore", NormalizedName: "copper-ore"}}
+		if err := stor.WriteItem(item); err != nil {
+			t.Fatalf("could not write item: %s", err)
+		}
+
+		if found, exists, err := stor.GetItem(1); err != nil || !exists || found.Name != "Copper Ore" {
+			t.Fatalf("unexpected item: %+v %v %v", found, exists, err)
+		}
+		if _, exists, err := stor.GetItem(2); err != nil || exists {
+			t.Fatalf("expected a missing item to not exist, got %v %v", exists, err)
+		}
+
+		results := map[blizzard.ItemID]bool{}
+		for job := range stor.GetItems(blizzard.ItemIds{1, 2}) {
+			if job.Err != nil {
+				t.Fatalf("could not get items: %s", job.Err)
+			}
+
+			results[job.ID] = job.Exists
+		}
+		if !reflect.DeepEqual(results, map[blizzard.ItemID]bool{1: true, 2: false}) {
+			t.Fatalf("unexpected items: %v", results)
+		}
+	})
+}
+
+func TestStoreItemIcons(t *testing.T) {
+	forEachStore(t, func(t *testing.T, stor Store) {
+		if _, exists, err := stor.GetItemIcon("inv_ore_copper_01"); err != nil || exists {
+			t.Fatalf("expected a missing icon to not exist, got %v %v", exists, err)
+		}
+
+		if err := stor.WriteItemIcon("inv_ore_copper_01", []byte("jpeg")); err != nil {
+			t.Fatalf("could not write icon: %s", err)
+		}
+
+		body, exists, err := stor.GetItemIcon("inv_ore_copper_01")
+		if err != nil || !exists || string(body) != "jpeg" {
+			t.Fatalf("unexpected icon: %s %v %v", body, exists, err)
+		}
+	})
+}
+
+func TestStoreRealms(t *testing.T) {
+	forEachStore(t, func(t *testing.T, stor Store) {
+		for _, rea := range []sotah.Realm{
+			newTestRealm("us", "earthen-ring"),
+			newTestRealm("us", "aegwynn"),
+			newTestRealm("eu", "silvermoon"),
+		} {
+			if err := stor.WriteRealm(rea); err != nil {
+				t.Fatalf("could not write realm: %s", err)
+			}
+		}
+
+		realms, err := stor.GetRealms("us")
+		if err != nil {
+			t.Fatalf("could not get realms: %s", err)
+		}
+		if len(realms) != 2 {
+			t.Fatalf("expected only the region's realms, got %+v", realms)
+		}
+		for _, rea := range realms {
+			if rea.Region.Name != "us" {
+				t.Fatalf("unexpected realm: %+v", rea)
+			}
+		}
+
+		if realms, err := stor.GetRealms("kr"); err != nil || len(realms) != 0 {
+			t.Fatalf("expected no realms for an unknown region, got %+v %v", realms, err)
+		}
+	})
+}
+
+func TestStorePricelistHistories(t *testing.T) {
+	forEachStore(t, func(t *testing.T, stor Store) {
+		rea := newTestRealm("us", "earthen-ring")
+		targetDate := time.Unix(1500000000, 0)
+
+		if ipHistories, err := stor.GetPricelistHistories(rea, targetDate); err != nil || len(ipHistories) != 0 {
+			t.Fatalf("expected no pricelist-histories, got %v %v", ipHistories, err)
+		}
+
+		ipHistories := sotah.ItemPriceHistories{
+			1: sotah.PriceHistory{1500000000: sotah.Prices{MinBuyoutPer: 10, Volume: 2}},
+		}
+		if err := stor.WritePricelistHistories(rea, targetDate, ipHistories); err != nil {
+			t.Fatalf("could not write pricelist-histories: %s", err)
+		}
+
+		// histories are keyed by their normalized target date
+		found, err := stor.GetPricelistHistories(rea, targetDate.Add(time.Hour))
+		if err != nil {
+			t.Fatalf("could not get pricelist-histories: %s", err)
+		}
+		if !reflect.DeepEqual(found, ipHistories) {
+			t.Fatalf("unexpected pricelist-histories: %+v", found)
+		}
+
+		timestamps, err := stor.GetPricelistHistoriesTimestamps(rea)
+		if err != nil {
+			t.Fatalf("could not get timestamps: %s", err)
+		}
+		expected := []sotah.UnixTimestamp{sotah.UnixTimestamp(sotah.NormalizeTargetDate(targetDate).Unix())}
+		if !reflect.DeepEqual(timestamps, expected) {
+			t.Fatalf("unexpected timestamps: %v", timestamps)
+		}
+	})
+}
@@ -0,0 +1,67 @@
+package transport
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/bus"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/messenger"
+)
+
+// Kind - typehint for these enums
+type Kind string
+
+/*
+Kinds - available bus transports
+*/
+const (
+	Pubsub    Kind = "pubsub"
+	Nats      Kind = "nats"
+	InProcess Kind = "inprocess"
+)
+
+// ErrTopicNotFound is returned when a topic is required to exist but does not
+var ErrTopicNotFound = errors.New("topic does not exist")
+
+// Subscription is an open subscription on a topic
+type Subscription interface {
+	Unsubscribe() error
+}
+
+// Transport moves raw message payloads between named topics
+type Transport interface {
+	CreateTopic(id string) error
+	DeleteTopic(id string) error
+	TopicExists(id string) (bool, error)
+	Publish(topicID string, data []byte) error
+	Subscribe(topicID string, onMessage func(data []byte)) (Subscription, error)
+}
+
+type Config struct {
+	Kind Kind
+
+	// pubsub transport
+	GCloudProjectID string
+	SubscriberID    string
+
+	// nats transport
+	Messenger messenger.Messenger
+}
+
+func NewTransport(config Config) (Transport, error) {
+	switch config.Kind {
+	case Pubsub:
+		busClient, err := bus.NewClient(config.GCloudProjectID, config.SubscriberID)
+		if err != nil {
+			return nil, err
+		}
+
+		return NewPubsubTransport(busClient), nil
+	case Nats:
+		return NewNatsTransport(config.Messenger), nil
+	case InProcess:
+		return NewInProcessTransport(), nil
+	default:
+		return nil, fmt.Errorf("invalid transport kind: %s", config.Kind)
+	}
+}
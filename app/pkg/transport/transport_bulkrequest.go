@@ -0,0 +1,109 @@
+package transport
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/bus"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/logging"
+	"github.com/twinj/uuid"
+)
+
+func (c Client) BulkRequest(
+	topicID string,
+	messages []bus.Message,
+	timeout time.Duration,
+) (bus.BulkRequestMessages, error) {
+	// producing a topic to receive responses
+	logging.Info("Producing a topic to receive responses")
+	recipientTopicID := fmt.Sprintf("bulk-request-%s", uuid.NewV4().String())
+	if err := c.transport.CreateTopic(recipientTopicID); err != nil {
+		return bus.BulkRequestMessages{}, err
+	}
+
+	// updating messages with reply-to topic
+	for i, msg := range messages {
+		msg.ReplyTo = recipientTopicID
+		messages[i] = msg
+	}
+
+	// producing a blank list of message responses
+	responses := bus.MessageResponses{
+		Mutex: &sync.Mutex{},
+		Items: bus.NewBulkRequestMessages(messages),
+	}
+
+	// opening a listener
+	logging.Info("Opening a listener and waiting for it to finish opening")
+	onComplete := make(chan interface{}, 1)
+	receiveConfig := SubscribeConfig{
+		OnReady:   make(chan interface{}),
+		Stop:      make(chan interface{}),
+		OnStopped: make(chan interface{}),
+		Callback: func(busMsg bus.Message) {
+			responses.Mutex.Lock()
+			defer responses.Mutex.Unlock()
+			responses.Items[busMsg.ReplyToId] = busMsg
+
+			if !responses.IsComplete() {
+				return
+			}
+
+			select {
+			case onComplete <- struct{}{}:
+			default:
+			}
+		},
+	}
+	onSubscribeErr := make(chan error, 1)
+	go func() {
+		if err := c.SubscribeToTopic(recipientTopicID, receiveConfig); err != nil {
+			onSubscribeErr <- err
+		}
+	}()
+	select {
+	case <-receiveConfig.OnReady:
+	case err := <-onSubscribeErr:
+		return bus.BulkRequestMessages{}, err
+	}
+
+	// bulk publishing
+	logging.Info("Bulk publishing")
+	startTime := time.Now()
+	for outJob := range c.BulkPublish(topicID, messages) {
+		if outJob.Err != nil {
+			return bus.BulkRequestMessages{}, outJob.Err
+		}
+	}
+
+	// waiting for responses is complete or timer runs out
+	logging.Info("Waiting for responses to complete or timer runs out")
+	select {
+	case <-time.After(timeout):
+		logging.Info("Timer timed out, going over results in allotted time")
+	case <-onComplete:
+		logging.Info("Received all responses, going over all responses")
+	}
+	responses.Mutex.Lock()
+	responseItems := responses.FilterInCompleted()
+	responses.Mutex.Unlock()
+	duration := time.Since(startTime)
+
+	// stopping the receiver
+	logging.WithFields(
+		logrus.Fields{
+			"duration":  int(duration.Seconds()),
+			"responses": len(responseItems),
+		},
+	).Info("Finished receiving responses, stopping the listener and waiting for it to stop")
+	receiveConfig.Stop <- struct{}{}
+	<-receiveConfig.OnStopped
+
+	if err := c.transport.DeleteTopic(recipientTopicID); err != nil {
+		return bus.BulkRequestMessages{}, err
+	}
+
+	return responseItems, nil
+}
@@ -0,0 +1,242 @@
+package transport
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/bus"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/bus/codes"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/logging"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/metric"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/state/subjects"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/util"
+	"github.com/twinj/uuid"
+)
+
+func NewClient(t Transport) Client {
+	return Client{transport: t}
+}
+
+// Client mirrors the bus client api on top of any transport
+type Client struct {
+	transport Transport
+}
+
+func (c Client) CreateTopic(id string) error {
+	return c.transport.CreateTopic(id)
+}
+
+func (c Client) DeleteTopic(id string) error {
+	return c.transport.DeleteTopic(id)
+}
+
+func (c Client) FirmTopic(id string) error {
+	exists, err := c.transport.TopicExists(id)
+	if err != nil {
+		return err
+	}
+
+	if !exists {
+		return ErrTopicNotFound
+	}
+
+	return nil
+}
+
+func (c Client) ResolveTopic(id string) error {
+	exists, err := c.transport.TopicExists(id)
+	if err != nil {
+		return err
+	}
+
+	if exists {
+		return nil
+	}
+
+	return c.transport.CreateTopic(id)
+}
+
+func (c Client) Publish(topicID string, msg bus.Message) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	return c.transport.Publish(topicID, data)
+}
+
+func (c Client) BulkPublish(topicID string, messages []bus.Message) chan bus.BulkPublishOutJob {
+	// opening workers and channels
+	in := make(chan bus.Message)
+	out := make(chan bus.BulkPublishOutJob)
+	worker := func() {
+		for msg := range in {
+			out <- bus.BulkPublishOutJob{
+				Err: c.Publish(topicID, msg),
+				Msg: msg,
+			}
+		}
+	}
+	postWork := func() {
+		close(out)
+	}
+	util.Work(32, worker, postWork)
+
+	// queueing it up
+	go func() {
+		for _, msg := range messages {
+			in <- msg
+		}
+
+		close(in)
+	}()
+
+	return out
+}
+
+type SubscribeConfig struct {
+	Stop      chan interface{}
+	OnReady   chan interface{}
+	OnStopped chan interface{}
+	Callback  func(bus.Message)
+}
+
+// SubscribeToTopic blocks until the stop channel is signalled
+func (c Client) SubscribeToTopic(id string, config SubscribeConfig) error {
+	if err := c.ResolveTopic(id); err != nil {
+		return err
+	}
+
+	entry := logging.WithField("topic", id)
+	sub, err := c.transport.Subscribe(id, func(data []byte) {
+		var msg bus.Message
+		if err := json.Unmarshal(data, &msg); err != nil {
+			entry.WithField("error", err.Error()).Error("Failed to parse message")
+
+			return
+		}
+
+		config.Callback(msg)
+	})
+	if err != nil {
+		return err
+	}
+
+	config.OnReady <- struct{}{}
+
+	entry.Info("Waiting for messages")
+	<-config.Stop
+
+	if err := sub.Unsubscribe(); err != nil {
+		return err
+	}
+
+	config.OnStopped <- struct{}{}
+
+	return nil
+}
+
+func (c Client) ReplyToWithError(recipient bus.Message, err error, code codes.Code) error {
+	reply := bus.NewMessage()
+	reply.Code = code
+	reply.Err = err.Error()
+
+	return c.ReplyTo(recipient, reply)
+}
+
+func (c Client) ReplyTo(target bus.Message, payload bus.Message) error {
+	if target.ReplyTo == "" {
+		return errors.New("cannot reply to blank reply-to topic name")
+	}
+
+	// validating topic already exists
+	if err := c.FirmTopic(target.ReplyTo); err != nil {
+		return err
+	}
+
+	logging.WithField("reply-to-topic", target.ReplyTo).Info("Replying to topic")
+
+	if len(payload.ReplyToId) == 0 {
+		payload.ReplyToId = target.ReplyToId
+	}
+
+	return c.Publish(target.ReplyTo, payload)
+}
+
+func (c Client) Request(topicID string, payload string, timeout time.Duration) (bus.Message, error) {
+	if err := c.FirmTopic(topicID); err != nil {
+		return bus.Message{}, err
+	}
+
+	// producing a reply-to topic
+	replyToTopicID := fmt.Sprintf("reply-to-%s", uuid.NewV4().String())
+	if err := c.transport.CreateTopic(replyToTopicID); err != nil {
+		return bus.Message{}, err
+	}
+	defer func() {
+		if err := c.transport.DeleteTopic(replyToTopicID); err != nil {
+			logging.WithFields(logrus.Fields{
+				"error": err.Error(),
+				"topic": replyToTopicID,
+			}).Error("Failed to delete reply-to topic")
+		}
+	}()
+
+	// waiting for a response on the reply-to topic
+	receiver := make(chan bus.Message, 1)
+	sub, err := c.transport.Subscribe(replyToTopicID, func(data []byte) {
+		var msg bus.Message
+		if err := json.Unmarshal(data, &msg); err != nil {
+			logging.WithField("error", err.Error()).Error("Failed to parse reply")
+
+			return
+		}
+
+		select {
+		case receiver <- msg:
+		default:
+		}
+	})
+	if err != nil {
+		return bus.Message{}, err
+	}
+	defer func() {
+		if err := sub.Unsubscribe(); err != nil {
+			logging.WithField("error", err.Error()).Error("Failed to unsubscribe from reply-to topic")
+		}
+	}()
+
+	// publishing the payload to the recipient topic
+	msg := bus.NewMessage()
+	msg.Data = payload
+	msg.ReplyTo = replyToTopicID
+	if err := c.Publish(topicID, msg); err != nil {
+		return bus.Message{}, err
+	}
+
+	select {
+	case result := <-receiver:
+		return result, nil
+	case <-time.After(timeout):
+		return bus.Message{}, errors.New("timed out")
+	}
+}
+
+func (c Client) PublishMetrics(m metric.Metrics) error {
+	if err := c.FirmTopic(string(subjects.AppMetrics)); err != nil {
+		return err
+	}
+
+	jsonEncoded, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+
+	msg := bus.NewMessage()
+	msg.Data = string(jsonEncoded)
+
+	return c.Publish(string(subjects.AppMetrics), msg)
+}
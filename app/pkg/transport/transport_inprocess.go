@@ -0,0 +1,174 @@
+package transport
+
+import (
+	"sync"
+)
+
+func NewInProcessTransport() *InProcessTransport {
+	return &InProcessTransport{topics: map[string]map[int]*inProcessSubscription{}}
+}
+
+// InProcessTransport delivers messages between subscribers within a single process
+type InProcessTransport struct {
+	sync.Mutex
+
+	topics map[string]map[int]*inProcessSubscription
+	nextID int
+}
+
+func (t *InProcessTransport) CreateTopic(id string) error {
+	t.Lock()
+	defer t.Unlock()
+
+	if _, ok := t.topics[id]; !ok {
+		t.topics[id] = map[int]*inProcessSubscription{}
+	}
+
+	return nil
+}
+
+func (t *InProcessTransport) DeleteTopic(id string) error {
+	t.Lock()
+	defer t.Unlock()
+
+	subs, ok := t.topics[id]
+	if !ok {
+		return ErrTopicNotFound
+	}
+
+	for _, sub := range subs {
+		sub.close()
+	}
+	delete(t.topics, id)
+
+	return nil
+}
+
+func (t *InProcessTransport) TopicExists(id string) (bool, error) {
+	t.Lock()
+	defer t.Unlock()
+
+	_, ok := t.topics[id]
+
+	return ok, nil
+}
+
+func (t *InProcessTransport) Publish(topicID string, data []byte) error {
+	t.Lock()
+	defer t.Unlock()
+
+	subs, ok := t.topics[topicID]
+	if !ok {
+		return ErrTopicNotFound
+	}
+
+	for _, sub := range subs {
+		sub.deliver(data)
+	}
+
+	return nil
+}
+
+func (t *InProcessTransport) Subscribe(topicID string, onMessage func(data []byte)) (Subscription, error) {
+	t.Lock()
+	defer t.Unlock()
+
+	subs, ok := t.topics[topicID]
+	if !ok {
+		return nil, ErrTopicNotFound
+	}
+
+	t.nextID++
+	sub := &inProcessSubscription{
+		id:        t.nextID,
+		topicID:   topicID,
+		transport: t,
+		notify:    make(chan struct{}, 1),
+		done:      make(chan struct{}),
+	}
+	subs[sub.id] = sub
+
+	// delivering messages in order on a dedicated worker
+	go func() {
+		for {
+			select {
+			case <-sub.notify:
+				for _, data := range sub.drain() {
+					onMessage(data)
+				}
+			case <-sub.done:
+				return
+			}
+		}
+	}()
+
+	return sub, nil
+}
+
+type inProcessSubscription struct {
+	sync.Mutex
+
+	id        int
+	topicID   string
+	transport *InProcessTransport
+
+	queue  [][]byte
+	notify chan struct{}
+	done   chan struct{}
+	closed bool
+}
+
+func (s *inProcessSubscription) deliver(data []byte) {
+	s.Lock()
+	if s.closed {
+		s.Unlock()
+
+		return
+	}
+
+	// copying so that subscribers cannot observe each other's mutations
+	payload := make([]byte, len(data))
+	copy(payload, data)
+	s.queue = append(s.queue, payload)
+	s.Unlock()
+
+	// waking up the worker without blocking the publisher
+	select {
+	case s.notify <- struct{}{}:
+	default:
+	}
+}
+
+func (s *inProcessSubscription) drain() [][]byte {
+	s.Lock()
+	defer s.Unlock()
+
+	out := s.queue
+	s.queue = nil
+
+	return out
+}
+
+func (s *inProcessSubscription) close() {
+	s.Lock()
+	defer s.Unlock()
+
+	if s.closed {
+		return
+	}
+
+	s.closed = true
+	close(s.done)
+}
+
+func (s *inProcessSubscription) Unsubscribe() error {
+	s.transport.Lock()
+	if subs, ok := s.transport.topics[s.topicID]; ok {
+		delete(subs, s.id)
+	}
+	s.transport.Unlock()
+
+	s.close()
+
+	return nil
+}
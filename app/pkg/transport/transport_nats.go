@@ -0,0 +1,60 @@
+package transport
+
+import (
+	"fmt"
+
+	nats "github.com/nats-io/go-nats"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/messenger"
+)
+
+func NewNatsTransport(mess messenger.Messenger) NatsTransport {
+	return NatsTransport{messenger: mess}
+}
+
+// NatsTransport sends messages over nats, where topics are implicit subjects
+type NatsTransport struct {
+	messenger messenger.Messenger
+}
+
+// subject namespaces bus topics apart from the request/reply subjects served over the same connection
+func (t NatsTransport) subject(topicID string) string {
+	return fmt.Sprintf("bus.%s", topicID)
+}
+
+func (t NatsTransport) CreateTopic(id string) error {
+	return nil
+}
+
+func (t NatsTransport) DeleteTopic(id string) error {
+	return nil
+}
+
+func (t NatsTransport) TopicExists(id string) (bool, error) {
+	return true, nil
+}
+
+func (t NatsTransport) Publish(topicID string, data []byte) error {
+	return t.messenger.Publish(t.subject(topicID), data)
+}
+
+func (t NatsTransport) Subscribe(topicID string, onMessage func(data []byte)) (Subscription, error) {
+	stop := make(chan interface{})
+	err := t.messenger.Subscribe(t.subject(topicID), stop, func(natsMsg nats.Msg) {
+		onMessage(natsMsg.Data)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return natsSubscription{stop: stop}, nil
+}
+
+type natsSubscription struct {
+	stop chan interface{}
+}
+
+func (s natsSubscription) Unsubscribe() error {
+	close(s.stop)
+
+	return nil
+}
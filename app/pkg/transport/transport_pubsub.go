@@ -0,0 +1,96 @@
+package transport
+
+import (
+	"context"
+
+	"cloud.google.com/go/pubsub"
+	"github.com/sirupsen/logrus"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/bus"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/logging"
+)
+
+func NewPubsubTransport(c bus.Client) PubsubTransport {
+	return PubsubTransport{client: c, context: context.Background()}
+}
+
+// PubsubTransport sends messages through gcloud pubsub via the existing bus client
+type PubsubTransport struct {
+	client  bus.Client
+	context context.Context
+}
+
+func (t PubsubTransport) CreateTopic(id string) error {
+	_, err := t.client.CreateTopic(id)
+
+	return err
+}
+
+func (t PubsubTransport) DeleteTopic(id string) error {
+	return t.client.Topic(id).Delete(t.context)
+}
+
+func (t PubsubTransport) TopicExists(id string) (bool, error) {
+	return t.client.Topic(id).Exists(t.context)
+}
+
+func (t PubsubTransport) Publish(topicID string, data []byte) error {
+	topic, err := t.client.FirmTopic(topicID)
+	if err != nil {
+		return err
+	}
+
+	_, err = topic.Publish(t.context, &pubsub.Message{Data: data}).Get(t.context)
+
+	return err
+}
+
+func (t PubsubTransport) Subscribe(topicID string, onMessage func(data []byte)) (Subscription, error) {
+	topic, err := t.client.FirmTopic(topicID)
+	if err != nil {
+		return nil, err
+	}
+
+	sub, err := t.client.CreateSubscription(topic)
+	if err != nil {
+		return nil, err
+	}
+
+	cctx, cancel := context.WithCancel(t.context)
+	onStopped := make(chan interface{})
+	go func() {
+		defer close(onStopped)
+
+		err := sub.Receive(cctx, func(ctx context.Context, pubsubMsg *pubsub.Message) {
+			pubsubMsg.Ack()
+
+			onMessage(pubsubMsg.Data)
+		})
+		if err != nil && err != context.Canceled {
+			logging.WithFields(logrus.Fields{
+				"error":        err.Error(),
+				"subscription": sub.ID(),
+			}).Error("Failed to receive from subscription")
+		}
+	}()
+
+	return pubsubSubscription{
+		context:   t.context,
+		sub:       sub,
+		cancel:    cancel,
+		onStopped: onStopped,
+	}, nil
+}
+
+type pubsubSubscription struct {
+	context   context.Context
+	sub       *pubsub.Subscription
+	cancel    context.CancelFunc
+	onStopped chan interface{}
+}
+
+func (s pubsubSubscription) Unsubscribe() error {
+	s.cancel()
+	<-s.onStopped
+
+	return s.sub.Delete(s.context)
+}
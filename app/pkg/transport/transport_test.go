@@ -0,0 +1,264 @@
+package transport
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/bus"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/bus/codes"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/metric"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/state/subjects"
+)
+
+// subscribe opens a subscription on the client and returns once it is ready, along with a func to stop it
+func subscribe(t *testing.T, c Client, topicID string, callback func(bus.Message)) func() {
+	config := SubscribeConfig{
+		Stop:      make(chan interface{}),
+		OnReady:   make(chan interface{}),
+		OnStopped: make(chan interface{}),
+		Callback:  callback,
+	}
+	onErr := make(chan error, 1)
+	go func() {
+		if err := c.SubscribeToTopic(topicID, config); err != nil {
+			onErr <- err
+		}
+	}()
+
+	select {
+	case <-config.OnReady:
+	case err := <-onErr:
+		t.Fatalf("could not subscribe to %s: %s", topicID, err)
+	}
+
+	return func() {
+		config.Stop <- struct{}{}
+		<-config.OnStopped
+	}
+}
+
+func receive(t *testing.T, in chan []byte) []byte {
+	select {
+	case data := <-in:
+		return data
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for a message")
+	}
+
+	return nil
+}
+
+func TestInProcessTransportTopics(t *testing.T) {
+	tr := NewInProcessTransport()
+
+	if exists, err := tr.TopicExists("a"); err != nil || exists {
+		t.Fatalf("expected the topic to not exist, got %v %v", exists, err)
+	}
+	if err := tr.Publish("a", []byte("x")); err != ErrTopicNotFound {
+		t.Fatalf("expected publishing to a missing topic to fail, got %v", err)
+	}
+	if _, err := tr.Subscribe("a", func([]byte) {}); err != ErrTopicNotFound {
+		t.Fatalf("expected subscribing to a missing topic to fail, got %v", err)
+	}
+	if err := tr.DeleteTopic("a"); err != ErrTopicNotFound {
+		t.Fatalf("expected deleting a missing topic to fail, got %v", err)
+	}
+
+	if err := tr.CreateTopic("a"); err != nil {
+		t.Fatalf("could not create topic: %s", err)
+	}
+	if err := tr.CreateTopic("a"); err != nil {
+		t.Fatalf("expected creating an existing topic to be a no-op, got %s", err)
+	}
+	if exists, err := tr.TopicExists("a"); err != nil || !exists {
+		t.Fatalf("expected the topic to exist, got %v %v", exists, err)
+	}
+
+	if err := tr.DeleteTopic("a"); err != nil {
+		t.Fatalf("could not delete topic: %s", err)
+	}
+	if exists, _ := tr.TopicExists("a"); exists {
+		t.Fatal("expected the deleted topic to not exist")
+	}
+}
+
+func TestInProcessTransportDelivery(t *testing.T) {
+	tr := NewInProcessTransport()
+	if err := tr.CreateTopic("a"); err != nil {
+		t.Fatalf("could not create topic: %s", err)
+	}
+
+	first := make(chan []byte, 10)
+	second := make(chan []byte, 10)
+	firstSub, err := tr.Subscribe("a", func(data []byte) { first <- data })
+	if err != nil {
+		t.Fatalf("could not subscribe: %s", err)
+	}
+	if _, err := tr.Subscribe("a", func(data []byte) {
+		// mutating the payload must not reach the other subscriber
+		data[0] = 'z'
+		second <- data
+	}); err != nil {
+		t.Fatalf("could not subscribe: %s", err)
+	}
+
+	for _, payload := range []string{"1", "2", "3"} {
+		if err := tr.Publish("a", []byte(payload)); err != nil {
+			t.Fatalf("could not publish: %s", err)
+		}
+	}
+
+	// every subscriber receives each message in order
+	for _, expected := range []string{"1", "2", "3"} {
+		if data := receive(t, first); string(data) != expected {
+			t.Fatalf("expected %s, got %s", expected, data)
+		}
+		if data := receive(t, second); string(data) != "z" {
+			t.Fatalf("expected the mutated copy, got %s", data)
+		}
+	}
+
+	// unsubscribing stops delivery to that subscriber only
+	if err := firstSub.Unsubscribe(); err != nil {
+		t.Fatalf("could not unsubscribe: %s", err)
+	}
+	if err := tr.Publish("a", []byte("4")); err != nil {
+		t.Fatalf("could not publish: %s", err)
+	}
+	receive(t, second)
+	select {
+	case data := <-first:
+		t.Fatalf("expected no delivery after unsubscribing, got %s", data)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestClientFirmAndResolveTopic(t *testing.T) {
+	c := NewClient(NewInProcessTransport())
+
+	if err := c.FirmTopic("a"); err != ErrTopicNotFound {
+		t.Fatalf("expected a missing topic to not be firm, got %v", err)
+	}
+	if err := c.ResolveTopic("a"); err != nil {
+		t.Fatalf("could not resolve topic: %s", err)
+	}
+	if err := c.FirmTopic("a"); err != nil {
+		t.Fatalf("expected a resolved topic to be firm, got %s", err)
+	}
+}
+
+func TestClientRequestAndReply(t *testing.T) {
+	c := NewClient(NewInProcessTransport())
+
+	stop := subscribe(t, c, "echo", func(busMsg bus.Message) {
+		if busMsg.Data == "fail" {
+			if err := c.ReplyToWithError(busMsg, errors.New("failed"), codes.GenericError); err != nil {
+				t.Errorf("could not reply with error: %s", err)
+			}
+
+			return
+		}
+
+		reply := bus.NewMessage()
+		reply.Data = "echo:" + busMsg.Data
+		if err := c.ReplyTo(busMsg, reply); err != nil {
+			t.Errorf("could not reply: %s", err)
+		}
+	})
+	defer stop()
+
+	reply, err := c.Request("echo", "hello", time.Second)
+	if err != nil {
+		t.Fatalf("could not request: %s", err)
+	}
+	if reply.Data != "echo:hello" || reply.Code != codes.Ok {
+		t.Fatalf("unexpected reply: %+v", reply)
+	}
+
+	reply, err = c.Request("echo", "fail", time.Second)
+	if err != nil {
+		t.Fatalf("could not request: %s", err)
+	}
+	if reply.Code != codes.GenericError || reply.Err != "failed" {
+		t.Fatalf("expected an error reply, got %+v", reply)
+	}
+
+	if _, err := c.Request("missing", "hello", time.Second); err != ErrTopicNotFound {
+		t.Fatalf("expected requesting a missing topic to fail, got %v", err)
+	}
+	if err := c.ReplyTo(bus.NewMessage(), bus.NewMessage()); err == nil {
+		t.Fatal("expected replying without a reply-to topic to fail")
+	}
+}
+
+func TestClientRequestTimeout(t *testing.T) {
+	c := NewClient(NewInProcessTransport())
+
+	stop := subscribe(t, c, "silent", func(bus.Message) {})
+	defer stop()
+
+	if _, err := c.Request("silent", "hello", 10*time.Millisecond); err == nil {
+		t.Fatal("expected an unanswered request to time out")
+	}
+}
+
+func TestClientBulkRequest(t *testing.T) {
+	c := NewClient(NewInProcessTransport())
+
+	stop := subscribe(t, c, "intake", func(busMsg bus.Message) {
+		reply := bus.NewMessage()
+		reply.Data = busMsg.Data
+		if err := c.ReplyTo(busMsg, reply); err != nil {
+			t.Errorf("could not reply: %s", err)
+		}
+	})
+	defer stop()
+
+	messages := []bus.Message{}
+	for _, id := range []string{"a", "b", "c"} {
+		msg := bus.NewMessage()
+		msg.ReplyToId = id
+		msg.Data = "data-" + id
+		messages = append(messages, msg)
+	}
+
+	responses, err := c.BulkRequest("intake", messages, time.Second)
+	if err != nil {
+		t.Fatalf("could not bulk request: %s", err)
+	}
+	if len(responses) != 3 {
+		t.Fatalf("expected every message to be answered, got %+v", responses)
+	}
+	for _, id := range []string{"a", "b", "c"} {
+		if responses[id].Data != "data-"+id {
+			t.Fatalf("unexpected response for %s: %+v", id, responses[id])
+		}
+	}
+}
+
+func TestClientPublishMetrics(t *testing.T) {
+	c := NewClient(NewInProcessTransport())
+
+	if err := c.PublishMetrics(metric.Metrics{"a": 1}); err != ErrTopicNotFound {
+		t.Fatalf("expected publishing metrics without a metrics topic to fail, got %v", err)
+	}
+
+	received := make(chan bus.Message, 1)
+	stop := subscribe(t, c, string(subjects.AppMetrics), func(busMsg bus.Message) {
+		received <- busMsg
+	})
+	defer stop()
+
+	if err := c.PublishMetrics(metric.Metrics{"a": 1}); err != nil {
+		t.Fatalf("could not publish metrics: %s", err)
+	}
+	select {
+	case busMsg := <-received:
+		if busMsg.Data != `{"a":1}` {
+			t.Fatalf("unexpected metrics payload: %s", busMsg.Data)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for metrics")
+	}
+}
@@ -0,0 +1,49 @@
+package variants
+
+import (
+	"encoding/binary"
+	"fmt"
+	"time"
+
+	"github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/pricelists"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/blizzard"
+)
+
+// keying
+func variantPricesKeyName(key pricelists.VariantKey) []byte {
+	return []byte(key)
+}
+
+// variantPricesItemKeyPrefix prefixes the keys of every variant of an item
+func variantPricesItemKeyPrefix(ID blizzard.ItemID) []byte {
+	return []byte(fmt.Sprintf("%d:", ID))
+}
+
+func lastSnapshotKeyName() []byte {
+	return []byte("last-snapshot")
+}
+
+func snapshotTimeValue(targetTime time.Time) []byte {
+	value := make([]byte, 8)
+	binary.BigEndian.PutUint64(value, uint64(targetTime.Unix()))
+
+	return value
+}
+
+func snapshotTime(value []byte) time.Time {
+	return time.Unix(int64(binary.BigEndian.Uint64(value)), 0)
+}
+
+// bucketing
+func variantPricesBucketName() []byte {
+	return []byte("variant-prices")
+}
+
+func metaBucketName() []byte {
+	return []byte("meta")
+}
+
+// db
+func databaseDirPath(dirPath string) string {
+	return fmt.Sprintf("%s/variants", dirPath)
+}
@@ -0,0 +1,96 @@
+package variants
+
+import (
+	"bytes"
+	"encoding/json"
+	"time"
+
+	"github.com/boltdb/bolt"
+	"github.com/sirupsen/logrus"
+	"github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/pricelists"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/blizzard"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/logging"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/sotah"
+)
+
+// Database holds the current prices of each item variant of a realm, which the mini-auctions do not distinguish
+type Database struct {
+	db    *bolt.DB
+	realm sotah.Realm
+}
+
+// Persist replaces the variant prices with those of the snapshot
+func (vBase Database) Persist(targetTime time.Time, vPrices pricelists.VariantPrices) error {
+	logging.WithFields(logrus.Fields{
+		"db":       vBase.db.Path(),
+		"variants": len(vPrices),
+	}).Debug("Persisting variant prices")
+
+	return vBase.db.Update(func(tx *bolt.Tx) error {
+		metaBucket, err := tx.CreateBucketIfNotExists(metaBucketName())
+		if err != nil {
+			return err
+		}
+
+		// an out-of-order snapshot would replace current prices with older ones
+		if value := metaBucket.Get(lastSnapshotKeyName()); value != nil && !targetTime.After(snapshotTime(value)) {
+			return nil
+		}
+
+		if tx.Bucket(variantPricesBucketName()) != nil {
+			if err := tx.DeleteBucket(variantPricesBucketName()); err != nil {
+				return err
+			}
+		}
+
+		bkt, err := tx.CreateBucket(variantPricesBucketName())
+		if err != nil {
+			return err
+		}
+
+		for key, p := range vPrices {
+			encoded, err := json.Marshal(p)
+			if err != nil {
+				return err
+			}
+
+			if err := bkt.Put(variantPricesKeyName(key), encoded); err != nil {
+				return err
+			}
+		}
+
+		return metaBucket.Put(lastSnapshotKeyName(), snapshotTimeValue(targetTime))
+	})
+}
+
+// GetVariantPrices gathers the prices of every variant of the items
+func (vBase Database) GetVariantPrices(itemIds []blizzard.ItemID) (pricelists.VariantPrices, error) {
+	out := pricelists.VariantPrices{}
+
+	err := vBase.db.View(func(tx *bolt.Tx) error {
+		bkt := tx.Bucket(variantPricesBucketName())
+		if bkt == nil {
+			return nil
+		}
+
+		c := bkt.Cursor()
+		for _, itemId := range itemIds {
+			prefix := variantPricesItemKeyPrefix(itemId)
+			for k, v := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, v = c.Next() {
+				p := pricelists.Prices{}
+				if err := json.Unmarshal(v, &p); err != nil {
+					return err
+				}
+
+				out[pricelists.VariantKey(k)] = p
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return pricelists.VariantPrices{}, err
+	}
+
+	return out, nil
+}
@@ -0,0 +1,40 @@
+package variants
+
+import (
+	"time"
+
+	"github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/connectedrealms"
+	"github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/pricelists"
+	"github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/realmdb"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/blizzard"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/sotah"
+)
+
+func NewDatabases(dirPath string, stas sotah.Statuses, groups connectedrealms.Groups) (Databases, error) {
+	registry, err := realmdb.Open(databaseDirPath(dirPath), stas, groups)
+	if err != nil {
+		return Databases{}, err
+	}
+
+	vBases := Databases{}
+	for regionName, handles := range registry {
+		vBases[regionName] = map[blizzard.RealmSlug]Database{}
+		for realmSlug, handle := range handles {
+			vBases[regionName][realmSlug] = Database{handle.DB, handle.Realm}
+		}
+	}
+
+	return vBases, nil
+}
+
+type Databases map[blizzard.RegionName]map[blizzard.RealmSlug]Database
+
+// Record persists the variant prices of a snapshot's auctions
+func (vBases Databases) Record(rea sotah.Realm, targetTime time.Time, aucs blizzard.Auctions) error {
+	vBase, ok := vBases[rea.Region.Name][rea.Slug]
+	if !ok {
+		return nil
+	}
+
+	return vBase.Persist(targetTime, pricelists.NewVariantPrices(aucs))
+}
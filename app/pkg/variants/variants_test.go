@@ -0,0 +1,56 @@
+package variants
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/pricelists"
+	"github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/realmdb"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/blizzard"
+)
+
+func newTestDatabase(t *testing.T) (Database, func()) {
+	handle, cleanup := realmdb.OpenTemp(t, "variants")
+
+	return Database{handle.DB, handle.Realm}, cleanup
+}
+
+func TestDatabasePersistReplacesPrices(t *testing.T) {
+	vBase, cleanup := newTestDatabase(t)
+	defer cleanup()
+
+	first := pricelists.VariantPrices{
+		pricelists.NewVariantKey(1, 5, 0):  {MinBuyoutPer: 100},
+		pricelists.NewVariantKey(10, 5, 0): {MinBuyoutPer: 200},
+	}
+	if err := vBase.Persist(time.Unix(100, 0), first); err != nil {
+		t.Fatalf("could not persist: %s", err)
+	}
+
+	// the item prefix does not match items sharing leading digits
+	vPrices, err := vBase.GetVariantPrices([]blizzard.ItemID{1})
+	if err != nil {
+		t.Fatalf("could not get prices: %s", err)
+	}
+	if len(vPrices) != 1 || vPrices[pricelists.NewVariantKey(1, 5, 0)].MinBuyoutPer != 100 {
+		t.Fatalf("unexpected variant prices: %v", vPrices)
+	}
+
+	second := pricelists.VariantPrices{pricelists.NewVariantKey(1, 6, 0): {MinBuyoutPer: 50}}
+	if err := vBase.Persist(time.Unix(200, 0), second); err != nil {
+		t.Fatalf("could not persist: %s", err)
+	}
+
+	// out-of-order snapshots are skipped
+	if err := vBase.Persist(time.Unix(150, 0), first); err != nil {
+		t.Fatalf("could not persist: %s", err)
+	}
+
+	vPrices, err = vBase.GetVariantPrices([]blizzard.ItemID{1, 10})
+	if err != nil {
+		t.Fatalf("could not get prices: %s", err)
+	}
+	if len(vPrices) != 1 || vPrices[pricelists.NewVariantKey(1, 6, 0)].MinBuyoutPer != 50 {
+		t.Fatalf("expected only the latest snapshot's prices, got %v", vPrices)
+	}
+}
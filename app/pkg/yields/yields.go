@@ -0,0 +1,144 @@
+package yields
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/blizzard"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/logging"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/util"
+)
+
+// Method - typehint for these enums
+type Method string
+
+/*
+Methods - how an item is broken down into other items
+*/
+const (
+	Disenchant Method = "disenchant"
+	Mill       Method = "mill"
+	Prospect   Method = "prospect"
+)
+
+func (m Method) Validate() error {
+	switch m {
+	case Disenchant, Mill, Prospect:
+		return nil
+	default:
+		return fmt.Errorf("invalid method: %s", m)
+	}
+}
+
+type Outcome struct {
+	ItemID blizzard.ItemID `json:"item_id"`
+
+	// average number of items produced per breakdown
+	Quantity float64 `json:"quantity"`
+}
+
+// Yield describes what breaking down an item produces
+type Yield struct {
+	ItemID blizzard.ItemID `json:"item_id"`
+	Method Method          `json:"method"`
+
+	// number of items consumed per breakdown, such as five herbs per mill, defaulting to one
+	InputQuantity int `json:"input_quantity"`
+
+	Outcomes []Outcome `json:"outcomes"`
+}
+
+func (y Yield) Validate() error {
+	if y.ItemID == 0 {
+		return fmt.Errorf("yield has no item")
+	}
+	if err := y.Method.Validate(); err != nil {
+		return fmt.Errorf("yield of item %d: %s", y.ItemID, err.Error())
+	}
+	if len(y.Outcomes) == 0 {
+		return fmt.Errorf("yield of item %d has no outcomes", y.ItemID)
+	}
+	for _, o := range y.Outcomes {
+		if o.ItemID == 0 || o.Quantity <= 0 {
+			return fmt.Errorf("yield of item %d has an invalid outcome", y.ItemID)
+		}
+	}
+
+	return nil
+}
+
+func (y Yield) ConsumedQuantity() int {
+	if y.InputQuantity <= 0 {
+		return 1
+	}
+
+	return y.InputQuantity
+}
+
+func NewYieldsFromFilepath(relativePath string) (Yields, error) {
+	logging.WithField("path", relativePath).Info("Reading yields")
+
+	body, err := util.ReadFile(relativePath)
+	if err != nil {
+		return Yields{}, err
+	}
+
+	return NewYields(body)
+}
+
+func NewYields(body []byte) (Yields, error) {
+	out := Yields{}
+	if err := json.Unmarshal(body, &out); err != nil {
+		return Yields{}, err
+	}
+
+	return out, nil
+}
+
+type Yields []Yield
+
+func (ys Yields) Validate() error {
+	for _, y := range ys {
+		if err := y.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (ys Yields) FilterByMethod(m Method) Yields {
+	out := Yields{}
+	for _, y := range ys {
+		if y.Method != m {
+			continue
+		}
+
+		out = append(out, y)
+	}
+
+	return out
+}
+
+// ItemIds gathers every source and outcome item
+func (ys Yields) ItemIds() []blizzard.ItemID {
+	seen := map[blizzard.ItemID]struct{}{}
+	out := []blizzard.ItemID{}
+	add := func(ID blizzard.ItemID) {
+		if _, ok := seen[ID]; ok {
+			return
+		}
+		seen[ID] = struct{}{}
+
+		out = append(out, ID)
+	}
+
+	for _, y := range ys {
+		add(y.ItemID)
+		for _, o := range y.Outcomes {
+			add(o.ItemID)
+		}
+	}
+
+	return out
+}
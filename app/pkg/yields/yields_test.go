@@ -0,0 +1,56 @@
+package yields
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/blizzard"
+)
+
+func TestNewYields(t *testing.T) {
+	ys, err := NewYields([]byte(`[
+		{"item_id":1,"method":"mill","input_quantity":5,"outcomes":[{"item_id":10,"quantity":2.5}]},
+		{"item_id":2,"method":"disenchant","outcomes":[{"item_id":10,"quantity":1},{"item_id":11,"quantity":0.2}]}
+	]`))
+	if err != nil {
+		t.Fatalf("could not decode yields: %s", err)
+	}
+	if err := ys.Validate(); err != nil {
+		t.Fatalf("expected the yields to be valid, got %s", err)
+	}
+
+	if ys[0].ConsumedQuantity() != 5 || ys[1].ConsumedQuantity() != 1 {
+		t.Fatalf("unexpected consumed quantities: %d %d", ys[0].ConsumedQuantity(), ys[1].ConsumedQuantity())
+	}
+	if ids := ys.ItemIds(); !reflect.DeepEqual(ids, []blizzard.ItemID{1, 10, 2, 11}) {
+		t.Fatalf("unexpected item-ids: %v", ids)
+	}
+	if milled := ys.FilterByMethod(Mill); len(milled) != 1 || milled[0].ItemID != 1 {
+		t.Fatalf("unexpected milled yields: %+v", milled)
+	}
+
+	if _, err := NewYields([]byte("{")); err == nil {
+		t.Fatal("expected malformed yields to fail")
+	}
+}
+
+func TestYieldValidate(t *testing.T) {
+	valid := Yield{ItemID: 1, Method: Prospect, Outcomes: []Outcome{{ItemID: 10, Quantity: 1}}}
+	if err := valid.Validate(); err != nil {
+		t.Fatalf("expected the yield to be valid, got %s", err)
+	}
+
+	noItem := valid
+	noItem.ItemID = 0
+	badMethod := valid
+	badMethod.Method = "smelt"
+	noOutcomes := valid
+	noOutcomes.Outcomes = nil
+	badOutcome := valid
+	badOutcome.Outcomes = []Outcome{{ItemID: 10}}
+	for _, y := range []Yield{noItem, badMethod, noOutcomes, badOutcome} {
+		if err := (Yields{valid, y}).Validate(); err == nil {
+			t.Fatalf("expected %+v to be invalid", y)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package yields
+
+import (
+	"sort"
+
+	"github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/pricelists"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/blizzard"
+)
+
+// Value compares the price of an item against what it breaks down into, per item
+type Value struct {
+	ItemID blizzard.ItemID `json:"item_id"`
+	Method Method          `json:"method"`
+
+	Price      float64 `json:"price"`
+	YieldValue float64 `json:"yield_value"`
+	Margin     float64 `json:"margin"`
+
+	// outcome items without a current price, which leave the yield value incomplete
+	UnpricedItems []blizzard.ItemID `json:"unpriced_items"`
+}
+
+func NewValue(y Yield, iPrices pricelists.ItemPrices, basis pricelists.PriceBasis) Value {
+	out := Value{
+		ItemID:        y.ItemID,
+		Method:        y.Method,
+		Price:         basis.Price(iPrices[y.ItemID]),
+		UnpricedItems: []blizzard.ItemID{},
+	}
+
+	breakdownValue := float64(0)
+	for _, o := range y.Outcomes {
+		price := basis.Price(iPrices[o.ItemID])
+		if price == 0 {
+			out.UnpricedItems = append(out.UnpricedItems, o.ItemID)
+
+			continue
+		}
+
+		breakdownValue += price * o.Quantity
+	}
+	out.YieldValue = breakdownValue / float64(y.ConsumedQuantity())
+
+	// items without a price can't be bought, so the margin is only known for listed items
+	if out.Price > 0 {
+		out.Margin = out.YieldValue - out.Price
+	}
+
+	return out
+}
+
+type Values []Value
+
+// NewValues values every yield, ranked by margin with unlisted items last
+func NewValues(ys Yields, iPrices pricelists.ItemPrices, basis pricelists.PriceBasis) Values {
+	out := Values{}
+	for _, y := range ys {
+		out = append(out, NewValue(y, iPrices, basis))
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		if (out[i].Price > 0) != (out[j].Price > 0) {
+			return out[i].Price > 0
+		}
+
+		if out[i].Margin != out[j].Margin {
+			return out[i].Margin > out[j].Margin
+		}
+
+		return out[i].ItemID < out[j].ItemID
+	})
+
+	return out
+}
+
+func (vs Values) Limit(count int) Values {
+	if len(vs) <= count {
+		return vs
+	}
+
+	return vs[:count]
+}
@@ -0,0 +1,59 @@
+package yields
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/sotah-inc/steamwheedle-cartel-server/app/pkg/pricelists"
+	"github.com/sotah-inc/steamwheedle-cartel/pkg/blizzard"
+)
+
+func TestNewValue(t *testing.T) {
+	y := Yield{ItemID: 1, Method: Mill, InputQuantity: 5, Outcomes: []Outcome{{ItemID: 10, Quantity: 2.5}}}
+	iPrices := pricelists.ItemPrices{1: {MinBuyoutPer: 3}, 10: {MinBuyoutPer: 8, MarketPrice: 10}}
+
+	// the yield value is per consumed item
+	v := NewValue(y, iPrices, pricelists.MinBuyoutPer)
+	if v.Price != 3 || v.YieldValue != 4 || v.Margin != 1 || len(v.UnpricedItems) != 0 {
+		t.Fatalf("unexpected min-buyout value: %+v", v)
+	}
+
+	v = NewValue(y, iPrices, pricelists.MarketPrice)
+	if v.YieldValue != 5 || v.Margin != 2 {
+		t.Fatalf("unexpected market value: %+v", v)
+	}
+
+	// unlisted items have no margin and unpriced outcomes are reported
+	v = NewValue(y, pricelists.ItemPrices{}, pricelists.MinBuyoutPer)
+	if v.Margin != 0 || !reflect.DeepEqual(v.UnpricedItems, []blizzard.ItemID{10}) {
+		t.Fatalf("unexpected unpriced value: %+v", v)
+	}
+}
+
+func TestNewValues(t *testing.T) {
+	ys := Yields{
+		{ItemID: 1, Method: Disenchant, Outcomes: []Outcome{{ItemID: 10, Quantity: 1}}},
+		{ItemID: 2, Method: Disenchant, Outcomes: []Outcome{{ItemID: 10, Quantity: 2}}},
+		{ItemID: 3, Method: Disenchant, Outcomes: []Outcome{{ItemID: 10, Quantity: 10}}},
+		{ItemID: 4, Method: Disenchant, Outcomes: []Outcome{{ItemID: 10, Quantity: 1}}},
+	}
+	iPrices := pricelists.ItemPrices{
+		1:  {MinBuyoutPer: 15},
+		2:  {MinBuyoutPer: 5},
+		4:  {MinBuyoutPer: 5},
+		10: {MinBuyoutPer: 10},
+	}
+
+	// ranked by margin, with ties broken by item and unlisted items last
+	ranking := []blizzard.ItemID{}
+	for _, v := range NewValues(ys, iPrices, pricelists.MinBuyoutPer) {
+		ranking = append(ranking, v.ItemID)
+	}
+	if !reflect.DeepEqual(ranking, []blizzard.ItemID{2, 4, 1, 3}) {
+		t.Fatalf("unexpected ranking: %v", ranking)
+	}
+
+	if limited := NewValues(ys, iPrices, pricelists.MinBuyoutPer).Limit(2); len(limited) != 2 {
+		t.Fatalf("unexpected limited values: %+v", limited)
+	}
+}